@@ -13,6 +13,7 @@ import (
 	cd_blobstore "github.com/buildbarn/bb-clientd/pkg/blobstore"
 	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-clientd/pkg/outputpathpersistency"
+	"github.com/buildbarn/bb-clientd/pkg/proto/clientd"
 	"github.com/buildbarn/bb-clientd/pkg/proto/configuration/bb_clientd"
 	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
@@ -31,10 +32,13 @@ import (
 	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
 	"github.com/buildbarn/bb-storage/pkg/random"
 	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"go.opentelemetry.io/otel"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/genproto/googleapis/bytestream"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -244,14 +248,165 @@ func main() {
 			symlinkFactory)
 	}
 
+	remoteOutputServiceConfiguration := configuration.RemoteOutputService
+	var snapshotUploadConcurrency *semaphore.Weighted
+	if concurrency := remoteOutputServiceConfiguration.GetSnapshotUploadConcurrency(); concurrency > 0 {
+		snapshotUploadConcurrency = semaphore.NewWeighted(concurrency)
+	}
+	var snapshotStore cd_vfs.SnapshotStore
+	if count := remoteOutputServiceConfiguration.GetMaximumSnapshotsCount(); count > 0 {
+		snapshotStore = cd_vfs.NewInMemorySnapshotStore(int(count))
+	}
+	var filterMissingConcurrency *semaphore.Weighted
+	if concurrency := remoteOutputServiceConfiguration.GetFilterMissingConcurrency(); concurrency > 0 {
+		filterMissingConcurrency = semaphore.NewWeighted(concurrency)
+	}
+	var findMissingRetry *cd_vfs.FindMissingRetryConfiguration
+	if retryConfiguration := remoteOutputServiceConfiguration.GetFindMissingRetry(); retryConfiguration != nil {
+		initialBackoff := retryConfiguration.InitialBackoff
+		if err := initialBackoff.CheckValid(); err != nil {
+			log.Fatal("Invalid FindMissingBlobs() initial backoff: ", err)
+		}
+		maximumBackoff := retryConfiguration.MaximumBackoff
+		if err := maximumBackoff.CheckValid(); err != nil {
+			log.Fatal("Invalid FindMissingBlobs() maximum backoff: ", err)
+		}
+		findMissingRetry = &cd_vfs.FindMissingRetryConfiguration{
+			MaximumAttempts: int(retryConfiguration.MaximumAttempts),
+			InitialBackoff:  initialBackoff.AsDuration(),
+			MaximumBackoff:  maximumBackoff.AsDuration(),
+		}
+	}
+	var concurrentBuildGracePeriod time.Duration
+	if gracePeriod := remoteOutputServiceConfiguration.GetConcurrentBuildGracePeriod(); gracePeriod != nil {
+		if err := gracePeriod.CheckValid(); err != nil {
+			log.Fatal("Invalid concurrent build grace period: ", err)
+		}
+		concurrentBuildGracePeriod = gracePeriod.AsDuration()
+	}
+	var preparedOutputBaseTimeout time.Duration
+	if timeout := remoteOutputServiceConfiguration.GetPreparedOutputBaseTimeout(); timeout != nil {
+		if err := timeout.CheckValid(); err != nil {
+			log.Fatal("Invalid prepared output base timeout: ", err)
+		}
+		preparedOutputBaseTimeout = timeout.AsDuration()
+	}
+	var directoryFetchTimeout time.Duration
+	if timeout := remoteOutputServiceConfiguration.GetDirectoryFetchTimeout(); timeout != nil {
+		if err := timeout.CheckValid(); err != nil {
+			log.Fatal("Invalid directory fetch timeout: ", err)
+		}
+		directoryFetchTimeout = timeout.AsDuration()
+	}
+	var findMissingRevalidationInterval time.Duration
+	if interval := remoteOutputServiceConfiguration.GetFindMissingRevalidationInterval(); interval != nil {
+		if err := interval.CheckValid(); err != nil {
+			log.Fatal("Invalid FindMissingBlobs() revalidation interval: ", err)
+		}
+		findMissingRevalidationInterval = interval.AsDuration()
+	}
+	var idempotencyTokenExpiration time.Duration
+	if expiration := remoteOutputServiceConfiguration.GetIdempotencyTokenExpiration(); expiration != nil {
+		if err := expiration.CheckValid(); err != nil {
+			log.Fatal("Invalid idempotency token expiration: ", err)
+		}
+		idempotencyTokenExpiration = expiration.AsDuration()
+	}
+	var accessLog *cd_vfs.AccessLogConfiguration
+	if accessLogConfiguration := remoteOutputServiceConfiguration.GetAccessLog(); accessLogConfiguration != nil {
+		accessLog = &cd_vfs.AccessLogConfiguration{
+			Logger:            cd_vfs.NewLogAccessLogger(),
+			MaximumPathsCount: int(accessLogConfiguration.MaximumPathsCount),
+		}
+	}
+	findMissingContentAddressableStorage := bareContentAddressableStorage
+	if findMissingConfiguration := remoteOutputServiceConfiguration.GetFindMissingContentAddressableStorage(); findMissingConfiguration != nil {
+		info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+			terminationContext,
+			terminationGroup,
+			findMissingConfiguration,
+			blobstore_configuration.NewCASBlobAccessCreator(
+				grpcClientFactory,
+				int(configuration.MaximumMessageSizeBytes)))
+		if err != nil {
+			log.Fatal("Failed to create Content Addressable Storage for FindMissingBlobs(): ", err)
+		}
+		findMissingContentAddressableStorage = info.BlobAccess
+	}
 	outputsDirectory := cd_vfs.NewRemoteOutputServiceDirectory(
 		rootHandleAllocator,
 		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
+		cd_vfs.ContentAddressableStorageRoles{
+			FileReads:   retryingContentAddressableStorage,
+			TreeReads:   bareContentAddressableStorage,
+			FindMissing: findMissingContentAddressableStorage,
+			Uploads:     bareContentAddressableStorage,
+		},
 		directoryFetcher,
 		symlinkFactory,
-		configuration.MaximumTreeSizeBytes)
+		otel.GetTracerProvider(),
+		&cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+			MaximumTreeSizeBytes:         configuration.MaximumTreeSizeBytes,
+			Clock:                        clock.SystemClock,
+			CaseInsensitiveOutputBaseIDs: remoteOutputServiceConfiguration.GetCaseInsensitiveOutputBaseIds(),
+			AccessLog:                    accessLog,
+			TrackLazyDirectories:         remoteOutputServiceConfiguration.GetTrackLazyDirectories(),
+			SortedDirectoryListing:       remoteOutputServiceConfiguration.GetSortedDirectoryListing(),
+			ReadOnly:                     remoteOutputServiceConfiguration.GetReadOnly(),
+			MetricsRegisterer:            prometheus.DefaultRegisterer,
+			Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+				MaximumFilesCountPerOutputPath:   remoteOutputServiceConfiguration.GetMaximumFilesCountPerOutputPath(),
+				MaximumSizeBytesPerOutputPath:    remoteOutputServiceConfiguration.GetMaximumSizeBytesPerOutputPath(),
+				MaximumSymlinkFollowsPerPath:     int(remoteOutputServiceConfiguration.GetMaximumSymlinkFollowsPerPath()),
+				MaximumPathDepth:                 int(remoteOutputServiceConfiguration.GetMaximumPathDepth()),
+				MaximumBatchCreateEntriesCount:   int(remoteOutputServiceConfiguration.GetMaximumBatchCreateEntriesCount()),
+				MaximumBatchStatPathsCount:       int(remoteOutputServiceConfiguration.GetMaximumBatchStatPathsCount()),
+				MaximumBatchRemovePathsCount:     int(remoteOutputServiceConfiguration.GetMaximumBatchRemovePathsCount()),
+				MaximumSymlinkTargetLengthBytes:  int(remoteOutputServiceConfiguration.GetMaximumSymlinkTargetLengthBytes()),
+				MaximumReadFileRangeSizeBytes:    remoteOutputServiceConfiguration.GetMaximumReadFileRangeSizeBytes(),
+				MaximumEstimatedMemoryUsageBytes: remoteOutputServiceConfiguration.GetMaximumEstimatedMemoryUsageBytes(),
+			},
+			Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+				CleanCorruptedOutputPaths:      remoteOutputServiceConfiguration.GetCleanCorruptedOutputPaths(),
+				FreezeOutputPathsBetweenBuilds: remoteOutputServiceConfiguration.GetFreezeOutputPathsBetweenBuilds(),
+				RejectConcurrentBuilds:         remoteOutputServiceConfiguration.GetRejectConcurrentBuilds(),
+				ConcurrentBuildGracePeriod:     concurrentBuildGracePeriod,
+				PreparedOutputBaseTimeout:      preparedOutputBaseTimeout,
+				PinDigestFunctionPerOutputBase: remoteOutputServiceConfiguration.GetPinDigestFunctionPerOutputBase(),
+				InstanceNameAliases:            remoteOutputServiceConfiguration.GetInstanceNameAliases(),
+			},
+			FindMissing: cd_vfs.FindMissingConfiguration{
+				Concurrency:                      int(remoteOutputServiceConfiguration.GetFindMissingConcurrency()),
+				BatchSize:                        int(remoteOutputServiceConfiguration.GetFindMissingBatchSize()),
+				OutputPathsConcurrency:           filterMissingConcurrency,
+				Retry:                            findMissingRetry,
+				RevalidationInterval:             findMissingRevalidationInterval,
+				RemoveChildrenMissingDuringBuild: remoteOutputServiceConfiguration.GetRemoveChildrenMissingDuringBuild(),
+			},
+			Snapshots: cd_vfs.SnapshotConfiguration{
+				UploadConcurrency: snapshotUploadConcurrency,
+				Store:             snapshotStore,
+			},
+			BatchCreate: cd_vfs.BatchCreateConfiguration{
+				EagerlyFetchDirectories:          remoteOutputServiceConfiguration.GetEagerlyFetchDirectories(),
+				PrefetchRootDirectories:          remoteOutputServiceConfiguration.GetPrefetchRootDirectories(),
+				ValidatePaths:                    remoteOutputServiceConfiguration.GetValidatePaths(),
+				PreserveUnchangedFiles:           remoteOutputServiceConfiguration.GetPreserveUnchangedFiles(),
+				IdempotencyTokenExpiration:       idempotencyTokenExpiration,
+				MaximumIdempotencyTokensPerBuild: int(remoteOutputServiceConfiguration.GetMaximumIdempotencyTokensPerBuild()),
+				TrackExecutableBitChanges:        remoteOutputServiceConfiguration.GetTrackExecutableBitChanges(),
+			},
+			Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+				CacheDirectoryAttributes:  remoteOutputServiceConfiguration.GetCacheDirectoryAttributes(),
+				FileDigestCacheSize:       int(remoteOutputServiceConfiguration.GetFileDigestCacheSize()),
+				MaximumInternedNamesCount: int(remoteOutputServiceConfiguration.GetMaximumInternedNamesCount()),
+			},
+			Fetching: cd_vfs.FetchConfiguration{
+				DirectoryConcurrency:   remoteOutputServiceConfiguration.GetDirectoryFetchConcurrency(),
+				DirectoryTimeout:       directoryFetchTimeout,
+				CASFileReadConcurrency: remoteOutputServiceConfiguration.GetCasFileReadConcurrency(),
+			},
+		})
 
 	// Construct the top-level directory of the virtual file system
 	// mount. It contains three subdirectories:
@@ -287,6 +442,56 @@ func main() {
 		log.Fatal("Failed to expose virtual file system mount: ", err)
 	}
 
+	// Upon termination, give running builds the opportunity to
+	// complete, and persist the state of output paths.
+	if shutdownTimeout := remoteOutputServiceConfiguration.GetShutdownTimeout(); shutdownTimeout != nil {
+		if err := shutdownTimeout.CheckValid(); err != nil {
+			log.Fatal("Invalid shutdown timeout: ", err)
+		}
+		terminationGroup.Go(func() error {
+			<-terminationContext.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout.AsDuration())
+			defer cancel()
+			if err := outputsDirectory.Shutdown(ctx); err != nil {
+				log.Print("Failed to shut down Remote Output Service: ", err)
+			}
+			return nil
+		})
+	}
+
+	// Optionally let the ClientDaemon gRPC service report whether the
+	// Content Addressable Storage can be reached. A non-empty
+	// sentinel blob is used, as requests for the empty blob may be
+	// answered without contacting the backend.
+	var contentAddressableStorageHealthServer grpc_health_v1.HealthServer
+	if healthCheckConfiguration := configuration.ContentAddressableStorageHealthCheck; healthCheckConfiguration != nil {
+		instanceName, err := digest.NewInstanceName(healthCheckConfiguration.InstanceName)
+		if err != nil {
+			log.Fatalf("Invalid health check instance name %#v: %s", healthCheckConfiguration.InstanceName, err)
+		}
+		digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_SHA256, 0)
+		if err != nil {
+			log.Fatal("Failed to obtain health check digest function: ", err)
+		}
+		cacheDuration := healthCheckConfiguration.CacheDuration
+		if err := cacheDuration.CheckValid(); err != nil {
+			log.Fatal("Invalid health check cache duration: ", err)
+		}
+		timeout := healthCheckConfiguration.Timeout
+		if err := timeout.CheckValid(); err != nil {
+			log.Fatal("Invalid health check timeout: ", err)
+		}
+		sentinelContents := []byte("bb_clientd health check")
+		sentinelGenerator := digestFunction.NewGenerator(int64(len(sentinelContents)))
+		sentinelGenerator.Write(sentinelContents)
+		contentAddressableStorageHealthServer = cd_blobstore.NewContentAddressableStorageHealthServer(
+			bareContentAddressableStorage,
+			sentinelGenerator.Sum(),
+			clock.SystemClock,
+			cacheDuration.AsDuration(),
+			timeout.AsDuration())
+	}
+
 	// Create a gRPC server that forwards requests to backend clusters.
 	if err := bb_grpc.NewServersFromConfigurationAndServe(
 		configuration.GrpcServers,
@@ -317,6 +522,7 @@ func main() {
 			remoteexecution.RegisterExecutionServer(s, buildQueue)
 
 			remoteoutputservice.RegisterRemoteOutputServiceServer(s, outputsDirectory)
+			clientd.RegisterClientDaemonServer(s, cd_vfs.NewClientDaemonServer(outputsDirectory, contentAddressableStorageHealthServer))
 		}); err != nil {
 		log.Fatal("gRPC server failure: ", err)
 	}
@@ -0,0 +1,68 @@
+package blobstore
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+)
+
+type concurrencyLimitingBlobAccess struct {
+	blobstore.BlobAccess
+	semaphore *semaphore.Weighted
+}
+
+// NewConcurrencyLimitingBlobAccess creates a decorator for BlobAccess
+// that limits the number of Get() and GetFromComposite() operations
+// that may be in flight concurrently. An operation is considered to be
+// in flight from the moment it is started until the buffer it returns
+// has been consumed or discarded. Excess operations block until
+// capacity becomes available, or until their context is done.
+//
+// This decorator can be used to prevent large numbers of files being
+// read through the virtual file system at once from overwhelming the
+// Content Addressable Storage.
+func NewConcurrencyLimitingBlobAccess(base blobstore.BlobAccess, semaphore *semaphore.Weighted) blobstore.BlobAccess {
+	return &concurrencyLimitingBlobAccess{
+		BlobAccess: base,
+		semaphore:  semaphore,
+	}
+}
+
+func (ba *concurrencyLimitingBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	if ba.semaphore.Acquire(ctx, 1) != nil {
+		return buffer.NewBufferFromError(util.StatusWrap(util.StatusFromContext(ctx), "Failed to wait for other reads to complete"))
+	}
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.Get(ctx, digest),
+		semaphoreReleasingErrorHandler{semaphore: ba.semaphore})
+}
+
+func (ba *concurrencyLimitingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	if ba.semaphore.Acquire(ctx, 1) != nil {
+		return buffer.NewBufferFromError(util.StatusWrap(util.StatusFromContext(ctx), "Failed to wait for other reads to complete"))
+	}
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.GetFromComposite(ctx, parentDigest, childDigest, slicer),
+		semaphoreReleasingErrorHandler{semaphore: ba.semaphore})
+}
+
+// semaphoreReleasingErrorHandler is an ErrorHandler that is used by
+// concurrencyLimitingBlobAccess to release capacity once a buffer has
+// been consumed. Errors are propagated unmodified.
+type semaphoreReleasingErrorHandler struct {
+	semaphore *semaphore.Weighted
+}
+
+func (eh semaphoreReleasingErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	return nil, err
+}
+
+func (eh semaphoreReleasingErrorHandler) Done() {
+	eh.semaphore.Release(1)
+}
@@ -0,0 +1,108 @@
+package blobstore_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/semaphore"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimitingBlobAccessGet(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBlobAccess := mock.NewMockBlobAccess(ctrl)
+	blobAccess := blobstore.NewConcurrencyLimitingBlobAccess(baseBlobAccess, semaphore.NewWeighted(2))
+
+	helloDigest := digest.MustNewDigest("instance_name", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("SlowBackend", func(t *testing.T) {
+		// Let many reads against a slow backend run in
+		// parallel. The number of reads in flight should never
+		// exceed the configured limit.
+		var inFlight, maximumInFlight atomic.Int32
+		baseBlobAccess.EXPECT().Get(gomock.Any(), helloDigest).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+				current := inFlight.Add(1)
+				for {
+					maximum := maximumInFlight.Load()
+					if current <= maximum || maximumInFlight.CompareAndSwap(maximum, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				inFlight.Add(-1)
+				return buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))
+			}).
+			Times(20)
+
+		var wg sync.WaitGroup
+		results := make([][]byte, 20)
+		errs := make([]error, 20)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = blobAccess.Get(ctx, helloDigest).ToByteSlice(10000)
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range results {
+			require.NoError(t, errs[i])
+			require.Equal(t, []byte("Hello"), results[i])
+		}
+
+		require.LessOrEqual(t, maximumInFlight.Load(), int32(2))
+	})
+
+	t.Run("HeldUntilConsumed", func(t *testing.T) {
+		// Capacity should only be released after buffers have
+		// been consumed. While two buffers are held, a third
+		// read should block until its context is done.
+		baseBlobAccess.EXPECT().Get(ctx, helloDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))).
+			Times(2)
+		b1 := blobAccess.Get(ctx, helloDigest)
+		b2 := blobAccess.Get(ctx, helloDigest)
+
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := blobAccess.Get(ctxWithTimeout, helloDigest).ToByteSlice(10000)
+		testutil.RequireEqualStatus(t, status.Error(codes.DeadlineExceeded, "Failed to wait for other reads to complete: context deadline exceeded"), err)
+
+		// Consuming one of the buffers should make capacity
+		// available again. Errors should also cause capacity
+		// to be released.
+		b1.Discard()
+		baseBlobAccess.EXPECT().Get(ctx, helloDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.Internal, "Server on fire")))
+		_, err = blobAccess.Get(ctx, helloDigest).ToByteSlice(10000)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Server on fire"), err)
+
+		data, err := b2.ToByteSlice(10000)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+
+		baseBlobAccess.EXPECT().Get(ctx, helloDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))).
+			Times(2)
+		b1 = blobAccess.Get(ctx, helloDigest)
+		b2 = blobAccess.Get(ctx, helloDigest)
+		b1.Discard()
+		b2.Discard()
+	})
+}
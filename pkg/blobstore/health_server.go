@@ -0,0 +1,92 @@
+package blobstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type contentAddressableStorageHealthServer struct {
+	contentAddressableStorage blobstore.BlobAccess
+	sentinelDigests           digest.Set
+	clock                     clock.Clock
+	cacheDuration             time.Duration
+	timeout                   time.Duration
+
+	lock            sync.Mutex
+	lastCheckTime   time.Time
+	lastCheckStatus grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+// NewContentAddressableStorageHealthServer creates an implementation of
+// the gRPC health checking protocol that reports whether the Content
+// Addressable Storage (CAS) can be reached. This is done by calling
+// FindMissingBlobs() against a sentinel digest. An empty set of digests
+// cannot be used for this purpose, as many BlobAccess implementations
+// don't forward such requests to the backend.
+//
+// The results of health checks are cached for a given amount of time,
+// so that frequent readiness probes don't cause excessive load on the
+// CAS. Calls against the CAS are performed using a context that is not
+// tied to the request of an individual client, as the result is shared
+// with other clients. Checks that time out are not cached.
+//
+// This server is not registered as the gRPC health checking service,
+// as NewServersFromConfigurationAndServe() already registers one that
+// unconditionally reports SERVING. It is invoked through the
+// CheckHealth() method of the ClientDaemon service instead.
+func NewContentAddressableStorageHealthServer(contentAddressableStorage blobstore.BlobAccess, sentinelDigest digest.Digest, clock clock.Clock, cacheDuration, timeout time.Duration) grpc_health_v1.HealthServer {
+	return &contentAddressableStorageHealthServer{
+		contentAddressableStorage: contentAddressableStorage,
+		sentinelDigests:           sentinelDigest.ToSingletonSet(),
+		clock:                     clock,
+		cacheDuration:             cacheDuration,
+		timeout:                   timeout,
+	}
+}
+
+func (hs *contentAddressableStorageHealthServer) Check(ctx context.Context, request *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if request.Service != "" {
+		return nil, status.Error(codes.NotFound, "Unknown service")
+	}
+
+	// Hold the lock while contacting the CAS, so that concurrent
+	// health checks share the result of a single call.
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+
+	if now := hs.clock.Now(); hs.lastCheckTime.IsZero() || !now.Before(hs.lastCheckTime.Add(hs.cacheDuration)) {
+		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), hs.timeout)
+		_, err := hs.contentAddressableStorage.FindMissing(ctxWithTimeout, hs.sentinelDigests)
+		timedOut := ctxWithTimeout.Err() != nil
+		cancel()
+		if err != nil {
+			if timedOut {
+				// Don't cache the result, so that the
+				// next health check retries immediately.
+				return &grpc_health_v1.HealthCheckResponse{
+					Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+				}, nil
+			}
+			hs.lastCheckStatus = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		} else {
+			hs.lastCheckStatus = grpc_health_v1.HealthCheckResponse_SERVING
+		}
+		hs.lastCheckTime = now
+	}
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: hs.lastCheckStatus,
+	}, nil
+}
+
+func (hs *contentAddressableStorageHealthServer) Watch(request *grpc_health_v1.HealthCheckRequest, server grpc_health_v1.Health_WatchServer) error {
+	return status.Error(codes.Unimplemented, "Watching the health of the Content Addressable Storage is not supported")
+}
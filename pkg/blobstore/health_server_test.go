@@ -0,0 +1,115 @@
+package blobstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestContentAddressableStorageHealthServer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	sentinelDigest := digest.MustNewDigest("instance_name", remoteexecution.DigestFunction_MD5, "d41d8cd98f00b204e9800998ecf8427e", 0)
+	healthServer := blobstore.NewContentAddressableStorageHealthServer(
+		contentAddressableStorage,
+		sentinelDigest,
+		clock,
+		10*time.Second,
+		100*time.Millisecond)
+
+	t.Run("UnknownService", func(t *testing.T) {
+		_, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+			Service: "build.bazel.remote.execution.v2.Execution",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Unknown service"), err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// If the CAS cannot be reached, the service should be
+		// reported as not serving.
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), sentinelDigest.ToSingletonSet()).
+			Return(digest.EmptySet, status.Error(codes.Unavailable, "Server not reachable"))
+
+		response, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, response)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		// Successive health checks should reuse the previous
+		// result, without contacting the CAS.
+		clock.EXPECT().Now().Return(time.Unix(1009, 0))
+
+		response, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, response)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Once the cached result expires, the CAS should be
+		// contacted once more.
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), sentinelDigest.ToSingletonSet()).
+			Return(sentinelDigest.ToSingletonSet(), nil)
+
+		response, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_SERVING,
+		}, response)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		// Calls against the CAS should not use the context of
+		// the client, but one that has a timeout applied.
+		// Cancelation of the client's request should thus have
+		// no effect.
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		clock.EXPECT().Now().Return(time.Unix(1020, 0))
+		contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), sentinelDigest.ToSingletonSet()).
+			DoAndReturn(func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+				<-ctx.Done()
+				require.Equal(t, context.DeadlineExceeded, ctx.Err())
+				return digest.EmptySet, util.StatusFromContext(ctx)
+			})
+
+		response, err := healthServer.Check(canceledCtx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, response)
+
+		// Results of health checks that timed out should not
+		// be cached.
+		clock.EXPECT().Now().Return(time.Unix(1021, 0))
+		contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), sentinelDigest.ToSingletonSet()).
+			Return(sentinelDigest.ToSingletonSet(), nil)
+
+		response, err = healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_SERVING,
+		}, response)
+	})
+}
@@ -0,0 +1,96 @@
+package cas
+
+import (
+	"context"
+	"fmt"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type decodedTreeDirectoryWalker struct {
+	treeDigest digest.Digest
+	children   map[digest.Digest]*remoteexecution.Directory
+}
+
+// NewDecodedTreeDirectoryWalker creates a DirectoryWalker for a Tree
+// object that has already been fetched from the Content Addressable
+// Storage (CAS) and decoded. Unlike the DirectoryWalker returned by
+// NewTreeDirectoryWalker(), it does not perform any I/O when
+// directories are accessed. This makes it suitable for cases where the
+// full directory hierarchy is expected to be traversed.
+func NewDecodedTreeDirectoryWalker(tree *remoteexecution.Tree, treeDigest digest.Digest) (cas.DirectoryWalker, error) {
+	// Index all child directories by digest, so that they can be
+	// looked up by the DirectoryNodes referring to them.
+	digestFunction := treeDigest.GetDigestFunction()
+	children := make(map[digest.Digest]*remoteexecution.Directory, len(tree.Children))
+	for i, child := range tree.Children {
+		data, err := proto.Marshal(child)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to marshal child directory at index %d", i)
+		}
+		generator := digestFunction.NewGenerator(int64(len(data)))
+		if _, err := generator.Write(data); err != nil {
+			return nil, util.StatusWrapf(err, "Failed to compute digest of child directory at index %d", i)
+		}
+		children[generator.Sum()] = child
+	}
+
+	return &decodedTreeRootDirectoryWalker{
+		decodedTreeDirectoryWalker: decodedTreeDirectoryWalker{
+			treeDigest: treeDigest,
+			children:   children,
+		},
+		rootDirectory: tree.Root,
+	}, nil
+}
+
+func (dw *decodedTreeDirectoryWalker) GetChild(childDigest digest.Digest) cas.DirectoryWalker {
+	return &decodedTreeChildDirectoryWalker{
+		decodedTreeDirectoryWalker: dw,
+		childDigest:                childDigest,
+	}
+}
+
+func (dw *decodedTreeDirectoryWalker) GetContainingDigest() digest.Digest {
+	return dw.treeDigest
+}
+
+type decodedTreeRootDirectoryWalker struct {
+	decodedTreeDirectoryWalker
+	rootDirectory *remoteexecution.Directory
+}
+
+func (dw *decodedTreeRootDirectoryWalker) GetDirectory(ctx context.Context) (*remoteexecution.Directory, error) {
+	if dw.rootDirectory == nil {
+		return nil, status.Error(codes.InvalidArgument, "Tree does not contain a root directory")
+	}
+	return dw.rootDirectory, nil
+}
+
+func (dw *decodedTreeRootDirectoryWalker) GetDescription() string {
+	return fmt.Sprintf("Tree %#v root directory", dw.treeDigest.String())
+}
+
+type decodedTreeChildDirectoryWalker struct {
+	*decodedTreeDirectoryWalker
+	childDigest digest.Digest
+}
+
+func (dw *decodedTreeChildDirectoryWalker) GetDirectory(ctx context.Context) (*remoteexecution.Directory, error) {
+	directory, ok := dw.children[dw.childDigest]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "Tree does not contain a child directory with this digest")
+	}
+	return directory, nil
+}
+
+func (dw *decodedTreeChildDirectoryWalker) GetDescription() string {
+	return fmt.Sprintf("Tree %#v child directory %#v", dw.treeDigest.String(), dw.childDigest.String())
+}
@@ -0,0 +1,189 @@
+package cas_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/cas"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// getDirectoryDigest computes the digest of a Directory message, so
+// that it can be referenced by a DirectoryNode.
+func getDirectoryDigest(t testing.TB, digestFunction digest.Function, directory *remoteexecution.Directory) digest.Digest {
+	data, err := proto.Marshal(directory)
+	require.NoError(t, err)
+	generator := digestFunction.NewGenerator(int64(len(data)))
+	_, err = generator.Write(data)
+	require.NoError(t, err)
+	return generator.Sum()
+}
+
+func TestDecodedTreeDirectoryWalker(t *testing.T) {
+	ctx := context.Background()
+
+	treeDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6884a9e20905b512d1122a2b1ad8ba16", 123)
+	digestFunction := treeDigest.GetDigestFunction()
+	exampleChildDirectory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{
+				Name: "bar",
+				Digest: &remoteexecution.Digest{
+					Hash:      "f9c2df111171a614b738e157a482e117",
+					SizeBytes: 789,
+				},
+			},
+		},
+	}
+	childDigest := getDirectoryDigest(t, digestFunction, exampleChildDirectory)
+	exampleRootDirectory := &remoteexecution.Directory{
+		Directories: []*remoteexecution.DirectoryNode{
+			{
+				Name:   "foo",
+				Digest: childDigest.GetProto(),
+			},
+		},
+	}
+
+	rootDirectoryWalker, err := cas.NewDecodedTreeDirectoryWalker(&remoteexecution.Tree{
+		Root:     exampleRootDirectory,
+		Children: []*remoteexecution.Directory{exampleChildDirectory},
+	}, treeDigest)
+	require.NoError(t, err)
+
+	t.Run("RootGetDirectory", func(t *testing.T) {
+		rootDirectory, err := rootDirectoryWalker.GetDirectory(ctx)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, exampleRootDirectory, rootDirectory)
+	})
+
+	t.Run("RootGetDescription", func(t *testing.T) {
+		require.Equal(
+			t,
+			"Tree \"3-6884a9e20905b512d1122a2b1ad8ba16-123-example\" root directory",
+			rootDirectoryWalker.GetDescription())
+	})
+
+	t.Run("RootGetContainingDigest", func(t *testing.T) {
+		require.Equal(t, treeDigest, rootDirectoryWalker.GetContainingDigest())
+	})
+
+	t.Run("ChildGetDirectorySuccess", func(t *testing.T) {
+		childDirectory, err := rootDirectoryWalker.GetChild(childDigest).GetDirectory(ctx)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, exampleChildDirectory, childDirectory)
+	})
+
+	t.Run("ChildGetDirectoryNonexistent", func(t *testing.T) {
+		// Attempting to access directories that are not part
+		// of the Tree should fail, as opposed to causing
+		// requests against the CAS.
+		nonexistentDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "4df5f448a5e6b3c41e6aae7a8a9832aa", 456)
+		_, err := rootDirectoryWalker.GetChild(nonexistentDigest).GetDirectory(ctx)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Tree does not contain a child directory with this digest"), err)
+	})
+
+	t.Run("ChildGetContainingDigest", func(t *testing.T) {
+		require.Equal(t, treeDigest, rootDirectoryWalker.GetChild(childDigest).GetContainingDigest())
+	})
+}
+
+// walkDirectoriesRecursively loads all directories reachable through a
+// DirectoryWalker, similar to a build that traverses an output
+// directory in its entirety.
+func walkDirectoriesRecursively(ctx context.Context, b *testing.B, digestFunction digest.Function, directoryWalker re_cas.DirectoryWalker) {
+	directory, err := directoryWalker.GetDirectory(ctx)
+	require.NoError(b, err)
+	for _, entry := range directory.Directories {
+		childDigest, err := digestFunction.NewDigestFromProto(entry.Digest)
+		require.NoError(b, err)
+		walkDirectoriesRecursively(ctx, b, digestFunction, directoryWalker.GetChild(childDigest))
+	}
+}
+
+func BenchmarkTreeDirectoryWalkerFullTraversal(b *testing.B) {
+	ctrl, ctx := gomock.WithContext(context.Background(), b)
+
+	// Create a Tree object containing a root directory that has
+	// many child directories.
+	treeDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_SHA256, "ec3b8b4f2b1a6b1f6a4dcd7f1c1c0a1b3e6c8f5d2a4b9e7c1d3f5a7b9c1e3f5a", 123)
+	digestFunction := treeDigest.GetDigestFunction()
+	tree := &remoteexecution.Tree{
+		Root: &remoteexecution.Directory{},
+	}
+	for i := 0; i < 100; i++ {
+		child := &remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: fmt.Sprintf("file%d", i),
+					Digest: &remoteexecution.Digest{
+						Hash:      "f9c2df111171a614b738e157a482e117f9c2df111171a614b738e157a482e117",
+						SizeBytes: 789,
+					},
+				},
+			},
+		}
+		tree.Children = append(tree.Children, child)
+		tree.Root.Directories = append(tree.Root.Directories, &remoteexecution.DirectoryNode{
+			Name:   fmt.Sprintf("directory%d", i),
+			Digest: getDirectoryDigest(b, digestFunction, child).GetProto(),
+		})
+	}
+	treeData, err := proto.Marshal(tree)
+	require.NoError(b, err)
+
+	b.Run("Lazy", func(b *testing.B) {
+		// Let every access to a directory decode the Tree
+		// object, which is what a DirectoryFetcher needs to do
+		// if the Tree object is no longer cached.
+		directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+				var tree remoteexecution.Tree
+				if err := proto.Unmarshal(treeData, &tree); err != nil {
+					return nil, err
+				}
+				return tree.Root, nil
+			}).AnyTimes()
+		directoryFetcher.EXPECT().GetTreeChildDirectory(gomock.Any(), treeDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+				var tree remoteexecution.Tree
+				if err := proto.Unmarshal(treeData, &tree); err != nil {
+					return nil, err
+				}
+				for _, child := range tree.Children {
+					if getDirectoryDigest(b, digestFunction, child) == childDigest {
+						return child, nil
+					}
+				}
+				return nil, status.Error(codes.NotFound, "Child directory not found")
+			}).AnyTimes()
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			walkDirectoriesRecursively(ctx, b, digestFunction, cas.NewTreeDirectoryWalker(directoryFetcher, treeDigest))
+		}
+	})
+
+	b.Run("Eager", func(b *testing.B) {
+		// Decode the Tree object once, and walk over it.
+		for i := 0; i < b.N; i++ {
+			var tree remoteexecution.Tree
+			require.NoError(b, proto.Unmarshal(treeData, &tree))
+			directoryWalker, err := cas.NewDecodedTreeDirectoryWalker(&tree, treeDigest)
+			require.NoError(b, err)
+			walkDirectoriesRecursively(ctx, b, digestFunction, directoryWalker)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package cas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/singleflight"
+)
+
+type deduplicatingDirectoryFetcher struct {
+	base      cas.DirectoryFetcher
+	semaphore *semaphore.Weighted
+	timeout   time.Duration
+	group     singleflight.Group
+}
+
+// NewDeduplicatingDirectoryFetcher creates a decorator for
+// DirectoryFetcher that coalesces concurrent requests for the same
+// Directory message into a single request against the backend. In
+// addition to that, it limits the number of requests that are sent to
+// the backend concurrently.
+//
+// As the results of a single request may be returned to multiple
+// callers, requests against the backend are not canceled when the
+// context of the caller that initiated them is canceled. They do
+// retain the values of that context, so that tracing and request
+// metadata are propagated. To prevent requests that hang from
+// occupying one of the slots indefinitely, requests against the
+// backend are bounded by a timeout, which must be positive.
+//
+// Callers whose context is canceled stop waiting for the results
+// immediately. Directory messages that are returned are shared between
+// callers, and must not be modified.
+func NewDeduplicatingDirectoryFetcher(base cas.DirectoryFetcher, concurrency int64, timeout time.Duration) cas.DirectoryFetcher {
+	return &deduplicatingDirectoryFetcher{
+		base:      base,
+		semaphore: semaphore.NewWeighted(concurrency),
+		timeout:   timeout,
+	}
+}
+
+// detachedContext is a Context that carries the values of another
+// Context, but is not canceled when the other Context is canceled.
+//
+// TODO: Replace this with context.WithoutCancel() once Go 1.21 is
+// used.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (df *deduplicatingDirectoryFetcher) fetch(ctx context.Context, key string, fetchFunc func(ctx context.Context) (*remoteexecution.Directory, error)) (*remoteexecution.Directory, error) {
+	results := df.group.DoChan(key, func() (interface{}, error) {
+		fetchCtx := detachedContext{Context: ctx}
+		if err := df.semaphore.Acquire(fetchCtx, 1); err != nil {
+			return nil, err
+		}
+		defer df.semaphore.Release(1)
+
+		ctxWithTimeout, cancel := context.WithTimeout(fetchCtx, df.timeout)
+		defer cancel()
+		directory, err := fetchFunc(ctxWithTimeout)
+		if err != nil && ctxWithTimeout.Err() != nil {
+			return nil, util.StatusWrap(util.StatusFromContext(ctxWithTimeout), "Timed out while fetching directory")
+		}
+		return directory, err
+	})
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*remoteexecution.Directory), nil
+	case <-ctx.Done():
+		return nil, util.StatusFromContext(ctx)
+	}
+}
+
+func (df *deduplicatingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.fetch(ctx, fmt.Sprintf("directory/%s", directoryDigest), func(ctx context.Context) (*remoteexecution.Directory, error) {
+		return df.base.GetDirectory(ctx, directoryDigest)
+	})
+}
+
+func (df *deduplicatingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.fetch(ctx, fmt.Sprintf("tree_root/%s", treeDigest), func(ctx context.Context) (*remoteexecution.Directory, error) {
+		return df.base.GetTreeRootDirectory(ctx, treeDigest)
+	})
+}
+
+func (df *deduplicatingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.fetch(ctx, fmt.Sprintf("tree_child/%s/%s", treeDigest, childDigest), func(ctx context.Context) (*remoteexecution.Directory, error) {
+		return df.base.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+	})
+}
@@ -0,0 +1,214 @@
+package cas_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// doneNotifyingContext is a Context that closes a channel once Done()
+// is called. As DeduplicatingDirectoryFetcher only calls Done() after
+// it has registered a request, this allows tests to wait for requests
+// to be coalesced without sleeping.
+type doneNotifyingContext struct {
+	context.Context
+	once   sync.Once
+	called chan struct{}
+}
+
+func newDoneNotifyingContext(ctx context.Context) *doneNotifyingContext {
+	return &doneNotifyingContext{
+		Context: ctx,
+		called:  make(chan struct{}),
+	}
+}
+
+func (ctx *doneNotifyingContext) Done() <-chan struct{} {
+	ctx.once.Do(func() { close(ctx.called) })
+	return ctx.Context.Done()
+}
+
+func TestDeduplicatingDirectoryFetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	treeDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6884a9e20905b512d1122a2b1ad8ba16", 123)
+	childDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "4df5f448a5e6b3c41e6aae7a8a9832aa", 456)
+	exampleDirectory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{
+				Name: "bar",
+				Digest: &remoteexecution.Digest{
+					Hash:      "f9c2df111171a614b738e157a482e117",
+					SizeBytes: 789,
+				},
+			},
+		},
+	}
+
+	t.Run("Failure", func(t *testing.T) {
+		baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher := cas.NewDeduplicatingDirectoryFetcher(baseDirectoryFetcher, 10, time.Minute)
+
+		baseDirectoryFetcher.EXPECT().GetTreeChildDirectory(gomock.Any(), treeDigest, childDigest).
+			Return(nil, status.Error(codes.Internal, "Server failure"))
+
+		_, err := directoryFetcher.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Server failure"), err)
+	})
+
+	t.Run("Coalescing", func(t *testing.T) {
+		// Concurrent requests for the same Directory message
+		// should only cause a single request to be sent to the
+		// backend.
+		baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher := cas.NewDeduplicatingDirectoryFetcher(baseDirectoryFetcher, 10, time.Minute)
+
+		fetchStarted := make(chan struct{})
+		fetchRelease := make(chan struct{})
+		baseDirectoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+				close(fetchStarted)
+				<-fetchRelease
+				return exampleDirectory, nil
+			})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			directory, err := directoryFetcher.GetTreeRootDirectory(ctx, treeDigest)
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, exampleDirectory, directory)
+		}()
+		<-fetchStarted
+
+		secondCtx := newDoneNotifyingContext(ctx)
+		go func() {
+			defer wg.Done()
+			directory, err := directoryFetcher.GetTreeRootDirectory(secondCtx, treeDigest)
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, exampleDirectory, directory)
+		}()
+		<-secondCtx.called
+
+		close(fetchRelease)
+		wg.Wait()
+	})
+
+	t.Run("ConcurrencyLimit", func(t *testing.T) {
+		// Requests for different Directory messages should not
+		// be coalesced. With a concurrency of one, the second
+		// request may only be sent once the first completes.
+		baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher := cas.NewDeduplicatingDirectoryFetcher(baseDirectoryFetcher, 1, time.Minute)
+
+		fetchStarted := make(chan struct{})
+		fetchRelease := make(chan struct{})
+		baseDirectoryFetcher.EXPECT().GetDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+				close(fetchStarted)
+				<-fetchRelease
+				return exampleDirectory, nil
+			})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := directoryFetcher.GetDirectory(ctx, treeDigest)
+			require.NoError(t, err)
+		}()
+		<-fetchStarted
+
+		secondCtx := newDoneNotifyingContext(ctx)
+		go func() {
+			defer wg.Done()
+			_, err := directoryFetcher.GetDirectory(secondCtx, childDigest)
+			require.NoError(t, err)
+		}()
+		<-secondCtx.called
+
+		baseDirectoryFetcher.EXPECT().GetDirectory(gomock.Any(), childDigest).Return(exampleDirectory, nil)
+		close(fetchRelease)
+		wg.Wait()
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		// Callers should stop waiting once their context is
+		// canceled. The request against the backend should
+		// continue, as it may be shared with other callers.
+		baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher := cas.NewDeduplicatingDirectoryFetcher(baseDirectoryFetcher, 10, time.Minute)
+
+		fetchRelease := make(chan struct{})
+		fetchDone := make(chan struct{})
+		baseDirectoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+				defer close(fetchDone)
+				<-fetchRelease
+				return exampleDirectory, nil
+			})
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := directoryFetcher.GetTreeRootDirectory(canceledCtx, treeDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+
+		close(fetchRelease)
+		<-fetchDone
+	})
+
+	t.Run("ContextValues", func(t *testing.T) {
+		// Requests against the backend should retain the values
+		// of the caller's context, while having a timeout.
+		baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher := cas.NewDeduplicatingDirectoryFetcher(baseDirectoryFetcher, 10, time.Minute)
+
+		type contextKey struct{}
+		baseDirectoryFetcher.EXPECT().GetDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+				require.Equal(t, "value", ctx.Value(contextKey{}))
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+				return exampleDirectory, nil
+			})
+
+		directory, err := directoryFetcher.GetDirectory(context.WithValue(ctx, contextKey{}, "value"), treeDigest)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, exampleDirectory, directory)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		// Requests against the backend that hang should fail
+		// with DEADLINE_EXCEEDED, releasing their slot.
+		baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+		directoryFetcher := cas.NewDeduplicatingDirectoryFetcher(baseDirectoryFetcher, 1, time.Millisecond)
+
+		baseDirectoryFetcher.EXPECT().GetDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+				<-ctx.Done()
+				return nil, util.StatusFromContext(ctx)
+			})
+
+		_, err := directoryFetcher.GetDirectory(ctx, treeDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.DeadlineExceeded, "Timed out while fetching directory: context deadline exceeded"), err)
+
+		baseDirectoryFetcher.EXPECT().GetDirectory(gomock.Any(), childDigest).Return(exampleDirectory, nil)
+
+		_, err = directoryFetcher.GetDirectory(ctx, childDigest)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+package cas
+
+import (
+	"context"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type timeoutDirectoryFetcher struct {
+	base    cas.DirectoryFetcher
+	timeout time.Duration
+}
+
+// NewTimeoutDirectoryFetcher creates a decorator for DirectoryFetcher
+// that bounds the amount of time each request may take. This is
+// needed, because directories in output paths are loaded lazily using
+// a context that is not associated with any RPC, meaning that an
+// unresponsive Content Addressable Storage would otherwise cause
+// operations against the virtual file system to hang indefinitely.
+// Requests that take too long fail with DEADLINE_EXCEEDED.
+func NewTimeoutDirectoryFetcher(base cas.DirectoryFetcher, timeout time.Duration) cas.DirectoryFetcher {
+	return &timeoutDirectoryFetcher{
+		base:    base,
+		timeout: timeout,
+	}
+}
+
+func (df *timeoutDirectoryFetcher) fetch(ctx context.Context, fetchFunc func(ctx context.Context) (*remoteexecution.Directory, error)) (*remoteexecution.Directory, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, df.timeout)
+	defer cancel()
+	directory, err := fetchFunc(ctxWithTimeout)
+	if err != nil && ctx.Err() == nil && ctxWithTimeout.Err() != nil {
+		// Make sure that timeouts are reported consistently,
+		// regardless of how the backend propagates them.
+		return nil, util.StatusWrap(util.StatusFromContext(ctxWithTimeout), "Timed out while fetching directory")
+	}
+	return directory, err
+}
+
+func (df *timeoutDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.fetch(ctx, func(ctx context.Context) (*remoteexecution.Directory, error) {
+		return df.base.GetDirectory(ctx, directoryDigest)
+	})
+}
+
+func (df *timeoutDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.fetch(ctx, func(ctx context.Context) (*remoteexecution.Directory, error) {
+		return df.base.GetTreeRootDirectory(ctx, treeDigest)
+	})
+}
+
+func (df *timeoutDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.fetch(ctx, func(ctx context.Context) (*remoteexecution.Directory, error) {
+		return df.base.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+	})
+}
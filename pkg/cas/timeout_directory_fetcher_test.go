@@ -0,0 +1,81 @@
+package cas_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTimeoutDirectoryFetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	directoryFetcher := cas.NewTimeoutDirectoryFetcher(baseDirectoryFetcher, 10*time.Millisecond)
+
+	treeDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6884a9e20905b512d1122a2b1ad8ba16", 123)
+	childDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "4df5f448a5e6b3c41e6aae7a8a9832aa", 456)
+
+	t.Run("Success", func(t *testing.T) {
+		exampleDirectory := &remoteexecution.Directory{}
+		baseDirectoryFetcher.EXPECT().GetTreeChildDirectory(gomock.Any(), treeDigest, childDigest).DoAndReturn(
+			func(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+				return exampleDirectory, nil
+			})
+
+		directory, err := directoryFetcher.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+		require.NoError(t, err)
+		require.Same(t, exampleDirectory, directory)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// Errors returned by the backend should be propagated
+		// as is.
+		baseDirectoryFetcher.EXPECT().GetDirectory(gomock.Any(), childDigest).
+			Return(nil, status.Error(codes.Internal, "Server failure"))
+
+		_, err := directoryFetcher.GetDirectory(ctx, childDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Server failure"), err)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		// A backend that blocks should cause the request to
+		// fail once the timeout expires, even if the caller
+		// provided a context without a deadline.
+		baseDirectoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+				<-ctx.Done()
+				return nil, status.Error(codes.Unavailable, "Connection interrupted")
+			})
+
+		_, err := directoryFetcher.GetTreeRootDirectory(context.Background(), treeDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.DeadlineExceeded, "Timed out while fetching directory: context deadline exceeded"), err)
+	})
+
+	t.Run("CallerCanceled", func(t *testing.T) {
+		// Cancelation of the caller's context should not be
+		// reported as a timeout.
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		baseDirectoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).DoAndReturn(
+			func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+				return nil, util.StatusFromContext(ctx)
+			})
+
+		_, err := directoryFetcher.GetTreeRootDirectory(canceledCtx, treeDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+	})
+}
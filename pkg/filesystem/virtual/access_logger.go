@@ -0,0 +1,60 @@
+package virtual
+
+import (
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// AccessLogEntry describes a single call against the Remote Output
+// Service that has completed.
+type AccessLogEntry struct {
+	Method       string
+	BuildID      string
+	OutputBaseID string
+
+	// The number of files, directories, symbolic links and paths
+	// contained in the request, where applicable.
+	FilesCount       int
+	DirectoriesCount int
+	SymlinksCount    int
+	PathsCount       int
+
+	// Some of the paths contained in the request. The number of
+	// paths is limited, so that large requests don't cause
+	// excessive amounts of logging.
+	SamplePaths []string
+
+	Code    codes.Code
+	Latency time.Duration
+}
+
+// AccessLogger is called into by RemoteOutputServiceDirectory every
+// time a call against the Remote Output Service has completed.
+type AccessLogger interface {
+	LogAccess(entry *AccessLogEntry)
+}
+
+type logAccessLogger struct{}
+
+// NewLogAccessLogger creates an AccessLogger that writes entries to
+// the standard logger, one line per call.
+func NewLogAccessLogger() AccessLogger {
+	return logAccessLogger{}
+}
+
+func (logAccessLogger) LogAccess(entry *AccessLogEntry) {
+	log.Printf(
+		"Remote Output Service access: method=%s build_id=%#v output_base_id=%#v files=%d directories=%d symlinks=%d paths=%d sample_paths=%#v code=%s latency=%s",
+		entry.Method,
+		entry.BuildID,
+		entry.OutputBaseID,
+		entry.FilesCount,
+		entry.DirectoriesCount,
+		entry.SymlinksCount,
+		entry.PathsCount,
+		entry.SamplePaths,
+		entry.Code,
+		entry.Latency)
+}
@@ -0,0 +1,130 @@
+package virtual
+
+import (
+	"context"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+// BuildEventType indicates what kind of change to an output path is
+// described by a BuildEvent.
+type BuildEventType int
+
+const (
+	// BuildEventChildRemoved indicates that a file or directory was
+	// removed from the output path at the start of the build,
+	// because it was absent from the Content Addressable Storage.
+	// FilterChildren() does not report the paths of children, so
+	// the child is identified by the digest that caused it to be
+	// removed.
+	BuildEventChildRemoved BuildEventType = iota
+	// BuildEventDirectoryChanged indicates that the contents of a
+	// directory in the output path were replaced or removed in
+	// their entirety.
+	BuildEventDirectoryChanged
+	// BuildEventBlobMissing indicates that a blob on which a file or
+	// directory in the output path depends was found to be absent
+	// from the Content Addressable Storage while the build was
+	// running. The file or directory is left in place, unless
+	// RemoveChildrenMissingDuringBuild is set, in which case
+	// BuildEventChildRemoved is reported instead.
+	BuildEventBlobMissing
+)
+
+// BuildEvent describes a single change to an output path that was not
+// requested by the build client through BatchCreate(), and is reported
+// through WatchBuild().
+type BuildEvent struct {
+	// Change IDs are assigned to events in increasing order,
+	// starting at one for every build. They can be provided to
+	// WatchBuild() to resume watching.
+	ChangeID uint64
+	Type     BuildEventType
+
+	// The digest that caused a child to be removed, if known. Set
+	// for BuildEventChildRemoved and BuildEventBlobMissing.
+	Digest digest.Digest
+
+	// The path of the directory whose contents changed, relative
+	// to the root of the output path. Set for
+	// BuildEventDirectoryChanged.
+	Path string
+}
+
+// buildEventLog contains all of the events that occurred while a build
+// was running against an output path. Events are retained until the
+// build is finalized, so that clients that call WatchBuild() after
+// StartBuild() has completed still observe removals that were
+// performed by StartBuild().
+type buildEventLog struct {
+	lock     sync.Mutex
+	events   []BuildEvent
+	closed   bool
+	wakeup   chan struct{}
+	done     chan struct{}
+	changeID uint64
+}
+
+func newBuildEventLog() *buildEventLog {
+	return &buildEventLog{
+		wakeup: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// append an event to the log, waking up any watchers. Events that are
+// appended after the build has ended are discarded.
+func (l *buildEventLog) append(event BuildEvent) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return
+	}
+	l.changeID++
+	event.ChangeID = l.changeID
+	l.events = append(l.events, event)
+	close(l.wakeup)
+	l.wakeup = make(chan struct{})
+}
+
+// close the log, causing watchers to terminate after all events have
+// been returned. This needs to be called when the build ends.
+func (l *buildEventLog) close() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.wakeup)
+		close(l.done)
+	}
+}
+
+// wait for events to be appended to the log whose change ID exceeds
+// the one provided. The boolean return value indicates whether the log
+// has been closed, and no further events will be returned.
+func (l *buildEventLog) wait(ctx context.Context, afterChangeID uint64) ([]BuildEvent, bool, error) {
+	for {
+		l.lock.Lock()
+		// Change IDs are assigned sequentially, meaning they
+		// can be used to index the log directly.
+		if afterChangeID < l.changeID {
+			events := l.events[afterChangeID:]
+			l.lock.Unlock()
+			return events, false, nil
+		}
+		if l.closed {
+			l.lock.Unlock()
+			return nil, true, nil
+		}
+		wakeup := l.wakeup
+		l.lock.Unlock()
+
+		select {
+		case <-wakeup:
+		case <-ctx.Done():
+			return nil, false, util.StatusFromContext(ctx)
+		}
+	}
+}
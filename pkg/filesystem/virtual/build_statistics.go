@@ -0,0 +1,98 @@
+package virtual
+
+import (
+	"context"
+	"sync/atomic"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// BuildStatistics contains counters of the amount of data that was
+// loaded from the Content Addressable Storage (CAS) while a build was
+// running against an output path.
+type BuildStatistics struct {
+	// The number of bytes read from files that are backed by the
+	// CAS, either through the virtual file system or by uploading
+	// the output path.
+	CASFileBytesRead uint64
+
+	// The total size of the REv2 Directory messages that were
+	// loaded as part of directories created through BatchCreate().
+	// Directories that are fetched eagerly are accounted for by the
+	// size of the Tree object.
+	DirectoryBytesFetched uint64
+}
+
+// outputPathStatistics contains the counters of the amount of data
+// loaded from the CAS over the lifetime of an output path. Statistics
+// of individual builds are obtained by taking the difference between
+// the values at the start and end of the build.
+type outputPathStatistics struct {
+	casFileBytesRead      atomic.Uint64
+	directoryBytesFetched atomic.Uint64
+}
+
+func (s *outputPathStatistics) get() BuildStatistics {
+	return BuildStatistics{
+		CASFileBytesRead:      s.casFileBytesRead.Load(),
+		DirectoryBytesFetched: s.directoryBytesFetched.Load(),
+	}
+}
+
+// byteCountingBlobAccess is a decorator for BlobAccess that counts the
+// number of bytes read through ReadAt() on buffers returned by Get().
+// This is the method that is used by files backed by the CAS.
+type byteCountingBlobAccess struct {
+	blobstore.BlobAccess
+	bytesRead *atomic.Uint64
+}
+
+func (ba *byteCountingBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	return &byteCountingBuffer{
+		Buffer:    ba.BlobAccess.Get(ctx, blobDigest),
+		bytesRead: ba.bytesRead,
+	}
+}
+
+type byteCountingBuffer struct {
+	buffer.Buffer
+	bytesRead *atomic.Uint64
+}
+
+func (b *byteCountingBuffer) ReadAt(p []byte, off int64) (int, error) {
+	n, err := b.Buffer.ReadAt(p, off)
+	b.bytesRead.Add(uint64(n))
+	return n, err
+}
+
+// byteCountingDirectoryFetcher is a decorator for DirectoryFetcher that
+// counts the size of the Directory messages that are returned.
+type byteCountingDirectoryFetcher struct {
+	cas.DirectoryFetcher
+	bytesFetched *atomic.Uint64
+}
+
+func (df *byteCountingDirectoryFetcher) record(directory *remoteexecution.Directory, err error) (*remoteexecution.Directory, error) {
+	if err == nil {
+		df.bytesFetched.Add(uint64(proto.Size(directory)))
+	}
+	return directory, err
+}
+
+func (df *byteCountingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.record(df.DirectoryFetcher.GetDirectory(ctx, directoryDigest))
+}
+
+func (df *byteCountingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.record(df.DirectoryFetcher.GetTreeRootDirectory(ctx, treeDigest))
+}
+
+func (df *byteCountingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	return df.record(df.DirectoryFetcher.GetTreeChildDirectory(ctx, treeDigest, childDigest))
+}
@@ -0,0 +1,89 @@
+package virtual
+
+import (
+	"context"
+	"sync/atomic"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// inFlightCountingBlobAccess is a decorator for BlobAccess that keeps
+// track of the number of calls that are in flight. Calls to Get() and
+// GetFromComposite() are considered to be in flight until the buffer
+// they return has been consumed. The count is reported through
+// GetRuntimeStatistics().
+type inFlightCountingBlobAccess struct {
+	blobstore.BlobAccess
+	callsInFlight *atomic.Int64
+}
+
+func (ba *inFlightCountingBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	ba.callsInFlight.Add(1)
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.Get(ctx, blobDigest),
+		inFlightDecrementingErrorHandler{callsInFlight: ba.callsInFlight})
+}
+
+func (ba *inFlightCountingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	ba.callsInFlight.Add(1)
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.GetFromComposite(ctx, parentDigest, childDigest, slicer),
+		inFlightDecrementingErrorHandler{callsInFlight: ba.callsInFlight})
+}
+
+func (ba *inFlightCountingBlobAccess) Put(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+	ba.callsInFlight.Add(1)
+	defer ba.callsInFlight.Add(-1)
+	return ba.BlobAccess.Put(ctx, blobDigest, b)
+}
+
+func (ba *inFlightCountingBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	ba.callsInFlight.Add(1)
+	defer ba.callsInFlight.Add(-1)
+	return ba.BlobAccess.FindMissing(ctx, digests)
+}
+
+// inFlightDecrementingErrorHandler is an ErrorHandler that is used by
+// inFlightCountingBlobAccess to decrement the number of calls in flight
+// once a buffer has been consumed. Errors are propagated unmodified.
+type inFlightDecrementingErrorHandler struct {
+	callsInFlight *atomic.Int64
+}
+
+func (eh inFlightDecrementingErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	return nil, err
+}
+
+func (eh inFlightDecrementingErrorHandler) Done() {
+	eh.callsInFlight.Add(-1)
+}
+
+// inFlightCountingDirectoryFetcher is a decorator for DirectoryFetcher
+// that keeps track of the number of calls that are in flight.
+type inFlightCountingDirectoryFetcher struct {
+	cas.DirectoryFetcher
+	callsInFlight *atomic.Int64
+}
+
+func (df *inFlightCountingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	df.callsInFlight.Add(1)
+	defer df.callsInFlight.Add(-1)
+	return df.DirectoryFetcher.GetDirectory(ctx, directoryDigest)
+}
+
+func (df *inFlightCountingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	df.callsInFlight.Add(1)
+	defer df.callsInFlight.Add(-1)
+	return df.DirectoryFetcher.GetTreeRootDirectory(ctx, treeDigest)
+}
+
+func (df *inFlightCountingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	df.callsInFlight.Add(1)
+	defer df.callsInFlight.Add(-1)
+	return df.DirectoryFetcher.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+}
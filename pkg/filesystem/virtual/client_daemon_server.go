@@ -0,0 +1,722 @@
+package virtual
+
+import (
+	"context"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/pkg/proto/clientd"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	status_pb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// cleanProgressReportingInterval is the number of files and symbolic
+// links that CleanStreaming() removes between sending progress
+// messages. Sending a message for every file would be wasteful when
+// cleaning output paths containing millions of files.
+const cleanProgressReportingInterval = 1000
+
+type clientDaemonServer struct {
+	directory    *RemoteOutputServiceDirectory
+	healthServer grpc_health_v1.HealthServer
+}
+
+// NewClientDaemonServer creates a gRPC server that exposes
+// functionality of RemoteOutputServiceDirectory that is not part of
+// the Remote Output Service protocol. Requests are converted to calls
+// against the methods of RemoteOutputServiceDirectory having the same
+// name. Calls to CheckHealth() are forwarded to a health checking
+// server that reports the availability of the Content Addressable
+// Storage. If no health checking server is provided, CheckHealth()
+// fails with code UNIMPLEMENTED.
+func NewClientDaemonServer(directory *RemoteOutputServiceDirectory, healthServer grpc_health_v1.HealthServer) clientd.ClientDaemonServer {
+	return &clientDaemonServer{
+		directory:    directory,
+		healthServer: healthServer,
+	}
+}
+
+// getStatusProto converts an error to a google.rpc.Status message.
+// Unlike status.Convert(), it yields a message with code OK for nil
+// errors, so that it may be placed in lists aligned with requests.
+func getStatusProto(err error) *status_pb.Status {
+	if err == nil {
+		return &status_pb.Status{}
+	}
+	return status.Convert(err).Proto()
+}
+
+func getStatusProtos(errs []error) []*status_pb.Status {
+	statuses := make([]*status_pb.Status, 0, len(errs))
+	for _, err := range errs {
+		statuses = append(statuses, getStatusProto(err))
+	}
+	return statuses
+}
+
+// getTimestampProto converts a time to a Timestamp message, yielding
+// nil for the zero time.
+func getTimestampProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// getDigestProto converts a digest to a Digest message, yielding an
+// empty message for digest.BadDigest.
+func getDigestProto(blobDigest digest.Digest) *remoteexecution.Digest {
+	if blobDigest == digest.BadDigest {
+		return &remoteexecution.Digest{}
+	}
+	return blobDigest.GetProto()
+}
+
+func (s *clientDaemonServer) CleanStreaming(request *remoteoutputservice.CleanRequest, server clientd.ClientDaemon_CleanStreamingServer) error {
+	// Progress can only be reported through a callback, meaning
+	// that failures to send progress messages need to be
+	// propagated by canceling the context.
+	ctx, cancel := context.WithCancel(server.Context())
+	defer cancel()
+	var removedLeavesCount uint64
+	var sendErr error
+	sendProgress := func() {
+		if err := server.Send(&clientd.CleanProgress{RemovedLeavesCount: removedLeavesCount}); err != nil {
+			sendErr = util.StatusWrap(err, "Failed to send progress")
+			cancel()
+		}
+	}
+	_, err := s.directory.CleanWithProgress(ctx, request, func(count uint64) {
+		removedLeavesCount = count
+		if sendErr == nil && count%cleanProgressReportingInterval == 0 {
+			sendProgress()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Report the final number of files and symbolic links that
+	// were removed, unless it was reported already.
+	if removedLeavesCount == 0 || removedLeavesCount%cleanProgressReportingInterval != 0 {
+		sendProgress()
+	}
+	return sendErr
+}
+
+func (s *clientDaemonServer) CleanPathPrefix(ctx context.Context, request *clientd.CleanPathPrefixRequest) (*emptypb.Empty, error) {
+	return s.directory.CleanPathPrefix(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: request.OutputBaseId,
+	}, request.PathPrefix)
+}
+
+func (s *clientDaemonServer) CleanDryRun(ctx context.Context, request *clientd.CleanDryRunRequest) (*clientd.CleanDryRunResponse, error) {
+	result, err := s.directory.CleanDryRun(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: request.OutputBaseId,
+	}, int(request.MaximumSampleLeafPathsCount))
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.CleanDryRunResponse{
+		DirectoriesCount: result.DirectoriesCount,
+		LeavesCount:      result.LeavesCount,
+		SampleLeafPaths:  result.SampleLeafPaths,
+	}, nil
+}
+
+func (s *clientDaemonServer) StartBuildAsynchronously(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return s.directory.StartBuildAsynchronously(ctx, request)
+}
+
+func (s *clientDaemonServer) StartBuildFromSnapshot(ctx context.Context, request *clientd.StartBuildFromSnapshotRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	if request.StartBuild == nil {
+		return nil, status.Error(codes.InvalidArgument, "No start build request provided")
+	}
+	if request.SnapshotDigest == nil {
+		return nil, status.Error(codes.InvalidArgument, "No snapshot digest provided")
+	}
+	return s.directory.StartBuildFromSnapshot(ctx, request.StartBuild, request.SnapshotDigest)
+}
+
+func (s *clientDaemonServer) StartBuildForcingReset(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return s.directory.StartBuildForcingReset(ctx, request)
+}
+
+func (s *clientDaemonServer) StartBuildWithDigestFunctions(ctx context.Context, request *clientd.StartBuildWithDigestFunctionsRequest) (*clientd.StartBuildWithDigestFunctionsResponse, error) {
+	if request.StartBuild == nil {
+		return nil, status.Error(codes.InvalidArgument, "No start build request provided")
+	}
+	response, digestFunction, err := s.directory.StartBuildWithDigestFunctions(ctx, request.StartBuild, request.DigestFunctions)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.StartBuildWithDigestFunctionsResponse{
+		StartBuild:     response,
+		DigestFunction: digestFunction,
+	}, nil
+}
+
+func (s *clientDaemonServer) RestoreSnapshot(ctx context.Context, request *clientd.RestoreSnapshotRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	if request.StartBuild == nil {
+		return nil, status.Error(codes.InvalidArgument, "No start build request provided")
+	}
+	return s.directory.RestoreSnapshot(ctx, request.StartBuild, request.SnapshotName)
+}
+
+func (s *clientDaemonServer) PrepareOutputBase(ctx context.Context, request *clientd.PrepareOutputBaseRequest) (*emptypb.Empty, error) {
+	if err := s.directory.PrepareOutputBase(ctx, request.OutputBaseId, request.InstanceName, request.DigestFunction); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientDaemonServer) GetOutputPathTree(ctx context.Context, request *clientd.GetOutputPathTreeRequest) (*clientd.GetOutputPathTreeResponse, error) {
+	treeDigest, err := s.directory.GetOutputPathTree(ctx, request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetOutputPathTreeResponse{
+		TreeDigest: treeDigest.GetProto(),
+	}, nil
+}
+
+var outputPathRemovalReasons = map[OutputPathRemovalReason]clientd.OutputPathRemovalEvent_Reason{
+	OutputPathRemovalReasonClean:     clientd.OutputPathRemovalEvent_CLEAN,
+	OutputPathRemovalReasonCorrupted: clientd.OutputPathRemovalEvent_CORRUPTED,
+}
+
+// outputPathRemovalEventStream converts events sent by
+// WatchOutputPathRemovals() to Protobuf messages.
+type outputPathRemovalEventStream struct {
+	clientd.ClientDaemon_WatchOutputPathRemovalsServer
+}
+
+func (s outputPathRemovalEventStream) Send(event *OutputPathRemovalEvent) error {
+	return s.ClientDaemon_WatchOutputPathRemovalsServer.Send(&clientd.OutputPathRemovalEvent{
+		OutputBaseId:   event.OutputBaseID,
+		Reason:         outputPathRemovalReasons[event.Reason],
+		LastAccessTime: getTimestampProto(event.LastAccessTime),
+	})
+}
+
+func (s *clientDaemonServer) WatchOutputPathRemovals(request *emptypb.Empty, server clientd.ClientDaemon_WatchOutputPathRemovalsServer) error {
+	return s.directory.WatchOutputPathRemovals(outputPathRemovalEventStream{
+		ClientDaemon_WatchOutputPathRemovalsServer: server,
+	})
+}
+
+func (s *clientDaemonServer) Checkpoint(ctx context.Context, request *emptypb.Empty) (*clientd.CheckpointResponse, error) {
+	outputPathsCount, err := s.directory.Checkpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.CheckpointResponse{
+		OutputPathsCount: int64(outputPathsCount),
+	}, nil
+}
+
+func (s *clientDaemonServer) CloneOutputPath(ctx context.Context, request *clientd.CloneOutputPathRequest) (*emptypb.Empty, error) {
+	if err := s.directory.CloneOutputPath(ctx, request.SourceOutputBaseId, request.DestinationOutputBaseId, request.Overwrite); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientDaemonServer) RenameOutputBase(ctx context.Context, request *clientd.RenameOutputBaseRequest) (*emptypb.Empty, error) {
+	if err := s.directory.RenameOutputBase(ctx, request.SourceOutputBaseId, request.DestinationOutputBaseId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+var selfTestStages = map[SelfTestStage]clientd.DiagnoseSelfTestResponse_StageResult_Stage{
+	SelfTestStageUpload:      clientd.DiagnoseSelfTestResponse_StageResult_UPLOAD,
+	SelfTestStageStartBuild:  clientd.DiagnoseSelfTestResponse_StageResult_START_BUILD,
+	SelfTestStageBatchCreate: clientd.DiagnoseSelfTestResponse_StageResult_BATCH_CREATE,
+	SelfTestStageBatchStat:   clientd.DiagnoseSelfTestResponse_StageResult_BATCH_STAT,
+	SelfTestStageRead:        clientd.DiagnoseSelfTestResponse_StageResult_READ,
+	SelfTestStageClean:       clientd.DiagnoseSelfTestResponse_StageResult_CLEAN,
+}
+
+func (s *clientDaemonServer) DiagnoseSelfTest(ctx context.Context, request *clientd.DiagnoseSelfTestRequest) (*clientd.DiagnoseSelfTestResponse, error) {
+	results, err := s.directory.DiagnoseSelfTest(ctx, request.InstanceName, request.DigestFunction)
+	if err != nil {
+		return nil, err
+	}
+	stageResults := make([]*clientd.DiagnoseSelfTestResponse_StageResult, 0, len(results))
+	for _, result := range results {
+		stageResults = append(stageResults, &clientd.DiagnoseSelfTestResponse_StageResult{
+			Stage:  selfTestStages[result.Stage],
+			Status: getStatusProto(result.Err),
+		})
+	}
+	return &clientd.DiagnoseSelfTestResponse{
+		StageResults: stageResults,
+	}, nil
+}
+
+func (s *clientDaemonServer) CheckHealth(ctx context.Context, request *emptypb.Empty) (*clientd.CheckHealthResponse, error) {
+	if s.healthServer == nil {
+		return nil, status.Error(codes.Unimplemented, "Health checking of the Content Addressable Storage is not enabled")
+	}
+	response, err := s.healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.CheckHealthResponse{
+		Serving: response.Status == grpc_health_v1.HealthCheckResponse_SERVING,
+	}, nil
+}
+
+func (s *clientDaemonServer) GetRuntimeStatistics(ctx context.Context, request *emptypb.Empty) (*clientd.GetRuntimeStatisticsResponse, error) {
+	statistics := s.directory.GetRuntimeStatistics()
+	return &clientd.GetRuntimeStatisticsResponse{
+		OutputPathsCount:                         int64(statistics.OutputPathsCount),
+		RunningBuildsCount:                       int64(statistics.RunningBuildsCount),
+		CorruptedOutputPathsCount:                int64(statistics.CorruptedOutputPathsCount),
+		QueuedStartBuildsCount:                   statistics.QueuedStartBuildsCount,
+		FilteringOutputPathsCount:                statistics.FilteringOutputPathsCount,
+		CasCallsInFlightCount:                    statistics.CASCallsInFlightCount,
+		FileDigestCacheHits:                      statistics.FileDigestCacheHits,
+		FileDigestCacheMisses:                    statistics.FileDigestCacheMisses,
+		FileDigestCacheEntriesCount:              int64(statistics.FileDigestCacheEntriesCount),
+		NameInternerHits:                         statistics.NameInternerHits,
+		NameInternerMisses:                       statistics.NameInternerMisses,
+		InternedNamesCount:                       int64(statistics.InternedNamesCount),
+		EstimatedOutputPathsMemoryUsageBytes:     statistics.EstimatedOutputPathsMemoryUsageBytes,
+		EstimatedFileDigestCacheMemoryUsageBytes: statistics.EstimatedFileDigestCacheMemoryUsageBytes,
+		EstimatedNameInternerMemoryUsageBytes:    statistics.EstimatedNameInternerMemoryUsageBytes,
+	}, nil
+}
+
+func (s *clientDaemonServer) GetOutputPathLastAccessTime(ctx context.Context, request *clientd.GetOutputPathLastAccessTimeRequest) (*clientd.GetOutputPathLastAccessTimeResponse, error) {
+	lastAccessTime, err := s.directory.GetOutputPathLastAccessTime(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetOutputPathLastAccessTimeResponse{
+		LastAccessTime: getTimestampProto(lastAccessTime),
+	}, nil
+}
+
+func (s *clientDaemonServer) GetOutputBaseStatistics(ctx context.Context, request *clientd.GetOutputBaseStatisticsRequest) (*clientd.GetOutputBaseStatisticsResponse, error) {
+	statistics, err := s.directory.GetOutputBaseStatistics(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetOutputBaseStatisticsResponse{
+		FilesCount:         statistics.FilesCount,
+		DirectoriesCount:   statistics.DirectoriesCount,
+		SymlinksCount:      statistics.SymlinksCount,
+		SizeBytes:          statistics.SizeBytes,
+		LastBuildStartTime: getTimestampProto(statistics.LastBuildStartTime),
+	}, nil
+}
+
+var buildPhases = map[BuildPhase]clientd.GetBuildStatusResponse_Phase{
+	BuildPhaseFiltering: clientd.GetBuildStatusResponse_FILTERING,
+	BuildPhaseReady:     clientd.GetBuildStatusResponse_READY,
+	BuildPhaseFailed:    clientd.GetBuildStatusResponse_FAILED,
+}
+
+func (s *clientDaemonServer) GetBuildStatus(ctx context.Context, request *clientd.GetBuildStatusRequest) (*clientd.GetBuildStatusResponse, error) {
+	buildStatus, err := s.directory.GetBuildStatus(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	response := &clientd.GetBuildStatusResponse{
+		Phase:                buildPhases[buildStatus.Phase],
+		ChildrenScannedCount: buildStatus.ChildrenScannedCount,
+		ChildrenRemovedCount: buildStatus.ChildrenRemovedCount,
+	}
+	if buildStatus.Error != nil {
+		response.Error = status.Convert(buildStatus.Error).Proto()
+	}
+	return response, nil
+}
+
+func (s *clientDaemonServer) GetMissingBlobs(ctx context.Context, request *clientd.GetMissingBlobsRequest) (*clientd.GetMissingBlobsResponse, error) {
+	missingBlobs, err := s.directory.GetMissingBlobs(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	missingBlobDigests := make([]*remoteexecution.Digest, 0, missingBlobs.Length())
+	for _, blobDigest := range missingBlobs.Items() {
+		missingBlobDigests = append(missingBlobDigests, blobDigest.GetProto())
+	}
+	return &clientd.GetMissingBlobsResponse{
+		MissingBlobDigests: missingBlobDigests,
+	}, nil
+}
+
+func (s *clientDaemonServer) GetExecutableBitChanges(ctx context.Context, request *clientd.GetExecutableBitChangesRequest) (*clientd.GetExecutableBitChangesResponse, error) {
+	paths, err := s.directory.GetExecutableBitChanges(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetExecutableBitChangesResponse{
+		Paths: paths,
+	}, nil
+}
+
+func (s *clientDaemonServer) GetInitialOutputPathContents(ctx context.Context, request *clientd.GetInitialOutputPathContentsRequest) (*clientd.GetInitialOutputPathContentsResponse, error) {
+	treeDigest, err := s.directory.GetInitialOutputPathContents(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetInitialOutputPathContentsResponse{
+		TreeDigest: treeDigest.GetProto(),
+	}, nil
+}
+
+func (s *clientDaemonServer) GetBuildErrors(ctx context.Context, request *clientd.GetBuildErrorsRequest) (*clientd.GetBuildErrorsResponse, error) {
+	errs, err := s.directory.GetBuildErrors(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetBuildErrorsResponse{
+		Errors: getStatusProtos(errs),
+	}, nil
+}
+
+func (s *clientDaemonServer) GetOutputBasePath(ctx context.Context, request *clientd.GetOutputBasePathRequest) (*clientd.GetOutputBasePathResponse, error) {
+	outputBasePath, err := s.directory.GetOutputBasePath(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.GetOutputBasePathResponse{
+		OutputBasePath: outputBasePath,
+	}, nil
+}
+
+func (s *clientDaemonServer) PingBuild(ctx context.Context, request *clientd.PingBuildRequest) (*emptypb.Empty, error) {
+	if err := s.directory.PingBuild(ctx, request.BuildId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+var buildEventTypes = map[BuildEventType]clientd.BuildEvent_Type{
+	BuildEventChildRemoved:     clientd.BuildEvent_CHILD_REMOVED,
+	BuildEventDirectoryChanged: clientd.BuildEvent_DIRECTORY_CHANGED,
+	BuildEventBlobMissing:      clientd.BuildEvent_BLOB_MISSING,
+}
+
+// buildEventStream converts events sent by WatchBuild() to Protobuf
+// messages.
+type buildEventStream struct {
+	clientd.ClientDaemon_WatchBuildServer
+}
+
+func (s buildEventStream) Send(event *BuildEvent) error {
+	eventMessage := &clientd.BuildEvent{
+		ChangeId: event.ChangeID,
+		Type:     buildEventTypes[event.Type],
+		Path:     event.Path,
+	}
+	if event.Digest != digest.BadDigest {
+		eventMessage.Digest = event.Digest.GetProto()
+	}
+	return s.ClientDaemon_WatchBuildServer.Send(eventMessage)
+}
+
+func (s *clientDaemonServer) WatchBuild(request *clientd.WatchBuildRequest, server clientd.ClientDaemon_WatchBuildServer) error {
+	return s.directory.WatchBuild(request.BuildId, request.AfterChangeId, buildEventStream{
+		ClientDaemon_WatchBuildServer: server,
+	})
+}
+
+func (s *clientDaemonServer) BatchCreateWithIdempotencyToken(ctx context.Context, request *clientd.BatchCreateWithIdempotencyTokenRequest) (*emptypb.Empty, error) {
+	if request.BatchCreate == nil {
+		return nil, status.Error(codes.InvalidArgument, "No batch create request provided")
+	}
+	return s.directory.BatchCreateWithIdempotencyToken(ctx, request.BatchCreate, request.IdempotencyToken)
+}
+
+func (s *clientDaemonServer) BatchCreateWithInlineTrees(ctx context.Context, request *clientd.BatchCreateWithInlineTreesRequest) (*emptypb.Empty, error) {
+	if request.BatchCreate == nil {
+		return nil, status.Error(codes.InvalidArgument, "No batch create request provided")
+	}
+	// Protobuf cannot distinguish between absent and empty byte
+	// strings. Treat empty ones as absent, as an empty Tree object
+	// is never valid.
+	inlineTrees := make([][]byte, 0, len(request.InlineTrees))
+	for _, inlineTree := range request.InlineTrees {
+		if len(inlineTree) == 0 {
+			inlineTree = nil
+		}
+		inlineTrees = append(inlineTrees, inlineTree)
+	}
+	return s.directory.BatchCreateWithInlineTrees(ctx, request.BatchCreate, inlineTrees)
+}
+
+func (s *clientDaemonServer) BatchCreateContinuingOnError(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*clientd.BatchCreateContinuingOnErrorResponse, error) {
+	results, err := s.directory.BatchCreateContinuingOnError(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.BatchCreateContinuingOnErrorResponse{
+		FileErrors:      getStatusProtos(results.FileErrors),
+		DirectoryErrors: getStatusProtos(results.DirectoryErrors),
+		SymlinkErrors:   getStatusProtos(results.SymlinkErrors),
+	}, nil
+}
+
+func (s *clientDaemonServer) BatchCreateStream(server clientd.ClientDaemon_BatchCreateStreamServer) error {
+	if err := s.directory.BatchCreateStream(server); err != nil {
+		return err
+	}
+	return server.SendAndClose(&emptypb.Empty{})
+}
+
+func (s *clientDaemonServer) BatchCreateLinks(ctx context.Context, request *clientd.BatchCreateLinksRequest) (*emptypb.Empty, error) {
+	links := make([]OutputLink, 0, len(request.Links))
+	for _, link := range request.Links {
+		links = append(links, OutputLink{
+			SourcePath:      link.SourcePath,
+			DestinationPath: link.DestinationPath,
+		})
+	}
+	if err := s.directory.BatchCreateLinks(ctx, request.BuildId, links); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientDaemonServer) BatchRemove(ctx context.Context, request *clientd.BatchRemoveRequest) (*clientd.BatchRemoveResponse, error) {
+	errs, err := s.directory.BatchRemove(ctx, request.BuildId, request.Paths, request.Recursive)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.BatchRemoveResponse{
+		Errors: getStatusProtos(errs),
+	}, nil
+}
+
+func (s *clientDaemonServer) TrimBuild(ctx context.Context, request *clientd.TrimBuildRequest) (*clientd.TrimBuildResponse, error) {
+	trimmedDirectoriesCount, err := s.directory.TrimBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.TrimBuildResponse{
+		TrimmedDirectoriesCount: int64(trimmedDirectoriesCount),
+	}, nil
+}
+
+func (s *clientDaemonServer) ExportPath(ctx context.Context, request *clientd.ExportPathRequest) (*emptypb.Empty, error) {
+	destination, err := filesystem.NewLocalDirectory(request.DestinationPath)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to open destination directory %#v", request.DestinationPath)
+	}
+	defer destination.Close()
+
+	if err := s.directory.ExportPath(ctx, request.BuildId, request.SourcePath, destination); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+var digestInclusionModes = map[clientd.BatchStatWithOptionsRequest_DigestInclusionMode]DigestInclusionMode{
+	clientd.BatchStatWithOptionsRequest_NEVER:           DigestInclusionModeNever,
+	clientd.BatchStatWithOptionsRequest_ALWAYS:          DigestInclusionModeAlways,
+	clientd.BatchStatWithOptionsRequest_IF_KNOWN:        DigestInclusionModeIfKnown,
+	clientd.BatchStatWithOptionsRequest_ALWAYS_VERIFIED: DigestInclusionModeAlwaysVerified,
+}
+
+// getBatchStatOptions extracts the BatchStatRequest and
+// DigestInclusionMode from a BatchStatWithOptionsRequest.
+func getBatchStatOptions(request *clientd.BatchStatWithOptionsRequest) (*remoteoutputservice.BatchStatRequest, DigestInclusionMode, error) {
+	if request.BatchStat == nil {
+		return nil, 0, status.Error(codes.InvalidArgument, "No batch stat request provided")
+	}
+	digestInclusionMode, ok := digestInclusionModes[request.DigestInclusionMode]
+	if !ok {
+		return nil, 0, status.Errorf(codes.InvalidArgument, "Unknown digest inclusion mode %d", request.DigestInclusionMode)
+	}
+	return request.BatchStat, digestInclusionMode, nil
+}
+
+func (s *clientDaemonServer) BatchStatWithDigestInclusionMode(ctx context.Context, request *clientd.BatchStatWithOptionsRequest) (*remoteoutputservice.BatchStatResponse, error) {
+	batchStatRequest, digestInclusionMode, err := getBatchStatOptions(request)
+	if err != nil {
+		return nil, err
+	}
+	return s.directory.BatchStatWithDigestInclusionMode(ctx, batchStatRequest, digestInclusionMode)
+}
+
+func (s *clientDaemonServer) BatchStatWithExecutableBits(ctx context.Context, request *clientd.BatchStatWithOptionsRequest) (*clientd.BatchStatWithExecutableBitsResponse, error) {
+	batchStatRequest, digestInclusionMode, err := getBatchStatOptions(request)
+	if err != nil {
+		return nil, err
+	}
+	response, executableBits, err := s.directory.BatchStatWithExecutableBits(ctx, batchStatRequest, digestInclusionMode)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.BatchStatWithExecutableBitsResponse{
+		BatchStat:      response,
+		ExecutableBits: executableBits,
+	}, nil
+}
+
+func (s *clientDaemonServer) BatchStatWithMissingPathDetails(ctx context.Context, request *clientd.BatchStatWithOptionsRequest) (*clientd.BatchStatWithMissingPathDetailsResponse, error) {
+	batchStatRequest, digestInclusionMode, err := getBatchStatOptions(request)
+	if err != nil {
+		return nil, err
+	}
+	response, missingPathErrors, err := s.directory.BatchStatWithMissingPathDetails(ctx, batchStatRequest, digestInclusionMode)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.BatchStatWithMissingPathDetailsResponse{
+		BatchStat:         response,
+		MissingPathErrors: getStatusProtos(missingPathErrors),
+	}, nil
+}
+
+func (s *clientDaemonServer) BatchStatPartial(ctx context.Context, request *clientd.BatchStatWithOptionsRequest) (*clientd.BatchStatPartialResponse, error) {
+	batchStatRequest, digestInclusionMode, err := getBatchStatOptions(request)
+	if err != nil {
+		return nil, err
+	}
+	response, unprocessedPathsCount, err := s.directory.BatchStatPartial(ctx, batchStatRequest, digestInclusionMode)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.BatchStatPartialResponse{
+		BatchStat:             response,
+		UnprocessedPathsCount: int64(unprocessedPathsCount),
+	}, nil
+}
+
+var pathExistences = map[PathExistence]clientd.BatchStatExistenceResponse_PathExistence{
+	PathExistenceAbsent:  clientd.BatchStatExistenceResponse_ABSENT,
+	PathExistencePresent: clientd.BatchStatExistenceResponse_PRESENT,
+	PathExistenceUnknown: clientd.BatchStatExistenceResponse_UNKNOWN,
+}
+
+func (s *clientDaemonServer) BatchStatExistence(ctx context.Context, request *remoteoutputservice.BatchStatRequest) (*clientd.BatchStatExistenceResponse, error) {
+	results, err := s.directory.BatchStatExistence(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	existences := make([]clientd.BatchStatExistenceResponse_PathExistence, 0, len(results))
+	for _, result := range results {
+		existences = append(existences, pathExistences[result])
+	}
+	return &clientd.BatchStatExistenceResponse{
+		PathExistences: existences,
+	}, nil
+}
+
+func (s *clientDaemonServer) ReadDirectory(ctx context.Context, request *clientd.ReadDirectoryRequest) (*clientd.ReadDirectoryResponse, error) {
+	entries, err := s.directory.ReadDirectory(ctx, request.BuildId, request.DirectoryPath, request.IncludeFileDigests)
+	if err != nil {
+		return nil, err
+	}
+	entryMessages := make([]*clientd.ReadDirectoryResponse_Entry, 0, len(entries))
+	for _, entry := range entries {
+		entryMessages = append(entryMessages, &clientd.ReadDirectoryResponse_Entry{
+			Name:       entry.Name.String(),
+			FileStatus: entry.FileStatus,
+		})
+	}
+	return &clientd.ReadDirectoryResponse{
+		Entries: entryMessages,
+	}, nil
+}
+
+func (s *clientDaemonServer) GetFileContents(ctx context.Context, request *clientd.GetFileContentsRequest) (*clientd.GetFileContentsResponse, error) {
+	fileContents, err := s.directory.GetFileContents(ctx, request.BuildId, request.Paths, request.MaximumSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	fileContentsMessages := make([]*clientd.GetFileContentsResponse_FileContents, 0, len(fileContents))
+	for _, contents := range fileContents {
+		var fileContentsMessage clientd.GetFileContentsResponse_FileContents
+		if contents != nil {
+			if contents.Digest != nil {
+				fileContentsMessage.ContentsOrDigest = &clientd.GetFileContentsResponse_FileContents_Digest{
+					Digest: contents.Digest,
+				}
+			} else {
+				fileContentsMessage.ContentsOrDigest = &clientd.GetFileContentsResponse_FileContents_Contents{
+					Contents: contents.Contents,
+				}
+			}
+		}
+		fileContentsMessages = append(fileContentsMessages, &fileContentsMessage)
+	}
+	return &clientd.GetFileContentsResponse{
+		FileContents: fileContentsMessages,
+	}, nil
+}
+
+func (s *clientDaemonServer) ReadFileRange(ctx context.Context, request *clientd.ReadFileRangeRequest) (*clientd.ReadFileRangeResponse, error) {
+	contents, err := s.directory.ReadFileRange(ctx, request.BuildId, request.FilePath, request.Offset, request.SizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &clientd.ReadFileRangeResponse{
+		Contents: contents,
+	}, nil
+}
+
+func (s *clientDaemonServer) ResolveSymlink(ctx context.Context, request *clientd.ResolveSymlinkRequest) (*remoteoutputservice.StatResponse, error) {
+	return s.directory.ResolveSymlink(ctx, request.BuildId, request.AbsolutePath)
+}
+
+func (s *clientDaemonServer) FinalizeBuildWithStatistics(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (*clientd.FinalizeBuildWithStatisticsResponse, error) {
+	if err := s.directory.checkWritable(); err != nil {
+		return nil, err
+	}
+	statistics := s.directory.FinalizeBuildWithStatistics(ctx, request)
+	return &clientd.FinalizeBuildWithStatisticsResponse{
+		CasFileBytesRead:      statistics.CASFileBytesRead,
+		DirectoryBytesFetched: statistics.DirectoryBytesFetched,
+	}, nil
+}
+
+func (s *clientDaemonServer) FinalizeBuildWithRemovedChildren(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (*clientd.FinalizeBuildWithRemovedChildrenResponse, error) {
+	removedChildren, err := s.directory.FinalizeBuildWithRemovedChildren(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	removedChildDigests := make([]*remoteexecution.Digest, 0, len(removedChildren.Digests))
+	for _, blobDigest := range removedChildren.Digests {
+		removedChildDigests = append(removedChildDigests, getDigestProto(blobDigest))
+	}
+	return &clientd.FinalizeBuildWithRemovedChildrenResponse{
+		RemovedChildDigests: removedChildDigests,
+		Truncated:           removedChildren.Truncated,
+	}, nil
+}
+
+func (s *clientDaemonServer) FinalizeBuildWithSnapshot(ctx context.Context, request *clientd.FinalizeBuildWithSnapshotRequest) (*emptypb.Empty, error) {
+	if request.FinalizeBuild == nil {
+		return nil, status.Error(codes.InvalidArgument, "No finalize build request provided")
+	}
+	if err := s.directory.FinalizeBuildWithSnapshot(ctx, request.FinalizeBuild, request.SnapshotName); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *clientDaemonServer) CancelBuild(ctx context.Context, request *clientd.CancelBuildRequest) (*emptypb.Empty, error) {
+	if err := s.directory.CancelBuild(ctx, request.BuildId, request.RevertOutputPath); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,131 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-clientd/pkg/proto/clientd"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestClientDaemonServerGetRuntimeStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+	s := cd_vfs.NewClientDaemonServer(d, nil)
+
+	// Without any output paths, all statistics should be zero.
+	response, err := s.GetRuntimeStatistics(ctx, &emptypb.Empty{})
+	testutil.RequireEqualStatus(t, nil, err)
+	testutil.RequireEqualProto(t, &clientd.GetRuntimeStatisticsResponse{}, response)
+}
+
+func TestClientDaemonServerCheckHealth(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+	healthServer := health.NewServer()
+	s := cd_vfs.NewClientDaemonServer(d, healthServer)
+
+	t.Run("Serving", func(t *testing.T) {
+		response, err := s.CheckHealth(ctx, &emptypb.Empty{})
+		testutil.RequireEqualStatus(t, nil, err)
+		testutil.RequireEqualProto(t, &clientd.CheckHealthResponse{
+			Serving: true,
+		}, response)
+	})
+
+	t.Run("NotServing", func(t *testing.T) {
+		healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+		response, err := s.CheckHealth(ctx, &emptypb.Empty{})
+		testutil.RequireEqualStatus(t, nil, err)
+		testutil.RequireEqualProto(t, &clientd.CheckHealthResponse{}, response)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, err := cd_vfs.NewClientDaemonServer(d, nil).CheckHealth(ctx, &emptypb.Empty{})
+		testutil.RequireEqualStatus(t, status.Error(codes.Unimplemented, "Health checking of the Content Addressable Storage is not enabled"), err)
+	})
+}
+
+func TestClientDaemonServerPingBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+	s := cd_vfs.NewClientDaemonServer(d, nil)
+
+	t.Run("UnknownBuild", func(t *testing.T) {
+		// Errors returned by RemoteOutputServiceDirectory should
+		// be propagated as is.
+		_, err := s.PingBuild(ctx, &clientd.PingBuildRequest{
+			BuildId: "b7b4a1b5-3b2d-4c0f-9a0e-7bbf3e1f0e5f",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+}
+
+func TestClientDaemonServerGetOutputBaseStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+	s := cd_vfs.NewClientDaemonServer(d, nil)
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := s.GetOutputBaseStatistics(ctx, &clientd.GetOutputBaseStatisticsRequest{
+			OutputBaseId: "..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"), err)
+	})
+
+	t.Run("NonexistentOutputBase", func(t *testing.T) {
+		_, err := s.GetOutputBaseStatistics(ctx, &clientd.GetOutputBaseStatisticsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output path \"9da951b8cb759233037166e28f7ea186\" does not exist"), err)
+	})
+}
+
+func TestClientDaemonServerBatchStatWithDigestInclusionMode(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+	s := cd_vfs.NewClientDaemonServer(d, nil)
+
+	t.Run("MissingBatchStatRequest", func(t *testing.T) {
+		_, err := s.BatchStatWithDigestInclusionMode(ctx, &clientd.BatchStatWithOptionsRequest{
+			DigestInclusionMode: clientd.BatchStatWithOptionsRequest_ALWAYS,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "No batch stat request provided"), err)
+	})
+
+	t.Run("UnknownDigestInclusionMode", func(t *testing.T) {
+		_, err := s.BatchStatWithDigestInclusionMode(ctx, &clientd.BatchStatWithOptionsRequest{
+			BatchStat: &remoteoutputservice.BatchStatRequest{
+				BuildId: "b7b4a1b5-3b2d-4c0f-9a0e-7bbf3e1f0e5f",
+				Paths:   []string{"foo"},
+			},
+			DigestInclusionMode: 42,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Unknown digest inclusion mode 42"), err)
+	})
+}
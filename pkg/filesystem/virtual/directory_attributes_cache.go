@@ -0,0 +1,51 @@
+package virtual
+
+import (
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+)
+
+// directoryAttributesCache memoizes the last data modification times
+// of directories in an output path, as reported by BatchStat(). The
+// cache is invalidated in its entirety every time the output path is
+// modified through RemoteOutputServiceDirectory.
+type directoryAttributesCache struct {
+	lock                      sync.Mutex
+	generation                uint64
+	lastDataModificationTimes map[virtual.PrepopulatedDirectory]time.Time
+}
+
+// lookup the last data modification time of a directory. If no cached
+// value is present, the current generation of the cache is returned,
+// which needs to be provided to insert().
+func (c *directoryAttributesCache) lookup(directory virtual.PrepopulatedDirectory) (time.Time, bool, uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	lastDataModificationTime, ok := c.lastDataModificationTimes[directory]
+	return lastDataModificationTime, ok, c.generation
+}
+
+// insert the last data modification time of a directory into the
+// cache. The value is discarded if the cache was invalidated after
+// lookup() was called, as the value may already be stale.
+func (c *directoryAttributesCache) insert(directory virtual.PrepopulatedDirectory, generation uint64, lastDataModificationTime time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.generation == generation {
+		if c.lastDataModificationTimes == nil {
+			c.lastDataModificationTimes = map[virtual.PrepopulatedDirectory]time.Time{}
+		}
+		c.lastDataModificationTimes[directory] = lastDataModificationTime
+	}
+}
+
+// invalidate all entries in the cache. This needs to be called after
+// the contents of the output path have been modified.
+func (c *directoryAttributesCache) invalidate() {
+	c.lock.Lock()
+	c.generation++
+	c.lastDataModificationTimes = nil
+	c.lock.Unlock()
+}
@@ -0,0 +1,94 @@
+package virtual
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// fileDigestCacheKey identifies a version of a file for which a digest
+// has been computed. The change ID of a file is incremented every time
+// its contents are modified, meaning that rewriting a file causes
+// lookups against previously computed digests to fail.
+type fileDigestCacheKey struct {
+	leaf           virtual.NativeLeaf
+	changeID       uint64
+	digestFunction digest.Function
+}
+
+type fileDigestCacheEntry struct {
+	key        fileDigestCacheKey
+	fileDigest digest.Digest
+}
+
+// fileDigestCache memoizes the digests of files that are not backed by
+// the Content Addressable Storage, so that BatchStat() does not need to
+// recompute them when the same file is requested repeatedly, possibly
+// across builds. It is bounded in size, evicting the least recently
+// used entries first.
+type fileDigestCache struct {
+	maximumSize int
+
+	lock    sync.Mutex
+	entries map[fileDigestCacheKey]*list.Element
+	lru     list.List
+	hits    uint64
+	misses  uint64
+}
+
+func newFileDigestCache(maximumSize int) *fileDigestCache {
+	return &fileDigestCache{
+		maximumSize: maximumSize,
+		entries:     map[fileDigestCacheKey]*list.Element{},
+	}
+}
+
+// lookup the digest of a version of a file.
+func (c *fileDigestCache) lookup(key fileDigestCacheKey) (digest.Digest, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return digest.BadDigest, false
+	}
+	c.hits++
+	c.lru.MoveToFront(element)
+	return element.Value.(*fileDigestCacheEntry).fileDigest, true
+}
+
+// insert the digest of a version of a file into the cache, evicting
+// the least recently used entry if the cache is full.
+func (c *fileDigestCache) insert(key fileDigestCacheKey, fileDigest digest.Digest) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*fileDigestCacheEntry).fileDigest = fileDigest
+		c.lru.MoveToFront(element)
+		return
+	}
+	for len(c.entries) >= c.maximumSize {
+		oldest := c.lru.Back()
+		delete(c.entries, oldest.Value.(*fileDigestCacheEntry).key)
+		c.lru.Remove(oldest)
+	}
+	c.entries[key] = c.lru.PushFront(&fileDigestCacheEntry{
+		key:        key,
+		fileDigest: fileDigest,
+	})
+}
+
+// fileDigestCacheBytesPerEntry is the estimated amount of memory used
+// by a single entry in the file digest cache, including the list
+// element and the map entry pointing to it.
+const fileDigestCacheBytesPerEntry = 256
+
+// getStatistics returns the number of entries in the cache, and the
+// number of lookups that hit and missed the cache.
+func (c *fileDigestCache) getStatistics() (entriesCount int, hits, misses uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries), c.hits, c.misses
+}
@@ -0,0 +1,110 @@
+package virtual
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+// idempotencyTokenSet keeps track of the idempotency tokens of
+// BatchCreate requests that were processed as part of a single build,
+// so that requests retried by clients are not executed twice. The
+// number of tokens is bounded, and tokens expire after some time.
+type idempotencyTokenSet struct {
+	lock    sync.Mutex
+	entries map[string]*idempotencyTokenEntry
+	// Entries in the order in which they were inserted.
+	queue list.List
+}
+
+// idempotencyTokenEntry contains the outcome of a single request
+// having an idempotency token.
+type idempotencyTokenEntry struct {
+	token   string
+	element *list.Element
+	done    chan struct{}
+
+	// Fields that are set when the request completes.
+	err            error
+	expirationTime time.Time
+}
+
+// lookupOrInsert returns the entry of an idempotency token. If no entry
+// exists, or the existing entry has expired, a new entry is created.
+// In that case true is returned, meaning the caller is responsible for
+// processing the request and calling finish().
+func (s *idempotencyTokenSet) lookupOrInsert(token string, now time.Time, maximumCount int) (*idempotencyTokenEntry, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if entry, ok := s.entries[token]; ok {
+		if !entry.isExpired(now) {
+			return entry, false
+		}
+		s.remove(entry)
+	}
+
+	// Discard the oldest entries to make space.
+	for len(s.entries) >= maximumCount {
+		s.remove(s.queue.Front().Value.(*idempotencyTokenEntry))
+	}
+
+	if s.entries == nil {
+		s.entries = map[string]*idempotencyTokenEntry{}
+	}
+	entry := &idempotencyTokenEntry{
+		token: token,
+		done:  make(chan struct{}),
+	}
+	entry.element = s.queue.PushBack(entry)
+	s.entries[token] = entry
+	return entry, true
+}
+
+// finish processing a request having an idempotency token. Successful
+// results are retained until the expiration time. Failures are not
+// retained, so that the request is executed again when retried.
+func (s *idempotencyTokenSet) finish(entry *idempotencyTokenEntry, err error, expirationTime time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entry.err = err
+	entry.expirationTime = expirationTime
+	close(entry.done)
+	if err != nil && s.entries[entry.token] == entry {
+		s.remove(entry)
+	}
+}
+
+func (s *idempotencyTokenSet) remove(entry *idempotencyTokenEntry) {
+	if s.entries[entry.token] == entry {
+		delete(s.entries, entry.token)
+		s.queue.Remove(entry.element)
+	}
+}
+
+// isExpired returns whether an entry may be discarded, as the request
+// completed more than the expiration duration ago. This function must
+// be called with the lock held.
+func (e *idempotencyTokenEntry) isExpired(now time.Time) bool {
+	select {
+	case <-e.done:
+		return !now.Before(e.expirationTime)
+	default:
+		return false
+	}
+}
+
+// wait for the request corresponding to an entry to complete, and
+// return its result.
+func (e *idempotencyTokenEntry) wait(ctx context.Context) error {
+	select {
+	case <-e.done:
+		return e.err
+	case <-ctx.Done():
+		return util.StatusFromContext(ctx)
+	}
+}
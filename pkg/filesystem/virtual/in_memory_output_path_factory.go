@@ -2,6 +2,7 @@ package virtual
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
@@ -9,6 +10,9 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type inMemoryOutputPathFactory struct {
@@ -32,7 +36,7 @@ func NewInMemoryOutputPathFactory(filePool filesystem.FilePool, symlinkFactory v
 }
 
 func (opf *inMemoryOutputPathFactory) StartInitialBuild(outputBaseID path.Component, casFileFactory virtual.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) OutputPath {
-	return inMemoryOutputPath{
+	return &inMemoryOutputPath{
 		PrepopulatedDirectory: virtual.NewInMemoryPrepopulatedDirectory(
 			virtual.NewHandleAllocatingFileAllocator(
 				virtual.NewPoolBackedFileAllocator(
@@ -55,6 +59,53 @@ func (opf *inMemoryOutputPathFactory) Clean(outputBaseID path.Component) error {
 
 type inMemoryOutputPath struct {
 	virtual.PrepopulatedDirectory
+	frozen atomic.Bool
+}
+
+func (op *inMemoryOutputPath) FinalizeBuild(ctx context.Context, digestFunction digest.Function) {}
+
+func (op *inMemoryOutputPath) Checkpoint() error {
+	// No persistent state associated with in-memory output paths.
+	return nil
+}
+
+func (op *inMemoryOutputPath) Rename(outputBaseID path.Component) error {
+	// No persistent state associated with in-memory output paths.
+	return nil
+}
+
+func (op *inMemoryOutputPath) Freeze() {
+	op.frozen.Store(true)
+}
+
+func (op *inMemoryOutputPath) Unfreeze() {
+	op.frozen.Store(false)
 }
 
-func (op inMemoryOutputPath) FinalizeBuild(ctx context.Context, digestFunction digest.Function) {}
+func (op *inMemoryOutputPath) checkNotFrozen() error {
+	if op.frozen.Load() {
+		return status.Error(codes.FailedPrecondition, "Output path is frozen, as no build is running against it")
+	}
+	return nil
+}
+
+func (op *inMemoryOutputPath) CreateChildren(children map[path.Component]virtual.InitialNode, overwrite bool) error {
+	if err := op.checkNotFrozen(); err != nil {
+		return err
+	}
+	return op.PrepopulatedDirectory.CreateChildren(children, overwrite)
+}
+
+func (op *inMemoryOutputPath) CreateAndEnterPrepopulatedDirectory(name path.Component) (virtual.PrepopulatedDirectory, error) {
+	if err := op.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+	return op.PrepopulatedDirectory.CreateAndEnterPrepopulatedDirectory(name)
+}
+
+func (op *inMemoryOutputPath) RemoveAllChildren(forbidNewChildren bool) error {
+	if err := op.checkNotFrozen(); err != nil {
+		return err
+	}
+	return op.PrepopulatedDirectory.RemoveAllChildren(forbidNewChildren)
+}
@@ -12,8 +12,12 @@ import (
 	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestInMemoryOutputPathFactory(t *testing.T) {
@@ -49,4 +53,21 @@ func TestInMemoryOutputPathFactory(t *testing.T) {
 	lastDataModificationTime, ok := attributes.GetLastDataModificationTime()
 	require.True(t, ok)
 	require.Equal(t, time.Unix(1000, 0), lastDataModificationTime)
+
+	// While frozen, any attempts to modify the contents of the
+	// output path should be rejected.
+	outputPath.Freeze()
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.FailedPrecondition, "Output path is frozen, as no build is running against it"),
+		outputPath.CreateChildren(map[path.Component]re_vfs.InitialNode{}, false))
+	_, err := outputPath.CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory"))
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.FailedPrecondition, "Output path is frozen, as no build is running against it"),
+		err)
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.FailedPrecondition, "Output path is frozen, as no build is running against it"),
+		outputPath.RemoveAllChildren(false))
 }
@@ -0,0 +1,64 @@
+package virtual
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type inMemorySnapshotStore struct {
+	maximumSnapshotsCount int
+
+	lock      sync.Mutex
+	snapshots map[string]Snapshot
+	// Names of snapshots, in the order in which they were stored.
+	names []string
+}
+
+// NewInMemorySnapshotStore creates a SnapshotStore that keeps snapshots
+// in memory. If the number of snapshots exceeds the maximum provided,
+// the snapshots that were stored least recently are discarded. When
+// the maximum is zero, no snapshots are discarded.
+func NewInMemorySnapshotStore(maximumSnapshotsCount int) SnapshotStore {
+	return &inMemorySnapshotStore{
+		maximumSnapshotsCount: maximumSnapshotsCount,
+		snapshots:             map[string]Snapshot{},
+	}
+}
+
+func (ss *inMemorySnapshotStore) Put(ctx context.Context, name string, snapshot Snapshot) error {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	if _, ok := ss.snapshots[name]; ok {
+		// Overwriting a snapshot causes it to become the
+		// most recently stored one.
+		for i, existingName := range ss.names {
+			if existingName == name {
+				ss.names = append(ss.names[:i], ss.names[i+1:]...)
+				break
+			}
+		}
+	}
+	ss.snapshots[name] = snapshot
+	ss.names = append(ss.names, name)
+
+	for ss.maximumSnapshotsCount > 0 && len(ss.names) > ss.maximumSnapshotsCount {
+		delete(ss.snapshots, ss.names[0])
+		ss.names = ss.names[1:]
+	}
+	return nil
+}
+
+func (ss *inMemorySnapshotStore) Get(ctx context.Context, name string) (Snapshot, error) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	snapshot, ok := ss.snapshots[name]
+	if !ok {
+		return Snapshot{}, status.Errorf(codes.NotFound, "Snapshot %#v does not exist", name)
+	}
+	return snapshot, nil
+}
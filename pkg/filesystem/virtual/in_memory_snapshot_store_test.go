@@ -0,0 +1,83 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInMemorySnapshotStore(t *testing.T) {
+	ctx := context.Background()
+
+	snapshotStore := cd_vfs.NewInMemorySnapshotStore(2)
+	snapshot1 := cd_vfs.Snapshot{
+		OutputBaseID:    "9da951b8cb759233037166e28f7ea186",
+		BuildID:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		TreeDigest:      digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "7b5bb1e1a3e1e3ff9a8b2c5f5aa4b1e6d41b6a3c5d8e7f1029384756abcdef01", 200),
+		CreationTime:    time.Unix(1000, 0),
+		BuildSuccessful: true,
+	}
+	snapshot2 := cd_vfs.Snapshot{
+		OutputBaseID: "9da951b8cb759233037166e28f7ea186",
+		BuildID:      "bd4d4e08-a3f5-4d7c-94e8-2a4c6f0c5e9b",
+		TreeDigest:   digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 300),
+		CreationTime: time.Unix(1010, 0),
+	}
+	snapshot3 := cd_vfs.Snapshot{
+		OutputBaseID:    "9da951b8cb759233037166e28f7ea186",
+		BuildID:         "c3c9b4a0-1b0c-4f0e-9b43-6f3f1f8b7a21",
+		TreeDigest:      digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 400),
+		CreationTime:    time.Unix(1020, 0),
+		BuildSuccessful: true,
+	}
+
+	t.Run("NonexistentSnapshot", func(t *testing.T) {
+		_, err := snapshotStore.Get(ctx, "a")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Snapshot \"a\" does not exist"), err)
+	})
+
+	t.Run("PutAndGet", func(t *testing.T) {
+		require.NoError(t, snapshotStore.Put(ctx, "a", snapshot1))
+		snapshot, err := snapshotStore.Get(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, snapshot1, snapshot)
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		// Storing a snapshot under an existing name should
+		// replace it.
+		require.NoError(t, snapshotStore.Put(ctx, "a", snapshot2))
+		snapshot, err := snapshotStore.Get(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, snapshot2, snapshot)
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		// As the store has a capacity of two snapshots, storing
+		// a third should cause the least recently stored one to
+		// be discarded. Overwriting "a" makes it more recent
+		// than "b".
+		require.NoError(t, snapshotStore.Put(ctx, "b", snapshot1))
+		require.NoError(t, snapshotStore.Put(ctx, "a", snapshot2))
+		require.NoError(t, snapshotStore.Put(ctx, "c", snapshot3))
+
+		_, err := snapshotStore.Get(ctx, "b")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Snapshot \"b\" does not exist"), err)
+
+		snapshot, err := snapshotStore.Get(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, snapshot2, snapshot)
+		snapshot, err = snapshotStore.Get(ctx, "c")
+		require.NoError(t, err)
+		require.Equal(t, snapshot3, snapshot)
+	})
+}
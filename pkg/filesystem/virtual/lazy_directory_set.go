@@ -0,0 +1,47 @@
+package virtual
+
+import (
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+)
+
+// lazyDirectorySet keeps track of directories in an output path whose
+// contents are loaded from the Content Addressable Storage lazily, and
+// may thus not have been loaded yet. If TrackLazyDirectories is set, it
+// is used by BatchStatExistence() to prevent looking up children of
+// such directories, as that would cause their contents to be fetched.
+//
+// Directories are keyed by identity. Directories contained in the ones
+// tracked are not tracked explicitly, as they can only be reached by
+// traversing the ones tracked. Directories remain tracked after their
+// contents are loaded, or after they are removed from the output path,
+// until the set is cleared.
+type lazyDirectorySet struct {
+	lock        sync.Mutex
+	directories map[virtual.PrepopulatedDirectory]struct{}
+}
+
+func (s *lazyDirectorySet) add(directory virtual.PrepopulatedDirectory) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.directories == nil {
+		s.directories = map[virtual.PrepopulatedDirectory]struct{}{}
+	}
+	s.directories[directory] = struct{}{}
+}
+
+func (s *lazyDirectorySet) contains(directory virtual.PrepopulatedDirectory) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.directories[directory]
+	return ok
+}
+
+// clear the set. This may only be called after all contents of the
+// output path have been removed.
+func (s *lazyDirectorySet) clear() {
+	s.lock.Lock()
+	s.directories = nil
+	s.lock.Unlock()
+}
@@ -0,0 +1,168 @@
+package virtual
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// memoryBudgetBytesPerNode is the estimated amount of memory used by a
+// single file, directory or symbolic link stored in an output path.
+// It includes the directory entry, the leaf or directory object, and
+// its handle in the virtual file system.
+const memoryBudgetBytesPerNode = 256
+
+// memoryBudget keeps track of the estimated amount of memory used by
+// the contents of all output paths that were created through
+// BatchCreate(). It is shared by all output paths, so that the total
+// amount of memory used by bb_clientd can be bounded, regardless of
+// the number of output bases.
+//
+// Usage is only estimated, as the actual amount of memory used by
+// in-memory directories cannot be observed.
+type memoryBudget struct {
+	lock         sync.Mutex
+	usedBytes    int64
+	maximumBytes int64
+}
+
+func newMemoryBudget(maximumBytes int64) *memoryBudget {
+	return &memoryBudget{
+		maximumBytes: maximumBytes,
+	}
+}
+
+// acquire an estimated amount of memory. An error is returned if doing
+// so would cause the budget to be exceeded.
+func (b *memoryBudget) acquire(sizeBytes int64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.usedBytes+sizeBytes > b.maximumBytes {
+		return status.Errorf(codes.ResourceExhausted, "Output paths are estimated to use %d bytes of memory, meaning an additional %d bytes would exceed the permitted maximum of %d bytes", b.usedBytes, sizeBytes, b.maximumBytes)
+	}
+	b.usedBytes += sizeBytes
+	return nil
+}
+
+// release memory that was acquired previously.
+func (b *memoryBudget) release(sizeBytes int64) {
+	b.lock.Lock()
+	b.usedBytes -= sizeBytes
+	b.lock.Unlock()
+}
+
+// getUsedBytes returns the estimated amount of memory that is currently
+// acquired.
+func (b *memoryBudget) getUsedBytes() int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.usedBytes
+}
+
+// directoryMemoryCharge is the estimated amount of memory acquired from
+// a memoryBudget by a single directory created through BatchCreate().
+// It is released at most once.
+type directoryMemoryCharge struct {
+	budget    *memoryBudget
+	sizeBytes int64
+	released  atomic.Bool
+}
+
+func (c *directoryMemoryCharge) release() {
+	if !c.released.Swap(true) {
+		c.budget.release(c.sizeBytes)
+	}
+}
+
+// directoryMemoryChargeSet keeps track of the memory charges of
+// directories in an output path that were created through
+// BatchCreate(), so that they can be released once these directories
+// are removed or replaced.
+//
+// Unlike files and symbolic links, directories don't provide a way to
+// determine when they are removed. Charges are therefore keyed by the
+// path of the directory relative to the root of the output path, and
+// released when RemoteOutputServiceDirectory removes or replaces that
+// path. Charges of directories that are created or removed through
+// paths that are not in normalized form, or that are removed through
+// the virtual file system, are only released when the output path is
+// cleaned in its entirety.
+type directoryMemoryChargeSet struct {
+	lock      sync.Mutex
+	charges   map[string]*directoryMemoryCharge
+	untracked []*directoryMemoryCharge
+}
+
+// add the charge of a newly created directory to the set, releasing
+// the charges of any directories it replaced.
+func (s *directoryMemoryChargeSet) add(pathPrefix, p string, charge *directoryMemoryCharge) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key, ok := joinTrimmablePath(pathPrefix, p)
+	if !ok {
+		s.untracked = append(s.untracked, charge)
+		return
+	}
+	s.releaseLocked(key)
+	if s.charges == nil {
+		s.charges = map[string]*directoryMemoryCharge{}
+	}
+	s.charges[key] = charge
+}
+
+// release the charges of all directories at or below a given path, as
+// the path has been removed or replaced.
+func (s *directoryMemoryChargeSet) release(pathPrefix, p string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if key, ok := joinTrimmablePath(pathPrefix, p); ok {
+		s.releaseLocked(key)
+	}
+}
+
+func (s *directoryMemoryChargeSet) releaseLocked(key string) {
+	for directory, charge := range s.charges {
+		if key == "" || directory == key || strings.HasPrefix(directory, key+"/") {
+			charge.release()
+			delete(s.charges, directory)
+		}
+	}
+}
+
+// get the charge of the directory at a given path, if any.
+func (s *directoryMemoryChargeSet) get(p string) *directoryMemoryCharge {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.charges[p]
+}
+
+// releaseAll releases the charges of all directories in the set. This
+// needs to be called when the contents of the output path are removed.
+func (s *directoryMemoryChargeSet) releaseAll() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, charge := range s.charges {
+		charge.release()
+	}
+	for _, charge := range s.untracked {
+		charge.release()
+	}
+	s.charges = nil
+	s.untracked = nil
+}
+
+// memoryChargingInitialContentsFetcher is a decorator for
+// InitialContentsFetcher that holds the memory charge of the directory
+// that it populates. This permits filterMissingChildren() to release
+// the charge when removing a directory whose contents have not been
+// loaded yet, as the path of the directory is not known there.
+type memoryChargingInitialContentsFetcher struct {
+	virtual.InitialContentsFetcher
+	charge *directoryMemoryCharge
+}
@@ -0,0 +1,36 @@
+package virtual
+
+import (
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// missingBlobSet keeps track of the digests of blobs that were reported
+// as missing by FindMissingBlobs() while a build was running. It is
+// populated if FindMissing.RevalidationInterval is set, and its contents
+// are returned by GetMissingBlobs().
+type missingBlobSet struct {
+	lock    sync.Mutex
+	digests map[digest.Digest]struct{}
+}
+
+func (s *missingBlobSet) add(blobDigest digest.Digest) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.digests == nil {
+		s.digests = map[digest.Digest]struct{}{}
+	}
+	s.digests[blobDigest] = struct{}{}
+}
+
+// get the digests of all blobs that have been added to the set.
+func (s *missingBlobSet) get() digest.Set {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	set := digest.NewSetBuilder()
+	for blobDigest := range s.digests {
+		set.Add(blobDigest)
+	}
+	return set.Build()
+}
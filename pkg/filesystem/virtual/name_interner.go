@@ -0,0 +1,117 @@
+package virtual
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// nameInternerBytesPerEntry is the estimated amount of memory used by
+// a single interned name, excluding the name itself. It includes the
+// map entry and the string or slice header.
+const nameInternerBytesPerEntry = 64
+
+// nameInterner deduplicates the filenames and symbolic link targets
+// of nodes created in output paths. Builds tend to create the same
+// names (e.g., "_objs", "BUILD", "external") in many directories and
+// across many output paths. Without interning, each of these names is
+// backed by its own copy of the string, as they are parsed from
+// separate requests.
+//
+// Interning does not alter the values of names, meaning that equality
+// comparisons against interned names behave identically. It is bounded
+// in size. Once full, names that have not been interned before are
+// returned as is.
+type nameInterner struct {
+	maximumCount int
+
+	lock       sync.RWMutex
+	components map[path.Component]path.Component
+	targets    map[string][]byte
+	sizeBytes  int64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newNameInterner(maximumCount int) *nameInterner {
+	return &nameInterner{
+		maximumCount: maximumCount,
+		components:   map[path.Component]path.Component{},
+		targets:      map[string][]byte{},
+	}
+}
+
+// internComponent returns a copy of a filename that shares its storage
+// with previously interned filenames having the same value. It may be
+// called against a nil instance, in which case the filename is
+// returned as is.
+func (ni *nameInterner) internComponent(name path.Component) path.Component {
+	if ni == nil {
+		return name
+	}
+
+	ni.lock.RLock()
+	interned, ok := ni.components[name]
+	ni.lock.RUnlock()
+	if ok {
+		ni.hits.Add(1)
+		return interned
+	}
+
+	ni.lock.Lock()
+	defer ni.lock.Unlock()
+	if interned, ok := ni.components[name]; ok {
+		ni.hits.Add(1)
+		return interned
+	}
+	ni.misses.Add(1)
+	if len(ni.components)+len(ni.targets) >= ni.maximumCount {
+		return name
+	}
+	ni.components[name] = name
+	ni.sizeBytes += int64(len(name.String()))
+	return name
+}
+
+// internSymlinkTarget returns a symbolic link target that shares its
+// storage with previously interned targets having the same value.
+// Callers must not modify the returned slice. It may be called against
+// a nil instance, in which case the target is returned as is.
+func (ni *nameInterner) internSymlinkTarget(target []byte) []byte {
+	if ni == nil {
+		return target
+	}
+
+	ni.lock.RLock()
+	interned, ok := ni.targets[string(target)]
+	ni.lock.RUnlock()
+	if ok {
+		ni.hits.Add(1)
+		return interned
+	}
+
+	ni.lock.Lock()
+	defer ni.lock.Unlock()
+	if interned, ok := ni.targets[string(target)]; ok {
+		ni.hits.Add(1)
+		return interned
+	}
+	ni.misses.Add(1)
+	if len(ni.components)+len(ni.targets) >= ni.maximumCount {
+		return target
+	}
+	ni.targets[string(target)] = target
+	ni.sizeBytes += int64(len(target))
+	return target
+}
+
+// getStatistics returns the number of names that have been interned,
+// their total size in bytes, and the number of lookups that found or
+// did not find a previously interned name.
+func (ni *nameInterner) getStatistics() (count int, sizeBytes int64, hits, misses uint64) {
+	ni.lock.RLock()
+	defer ni.lock.RUnlock()
+	return len(ni.components) + len(ni.targets), ni.sizeBytes, ni.hits.Load(), ni.misses.Load()
+}
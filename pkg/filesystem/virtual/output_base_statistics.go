@@ -0,0 +1,108 @@
+package virtual
+
+import (
+	"sync"
+	"time"
+)
+
+// OutputBaseStatistics contains aggregate statistics about the
+// contents of the output path of an output base, as returned by
+// GetOutputBaseStatistics().
+//
+// The counters are maintained as files, directories and symbolic links
+// are created through BatchCreate(), and removed through BatchRemove()
+// or at the start of builds due to them no longer being present in the
+// Content Addressable Storage. The contents of output directories are
+// not accounted for, as these are loaded lazily. Modifications made
+// through the virtual file system are not accounted for either. The
+// counters should therefore be treated as an estimate.
+type OutputBaseStatistics struct {
+	FilesCount       int64
+	DirectoriesCount int64
+	SymlinksCount    int64
+
+	// The total size of all files in bytes.
+	SizeBytes int64
+
+	// The time at which the last build against the output base was
+	// started.
+	LastBuildStartTime time.Time
+}
+
+// outputPathContentCounters keeps track of the number of files,
+// directories and symbolic links contained in an output path. Counters
+// never become negative, as entries that are removed may not have been
+// accounted for when created.
+type outputPathContentCounters struct {
+	lock             sync.Mutex
+	filesCount       int64
+	directoriesCount int64
+	symlinksCount    int64
+	sizeBytes        int64
+}
+
+func decrementCounter(counter *int64, delta int64) {
+	if *counter > delta {
+		*counter -= delta
+	} else {
+		*counter = 0
+	}
+}
+
+func (c *outputPathContentCounters) addFile(sizeBytes int64) {
+	c.lock.Lock()
+	c.filesCount++
+	c.sizeBytes += sizeBytes
+	c.lock.Unlock()
+}
+
+func (c *outputPathContentCounters) removeFile(sizeBytes int64) {
+	c.lock.Lock()
+	decrementCounter(&c.filesCount, 1)
+	decrementCounter(&c.sizeBytes, sizeBytes)
+	c.lock.Unlock()
+}
+
+func (c *outputPathContentCounters) addDirectory() {
+	c.lock.Lock()
+	c.directoriesCount++
+	c.lock.Unlock()
+}
+
+func (c *outputPathContentCounters) removeDirectory() {
+	c.lock.Lock()
+	decrementCounter(&c.directoriesCount, 1)
+	c.lock.Unlock()
+}
+
+func (c *outputPathContentCounters) addSymlink() {
+	c.lock.Lock()
+	c.symlinksCount++
+	c.lock.Unlock()
+}
+
+func (c *outputPathContentCounters) removeSymlink() {
+	c.lock.Lock()
+	decrementCounter(&c.symlinksCount, 1)
+	c.lock.Unlock()
+}
+
+// reset the counters. This needs to be called when all contents of
+// the output path are replaced.
+func (c *outputPathContentCounters) reset(filesCount, directoriesCount, symlinksCount, sizeBytes int64) {
+	c.lock.Lock()
+	c.filesCount = filesCount
+	c.directoriesCount = directoriesCount
+	c.symlinksCount = symlinksCount
+	c.sizeBytes = sizeBytes
+	c.lock.Unlock()
+}
+
+func (c *outputPathContentCounters) get(statistics *OutputBaseStatistics) {
+	c.lock.Lock()
+	statistics.FilesCount = c.filesCount
+	statistics.DirectoriesCount = c.directoriesCount
+	statistics.SymlinksCount = c.symlinksCount
+	statistics.SizeBytes = c.sizeBytes
+	c.lock.Unlock()
+}
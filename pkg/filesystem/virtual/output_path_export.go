@@ -0,0 +1,109 @@
+package virtual
+
+import (
+	"context"
+	"os"
+	"syscall"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outputPathExporter contains the state that needs to be tracked by
+// ExportPath() while copying files out of an output path.
+type outputPathExporter struct {
+	context context.Context
+}
+
+func (e *outputPathExporter) exportDirectoryRecursive(source virtual.PrepopulatedDirectory, destination filesystem.Directory, dPath *path.Trace) error {
+	if e.context.Err() != nil {
+		return util.StatusFromContext(e.context)
+	}
+
+	directories, leaves, err := source.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+	for _, entry := range directories {
+		childPath := dPath.Append(entry.Name)
+		if err := destination.Mkdir(entry.Name, 0o777); err != nil {
+			return util.StatusWrapf(err, "Failed to create directory %#v", childPath.String())
+		}
+		childDestination, err := destination.EnterDirectory(entry.Name)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to enter directory %#v", childPath.String())
+		}
+		err = e.exportDirectoryRecursive(entry.Child, childDestination, childPath)
+		childDestination.Close()
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range leaves {
+		if err := e.exportLeaf(entry.Child, destination, entry.Name, dPath.Append(entry.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *outputPathExporter) exportLeaf(leaf virtual.NativeLeaf, destination filesystem.Directory, name path.Component, leafPath *path.Trace) error {
+	// Symbolic links are recreated as is, without following them.
+	// This means that symbolic links pointing to locations outside
+	// the output path are preserved.
+	target, err := leaf.Readlink()
+	if err == nil {
+		if err := destination.Symlink(target, name); err != nil {
+			return util.StatusWrapf(err, "Failed to create symbolic link %#v", leafPath.String())
+		}
+		return nil
+	} else if err != syscall.EINVAL {
+		return util.StatusWrapf(err, "Failed to read target of symbolic link %#v", leafPath.String())
+	}
+
+	// Copy the contents of regular files, preserving the
+	// executable bit. Any I/O errors that occur while reading are
+	// also reported through the output path's error logger, which
+	// provides more details than the status codes returned by the
+	// virtual file system.
+	var attributes virtual.Attributes
+	if s := leaf.VirtualOpenSelf(e.context, virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMaskPermissions, &attributes); s != virtual.StatusOK {
+		return status.Errorf(codes.Internal, "Failed to open file %#v", leafPath.String())
+	}
+	defer leaf.VirtualClose(1)
+
+	mode := os.FileMode(0o666)
+	if permissions, ok := attributes.GetPermissions(); ok && permissions&virtual.PermissionsExecute != 0 {
+		mode = 0o777
+	}
+	w, err := destination.OpenWrite(name, filesystem.CreateExcl(mode))
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to create file %#v", leafPath.String())
+	}
+
+	var buf [1 << 16]byte
+	for offset := uint64(0); ; {
+		n, eof, s := leaf.VirtualRead(buf[:], offset)
+		if s != virtual.StatusOK {
+			w.Close()
+			return status.Errorf(codes.Internal, "Failed to read file %#v at offset %d", leafPath.String(), offset)
+		}
+		if _, err := w.WriteAt(buf[:n], int64(offset)); err != nil {
+			w.Close()
+			return util.StatusWrapf(err, "Failed to write file %#v at offset %d", leafPath.String(), offset)
+		}
+		offset += uint64(n)
+		if eof {
+			break
+		}
+	}
+	if err := w.Close(); err != nil {
+		return util.StatusWrapf(err, "Failed to close file %#v", leafPath.String())
+	}
+	return nil
+}
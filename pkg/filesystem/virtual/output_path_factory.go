@@ -19,6 +19,27 @@ type OutputPath interface {
 	// Implementations of OutputPath may use this method to persist
 	// state.
 	FinalizeBuild(ctx context.Context, digestFunction digest.Function)
+
+	// Checkpoint() is called to persist the state of the output
+	// path on demand, as opposed to waiting for the current build
+	// to be finalized. Implementations of OutputPath that don't
+	// persist state may implement this as a no-op.
+	Checkpoint() error
+
+	// Rename() is called when the output path is moved to another
+	// output base ID. Implementations of OutputPath that persist
+	// state should ensure that this state is stored under the new
+	// output base ID afterwards.
+	Rename(outputBaseID path.Component) error
+
+	// Freeze() marks the output path as being immutable. Successive
+	// calls to CreateChildren(), CreateAndEnterPrepopulatedDirectory()
+	// and RemoveAllChildren() against the root directory fail with
+	// FAILED_PRECONDITION, until Unfreeze() is called. This prevents
+	// the contents of the output path from diverging from what was
+	// reported to the build client between builds.
+	Freeze()
+	Unfreeze()
 }
 
 // OutputPathFactory is an interface that is invoked by
@@ -0,0 +1,85 @@
+package virtual
+
+import (
+	"context"
+	"time"
+)
+
+// OutputPathRemovalReason indicates why an output path was removed,
+// as described by an OutputPathRemovalEvent.
+type OutputPathRemovalReason int
+
+const (
+	// OutputPathRemovalReasonClean indicates that the output path
+	// was removed, because Clean() was called against its output
+	// base.
+	OutputPathRemovalReasonClean OutputPathRemovalReason = iota
+	// OutputPathRemovalReasonCorrupted indicates that the output
+	// path was discarded by StartBuild(), because it was marked as
+	// being corrupted.
+	OutputPathRemovalReasonCorrupted
+)
+
+func (r OutputPathRemovalReason) String() string {
+	switch r {
+	case OutputPathRemovalReasonClean:
+		return "CLEAN"
+	case OutputPathRemovalReasonCorrupted:
+		return "CORRUPTED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// OutputPathRemovalEvent describes the removal of an output path, and
+// is reported through WatchOutputPathRemovals().
+type OutputPathRemovalEvent struct {
+	OutputBaseID   string
+	Reason         OutputPathRemovalReason
+	LastAccessTime time.Time
+}
+
+// OutputPathRemovalEventStream is the subset of a gRPC server streaming
+// server that is used by WatchOutputPathRemovals() to send events.
+type OutputPathRemovalEventStream interface {
+	Context() context.Context
+	Send(*OutputPathRemovalEvent) error
+}
+
+// outputPathRemovalSubscriberBufferSize is the maximum number of
+// events that may be queued for a single call to
+// WatchOutputPathRemovals(). Subscribers that fall further behind are
+// disconnected, so that they cannot cause memory usage to grow without
+// bounds.
+const outputPathRemovalSubscriberBufferSize = 100
+
+// outputPathRemovalSubscribers keeps track of all calls to
+// WatchOutputPathRemovals() that are in progress.
+type outputPathRemovalSubscribers map[chan OutputPathRemovalEvent]struct{}
+
+// subscribe returns a channel to which all events are written that
+// are published from this point onwards.
+func (s outputPathRemovalSubscribers) subscribe() chan OutputPathRemovalEvent {
+	events := make(chan OutputPathRemovalEvent, outputPathRemovalSubscriberBufferSize)
+	s[events] = struct{}{}
+	return events
+}
+
+// unsubscribe stops writing events to a channel. Calling this on a
+// channel that was already closed due to overflowing is a no-op.
+func (s outputPathRemovalSubscribers) unsubscribe(events chan OutputPathRemovalEvent) {
+	delete(s, events)
+}
+
+// publish an event to all subscribers. The channels of subscribers
+// whose buffer is full are closed, causing them to be disconnected.
+func (s outputPathRemovalSubscribers) publish(event OutputPathRemovalEvent) {
+	for events := range s {
+		select {
+		case events <- event:
+		default:
+			close(events)
+			delete(s, events)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package virtual
+
+import (
+	"context"
+	"sort"
+	"syscall"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/protobuf/proto"
+)
+
+// UploadOutputPathTree converts the contents of an output path to a
+// REv2 Tree message and stores it in the Content Addressable Storage
+// (CAS). Files in the output path that are only present locally are
+// uploaded as well, meaning that the resulting Tree can be used to
+// recreate the output path at a later point in time. The digest of the
+// Tree object is returned.
+//
+// The caller must ensure that the output path is not modified while
+// this function is running, as the resulting Tree object would
+// otherwise not reflect a consistent state.
+func UploadOutputPathTree(ctx context.Context, rootDirectory virtual.PrepopulatedDirectory, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, concurrency *semaphore.Weighted) (digest.Digest, error) {
+	batchedContentAddressableStorage, flusher := re_blobstore.NewBatchedStoreBlobAccess(
+		contentAddressableStorage,
+		digest.KeyWithoutInstance,
+		blobstore.RecommendedFindMissingDigestsCount,
+		concurrency)
+	tb := outputPathTreeBuilder{
+		context:                   ctx,
+		contentAddressableStorage: batchedContentAddressableStorage,
+		digestFunction:            digestFunction,
+		childDigests:              map[digest.Digest]struct{}{},
+	}
+	root, err := tb.createDirectoryRecursive(rootDirectory, nil)
+	if err != nil {
+		flusher(ctx)
+		return digest.BadDigest, err
+	}
+	if err := flusher(ctx); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to upload files")
+	}
+	tb.tree.Root = root
+
+	treeDigest, data, err := tb.getMessageDigest(&tb.tree)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal tree")
+	}
+	if err := contentAddressableStorage.Put(ctx, treeDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to upload tree")
+	}
+	return treeDigest, nil
+}
+
+// outputPathTreeBuilder contains the state that needs to be tracked by
+// UploadOutputPathTree() while traversing the output path.
+type outputPathTreeBuilder struct {
+	context                   context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+
+	tree         remoteexecution.Tree
+	childDigests map[digest.Digest]struct{}
+}
+
+func (tb *outputPathTreeBuilder) getMessageDigest(m proto.Message) (digest.Digest, []byte, error) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return digest.BadDigest, nil, err
+	}
+	generator := tb.digestFunction.NewGenerator(int64(len(data)))
+	if _, err := generator.Write(data); err != nil {
+		return digest.BadDigest, nil, err
+	}
+	return generator.Sum(), data, nil
+}
+
+func (tb *outputPathTreeBuilder) createDirectoryRecursive(d virtual.PrepopulatedDirectory, dPath *path.Trace) (*remoteexecution.Directory, error) {
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+
+	var directory remoteexecution.Directory
+	for _, entry := range directories {
+		childPath := dPath.Append(entry.Name)
+		child, err := tb.createDirectoryRecursive(entry.Child, childPath)
+		if err != nil {
+			return nil, err
+		}
+		childDigest, _, err := tb.getMessageDigest(child)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to marshal directory %#v", childPath.String())
+		}
+		if _, ok := tb.childDigests[childDigest]; !ok {
+			tb.childDigests[childDigest] = struct{}{}
+			tb.tree.Children = append(tb.tree.Children, child)
+		}
+		directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+			Name:   entry.Name.String(),
+			Digest: childDigest.GetProto(),
+		})
+	}
+
+	for _, entry := range leaves {
+		childPath := dPath.Append(entry.Name)
+		target, err := entry.Child.Readlink()
+		if err == nil {
+			directory.Symlinks = append(directory.Symlinks, &remoteexecution.SymlinkNode{
+				Name:   entry.Name.String(),
+				Target: target,
+			})
+			continue
+		} else if err != syscall.EINVAL {
+			return nil, util.StatusWrapf(err, "Failed to read target of symbolic link %#v", childPath.String())
+		}
+
+		childDigest, err := entry.Child.UploadFile(tb.context, tb.contentAddressableStorage, tb.digestFunction)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to upload file %#v", childPath.String())
+		}
+		var attributes virtual.Attributes
+		entry.Child.VirtualGetAttributes(tb.context, virtual.AttributesMaskPermissions, &attributes)
+		permissions, _ := attributes.GetPermissions()
+		directory.Files = append(directory.Files, &remoteexecution.FileNode{
+			Name:         entry.Name.String(),
+			Digest:       childDigest.GetProto(),
+			IsExecutable: permissions&virtual.PermissionsExecute != 0,
+		})
+	}
+
+	// The REv2 protocol requires that entries are sorted by name.
+	sort.Slice(directory.Directories, func(i, j int) bool {
+		return directory.Directories[i].Name < directory.Directories[j].Name
+	})
+	sort.Slice(directory.Files, func(i, j int) bool {
+		return directory.Files[i].Name < directory.Files[j].Name
+	})
+	sort.Slice(directory.Symlinks, func(i, j int) bool {
+		return directory.Symlinks[i].Name < directory.Symlinks[j].Name
+	})
+	return &directory, nil
+}
@@ -0,0 +1,161 @@
+package virtual_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUploadOutputPathTree(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	digestFunction := digest.MustNewFunction("example", remoteexecution.DigestFunction_SHA256)
+
+	t.Run("LookupAllChildrenFailure", func(t *testing.T) {
+		rootDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		rootDirectory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("subdirectory"), Child: subDirectory},
+			},
+			nil,
+			nil)
+		subDirectory.EXPECT().LookupAllChildren().Return(nil, nil, status.Error(codes.Internal, "I/O error"))
+		contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.EmptySet).Return(digest.EmptySet, nil).AnyTimes()
+
+		_, err := cd_vfs.UploadOutputPathTree(ctx, rootDirectory, contentAddressableStorage, digestFunction, semaphore.NewWeighted(1))
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to look up children of directory \"subdirectory\": I/O error"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Create an output path that contains a directory, a
+		// symbolic link, an executable that is stored remotely,
+		// and a file that is only present locally.
+		rootDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		executable := mock.NewMockNativeLeaf(ctrl)
+		rootDirectory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("subdirectory"), Child: subDirectory},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("symlink"), Child: symlink},
+				{Name: path.MustNewComponent("executable"), Child: executable},
+			},
+			nil)
+		localFile := mock.NewMockNativeLeaf(ctrl)
+		subDirectory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("local_file"), Child: localFile},
+			},
+			nil)
+
+		symlink.EXPECT().Readlink().Return("subdirectory/local_file", nil)
+
+		executableDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_SHA256, "888ddbf1e9cd5b201f67629d4efa9a4a0fb1cf5a910e9a44209eb07485f5f99d", 123)
+		executable.EXPECT().Readlink().Return("", syscall.EINVAL)
+		executable.EXPECT().UploadFile(gomock.Any(), gomock.Any(), digestFunction).Return(executableDigest, nil)
+		executable.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskPermissions, gomock.Any()).Do(
+			func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsExecute)
+			})
+
+		localFileDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_SHA256, "64ec88ca00b268e5ba1a35678a1b5316d212f4f366b2477232534a8aeca37f3c", 11)
+		localFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		localFile.EXPECT().UploadFile(gomock.Any(), gomock.Any(), digestFunction).DoAndReturn(
+			func(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function) (digest.Digest, error) {
+				require.NoError(t, contentAddressableStorage.Put(ctx, localFileDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello world"))))
+				return localFileDigest, nil
+			})
+		localFile.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskPermissions, gomock.Any()).Do(
+			func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsWrite)
+			})
+
+		// The local file should be uploaded, followed by the
+		// Tree object.
+		contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), localFileDigest.ToSingletonSet()).
+			Return(localFileDigest.ToSingletonSet(), nil)
+		contentAddressableStorage.EXPECT().Put(gomock.Any(), localFileDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(1000)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Hello world"), data)
+				return nil
+			})
+
+		subDirectoryContents := &remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name:   "local_file",
+					Digest: localFileDigest.GetProto(),
+				},
+			},
+		}
+		subDirectoryData, err := proto.Marshal(subDirectoryContents)
+		require.NoError(t, err)
+		subDirectoryGenerator := digestFunction.NewGenerator(int64(len(subDirectoryData)))
+		subDirectoryGenerator.Write(subDirectoryData)
+		expectedTree := &remoteexecution.Tree{
+			Root: &remoteexecution.Directory{
+				Files: []*remoteexecution.FileNode{
+					{
+						Name:         "executable",
+						Digest:       executableDigest.GetProto(),
+						IsExecutable: true,
+					},
+				},
+				Directories: []*remoteexecution.DirectoryNode{
+					{
+						Name:   "subdirectory",
+						Digest: subDirectoryGenerator.Sum().GetProto(),
+					},
+				},
+				Symlinks: []*remoteexecution.SymlinkNode{
+					{
+						Name:   "symlink",
+						Target: "subdirectory/local_file",
+					},
+				},
+			},
+			Children: []*remoteexecution.Directory{
+				subDirectoryContents,
+			},
+		}
+		var treeDigest digest.Digest
+		contentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(10000)
+				require.NoError(t, err)
+				var tree remoteexecution.Tree
+				require.NoError(t, proto.Unmarshal(data, &tree))
+				testutil.RequireEqualProto(t, expectedTree, &tree)
+				treeDigest = blobDigest
+				return nil
+			})
+
+		uploadedDigest, err := cd_vfs.UploadOutputPathTree(ctx, rootDirectory, contentAddressableStorage, digestFunction, semaphore.NewWeighted(1))
+		require.NoError(t, err)
+		require.Equal(t, treeDigest, uploadedDigest)
+	})
+}
@@ -0,0 +1,79 @@
+package virtual
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outputPathUsage keeps track of the number of files and their total
+// size in bytes that were created in an output path through
+// BatchCreate(). It is used to enforce per output path quotas.
+type outputPathUsage struct {
+	lock       sync.Mutex
+	filesCount int64
+	sizeBytes  int64
+}
+
+// acquire a single file of a given size. An error is returned if doing
+// so would cause the output path to exceed its quota. Limits that are
+// zero are not enforced.
+func (u *outputPathUsage) acquire(sizeBytes, maximumFilesCount, maximumSizeBytes int64) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if maximumFilesCount > 0 && u.filesCount >= maximumFilesCount {
+		return status.Errorf(codes.ResourceExhausted, "Output path already contains %d files, which is the permitted maximum", u.filesCount)
+	}
+	if maximumSizeBytes > 0 && u.sizeBytes+sizeBytes > maximumSizeBytes {
+		return status.Errorf(codes.ResourceExhausted, "Output path already contains %d bytes of files, meaning a file of %d bytes would exceed the permitted maximum of %d bytes", u.sizeBytes, sizeBytes, maximumSizeBytes)
+	}
+	u.filesCount++
+	u.sizeBytes += sizeBytes
+	return nil
+}
+
+// release a single file that was acquired previously.
+func (u *outputPathUsage) release(sizeBytes int64) {
+	u.lock.Lock()
+	u.filesCount--
+	u.sizeBytes -= sizeBytes
+	u.lock.Unlock()
+}
+
+// quotaEnforcingLeaf is a decorator for NativeLeaf that releases the
+// quota acquired by a file or symbolic link, once the last link to it
+// is removed.
+type quotaEnforcingLeaf struct {
+	virtual.NativeLeaf
+	release   func()
+	linkCount atomic.Int64
+}
+
+func newQuotaEnforcingLeaf(base virtual.NativeLeaf, release func()) virtual.NativeLeaf {
+	l := &quotaEnforcingLeaf{
+		NativeLeaf: base,
+		release:    release,
+	}
+	l.linkCount.Store(1)
+	return l
+}
+
+func (l *quotaEnforcingLeaf) Link() virtual.Status {
+	if s := l.NativeLeaf.Link(); s != virtual.StatusOK {
+		return s
+	}
+	l.linkCount.Add(1)
+	return virtual.StatusOK
+}
+
+func (l *quotaEnforcingLeaf) Unlink() {
+	l.NativeLeaf.Unlink()
+	if l.linkCount.Add(-1) == 0 {
+		l.release()
+	}
+}
@@ -171,6 +171,36 @@ func (op *persistentOutputPath) FinalizeBuild(ctx context.Context, digestFunctio
 	}
 }
 
+func (op *persistentOutputPath) Checkpoint() error {
+	if err := op.OutputPath.Checkpoint(); err != nil {
+		return err
+	}
+	if err := op.saveOutputPath(); err != nil {
+		return util.StatusWrapf(err, "Failed to save the contents of output path %#v", op.outputBaseID.String())
+	}
+	return nil
+}
+
+func (op *persistentOutputPath) Rename(outputBaseID path.Component) error {
+	if err := op.OutputPath.Rename(outputBaseID); err != nil {
+		return err
+	}
+
+	// Write the state file under the new output base ID, prior to
+	// removing the old one. This ensures that the contents of the
+	// output path are not lost if saving fails.
+	oldOutputBaseID := op.outputBaseID
+	op.outputBaseID = outputBaseID
+	if err := op.saveOutputPath(); err != nil {
+		op.outputBaseID = oldOutputBaseID
+		return util.StatusWrapf(err, "Failed to save the contents of output path %#v", outputBaseID.String())
+	}
+	if err := op.factory.store.Clean(oldOutputBaseID); err != nil {
+		return util.StatusWrapf(err, "Failed to remove persistent state for output path %#v", oldOutputBaseID.String())
+	}
+	return nil
+}
+
 func (op *persistentOutputPath) saveOutputPath() error {
 	writer, err := op.factory.store.Write(op.outputBaseID)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestPersistentOutputPathFactoryStartInitialBuild(t *testing.T) {
@@ -272,3 +273,162 @@ func TestPersistentOutputPathFactoryClean(t *testing.T) {
 		require.NoError(t, outputPathFactory.Clean(outputBaseID))
 	})
 }
+
+func TestPersistentOutputPathCheckpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	baseOutputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	store := mock.NewMockOutputPathPersistencyStore(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	globalErrorLogger := mock.NewMockErrorLogger(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	outputPathFactory := cd_vfs.NewPersistentOutputPathFactory(baseOutputPathFactory, store, clock, globalErrorLogger, symlinkFactory)
+	digestFunction := digest.MustNewFunction("default", remoteexecution.DigestFunction_SHA256)
+	outputBaseID := path.MustNewComponent("1603ee70687380f12cc8e7417a83f581")
+
+	baseOutputPath := mock.NewMockOutputPath(ctrl)
+	casFileFactory := mock.NewMockCASFileFactory(ctrl)
+	fileErrorLogger := mock.NewMockErrorLogger(ctrl)
+	baseOutputPathFactory.EXPECT().StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger).
+		Return(baseOutputPath)
+	store.EXPECT().Read(outputBaseID).Return(nil, nil, status.Error(codes.NotFound, "No data found"))
+	globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.NotFound, "Failed to open state file for output path \"1603ee70687380f12cc8e7417a83f581\": No data found")))
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+
+	outputPath := outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger)
+
+	t.Run("BaseFailure", func(t *testing.T) {
+		baseOutputPath.EXPECT().Checkpoint().Return(status.Error(codes.Internal, "Disk failure"))
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Disk failure"), outputPath.Checkpoint())
+	})
+
+	t.Run("WriteFailure", func(t *testing.T) {
+		baseOutputPath.EXPECT().Checkpoint()
+		store.EXPECT().Write(outputBaseID).Return(nil, status.Error(codes.Internal, "Failed to create state file"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to save the contents of output path \"1603ee70687380f12cc8e7417a83f581\": Failed to create state file"),
+			outputPath.Checkpoint())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The contents of the output path should be written to
+		// the state file, even though no build was finalized.
+		baseOutputPath.EXPECT().Checkpoint()
+		writer := mock.NewMockOutputPathPersistencyWriteCloser(ctrl)
+		store.EXPECT().Write(outputBaseID).Return(writer, nil)
+		file1 := mock.NewMockNativeLeaf(ctrl)
+		baseOutputPath.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file1"), Child: file1},
+			},
+			nil)
+		file1Node := &remoteexecution.FileNode{
+			Name: "file1",
+			Digest: &remoteexecution.Digest{
+				Hash:      "f132632084ca4e2124fbc88223901e3976e126ebb8f8cc5a09116a0191369d9b",
+				SizeBytes: 34,
+			},
+		}
+		file1.EXPECT().AppendOutputPathPersistencyDirectoryNode(gomock.Any(), path.MustNewComponent("file1")).
+			Do(func(directory *outputpathpersistency.Directory, name path.Component) {
+				directory.Files = append(directory.Files, file1Node)
+			})
+		var savedRootDirectory *outputpathpersistency.RootDirectory
+		writer.EXPECT().Finalize(testutil.EqProto(t, &outputpathpersistency.RootDirectory{
+			InitialCreationTime: &timestamppb.Timestamp{Seconds: 1000},
+			Contents: &outputpathpersistency.Directory{
+				Files: []*remoteexecution.FileNode{file1Node},
+			},
+		})).DoAndReturn(func(rootDirectory *outputpathpersistency.RootDirectory) error {
+			savedRootDirectory = rootDirectory
+			return nil
+		})
+
+		require.NoError(t, outputPath.Checkpoint())
+
+		// Creating the output path once again, as would happen
+		// after a restart, should cause the contents of the
+		// output path to be restored.
+		newBaseOutputPath := mock.NewMockOutputPath(ctrl)
+		baseOutputPathFactory.EXPECT().StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger).
+			Return(newBaseOutputPath)
+		reader := mock.NewMockOutputPathPersistencyReadCloser(ctrl)
+		store.EXPECT().Read(outputBaseID).Return(reader, savedRootDirectory, nil)
+		restoredFile1 := mock.NewMockNativeLeaf(ctrl)
+		casFileFactory.EXPECT().LookupFile(digest.MustNewDigest("default", remoteexecution.DigestFunction_SHA256, "f132632084ca4e2124fbc88223901e3976e126ebb8f8cc5a09116a0191369d9b", 34), false).Return(restoredFile1)
+		newBaseOutputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("file1"): re_vfs.InitialNode{}.FromLeaf(restoredFile1),
+		}, true)
+		reader.EXPECT().Close()
+
+		require.NotNil(t, outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger))
+	})
+}
+
+func TestPersistentOutputPathRename(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	baseOutputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	store := mock.NewMockOutputPathPersistencyStore(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	globalErrorLogger := mock.NewMockErrorLogger(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	outputPathFactory := cd_vfs.NewPersistentOutputPathFactory(baseOutputPathFactory, store, clock, globalErrorLogger, symlinkFactory)
+	digestFunction := digest.MustNewFunction("default", remoteexecution.DigestFunction_SHA256)
+	oldOutputBaseID := path.MustNewComponent("1603ee70687380f12cc8e7417a83f581")
+	newOutputBaseID := path.MustNewComponent("8a5e0ba4d3b1a46f8e7cf5e4b3b0ff2c")
+
+	baseOutputPath := mock.NewMockOutputPath(ctrl)
+	casFileFactory := mock.NewMockCASFileFactory(ctrl)
+	fileErrorLogger := mock.NewMockErrorLogger(ctrl)
+	baseOutputPathFactory.EXPECT().StartInitialBuild(oldOutputBaseID, casFileFactory, digestFunction, fileErrorLogger).
+		Return(baseOutputPath)
+	store.EXPECT().Read(oldOutputBaseID).Return(nil, nil, status.Error(codes.NotFound, "No data found"))
+	globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.NotFound, "Failed to open state file for output path \"1603ee70687380f12cc8e7417a83f581\": No data found")))
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+
+	outputPath := outputPathFactory.StartInitialBuild(oldOutputBaseID, casFileFactory, digestFunction, fileErrorLogger)
+
+	t.Run("WriteFailure", func(t *testing.T) {
+		// If the state file cannot be written under the new
+		// output base ID, the old state file should be left
+		// intact.
+		baseOutputPath.EXPECT().Rename(newOutputBaseID)
+		store.EXPECT().Write(newOutputBaseID).Return(nil, status.Error(codes.Internal, "Failed to create state file"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to save the contents of output path \"8a5e0ba4d3b1a46f8e7cf5e4b3b0ff2c\": Failed to create state file"),
+			outputPath.Rename(newOutputBaseID))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The state file should be written under the new output
+		// base ID, followed by removing the old state file.
+		baseOutputPath.EXPECT().Rename(newOutputBaseID)
+		writer := mock.NewMockOutputPathPersistencyWriteCloser(ctrl)
+		store.EXPECT().Write(newOutputBaseID).Return(writer, nil)
+		baseOutputPath.EXPECT().LookupAllChildren().Return(nil, nil, nil)
+		writer.EXPECT().Finalize(testutil.EqProto(t, &outputpathpersistency.RootDirectory{
+			InitialCreationTime: &timestamppb.Timestamp{Seconds: 1000},
+			Contents:            &outputpathpersistency.Directory{},
+		}))
+		store.EXPECT().Clean(oldOutputBaseID)
+
+		require.NoError(t, outputPath.Rename(newOutputBaseID))
+
+		// Successive checkpoints should write to the state file
+		// associated with the new output base ID.
+		baseOutputPath.EXPECT().Checkpoint()
+		store.EXPECT().Write(newOutputBaseID).Return(nil, status.Error(codes.Internal, "Failed to create state file"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to save the contents of output path \"8a5e0ba4d3b1a46f8e7cf5e4b3b0ff2c\": Failed to create state file"),
+			outputPath.Checkpoint())
+	})
+}
@@ -0,0 +1,113 @@
+package virtual
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// remoteDataLossReason is the reason of the ErrorInfo detail that is
+// attached to DATA_LOSS errors caused by the Content Addressable
+// Storage, as opposed to the local storage backing output paths.
+const remoteDataLossReason = "REMOTE_DATA_LOSS"
+
+// markRemoteDataLoss attaches an ErrorInfo detail to a DATA_LOSS error
+// caused by the Content Addressable Storage. Such errors don't indicate
+// that the contents of an output path are corrupted, meaning that
+// detectCorruption() should ignore them. The detail also permits
+// clients to distinguish between both kinds of errors. Other errors are
+// returned as is.
+func markRemoteDataLoss(err error) error {
+	if status.Code(err) != codes.DataLoss || isRemoteDataLoss(err) {
+		return err
+	}
+	s, detailsErr := status.Convert(err).WithDetails(&errdetails.ErrorInfo{
+		Reason: remoteDataLossReason,
+		Domain: "github.com/buildbarn/bb-clientd",
+	})
+	if detailsErr != nil {
+		return err
+	}
+	return s.Err()
+}
+
+// isRemoteDataLoss returns whether an error was marked as being caused
+// by the Content Addressable Storage by markRemoteDataLoss().
+func isRemoteDataLoss(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok && errorInfo.Domain == "github.com/buildbarn/bb-clientd" && errorInfo.Reason == remoteDataLossReason {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteDataLossMarkingBlobAccess is a decorator for BlobAccess that
+// marks all DATA_LOSS errors returned by the Content Addressable
+// Storage using markRemoteDataLoss().
+type remoteDataLossMarkingBlobAccess struct {
+	blobstore.BlobAccess
+}
+
+func (ba *remoteDataLossMarkingBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.Get(ctx, blobDigest),
+		remoteDataLossMarkingErrorHandler{})
+}
+
+func (ba *remoteDataLossMarkingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.GetFromComposite(ctx, parentDigest, childDigest, slicer),
+		remoteDataLossMarkingErrorHandler{})
+}
+
+func (ba *remoteDataLossMarkingBlobAccess) Put(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+	return markRemoteDataLoss(ba.BlobAccess.Put(ctx, blobDigest, b))
+}
+
+func (ba *remoteDataLossMarkingBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	missing, err := ba.BlobAccess.FindMissing(ctx, digests)
+	return missing, markRemoteDataLoss(err)
+}
+
+// remoteDataLossMarkingErrorHandler is an ErrorHandler that is used by
+// remoteDataLossMarkingBlobAccess to mark errors that occur while
+// buffers are consumed, including checksum mismatches.
+type remoteDataLossMarkingErrorHandler struct{}
+
+func (remoteDataLossMarkingErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	return nil, markRemoteDataLoss(err)
+}
+
+func (remoteDataLossMarkingErrorHandler) Done() {}
+
+// remoteDataLossMarkingDirectoryFetcher is a decorator for
+// DirectoryFetcher that marks all DATA_LOSS errors using
+// markRemoteDataLoss().
+type remoteDataLossMarkingDirectoryFetcher struct {
+	cas.DirectoryFetcher
+}
+
+func (df *remoteDataLossMarkingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	directory, err := df.DirectoryFetcher.GetDirectory(ctx, directoryDigest)
+	return directory, markRemoteDataLoss(err)
+}
+
+func (df *remoteDataLossMarkingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	directory, err := df.DirectoryFetcher.GetTreeRootDirectory(ctx, treeDigest)
+	return directory, markRemoteDataLoss(err)
+}
+
+func (df *remoteDataLossMarkingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	directory, err := df.DirectoryFetcher.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+	return directory, markRemoteDataLoss(err)
+}
@@ -1,41 +1,401 @@
 package virtual
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	cd_blobstore "github.com/buildbarn/bb-clientd/pkg/blobstore"
 	cd_cas "github.com/buildbarn/bb-clientd/pkg/cas"
 	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// remoteOutputServiceDirectoryMetrics contains the Prometheus metrics
+// that are exposed by a single RemoteOutputServiceDirectory. Metrics
+// are labeled by output base ID. Label values are deleted when output
+// paths are removed, so that the number of series remains bounded by
+// the number of output paths.
+type remoteOutputServiceDirectoryMetrics struct {
+	findMissingDigestsQueued        *prometheus.CounterVec
+	findMissingBatchesFlushed       *prometheus.CounterVec
+	findMissingChildrenRemoved      *prometheus.CounterVec
+	findMissingBatchDurationSeconds *prometheus.HistogramVec
+}
+
+func newRemoteOutputServiceDirectoryMetrics(registerer prometheus.Registerer) *remoteOutputServiceDirectoryMetrics {
+	m := &remoteOutputServiceDirectoryMetrics{
+		findMissingDigestsQueued: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "buildbarn",
+				Subsystem: "clientd",
+				Name:      "remote_output_service_directory_find_missing_digests_queued_total",
+				Help:      "Number of digests of files and directories in output paths that were queued for FindMissingBlobs() at the start of builds.",
+			},
+			[]string{"output_base_id"}),
+		findMissingBatchesFlushed: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "buildbarn",
+				Subsystem: "clientd",
+				Name:      "remote_output_service_directory_find_missing_batches_flushed_total",
+				Help:      "Number of FindMissingBlobs() calls performed at the start of builds.",
+			},
+			[]string{"output_base_id"}),
+		findMissingChildrenRemoved: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "buildbarn",
+				Subsystem: "clientd",
+				Name:      "remote_output_service_directory_find_missing_children_removed_total",
+				Help:      "Number of files and directories removed from output paths at the start of builds, because they were absent from the Content Addressable Storage, or used a different instance name or digest function.",
+			},
+			[]string{"output_base_id"}),
+		findMissingBatchDurationSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "buildbarn",
+				Subsystem: "clientd",
+				Name:      "remote_output_service_directory_find_missing_batch_duration_seconds",
+				Help:      "Amount of time spent calling FindMissingBlobs() and removing missing files and directories from output paths, in seconds.",
+				Buckets:   util.DecimalExponentialBuckets(-3, 6, 2),
+			},
+			[]string{"output_base_id"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(
+			m.findMissingDigestsQueued,
+			m.findMissingBatchesFlushed,
+			m.findMissingChildrenRemoved,
+			m.findMissingBatchDurationSeconds)
+	}
+	return m
+}
+
+// deleteOutputBase removes all series associated with an output base.
+// This is called when its output path is removed.
+func (m *remoteOutputServiceDirectoryMetrics) deleteOutputBase(outputBaseID path.Component) {
+	outputBaseIDLabel := outputBaseID.String()
+	m.findMissingDigestsQueued.DeleteLabelValues(outputBaseIDLabel)
+	m.findMissingBatchesFlushed.DeleteLabelValues(outputBaseIDLabel)
+	m.findMissingChildrenRemoved.DeleteLabelValues(outputBaseIDLabel)
+	m.findMissingBatchDurationSeconds.DeleteLabelValues(outputBaseIDLabel)
+}
+
+// findMissingMetrics contains the Prometheus metrics that are updated
+// by filterMissingChildren() for a single output base.
+type findMissingMetrics struct {
+	digestsQueued        prometheus.Counter
+	batchesFlushed       prometheus.Counter
+	childrenRemoved      prometheus.Counter
+	batchDurationSeconds prometheus.Observer
+}
+
+func (m *remoteOutputServiceDirectoryMetrics) newFindMissingMetrics(outputBaseID path.Component) *findMissingMetrics {
+	outputBaseIDLabel := outputBaseID.String()
+	return &findMissingMetrics{
+		digestsQueued:        m.findMissingDigestsQueued.WithLabelValues(outputBaseIDLabel),
+		batchesFlushed:       m.findMissingBatchesFlushed.WithLabelValues(outputBaseIDLabel),
+		childrenRemoved:      m.findMissingChildrenRemoved.WithLabelValues(outputBaseIDLabel),
+		batchDurationSeconds: m.findMissingBatchDurationSeconds.WithLabelValues(outputBaseIDLabel),
+	}
+}
+
 type buildState struct {
-	id                 string
-	digestFunction     digest.Function
-	scopeWalkerFactory *path.VirtualRootScopeWalkerFactory
+	id                    string
+	digestFunction        digest.Function
+	scopeWalkerFactory    *path.VirtualRootScopeWalkerFactory
+	outputPathPrefix      string
+	outputBasePath        string
+	initialContentsDigest digest.Digest
+	preparation           *buildPreparation
+	events                *buildEventLog
+
+	// Digests of blobs that were found to be missing from the
+	// Content Addressable Storage while the build was running.
+	missingBlobs missingBlobSet
+
+	// Children that were removed from the output path at the
+	// start of the build.
+	removedChildren removedChildList
+
+	// Idempotency tokens of BatchCreate requests that were
+	// processed as part of the build.
+	idempotencyTokens idempotencyTokenSet
+
+	// Paths of files that BatchCreate() replaced by files having
+	// the same digest, but a different executable bit.
+	executableBitChangesLock sync.Mutex
+	executableBitChanges     []string
+
+	// Statistics of the output path at the start of the build,
+	// used to compute the statistics of the build itself.
+	initialStatistics BuildStatistics
+}
+
+func (bs *buildState) addExecutableBitChange(filePath string) {
+	bs.executableBitChangesLock.Lock()
+	bs.executableBitChanges = append(bs.executableBitChanges, filePath)
+	bs.executableBitChangesLock.Unlock()
+}
+
+// buildPreparation keeps track of the work StartBuild() performs to
+// prepare the output path for a build, such as removing files that are
+// no longer present in the Content Addressable Storage. This work may
+// be performed asynchronously, in which case BatchCreate() and
+// BatchStat() need to wait for it to complete.
+type buildPreparation struct {
+	done chan struct{}
+	err  error
+
+	childrenScanned atomic.Uint64
+	childrenRemoved atomic.Uint64
+
+	// Log of the build to which removals of children are reported,
+	// so that they can be observed through WatchBuild().
+	events *buildEventLog
+
+	// If set, digests of blobs that are missing are recorded in
+	// this set. This is done by passes that revalidate the
+	// contents of the output path while the build is running.
+	missingBlobs *missingBlobSet
+
+	// If set, children that are removed are recorded in this list.
+	// This is done at the start of the build.
+	removedChildren *removedChildList
+
+	// If set, children are left in place when blobs on which they
+	// depend are missing.
+	retainMissingChildren bool
+}
+
+func newBuildPreparation(events *buildEventLog) *buildPreparation {
+	return &buildPreparation{
+		done:   make(chan struct{}),
+		events: events,
+	}
+}
+
+// childRemoved reports that a child was removed from the output path,
+// because the blob with a given digest was missing.
+func (p *buildPreparation) childRemoved(blobDigest digest.Digest) {
+	p.childrenRemoved.Add(1)
+	p.events.append(BuildEvent{
+		Type:   BuildEventChildRemoved,
+		Digest: blobDigest,
+	})
+	if p.removedChildren != nil {
+		p.removedChildren.add(blobDigest)
+	}
+}
+
+func (p *buildPreparation) finish(err error) {
+	p.err = err
+	close(p.done)
+}
+
+// failed returns whether preparation of the output path has completed
+// with an error. It does not block.
+func (p *buildPreparation) failed() bool {
+	select {
+	case <-p.done:
+		return p.err != nil
+	default:
+		return false
+	}
+}
+
+// wait for preparation of the output path to complete, returning any
+// error that occurred while doing so.
+func (p *buildPreparation) wait(ctx context.Context) error {
+	select {
+	case <-p.done:
+		if p.err != nil {
+			return util.StatusWrap(p.err, "Failed to prepare the output path at the start of the build")
+		}
+		return nil
+	case <-ctx.Done():
+		return util.StatusFromContext(ctx)
+	}
+}
+
+// outputPathWarmup keeps track of the work PrepareOutputBase()
+// performs to prepare an output path ahead of a build. If a build is
+// started using the same digest function before the warmup expires,
+// StartBuild() may skip filtering the contents of the output path.
+type outputPathWarmup struct {
+	digestFunction digest.Function
+	preparation    *buildPreparation
+
+	// The time at which the results of the warmup are no longer
+	// considered valid. This field is set before preparation
+	// completes, meaning it may be read after waiting for it.
+	expirationTime time.Time
+}
+
+// maximumCapturedErrorsCount is the maximum number of errors that are
+// retained per output path by capturingErrorLogger. This prevents
+// unbounded memory usage in case the Content Addressable Storage is
+// unavailable for a prolonged period of time.
+const maximumCapturedErrorsCount = 100
+
+// capturingErrorLogger is an implementation of ErrorLogger that is
+// attached to every output path. It captures errors that occur
+// asynchronously (e.g., failures to load files from the Content
+// Addressable Storage), so that they can be returned to the build
+// client through GetBuildErrors().
+type capturingErrorLogger struct {
+	base util.ErrorLogger
+
+	lock               sync.Mutex
+	errors             []error
+	droppedErrorsCount int
+}
+
+func (el *capturingErrorLogger) Log(err error) {
+	el.base.Log(err)
+
+	el.lock.Lock()
+	if len(el.errors) < maximumCapturedErrorsCount {
+		el.errors = append(el.errors, err)
+	} else {
+		el.droppedErrorsCount++
+	}
+	el.lock.Unlock()
+}
+
+// reset all errors captured so far. This is called when a new build is
+// started, so that errors are only reported to the build during which
+// they occurred.
+func (el *capturingErrorLogger) reset() {
+	el.lock.Lock()
+	el.errors = nil
+	el.droppedErrorsCount = 0
+	el.lock.Unlock()
+}
+
+// getErrors returns a copy of the errors captured so far.
+func (el *capturingErrorLogger) getErrors() []error {
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	errs := append([]error(nil), el.errors...)
+	if el.droppedErrorsCount > 0 {
+		errs = append(errs, status.Errorf(codes.ResourceExhausted, "%d more errors occurred, which were not captured", el.droppedErrorsCount))
+	}
+	return errs
 }
 
 type outputPathState struct {
-	buildState     *buildState
-	rootDirectory  OutputPath
-	casFileFactory virtual.CASFileFactory
+	buildState       *buildState
+	rootDirectory    OutputPath
+	exposedDirectory outputPathRootDirectory
+	casFileFactory   virtual.CASFileFactory
+	directoryFetcher re_cas.DirectoryFetcher
+	errorLogger      *capturingErrorLogger
+
+	// The amount of data loaded from the Content Addressable
+	// Storage through casFileFactory and directoryFetcher.
+	statistics outputPathStatistics
+
+	// The digest function used by the most recent build. Files
+	// and directories using other digest functions are removed
+	// from the output path at the start of every build.
+	digestFunction digest.Function
+
+	// Set when an operation against the root directory failed with
+	// DATA_LOSS. Operations against corrupted output paths are
+	// rejected until the output path is cleaned.
+	corrupted bool
+
+	// The number of files and their total size that were created
+	// through BatchCreate(), used to enforce quotas.
+	usage outputPathUsage
+
+	// Estimated number of files, directories and symbolic links in
+	// the output path, reported by GetOutputBaseStatistics().
+	contentCounters outputPathContentCounters
+
+	// The time at which the last build against the output path was
+	// started. This field is protected by the directory lock.
+	lastBuildStartTime time.Time
+
+	// Set if the output path was prepared through
+	// PrepareOutputBase(), and no build has been started since.
+	// This field is protected by the directory lock.
+	preparedWarmup *outputPathWarmup
+
+	// Directories created through BatchCreate() that may be
+	// trimmed by TrimBuild().
+	trimmableDirectories trimmableDirectorySet
+
+	// Directories whose contents are loaded from the Content
+	// Addressable Storage lazily, and may not have been loaded yet.
+	lazyDirectories lazyDirectorySet
+
+	// The estimated amount of memory acquired from the memory
+	// budget by directories created through BatchCreate(). It is
+	// released when these directories are removed or replaced, or
+	// when the output path is cleaned.
+	directoryMemory directoryMemoryChargeSet
+
+	// Last data modification times of directories reported by
+	// BatchStat(), if CacheDirectoryAttributes is set.
+	directoryAttributes directoryAttributesCache
+
+	// The number of times the output path was modified through
+	// RemoteOutputServiceDirectory. It is added to the change ID of
+	// the root directory, so that modifications made anywhere in
+	// the output path are reflected by the change ID reported by
+	// VirtualLookup() and VirtualReadDir().
+	changeID atomic.Uint64
+
+	// The last time a build was started against the output path,
+	// or the output path was accessed through the virtual file
+	// system. This field is protected by the directory lock.
+	lastAccessTime time.Time
+
+	// Lock that is held exclusively while a snapshot of the output
+	// path is created, effectively freezing its contents. Operations
+	// that modify the output path acquire it in shared mode.
+	contentsLock sync.RWMutex
 
 	// Circular linked list, used by VirtualReadDir(). By only
 	// inserting new output paths at the end and ensuring that
 	// cookies are monotonically increasing, we can reliably perform
 	// partial reads against this directory.
+	//
+	// VirtualReadDir() does not retain any references to list
+	// elements between calls. It only retains cookies, and always
+	// traverses the list from the start while holding the lock.
+	// Removing output paths from the list therefore cannot cause
+	// partial reads to skip or duplicate entries.
 	previous     *outputPathState
 	next         *outputPathState
 	cookie       uint64
@@ -56,8 +416,10 @@ type outputPathState struct {
 // simple:
 //
 //   - There is no persistency of build information across restarts.
-//   - No snapshotting of completed builds takes place, meaning that only
-//     the results of the latest build of a given output base are exposed.
+//   - Snapshots of completed builds are only retained if a
+//     SnapshotStore is configured. Only the results of the latest build
+//     of a given output base are exposed, unless RestoreSnapshot() is
+//     called explicitly.
 //   - Every output path is backed by an InMemoryPrepopulatedDirectory,
 //     meaning that memory usage may be high.
 //   - No automatic garbage collection of old output paths is performed.
@@ -67,20 +429,48 @@ type outputPathState struct {
 type RemoteOutputServiceDirectory struct {
 	virtual.ReadOnlyDirectory
 
-	handleAllocator                   virtual.StatefulHandleAllocator
-	handle                            virtual.StatefulDirectoryHandle
-	outputPathFactory                 OutputPathFactory
-	bareContentAddressableStorage     blobstore.BlobAccess
-	retryingContentAddressableStorage blobstore.BlobAccess
-	directoryFetcher                  re_cas.DirectoryFetcher
-	symlinkFactory                    virtual.SymlinkFactory
-	maximumTreeSizeBytes              int64
+	handleAllocator           virtual.StatefulHandleAllocator
+	handle                    virtual.StatefulDirectoryHandle
+	outputPathFactory         OutputPathFactory
+	contentAddressableStorage ContentAddressableStorageRoles
+	directoryFetcher          re_cas.DirectoryFetcher
+	symlinkFactory            virtual.SymlinkFactory
+	tracer                    trace.Tracer
+	configuration             RemoteOutputServiceDirectoryConfiguration
+	memoryBudget              *memoryBudget
+	fileDigestCache           *fileDigestCache
+	nameInterner              *nameInterner
+	clock                     clock.Clock
+	metrics                   *remoteOutputServiceDirectoryMetrics
+
+	// Limits the number of concurrent writes performed while
+	// creating snapshots that are requested explicitly, e.g.,
+	// through GetOutputPathTree(). Unlike the snapshots created at
+	// the start of every build, these are also created if
+	// Snapshots.UploadConcurrency is not set.
+	onDemandSnapshotUploadConcurrency *semaphore.Weighted
+
+	// Counters that are reported through GetRuntimeStatistics().
+	queuedStartBuildsCount    atomic.Int64
+	filteringOutputPathsCount atomic.Int64
+	casCallsInFlightCount     atomic.Int64
 
 	lock          sync.Mutex
 	changeID      uint64
 	outputBaseIDs map[path.Component]*outputPathState
 	buildIDs      map[string]*outputPathState
 	outputPaths   outputPathState
+	shuttingDown  bool
+
+	// If SortedDirectoryListing is set, all output paths sorted by
+	// output base ID.
+	sortedOutputPaths *sortedOutputPathIndex
+
+	removalSubscribers outputPathRemovalSubscribers
+
+	// Held by DiagnoseSelfTest(), as all self-tests use the same
+	// output base ID and build ID.
+	selfTestLock sync.Mutex
 }
 
 var (
@@ -88,20 +478,548 @@ var (
 	_ remoteoutputservice.RemoteOutputServiceServer = &RemoteOutputServiceDirectory{}
 )
 
+// RemoteOutputServiceDirectoryConfiguration contains the tunable
+// options of RemoteOutputServiceDirectory. Related options are grouped
+// into separate structures. The zero value of each of these structures
+// disables the features it configures, or causes defaults to be used.
+type RemoteOutputServiceDirectoryConfiguration struct {
+	// The maximum size in bytes a Tree object may have for it to be
+	// created as a directory through BatchCreate().
+	MaximumTreeSizeBytes int64
+
+	// The clock that is used to track when output paths were last
+	// accessed, to measure the latency of calls written to the
+	// access log, and to wait between retries of FindMissingBlobs()
+	// calls. When not set, clock.SystemClock is used.
+	Clock clock.Clock
+
+	// When set, output base IDs are compared case insensitively,
+	// both when provided through the Remote Output Service protocol
+	// and when looked up through the virtual file system. This is
+	// needed on platforms like macOS, where file systems are
+	// typically case insensitive. Output paths continue to be
+	// listed under the case with which they were created.
+	CaseInsensitiveOutputBaseIDs bool
+
+	// When set, all methods that start or finalize builds, or
+	// modify the contents of output paths fail with
+	// FAILED_PRECONDITION. Methods that only read state, such as
+	// BatchStat() and lookups through the virtual file system,
+	// remain permitted. This is useful for deployments that only
+	// serve output paths that were populated previously.
+	ReadOnly bool
+
+	// The permissions of the root directory of the Remote Output
+	// Service, as reported through the virtual file system. When
+	// zero, DefaultRootDirectoryPermissions is used.
+	RootDirectoryPermissions virtual.Permissions
+
+	// When set, VirtualReadDir() reports output paths sorted by
+	// output base ID, as opposed to the order in which they were
+	// created. This causes the output base IDs of recently removed
+	// output paths to be retained, so that partial reads of the
+	// root directory can be resumed reliably.
+	SortedDirectoryListing bool
+
+	// When set, directories whose contents are loaded from the
+	// Content Addressable Storage lazily are tracked, so that
+	// BatchStatExistence() can be used. Directories remain tracked
+	// until the output path is cleaned or replaced by a snapshot,
+	// even if they are removed. This may cause memory used by
+	// removed directories to be retained.
+	TrackLazyDirectories bool
+
+	// When set, an entry is written to an access log every time a
+	// call to StartBuild(), BatchCreate(), BatchStat(),
+	// FinalizeBuild() or Clean() completes.
+	AccessLog *AccessLogConfiguration
+
+	// When set, Prometheus metrics of the Remote Output Service are
+	// registered with this Registerer. When not set, metrics are
+	// still collected, but not exposed.
+	MetricsRegisterer prometheus.Registerer
+
+	Limits      RemoteOutputServiceLimitsConfiguration
+	Builds      RemoteOutputServiceBuildsConfiguration
+	FindMissing FindMissingConfiguration
+	Snapshots   SnapshotConfiguration
+	BatchCreate BatchCreateConfiguration
+	Caching     RemoteOutputServiceCachingConfiguration
+	Fetching    FetchConfiguration
+}
+
+// RemoteOutputServiceLimitsConfiguration contains limits that
+// RemoteOutputServiceDirectory enforces on the contents of output
+// paths and on the size of requests, protecting against pathological
+// inputs and excessive memory usage.
+type RemoteOutputServiceLimitsConfiguration struct {
+	// The maximum number of files and their maximum total size in
+	// bytes that may be created in a single output path through
+	// BatchCreate(). Attempts to exceed these limits fail with
+	// RESOURCE_EXHAUSTED. Directories and symbolic links are not
+	// taken into account, as the former are loaded lazily. Limits
+	// that are zero are not enforced.
+	MaximumFilesCountPerOutputPath int64
+	MaximumSizeBytesPerOutputPath  int64
+
+	// The maximum estimated amount of memory in bytes that may be
+	// used by the contents of all output paths combined. Attempts
+	// to create files, directories and symbolic links through
+	// BatchCreate() that would cause this limit to be exceeded
+	// fail with RESOURCE_EXHAUSTED. Usage is estimated based on
+	// the number of nodes, the length of symbolic link targets and
+	// the size of Tree objects. When zero, no limit is enforced.
+	MaximumEstimatedMemoryUsageBytes int64
+
+	// The maximum number of symbolic links that BatchStat() may
+	// follow while resolving a single path. Paths that require more
+	// symbolic links to be followed cause BatchStat() to fail with
+	// FAILED_PRECONDITION. This limit is enforced independently of
+	// cycle detection. When zero, no limit is enforced.
+	MaximumSymlinkFollowsPerPath int
+
+	// The maximum number of components that paths provided to
+	// BatchCreate() and BatchStat() may contain, relative to the
+	// root of the output path. For BatchCreate(), the components of
+	// the path prefix are included. Paths having more components
+	// are rejected with INVALID_ARGUMENT. This protects against
+	// pathological inputs. When zero, no limit is enforced.
+	MaximumPathDepth int
+
+	// The maximum number of files, directories and symbolic links
+	// that may be contained in a single BatchCreate() request, and
+	// the maximum number of paths that may be contained in a single
+	// BatchStat() or BatchRemove() request. Requests exceeding these
+	// limits fail with RESOURCE_EXHAUSTED. Unlike gRPC's maximum
+	// message size, these limits bound the amount of work performed
+	// after a request has been decoded. When zero, no limit is
+	// enforced.
+	MaximumBatchCreateEntriesCount int
+	MaximumBatchStatPathsCount     int
+	MaximumBatchRemovePathsCount   int
+
+	// The maximum length in bytes of targets of symbolic links
+	// created through BatchCreate(). Symbolic links having longer
+	// targets are rejected with INVALID_ARGUMENT, as they would
+	// consume memory without being usable, as the kernel rejects
+	// targets exceeding PATH_MAX. When zero,
+	// DefaultMaximumSymlinkTargetLengthBytes is used.
+	MaximumSymlinkTargetLengthBytes int
+
+	// The maximum number of bytes that may be requested in a single
+	// call to ReadFileRange(). When zero,
+	// DefaultMaximumReadFileRangeSizeBytes is used.
+	MaximumReadFileRangeSizeBytes uint64
+}
+
+// RemoteOutputServiceBuildsConfiguration contains options of
+// RemoteOutputServiceDirectory that control how builds are started and
+// finalized, and how output paths are retained between builds.
+type RemoteOutputServiceBuildsConfiguration struct {
+	// When set, output paths whose contents have become corrupted
+	// are discarded automatically when the next build is started.
+	// When not set, StartBuild() fails with DATA_LOSS until the
+	// output path is cleaned explicitly.
+	CleanCorruptedOutputPaths bool
+
+	// When set, output paths are frozen by FinalizeBuild(), causing
+	// any attempts to modify them to fail until the next build is
+	// started against them.
+	FreezeOutputPathsBetweenBuilds bool
+
+	// When set, StartBuild() fails with ALREADY_EXISTS if another
+	// build is still running against the same output base. When
+	// not set, the previous build is finalized forcefully, causing
+	// successive calls against it to fail.
+	RejectConcurrentBuilds bool
+
+	// When positive and RejectConcurrentBuilds is not set,
+	// StartBuild() waits up to this amount of time for a previous
+	// build running against the same output base to be finalized,
+	// as opposed to finalizing it forcefully right away. If the
+	// previous build is not finalized in time, StartBuild() fails
+	// with UNAVAILABLE.
+	ConcurrentBuildGracePeriod time.Duration
+
+	// The amount of time for which the results of
+	// PrepareOutputBase() remain valid. If StartBuild() is called
+	// within this amount of time, it does not need to check which
+	// files and directories in the output path are absent from the
+	// Content Addressable Storage. When zero, PrepareOutputBase()
+	// is disabled.
+	PreparedOutputBaseTimeout time.Duration
+
+	// When set, StartBuild() fails with FAILED_PRECONDITION if the
+	// instance name or digest function differs from the one used
+	// by the previous build of the same output base, as opposed to
+	// silently removing all contents of the output path. Clients
+	// may call StartBuildForcingReset() to switch intentionally.
+	//
+	// The instance name and digest function are not persisted,
+	// meaning that the first build of an output base after a
+	// restart is always permitted.
+	PinDigestFunctionPerOutputBase bool
+
+	// Instance names that are aliases of other instance names,
+	// because they are backed by the same Content Addressable
+	// Storage. Keys are aliases, while values are the instance
+	// names they refer to. Switching between instance names that
+	// refer to the same instance name does not cause the contents
+	// of the output path to be removed.
+	InstanceNameAliases map[string]string
+}
+
+// FindMissingConfiguration contains options of
+// RemoteOutputServiceDirectory that control how the contents of output
+// paths are checked for existence in the Content Addressable Storage.
+type FindMissingConfiguration struct {
+	// The maximum number of FindMissingBlobs() calls that may be
+	// performed concurrently at the start of a build, when checking
+	// the existence of files and directories in the output path.
+	// Values below one are treated as one.
+	Concurrency int
+
+	// The maximum number of digests to provide to a single
+	// FindMissingBlobs() call at the start of a build. When zero,
+	// blobstore.RecommendedFindMissingDigestsCount is used. Values
+	// above 100000 are treated as 100000.
+	BatchSize int
+
+	// When set, the semaphore limits the number of output paths
+	// whose contents are checked for existence at the start of a
+	// build concurrently, regardless of the output base. Builds
+	// exceeding this limit wait until another build is done
+	// checking its output path. As opposed to Concurrency, which
+	// applies to a single output path, this protects the Content
+	// Addressable Storage against many builds being started at
+	// once.
+	OutputPathsConcurrency *semaphore.Weighted
+
+	// When set, FindMissingBlobs() calls performed at the start of
+	// a build are retried if they fail with a transient error.
+	Retry *FindMissingRetryConfiguration
+
+	// When positive, the contents of the output path of a running
+	// build are checked for existence in the Content Addressable
+	// Storage periodically, using this interval. This detects blobs
+	// that are evicted while long builds are running, which would
+	// otherwise only be noticed when reading files fails. Digests
+	// of missing blobs are reported through GetMissingBlobs() and
+	// WatchBuild().
+	RevalidationInterval time.Duration
+
+	// When set, files and directories that are found to be missing
+	// by periodic revalidation are removed from the output path, as
+	// is done at the start of the build. When not set, they are
+	// left in place, and are only reported.
+	RemoveChildrenMissingDuringBuild bool
+}
+
+// SnapshotConfiguration contains options of
+// RemoteOutputServiceDirectory related to storing the contents of
+// output paths in the Content Addressable Storage.
+type SnapshotConfiguration struct {
+	// When set, a snapshot of the output path is uploaded into the
+	// Content Addressable Storage in the form of a Tree object at
+	// the start of every build. The semaphore limits the number of
+	// concurrent writes of files that are only present locally.
+	UploadConcurrency *semaphore.Weighted
+
+	// When set, FinalizeBuildWithSnapshot() stores snapshots of
+	// the output path under a name provided by the client, so that
+	// they can be restored later on by calling RestoreSnapshot().
+	Store SnapshotStore
+}
+
+// BatchCreateConfiguration contains options of
+// RemoteOutputServiceDirectory that control how files, directories and
+// symbolic links are created through BatchCreate().
+type BatchCreateConfiguration struct {
+	// When set, the Tree objects of directories created through
+	// BatchCreate() are fetched from the Content Addressable
+	// Storage immediately, as opposed to loading the contents of
+	// each directory lazily when accessed. This reduces the number
+	// of round trips for builds that traverse output directories in
+	// their entirety, at the cost of making BatchCreate() slower.
+	//
+	// TODO: Make this configurable per output directory, once the
+	// Remote Output Service protocol permits it.
+	EagerlyFetchDirectories bool
+
+	// When set, the root Directory message of the Tree objects of
+	// directories created through BatchCreate() is fetched from the
+	// Content Addressable Storage immediately. This causes
+	// BatchCreate() to fail with NOT_FOUND if a Tree object does not
+	// exist, as opposed to such errors only being reported when the
+	// directory is accessed. Child directories are still loaded
+	// lazily. This option has no effect if EagerlyFetchDirectories
+	// is set.
+	PrefetchRootDirectories bool
+
+	// When set, BatchCreate() validates the paths of all entries
+	// before making any changes to the output path. Requests
+	// containing absolute paths, ".." components or empty
+	// components are rejected in their entirety. Without this
+	// option, such paths are only rejected once they are reached,
+	// meaning that preceding entries have already been created.
+	ValidatePaths bool
+
+	// When set, BatchCreate() leaves files in place if a file with
+	// the same digest and executable bit is already present at the
+	// requested path. This causes attributes of the file (e.g., its
+	// modification time and inode number) to remain stable across
+	// builds, and prevents its parent directory from being
+	// modified. This is at the cost of performing an additional
+	// lookup for every file that is created.
+	PreserveUnchangedFiles bool
+
+	// When set, BatchCreate() keeps track of files that are
+	// replaced by files having the same digest, but a different
+	// executable bit. Their paths are returned by
+	// GetExecutableBitChanges(). This permits clients to detect
+	// changes to the mode of files, which can't be observed
+	// through BatchStat() with include_file_digest set.
+	TrackExecutableBitChanges bool
+
+	// The duration for which the results of requests passed to
+	// BatchCreateWithIdempotencyToken() are retained, and the
+	// maximum number of results retained per build. When zero,
+	// DefaultIdempotencyTokenExpiration and
+	// DefaultMaximumIdempotencyTokensPerBuild are used.
+	IdempotencyTokenExpiration       time.Duration
+	MaximumIdempotencyTokensPerBuild int
+}
+
+// RemoteOutputServiceCachingConfiguration contains options of
+// RemoteOutputServiceDirectory that control caching of state that is
+// expensive to recompute, and sharing of storage between nodes.
+type RemoteOutputServiceCachingConfiguration struct {
+	// When set, BatchStat() caches the last data modification times
+	// of directories, so that repeatedly calling BatchStat() against
+	// the same directories doesn't require their attributes to be
+	// obtained every time. The cache is invalidated whenever the
+	// output path is modified through the Remote Output Service.
+	//
+	// Modifications made through the virtual file system are not
+	// taken into account. This option should therefore only be
+	// enabled if build clients don't write into output paths
+	// directly (e.g., when all actions are executed remotely).
+	CacheDirectoryAttributes bool
+
+	// The maximum number of digests of files that are not backed by
+	// the Content Addressable Storage (e.g., files written by
+	// locally executed actions) that are cached. This prevents
+	// BatchStat() from recomputing digests of files that have not
+	// been modified since they were last requested. When zero, no
+	// digests are cached.
+	FileDigestCacheSize int
+
+	// The maximum number of distinct filenames and symbolic link
+	// targets that are interned. Interning causes nodes created
+	// through the Remote Output Service having the same name or
+	// target to share storage, regardless of the output path in
+	// which they are created. This reduces memory usage for large
+	// builds, at the cost of retaining up to this number of names
+	// for the lifetime of the process. When zero, no names are
+	// interned.
+	MaximumInternedNamesCount int
+}
+
+// FetchConfiguration contains options of RemoteOutputServiceDirectory
+// that control how directories and files whose contents are backed by
+// the Content Addressable Storage are loaded.
+type FetchConfiguration struct {
+	// When positive, concurrent requests for the same Directory
+	// message made while loading directories lazily are coalesced,
+	// and the number of requests sent to the Content Addressable
+	// Storage concurrently is limited to this value. If no
+	// DirectoryTimeout is set, these requests time out after
+	// defaultDeduplicatedDirectoryFetchTimeout.
+	DirectoryConcurrency int64
+
+	// When positive, the maximum amount of time a single request for
+	// a Directory message may take. Directories are loaded lazily
+	// in the background, meaning that without a timeout an
+	// unresponsive Content Addressable Storage causes operations
+	// against the virtual file system to hang indefinitely.
+	DirectoryTimeout time.Duration
+
+	// When positive, the maximum number of reads of files backed by
+	// the Content Addressable Storage that may be in flight
+	// concurrently for a single output path. Excess reads are
+	// queued. This prevents builds that open many files through
+	// the virtual file system at once from overwhelming the
+	// Content Addressable Storage.
+	CASFileReadConcurrency int64
+}
+
+// AccessLogConfiguration contains the options for logging calls
+// against the Remote Output Service.
+type AccessLogConfiguration struct {
+	Logger AccessLogger
+
+	// The maximum number of paths contained in BatchCreate() and
+	// BatchStat() requests to include in access log entries. When
+	// zero, no paths are logged.
+	MaximumPathsCount int
+}
+
+// FindMissingRetryConfiguration contains the options for retrying
+// FindMissingBlobs() calls performed by StartBuild(). Retries are
+// performed using exponential backoff, and only for errors that are
+// likely to be transient (UNAVAILABLE and DEADLINE_EXCEEDED).
+type FindMissingRetryConfiguration struct {
+	// The maximum number of attempts, including the initial one.
+	MaximumAttempts int
+
+	// The delay before the first retry, which is doubled for every
+	// successive retry, up to MaximumBackoff.
+	InitialBackoff time.Duration
+	MaximumBackoff time.Duration
+}
+
+// DefaultMaximumSymlinkTargetLengthBytes is the maximum length of
+// targets of symbolic links created through BatchCreate() that is used
+// if none is configured. It corresponds to PATH_MAX on Linux.
+const DefaultMaximumSymlinkTargetLengthBytes = 4096
+
+// DefaultRootDirectoryPermissions are the permissions of the root
+// directory of the Remote Output Service that are used if none are
+// configured.
+const DefaultRootDirectoryPermissions = virtual.PermissionsRead | virtual.PermissionsExecute
+
+// DefaultMaximumReadFileRangeSizeBytes is the maximum number of bytes
+// that may be requested in a single call to ReadFileRange() that is
+// used if none is configured.
+const DefaultMaximumReadFileRangeSizeBytes = 4 * 1024 * 1024
+
+// DefaultIdempotencyTokenExpiration is the duration for which results
+// of BatchCreateWithIdempotencyToken() are retained that is used if
+// none is configured.
+const DefaultIdempotencyTokenExpiration = 5 * time.Minute
+
+// DefaultMaximumIdempotencyTokensPerBuild is the maximum number of
+// results of BatchCreateWithIdempotencyToken() that are retained per
+// build that is used if none is configured.
+const DefaultMaximumIdempotencyTokensPerBuild = 1000
+
+// ContentAddressableStorageRoles contains the Content Addressable
+// Storage backends used by RemoteOutputServiceDirectory, one for each
+// role in which it accesses the Content Addressable Storage. This
+// permits each workload to be directed to a differently configured
+// backend. The same backend may be used for multiple roles.
+type ContentAddressableStorageRoles struct {
+	// Used to load the contents of files stored in output paths.
+	// As files are loaded lazily, errors cannot always be
+	// propagated to clients. This backend should therefore retry
+	// requests that fail with transient errors.
+	FileReads blobstore.BlobAccess
+
+	// Used by BatchCreate() to fetch Tree objects, if
+	// EagerlyFetchDirectories is set.
+	TreeReads blobstore.BlobAccess
+
+	// Used by StartBuild() to remove files and directories from
+	// output paths that are no longer present.
+	FindMissing blobstore.BlobAccess
+
+	// Used to upload the contents of output paths, when snapshots
+	// of output paths are created.
+	Uploads blobstore.BlobAccess
+}
+
+// defaultDeduplicatedDirectoryFetchTimeout is the maximum amount of
+// time a request for a Directory message may take if requests are
+// coalesced, and no DirectoryTimeout is configured.
+const defaultDeduplicatedDirectoryFetchTimeout = 5 * time.Minute
+
 // NewRemoteOutputServiceDirectory creates a new instance of
 // RemoteOutputServiceDirectory.
-func NewRemoteOutputServiceDirectory(handleAllocator virtual.StatefulHandleAllocator, outputPathFactory OutputPathFactory, bareContentAddressableStorage, retryingContentAddressableStorage blobstore.BlobAccess, directoryFetcher re_cas.DirectoryFetcher, symlinkFactory virtual.SymlinkFactory, maximumTreeSizeBytes int64) *RemoteOutputServiceDirectory {
+//
+// All digests of files and directories stored in an output path carry
+// the instance name that was provided to StartBuild(). Builds may thus
+// load data from different clusters by providing BlobAccess and
+// DirectoryFetcher instances that route requests based on the prefix
+// of the instance name, such as the ones created by the "demultiplexing"
+// blobstore configuration.
+func NewRemoteOutputServiceDirectory(handleAllocator virtual.StatefulHandleAllocator, outputPathFactory OutputPathFactory, contentAddressableStorage ContentAddressableStorageRoles, directoryFetcher re_cas.DirectoryFetcher, symlinkFactory virtual.SymlinkFactory, tracerProvider trace.TracerProvider, configuration *RemoteOutputServiceDirectoryConfiguration) *RemoteOutputServiceDirectory {
+	if timeout := configuration.Fetching.DirectoryTimeout; timeout > 0 {
+		directoryFetcher = cd_cas.NewTimeoutDirectoryFetcher(directoryFetcher, timeout)
+	}
+	if concurrency := configuration.Fetching.DirectoryConcurrency; concurrency > 0 {
+		// Requests are not canceled when callers stop waiting
+		// for their results. Always bound their duration, so
+		// that requests that hang don't occupy a slot forever.
+		timeout := configuration.Fetching.DirectoryTimeout
+		if timeout <= 0 {
+			timeout = defaultDeduplicatedDirectoryFetchTimeout
+		}
+		directoryFetcher = cd_cas.NewDeduplicatingDirectoryFetcher(directoryFetcher, concurrency, timeout)
+	}
+
 	d := &RemoteOutputServiceDirectory{
-		handleAllocator:                   handleAllocator,
-		outputPathFactory:                 outputPathFactory,
-		bareContentAddressableStorage:     bareContentAddressableStorage,
-		retryingContentAddressableStorage: retryingContentAddressableStorage,
-		directoryFetcher:                  directoryFetcher,
-		symlinkFactory:                    symlinkFactory,
-		maximumTreeSizeBytes:              maximumTreeSizeBytes,
+		handleAllocator:   handleAllocator,
+		outputPathFactory: outputPathFactory,
+		symlinkFactory:    symlinkFactory,
+		tracer:            tracerProvider.Tracer("github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"),
+		configuration:     *configuration,
+		metrics:           newRemoteOutputServiceDirectoryMetrics(configuration.MetricsRegisterer),
 
 		outputBaseIDs: map[path.Component]*outputPathState{},
 		buildIDs:      map[string]*outputPathState{},
+
+		removalSubscribers: outputPathRemovalSubscribers{},
+	}
+	wrapBlobAccess := func(blobAccess blobstore.BlobAccess) blobstore.BlobAccess {
+		return &inFlightCountingBlobAccess{
+			BlobAccess:    &remoteDataLossMarkingBlobAccess{BlobAccess: blobAccess},
+			callsInFlight: &d.casCallsInFlightCount,
+		}
+	}
+	d.contentAddressableStorage = ContentAddressableStorageRoles{
+		FileReads:   wrapBlobAccess(contentAddressableStorage.FileReads),
+		TreeReads:   wrapBlobAccess(contentAddressableStorage.TreeReads),
+		FindMissing: wrapBlobAccess(contentAddressableStorage.FindMissing),
+		Uploads:     wrapBlobAccess(contentAddressableStorage.Uploads),
+	}
+	d.directoryFetcher = &inFlightCountingDirectoryFetcher{
+		DirectoryFetcher: &remoteDataLossMarkingDirectoryFetcher{DirectoryFetcher: directoryFetcher},
+		callsInFlight:    &d.casCallsInFlightCount,
+	}
+	if maximumBytes := configuration.Limits.MaximumEstimatedMemoryUsageBytes; maximumBytes > 0 {
+		d.memoryBudget = newMemoryBudget(maximumBytes)
+	}
+	if maximumSize := configuration.Caching.FileDigestCacheSize; maximumSize > 0 {
+		d.fileDigestCache = newFileDigestCache(maximumSize)
+	}
+	if d.configuration.Limits.MaximumSymlinkTargetLengthBytes == 0 {
+		d.configuration.Limits.MaximumSymlinkTargetLengthBytes = DefaultMaximumSymlinkTargetLengthBytes
+	}
+	if d.configuration.Limits.MaximumReadFileRangeSizeBytes == 0 {
+		d.configuration.Limits.MaximumReadFileRangeSizeBytes = DefaultMaximumReadFileRangeSizeBytes
+	}
+	if d.configuration.BatchCreate.IdempotencyTokenExpiration == 0 {
+		d.configuration.BatchCreate.IdempotencyTokenExpiration = DefaultIdempotencyTokenExpiration
+	}
+	if d.configuration.BatchCreate.MaximumIdempotencyTokensPerBuild == 0 {
+		d.configuration.BatchCreate.MaximumIdempotencyTokensPerBuild = DefaultMaximumIdempotencyTokensPerBuild
+	}
+	if d.configuration.RootDirectoryPermissions == 0 {
+		d.configuration.RootDirectoryPermissions = DefaultRootDirectoryPermissions
+	}
+	if maximumCount := configuration.Caching.MaximumInternedNamesCount; maximumCount > 0 {
+		d.nameInterner = newNameInterner(maximumCount)
+	}
+	if configuration.SortedDirectoryListing {
+		d.sortedOutputPaths = newSortedOutputPathIndex()
+	}
+	d.onDemandSnapshotUploadConcurrency = configuration.Snapshots.UploadConcurrency
+	if d.onDemandSnapshotUploadConcurrency == nil {
+		d.onDemandSnapshotUploadConcurrency = semaphore.NewWeighted(1)
+	}
+	d.clock = configuration.Clock
+	if d.clock == nil {
+		d.clock = clock.SystemClock
 	}
 	d.handle = handleAllocator.New().AsStatefulDirectory(d)
 	d.outputPaths.previous = &d.outputPaths
@@ -109,36 +1027,186 @@ func NewRemoteOutputServiceDirectory(handleAllocator virtual.StatefulHandleAlloc
 	return d
 }
 
+// checkWritable returns an error if the RemoteOutputServiceDirectory
+// is configured to be read-only. This needs to be called by all
+// methods that start or finalize builds, or modify output paths.
+func (d *RemoteOutputServiceDirectory) checkWritable() error {
+	if d.configuration.ReadOnly {
+		return status.Error(codes.FailedPrecondition, "Remote Output Service is read-only")
+	}
+	return nil
+}
+
+// endSpan terminates a tracing span, attaching the error that caused
+// the traced operation to fail, if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// accessLogRecord keeps track of a single call against the Remote
+// Output Service for which an access log entry needs to be written.
+type accessLogRecord struct {
+	configuration *AccessLogConfiguration
+	clock         clock.Clock
+	entry         AccessLogEntry
+	startTime     time.Time
+}
+
+// startAccessLogRecord starts tracking a call against the Remote Output
+// Service for the purpose of access logging. If no output base ID is
+// provided, it is obtained from the running build. This function
+// returns nil if access logging is disabled.
+func (d *RemoteOutputServiceDirectory) startAccessLogRecord(entry AccessLogEntry, paths []string) *accessLogRecord {
+	configuration := d.configuration.AccessLog
+	if configuration == nil {
+		return nil
+	}
+	if entry.OutputBaseID == "" {
+		d.lock.Lock()
+		if outputPathState, ok := d.buildIDs[entry.BuildID]; ok {
+			entry.OutputBaseID = outputPathState.outputBaseID.String()
+		}
+		d.lock.Unlock()
+	}
+	if len(paths) > configuration.MaximumPathsCount {
+		paths = paths[:configuration.MaximumPathsCount]
+	}
+	if len(paths) > 0 {
+		entry.SamplePaths = append([]string(nil), paths...)
+	}
+	return &accessLogRecord{
+		configuration: configuration,
+		clock:         d.clock,
+		entry:         entry,
+		startTime:     d.clock.Now(),
+	}
+}
+
+// finish the tracking of a call against the Remote Output Service,
+// writing an entry to the access log.
+func (r *accessLogRecord) finish(err error) {
+	if r == nil {
+		return
+	}
+	r.entry.Code = status.Code(err)
+	r.entry.Latency = r.clock.Now().Sub(r.startTime)
+	r.configuration.Logger.LogAccess(&r.entry)
+}
+
 // Clean all build outputs associated with a single output base.
-func (d *RemoteOutputServiceDirectory) Clean(ctx context.Context, request *remoteoutputservice.CleanRequest) (*emptypb.Empty, error) {
+func (d *RemoteOutputServiceDirectory) Clean(ctx context.Context, request *remoteoutputservice.CleanRequest) (_ *emptypb.Empty, err error) {
+	accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+		Method:       "Clean",
+		OutputBaseID: request.OutputBaseId,
+	}, nil)
+	defer func() { accessLogRecord.finish(err) }()
+
+	return d.CleanWithProgress(ctx, request, nil)
+}
+
+// CleanProgressFunc is invoked by CleanWithProgress() every time a file
+// or symbolic link has been removed from the output path. It is
+// provided the total number of files and symbolic links removed so far.
+type CleanProgressFunc func(removedLeavesCount uint64)
+
+// progressReportingRemover is used by CleanWithProgress() to remove
+// the contents of an output path one entry at a time, so that progress
+// can be reported to the caller.
+type progressReportingRemover struct {
+	context            context.Context
+	progress           CleanProgressFunc
+	removedLeavesCount uint64
+}
+
+func (r *progressReportingRemover) removeChildrenRecursive(d virtual.PrepopulatedDirectory, dPath *path.Trace) error {
+	// Output paths may consist of deeply nested directories that
+	// contain few files. Check for cancellation prior to
+	// processing every directory, so that traversal of such
+	// output paths also stops promptly.
+	if r.context.Err() != nil {
+		return util.StatusFromContext(r.context)
+	}
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+	for _, entry := range directories {
+		childPath := dPath.Append(entry.Name)
+		if err := r.removeChildrenRecursive(entry.Child, childPath); err != nil {
+			return err
+		}
+		if r.context.Err() != nil {
+			return util.StatusFromContext(r.context)
+		}
+		if err := d.Remove(entry.Name); err != nil {
+			return util.StatusWrapf(err, "Failed to remove directory %#v", childPath.String())
+		}
+	}
+	for _, entry := range leaves {
+		if r.context.Err() != nil {
+			return util.StatusFromContext(r.context)
+		}
+		if err := d.Remove(entry.Name); err != nil {
+			return util.StatusWrapf(err, "Failed to remove file %#v", dPath.Append(entry.Name).String())
+		}
+		r.removedLeavesCount++
+		r.progress(r.removedLeavesCount)
+	}
+	return nil
+}
+
+// CleanWithProgress is identical to Clean(), except that the contents
+// of the output path are removed incrementally, calling the provided
+// function to report the number of files removed so far. This allows
+// callers to display progress when cleaning large output paths, or to
+// detect that cleaning has stalled. If no function is provided, the
+// contents of the output path are removed in a single operation.
+func (d *RemoteOutputServiceDirectory) CleanWithProgress(ctx context.Context, request *remoteoutputservice.CleanRequest, progress CleanProgressFunc) (*emptypb.Empty, error) {
+	if err := d.checkWritable(); err != nil {
+		return nil, err
+	}
 	outputBaseID, ok := path.NewComponent(request.OutputBaseId)
 	if !ok {
 		return nil, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
 	}
 
 	d.lock.Lock()
-	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
 	d.lock.Unlock()
 	if ok {
+		// Use the output base ID with which the output path was
+		// created, as it may differ in case.
+		outputBaseID = outputPathState.outputBaseID
+
 		// Remove all data stored inside the output path. This
 		// must be done without holding the directory lock, as
 		// NotifyRemoval() calls generated by the output path
 		// could deadlock otherwise.
-		if err := outputPathState.rootDirectory.RemoveAllChildren(true); err != nil {
-			return nil, err
+		if d.configuration.Builds.FreezeOutputPathsBetweenBuilds {
+			outputPathState.rootDirectory.Unfreeze()
 		}
+		if err := removeOutputPathContents(ctx, outputPathState.rootDirectory, progress); err != nil {
+			if !d.detectCorruption(outputPathState, err) {
+				return nil, err
+			}
 
-		d.lock.Lock()
-		if outputPathState == d.outputBaseIDs[outputBaseID] {
-			delete(d.outputBaseIDs, outputBaseID)
-			outputPathState.previous.next = outputPathState.next
-			outputPathState.next.previous = outputPathState.previous
-			d.changeID++
-			if buildState := outputPathState.buildState; buildState != nil {
-				delete(d.buildIDs, buildState.id)
-				outputPathState.buildState = nil
+			// The output path is corrupted, meaning that its
+			// contents cannot be removed reliably. Discard
+			// the output path in its entirety, and make sure
+			// that any persistent state is removed as well.
+			if err := d.outputPathFactory.Clean(outputBaseID); err != nil {
+				return nil, err
 			}
 		}
+
+		d.lock.Lock()
+		if outputPathState == d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)] {
+			d.removeOutputPath(outputPathState, OutputPathRemovalReasonClean)
+		}
 		d.lock.Unlock()
 
 		d.handle.NotifyRemoval(outputBaseID)
@@ -152,40 +1220,563 @@ func (d *RemoteOutputServiceDirectory) Clean(ctx context.Context, request *remot
 	return &emptypb.Empty{}, nil
 }
 
-// findMissingAndRemove is called during StartBuild() to remove a single
-// batch of files from the output path that are no longer present in the
-// Content Addressable Storage.
-func (d *RemoteOutputServiceDirectory) findMissingAndRemove(ctx context.Context, queue map[digest.Digest][]func() error) error {
-	set := digest.NewSetBuilder()
-	for digest := range queue {
-		set.Add(digest)
+// CleanPathPrefix is identical to Clean(), except that only the
+// contents of a single directory within the output path are removed.
+// The directory itself and the remainder of the output path are left
+// intact. This can be used to invalidate parts of the output path
+// (e.g., "bazel-out/k8-fastbuild") without discarding the results of
+// other configurations.
+//
+// Unlike Clean(), this method fails if the output path hasn't been
+// accessed since startup, as its persistent state cannot be cleaned
+// partially.
+func (d *RemoteOutputServiceDirectory) CleanPathPrefix(ctx context.Context, request *remoteoutputservice.CleanRequest, pathPrefix string) (*emptypb.Empty, error) {
+	if err := d.checkWritable(); err != nil {
+		return nil, err
+	}
+	outputBaseID, ok := path.NewComponent(request.OutputBaseId)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
+	if !ok {
+		d.lock.Unlock()
+		return nil, status.Errorf(codes.NotFound, "Output path %#v does not exist", request.OutputBaseId)
 	}
-	missing, err := d.bareContentAddressableStorage.FindMissing(ctx, set.Build())
+	if outputPathState.corrupted {
+		d.lock.Unlock()
+		return nil, getCorruptedOutputPathError(outputPathState.outputBaseID)
+	}
+	d.lock.Unlock()
+
+	outputPathState.contentsLock.RLock()
+	defer outputPathState.contentsLock.RUnlock()
+
+	directory, err := lookupDirectory(outputPathState.rootDirectory, pathPrefix)
 	if err != nil {
-		return util.StatusWrap(err, "Failed to find missing blobs")
+		d.detectCorruption(outputPathState, err)
+		return nil, util.StatusWrapf(err, "Failed to look up path prefix %#v", pathPrefix)
 	}
-	for _, digest := range missing.Items() {
-		for _, removeFunc := range queue[digest] {
-			if err := removeFunc(); err != nil {
-				return util.StatusWrapf(err, "Failed to remove file with digest %#v", digest.String())
+
+	// Unlike Clean(), the output path itself is not removed. There
+	// is thus no need to call NotifyRemoval() against this
+	// directory. The removal of the children of the directory is
+	// reported by the directory itself.
+	if d.configuration.Builds.FreezeOutputPathsBetweenBuilds {
+		// Freeze the output path afterwards, unless a build has
+		// been started against it in the meantime.
+		outputPathState.rootDirectory.Unfreeze()
+		defer func() {
+			d.lock.Lock()
+			if outputPathState.buildState == nil {
+				outputPathState.rootDirectory.Freeze()
 			}
-		}
+			d.lock.Unlock()
+		}()
 	}
-	return nil
-}
+	outputPathState.trimmableDirectories.invalidate(pathPrefix, "")
+	err = directory.RemoveAllChildren(false)
+	outputPathState.markModified()
+	if err != nil {
+		d.detectCorruption(outputPathState, err)
+		return nil, util.StatusWrapf(err, "Failed to remove contents of path prefix %#v", pathPrefix)
+	}
+	outputPathState.directoryMemory.release(pathPrefix, "")
+
+	// Notify clients watching the build running against the
+	// output path, if any.
+	d.lock.Lock()
+	if buildState := outputPathState.buildState; buildState != nil {
+		buildState.events.append(BuildEvent{
+			Type: BuildEventDirectoryChanged,
+			Path: pathPrefix,
+		})
+	}
+	d.lock.Unlock()
+	return &emptypb.Empty{}, nil
+}
+
+// CleanDryRunResult describes the contents of an output path that
+// would be removed by Clean().
+type CleanDryRunResult struct {
+	DirectoriesCount uint64
+	LeavesCount      uint64
+
+	// Paths of some of the files and symbolic links that would be
+	// removed, relative to the root of the output path.
+	SampleLeafPaths []string
+}
+
+// dryRunCounter is used by CleanDryRun() to count the number of
+// directories and leaves contained in an output path.
+type dryRunCounter struct {
+	context                     context.Context
+	maximumSampleLeafPathsCount int
+	result                      CleanDryRunResult
+}
+
+func (c *dryRunCounter) countChildrenRecursive(d virtual.PrepopulatedDirectory, dPath *path.Trace) error {
+	if c.context.Err() != nil {
+		return util.StatusFromContext(c.context)
+	}
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+	for _, entry := range directories {
+		if err := c.countChildrenRecursive(entry.Child, dPath.Append(entry.Name)); err != nil {
+			return err
+		}
+		c.result.DirectoriesCount++
+	}
+	for _, entry := range leaves {
+		if len(c.result.SampleLeafPaths) < c.maximumSampleLeafPathsCount {
+			c.result.SampleLeafPaths = append(c.result.SampleLeafPaths, dPath.Append(entry.Name).String())
+		}
+		c.result.LeavesCount++
+	}
+	return nil
+}
+
+// CleanDryRun reports what would be removed if Clean() were called
+// against an output base, without making any changes. Up to
+// maximumSampleLeafPathsCount paths of files and symbolic links that
+// would be removed are returned as well.
+//
+// The contents of the output path are traversed in their entirety,
+// meaning that this operation is about as expensive as Clean() itself.
+// Persistent state of output paths that haven't been accessed since
+// startup is not inspected.
+func (d *RemoteOutputServiceDirectory) CleanDryRun(ctx context.Context, request *remoteoutputservice.CleanRequest, maximumSampleLeafPathsCount int) (*CleanDryRunResult, error) {
+	outputBaseID, ok := path.NewComponent(request.OutputBaseId)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
+	d.lock.Unlock()
+	if !ok {
+		return &CleanDryRunResult{}, nil
+	}
+
+	counter := dryRunCounter{
+		context:                     ctx,
+		maximumSampleLeafPathsCount: maximumSampleLeafPathsCount,
+	}
+	if err := counter.countChildrenRecursive(outputPathState.rootDirectory, nil); err != nil {
+		d.detectCorruption(outputPathState, err)
+		return nil, err
+	}
+	return &counter.result, nil
+}
+
+// removeOutputPathContents removes all files, directories and symbolic
+// links contained in an output path, preventing the creation of new
+// ones.
+func removeOutputPathContents(ctx context.Context, rootDirectory OutputPath, progress CleanProgressFunc) error {
+	if progress != nil {
+		remover := progressReportingRemover{
+			context:  ctx,
+			progress: progress,
+		}
+		if err := remover.removeChildrenRecursive(rootDirectory, nil); err != nil {
+			return err
+		}
+	}
+	return rootDirectory.RemoveAllChildren(true)
+}
+
+// getOutputBaseIDKey returns the key under which the output path of a
+// given output base is stored in outputBaseIDs. If output base IDs are
+// case insensitive, the key is converted to lowercase.
+func (d *RemoteOutputServiceDirectory) getOutputBaseIDKey(outputBaseID path.Component) path.Component {
+	if d.configuration.CaseInsensitiveOutputBaseIDs {
+		return path.MustNewComponent(strings.ToLower(outputBaseID.String()))
+	}
+	return outputBaseID
+}
+
+// createOutputPath creates a new output path and exposes it through
+// the virtual file system. This function must be called while holding
+// the directory's lock.
+func (d *RemoteOutputServiceDirectory) createOutputPath(outputBaseID path.Component, digestFunction digest.Function) *outputPathState {
+	// Errors are still written to the log, but are also captured,
+	// so that they can be propagated back to the build client
+	// through GetBuildErrors(). This allows the client to retry,
+	// or at least display the error immediately, so that users
+	// don't need to check logs.
+	errorLogger := &capturingErrorLogger{
+		base: util.DefaultErrorLogger,
+	}
+	state := &outputPathState{
+		errorLogger:    errorLogger,
+		digestFunction: digestFunction,
+
+		previous:     d.outputPaths.previous,
+		next:         &d.outputPaths,
+		cookie:       d.changeID,
+		outputBaseID: outputBaseID,
+	}
+	fileReadsBlobAccess := d.contentAddressableStorage.FileReads
+	if concurrency := d.configuration.Fetching.CASFileReadConcurrency; concurrency > 0 {
+		fileReadsBlobAccess = cd_blobstore.NewConcurrencyLimitingBlobAccess(fileReadsBlobAccess, semaphore.NewWeighted(concurrency))
+	}
+	state.casFileFactory = virtual.NewStatelessHandleAllocatingCASFileFactory(
+		virtual.NewBlobAccessCASFileFactory(
+			context.Background(),
+			&byteCountingBlobAccess{
+				BlobAccess: fileReadsBlobAccess,
+				bytesRead:  &state.statistics.casFileBytesRead,
+			},
+			errorLogger),
+		d.handleAllocator.New())
+	state.directoryFetcher = &byteCountingDirectoryFetcher{
+		DirectoryFetcher: d.directoryFetcher,
+		bytesFetched:     &state.statistics.directoryBytesFetched,
+	}
+	state.rootDirectory = d.outputPathFactory.StartInitialBuild(outputBaseID, state.casFileFactory, digestFunction, errorLogger)
+	state.exposedDirectory = outputPathRootDirectory{
+		Directory: state.rootDirectory,
+		directory: d,
+		state:     state,
+	}
+	d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)] = state
+	state.previous.next = state
+	state.next.previous = state
+	d.sortedOutputPaths.insert(state)
+	d.changeID++
+	d.markAccessed(state)
+	return state
+}
+
+// markAccessed needs to be called whenever an output path is used,
+// either by a build or through the virtual file system. This method
+// must be called with the directory lock held.
+func (d *RemoteOutputServiceDirectory) markAccessed(outputPathState *outputPathState) {
+	outputPathState.lastAccessTime = d.clock.Now()
+}
+
+// markModified needs to be called after the contents of an output path
+// have been modified through RemoteOutputServiceDirectory. It
+// invalidates any cached directory attributes, and increments the
+// change ID of the output path.
+func (s *outputPathState) markModified() {
+	s.directoryAttributes.invalidate()
+	s.changeID.Add(1)
+}
+
+// getRootDirectoryAttributes returns the attributes of the root
+// directory of an output path. The change ID of the root directory only
+// accounts for changes to its own children. It is therefore combined
+// with the change ID of the output path, so that it increases whenever
+// the output path is modified.
+func (s *outputPathState) getRootDirectoryAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	s.rootDirectory.VirtualGetAttributes(ctx, requested, attributes)
+	if requested&virtual.AttributesMaskChangeID != 0 {
+		attributes.SetChangeID(attributes.GetChangeID() + s.changeID.Load())
+	}
+}
+
+// outputPathRootDirectory is the root directory of an output path, as
+// it is returned by VirtualLookup() and VirtualReadDir(). Obtaining the
+// attributes of the root directory through the virtual file system
+// counts as accessing the output path, as kernels tend to revalidate
+// cached directory entries by calling VirtualGetAttributes() as
+// opposed to performing another lookup.
+type outputPathRootDirectory struct {
+	virtual.Directory
+	directory *RemoteOutputServiceDirectory
+	state     *outputPathState
+}
+
+func (d outputPathRootDirectory) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	d.directory.lock.Lock()
+	d.directory.markAccessed(d.state)
+	d.directory.lock.Unlock()
+	d.state.getRootDirectoryAttributes(ctx, requested, attributes)
+}
+
+// removeOutputPath removes an output path from the directory listing,
+// terminating any build that is running against it. This method must
+// be called with the directory lock held.
+func (d *RemoteOutputServiceDirectory) removeOutputPath(outputPathState *outputPathState, reason OutputPathRemovalReason) {
+	d.removalSubscribers.publish(OutputPathRemovalEvent{
+		OutputBaseID:   outputPathState.outputBaseID.String(),
+		Reason:         reason,
+		LastAccessTime: outputPathState.lastAccessTime,
+	})
+	d.releaseDirectoryMemory(outputPathState)
+	d.metrics.deleteOutputBase(outputPathState.outputBaseID)
+	delete(d.outputBaseIDs, d.getOutputBaseIDKey(outputPathState.outputBaseID))
+	outputPathState.previous.next = outputPathState.next
+	outputPathState.next.previous = outputPathState.previous
+	d.sortedOutputPaths.remove(outputPathState)
+	d.changeID++
+	if buildState := outputPathState.buildState; buildState != nil {
+		delete(d.buildIDs, buildState.id)
+		outputPathState.buildState = nil
+		buildState.events.close()
+	}
+}
+
+// releaseDirectoryMemory releases the estimated amount of memory that
+// was acquired by directories created in an output path. This needs to
+// be called when the contents of the output path are removed.
+func (d *RemoteOutputServiceDirectory) releaseDirectoryMemory(outputPathState *outputPathState) {
+	outputPathState.directoryMemory.releaseAll()
+}
+
+// detectCorruption inspects an error returned by an operation against
+// the root directory of an output path. If the error indicates that
+// the contents of the output path have become corrupted, the output
+// path is marked as such. This method returns whether the output path
+// is corrupted.
+//
+// DATA_LOSS errors caused by the Content Addressable Storage are
+// ignored, as they are marked by markRemoteDataLoss(). These don't
+// indicate that local storage of the output path is corrupted.
+func (d *RemoteOutputServiceDirectory) detectCorruption(outputPathState *outputPathState, err error) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if status.Code(err) == codes.DataLoss && !isRemoteDataLoss(err) {
+		outputPathState.corrupted = true
+	}
+	return outputPathState.corrupted
+}
+
+// getCorruptedOutputPathError returns the error that is returned by
+// all operations against an output path that is corrupted.
+func getCorruptedOutputPathError(outputBaseID path.Component) error {
+	return status.Errorf(codes.DataLoss, "Output path %#v is corrupted, and needs to be cleaned", outputBaseID.String())
+}
+
+// discardCorruptedOutputPath is called by StartBuild() to remove an
+// output path from the directory if it was previously marked as being
+// corrupted. This ensures that a fresh output path is created, allowing
+// the build to recover.
+func (d *RemoteOutputServiceDirectory) discardCorruptedOutputPath(outputBaseID path.Component) error {
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
+	if !ok || outputPathState.outputBaseID != outputBaseID || !outputPathState.corrupted {
+		d.lock.Unlock()
+		return nil
+	}
+	if !d.configuration.Builds.CleanCorruptedOutputPaths {
+		d.lock.Unlock()
+		return getCorruptedOutputPathError(outputBaseID)
+	}
+	d.removeOutputPath(outputPathState, OutputPathRemovalReasonCorrupted)
+	d.lock.Unlock()
+
+	d.handle.NotifyRemoval(outputBaseID)
+
+	// Attempt to release any resources held by the output path.
+	// Errors are ignored, as the contents of the output path are
+	// known to be corrupted. Any persistent state needs to be
+	// removed, as it would otherwise be reloaded.
+	outputPathState.rootDirectory.RemoveAllChildren(true)
+	if err := d.outputPathFactory.Clean(outputBaseID); err != nil {
+		return util.StatusWrap(err, "Failed to clean corrupted output path")
+	}
+	return nil
+}
+
+// findMissingAndRemove is called during StartBuild() to remove a single
+// batch of files from the output path that are no longer present in the
+// Content Addressable Storage. It is also called while the build is
+// running if FindMissing.RevalidationInterval is set, in which case
+// files may only be reported as missing.
+func (d *RemoteOutputServiceDirectory) findMissingAndRemove(ctx context.Context, queue map[digest.Digest][]func() error, metrics *findMissingMetrics, preparation *buildPreparation, removeLock *sync.Mutex) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.findMissingAndRemove", trace.WithAttributes(
+		attribute.Int("digests_count", len(queue)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	timer := prometheus.NewTimer(metrics.batchDurationSeconds)
+	defer timer.ObserveDuration()
+	metrics.batchesFlushed.Inc()
+
+	set := digest.NewSetBuilder()
+	for digest := range queue {
+		set.Add(digest)
+	}
+	missing, err := d.findMissingWithRetries(ctx, set.Build())
+	if err != nil {
+		return util.StatusWrap(err, "Failed to find missing blobs")
+	}
+	span.SetAttributes(attribute.Int("missing_digests_count", len(missing.Items())))
+
+	// Batches may be processed concurrently. Ensure that only one
+	// batch removes files at a time.
+	removeLock.Lock()
+	defer removeLock.Unlock()
+	for _, digest := range missing.Items() {
+		if preparation.missingBlobs != nil {
+			preparation.missingBlobs.add(digest)
+		}
+		if preparation.retainMissingChildren {
+			preparation.events.append(BuildEvent{
+				Type:   BuildEventBlobMissing,
+				Digest: digest,
+			})
+			continue
+		}
+		for _, removeFunc := range queue[digest] {
+			if err := removeFunc(); err != nil {
+				return util.StatusWrapf(err, "Failed to remove file with digest %#v", digest.String())
+			}
+			metrics.childrenRemoved.Inc()
+			preparation.childRemoved(digest)
+		}
+	}
+	return nil
+}
+
+// isRetriableFindMissingError returns whether a FindMissingBlobs() call
+// that failed with a given error may succeed when retried.
+func isRetriableFindMissingError(err error) bool {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// findMissingWithRetries calls FindMissingBlobs() against the Content
+// Addressable Storage. If configured, calls that fail with a transient
+// error are retried, so that a single failure doesn't cause the build
+// to fail, and the contents of the output path to be discarded.
+func (d *RemoteOutputServiceDirectory) findMissingWithRetries(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	retry := d.configuration.FindMissing.Retry
+	var backoff time.Duration
+	if retry != nil {
+		backoff = retry.InitialBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		missing, err := d.contentAddressableStorage.FindMissing.FindMissing(ctx, digests)
+		if err == nil {
+			return missing, nil
+		}
+		if retry == nil || attempt >= retry.MaximumAttempts || !isRetriableFindMissingError(err) || ctx.Err() != nil {
+			return digest.EmptySet, err
+		}
+
+		timer, t := d.clock.NewTimer(backoff)
+		select {
+		case <-t:
+		case <-ctx.Done():
+			timer.Stop()
+			return digest.EmptySet, err
+		}
+		backoff *= 2
+		if backoff > retry.MaximumBackoff {
+			backoff = retry.MaximumBackoff
+		}
+	}
+}
+
+// maximumFindMissingBatchSize is the largest number of digests that
+// filterMissingChildren() provides to a single FindMissingBlobs() call,
+// regardless of the configured batch size. Larger batches risk
+// exceeding the maximum gRPC message size.
+const maximumFindMissingBatchSize = 100000
 
 // filterMissingChildren is called during StartBuild() to traverse over
 // all files in the output path, calling FindMissingBlobs() on them to
 // ensure that they will not disappear during the build. Any files that
-// are missing are removed from the output path.
-func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context, rootDirectory virtual.PrepopulatedDirectory, digestFunction digest.Function) error {
+// are missing are removed from the output path. Progress is reported
+// through the provided buildPreparation.
+func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context, rootDirectory virtual.PrepopulatedDirectory, digestFunction digest.Function, outputBaseID path.Component, preparation *buildPreparation, contentCounters *outputPathContentCounters) error {
+	if outputPathsConcurrency := d.configuration.FindMissing.OutputPathsConcurrency; outputPathsConcurrency != nil {
+		d.queuedStartBuildsCount.Add(1)
+		err := outputPathsConcurrency.Acquire(ctx, 1)
+		d.queuedStartBuildsCount.Add(-1)
+		if err != nil {
+			return util.StatusWrap(util.StatusFromContext(ctx), "Failed to wait for other builds to finish checking the existence of their outputs")
+		}
+		defer outputPathsConcurrency.Release(1)
+	}
+	d.filteringOutputPathsCount.Add(1)
+	defer d.filteringOutputPathsCount.Add(-1)
+
+	metrics := d.metrics.newFindMissingMetrics(outputBaseID)
+
+	// Batches of digests are processed in the background, so that
+	// traversal of the output path and calls to FindMissingBlobs()
+	// can overlap. Calls to removal functions are serialized using
+	// removeLock.
+	concurrency := d.configuration.FindMissing.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	batchSize := d.configuration.FindMissing.BatchSize
+	if batchSize <= 0 {
+		batchSize = blobstore.RecommendedFindMissingDigestsCount
+	} else if batchSize > maximumFindMissingBatchSize {
+		batchSize = maximumFindMissingBatchSize
+	}
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+	var removeLock sync.Mutex
+	var findMissingFailed atomic.Bool
+	flushQueue := func(queue map[digest.Digest][]func() error) {
+		group.Go(func() error {
+			if err := d.findMissingAndRemove(ctx, queue, metrics, preparation, &removeLock); err != nil {
+				findMissingFailed.Store(true)
+				return err
+			}
+			return nil
+		})
+	}
+
 	queue := map[digest.Digest][]func() error{}
 	var savedErr error
 	if err := rootDirectory.FilterChildren(func(node virtual.InitialNode, removeFunc virtual.ChildRemover) bool {
+		// Stop traversal if processing of one of the previous
+		// batches failed, or if the client is no longer
+		// interested in the results.
+		if findMissingFailed.Load() {
+			return false
+		}
+		if savedErr = util.StatusFromContext(ctx); savedErr != nil {
+			return false
+		}
+		preparation.childrenScanned.Add(1)
+
 		// Obtain the transitive closure of digests on which
 		// this file or directory depends.
 		var digests digest.Set
-		if directory, leaf := node.GetPair(); leaf != nil {
+		directory, leaf := node.GetPair()
+
+		// Keep the estimated contents of the output path up to
+		// date when removing the file or directory. Removal
+		// may be attempted for every digest that is missing.
+		removeChild := removeFunc
+		removed := false
+		removeFunc = func() error {
+			if err := removeChild(); err != nil {
+				return err
+			}
+			if !removed {
+				removed = true
+				if leaf == nil {
+					if fetcher, ok := directory.(*memoryChargingInitialContentsFetcher); ok {
+						fetcher.charge.release()
+					}
+					contentCounters.removeDirectory()
+				} else {
+					contentCounters.removeFile(getDigestsSizeBytes(digests))
+				}
+			}
+			return nil
+		}
+
+		if leaf != nil {
 			digests = leaf.GetContainingDigests()
 		} else if digests, savedErr = directory.GetContainingDigests(ctx); savedErr != nil {
 			// Can't compute the set of digests underneath
@@ -193,10 +1784,22 @@ func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context
 			// entirely.
 			if status.Code(savedErr) == codes.NotFound {
 				savedErr = nil
-				if err := removeFunc(); err != nil {
+				if preparation.retainMissingChildren {
+					preparation.events.append(BuildEvent{
+						Type:   BuildEventBlobMissing,
+						Digest: digest.BadDigest,
+					})
+					return true
+				}
+				removeLock.Lock()
+				err := removeFunc()
+				removeLock.Unlock()
+				if err != nil {
 					savedErr = util.StatusWrap(err, "Failed to remove non-existent directory")
 					return false
 				}
+				metrics.childrenRemoved.Inc()
+				preparation.childRemoved(digest.BadDigest)
 				return true
 			}
 			return false
@@ -209,51 +1812,162 @@ func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context
 		// reupload them with a different hash. This may be
 		// slower than requiring a rebuild.
 		for _, blobDigest := range digests.Items() {
-			if !blobDigest.UsesDigestFunction(digestFunction) {
-				if err := removeFunc(); err != nil {
+			if !d.usesEquivalentDigestFunction(blobDigest, digestFunction) {
+				if preparation.retainMissingChildren {
+					// Not checked for existence, as
+					// doing so would require a
+					// different CAS.
+					return true
+				}
+				removeLock.Lock()
+				err := removeFunc()
+				removeLock.Unlock()
+				if err != nil {
 					savedErr = util.StatusWrapf(err, "Failed to remove file with different instance name or digest function with digest %#v", blobDigest.String())
 					return false
 				}
+				metrics.childrenRemoved.Inc()
+				preparation.childRemoved(blobDigest)
 				return true
 			}
 		}
 
 		for _, blobDigest := range digests.Items() {
-			if len(queue) >= blobstore.RecommendedFindMissingDigestsCount {
+			if len(queue) >= batchSize {
 				// Maximum number of digests reached.
-				savedErr = d.findMissingAndRemove(ctx, queue)
-				if savedErr != nil {
-					return false
-				}
+				flushQueue(queue)
 				queue = map[digest.Digest][]func() error{}
 			}
 			queue[blobDigest] = append(queue[blobDigest], removeFunc)
+			metrics.digestsQueued.Inc()
 		}
 		return true
 	}); err != nil {
+		group.Wait()
 		return err
 	}
 	if savedErr != nil {
+		group.Wait()
 		return savedErr
 	}
 
-	// Process the final batch of files.
-	if len(queue) > 0 {
-		return d.findMissingAndRemove(ctx, queue)
+	// Process the final batch of files, unless processing of one
+	// of the previous batches failed.
+	if len(queue) > 0 && !findMissingFailed.Load() {
+		if err := util.StatusFromContext(ctx); err != nil {
+			group.Wait()
+			return err
+		}
+		flushQueue(queue)
 	}
-	return nil
+	return group.Wait()
 }
 
 // StartBuild is called by a build client to indicate that a new build
 // in a given output base is starting.
-func (d *RemoteOutputServiceDirectory) StartBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+func (d *RemoteOutputServiceDirectory) StartBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (_ *remoteoutputservice.StartBuildResponse, err error) {
+	accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+		Method:       "StartBuild",
+		BuildID:      request.BuildId,
+		OutputBaseID: request.OutputBaseId,
+	}, nil)
+	defer func() { accessLogRecord.finish(err) }()
+
+	return d.startBuild(ctx, request, false, nil, false)
+}
+
+// StartBuildAsynchronously is identical to StartBuild(), except that
+// it returns before the output path has been prepared for the build.
+// Files and directories that are absent from the Content Addressable
+// Storage are removed in the background. Calls to BatchCreate() and
+// BatchStat() block until this has completed. Progress can be
+// observed by calling GetBuildStatus().
+//
+// As this changes the contract of StartBuild(), this should only be
+// used by clients that are known to support it.
+func (d *RemoteOutputServiceDirectory) StartBuildAsynchronously(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return d.startBuild(ctx, request, true, nil, false)
+}
+
+// StartBuildFromSnapshot is identical to StartBuild(), except that the
+// contents of the output path are replaced with those of a REv2 Tree
+// object before the build starts. This allows resumed builds to start
+// off with a known baseline, without needing to call BatchCreate() to
+// recreate all outputs. The Tree object may, for example, have been
+// obtained by calling GetOutputPathTree() or
+// GetInitialOutputPathContents().
+//
+// Directories contained in the Tree object are loaded lazily. The
+// contents of the output path are filtered afterwards, meaning that
+// files and directories that are absent from the Content Addressable
+// Storage are removed, just like for regular builds.
+func (d *RemoteOutputServiceDirectory) StartBuildFromSnapshot(ctx context.Context, request *remoteoutputservice.StartBuildRequest, snapshotDigest *remoteexecution.Digest) (*remoteoutputservice.StartBuildResponse, error) {
+	return d.startBuild(ctx, request, false, snapshotDigest, false)
+}
+
+// StartBuildForcingReset is identical to StartBuild(), except that the
+// build is permitted to use an instance name or digest function that
+// differs from the one used by the previous build of the output base,
+// even if PinDigestFunctionPerOutputBase is set. As with regular
+// builds, this causes all existing contents of the output path to be
+// removed.
+func (d *RemoteOutputServiceDirectory) StartBuildForcingReset(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return d.startBuild(ctx, request, false, nil, true)
+}
+
+// StartBuildWithDigestFunctions is identical to StartBuild(), except
+// that the client provides a list of digest functions it is able to
+// use, in order of preference. The build is started using the first
+// digest function that is supported, which is returned to the client.
+// The digest function contained in the request is ignored. This
+// permits clients to interoperate with clusters that don't support the
+// digest function they would use by default.
+func (d *RemoteOutputServiceDirectory) StartBuildWithDigestFunctions(ctx context.Context, request *remoteoutputservice.StartBuildRequest, digestFunctions []remoteexecution.DigestFunction_Value) (*remoteoutputservice.StartBuildResponse, remoteexecution.DigestFunction_Value, error) {
+	instanceName, err := digest.NewInstanceName(request.InstanceName)
+	if err != nil {
+		return nil, remoteexecution.DigestFunction_UNKNOWN, util.StatusWrapf(err, "Failed to parse instance name %#v", request.InstanceName)
+	}
+	for _, digestFunction := range digestFunctions {
+		if _, err := instanceName.GetDigestFunction(digestFunction, 0); err == nil {
+			negotiatedRequest := proto.Clone(request).(*remoteoutputservice.StartBuildRequest)
+			negotiatedRequest.DigestFunction = digestFunction
+			response, err := d.startBuild(ctx, negotiatedRequest, false, nil, false)
+			if err != nil {
+				return nil, remoteexecution.DigestFunction_UNKNOWN, err
+			}
+			return response, digestFunction, nil
+		}
+	}
+	return nil, remoteexecution.DigestFunction_UNKNOWN, status.Errorf(codes.InvalidArgument, "None of the %d provided digest functions are supported", len(digestFunctions))
+}
+
+func (d *RemoteOutputServiceDirectory) startBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest, asynchronous bool, snapshotDigest *remoteexecution.Digest, forceReset bool) (_ *remoteoutputservice.StartBuildResponse, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.StartBuild", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+		attribute.String("output_base_id", request.OutputBaseId),
+		attribute.String("instance_name", request.InstanceName),
+		attribute.String("digest_function", request.DigestFunction.String()),
+		attribute.Bool("asynchronous", asynchronous),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if err := d.checkWritable(); err != nil {
+		return nil, err
+	}
+
 	// Compute the full output path and the output path suffix. The
 	// former needs to be used by us, while the latter is
-	// communicated back to the client.
+	// communicated back to the client. Resolving the output path
+	// prefix normalizes it, meaning that redundant slashes and "."
+	// components are removed.
+	if !strings.HasPrefix(request.OutputPathPrefix, "/") {
+		return nil, status.Errorf(codes.InvalidArgument, "Output path prefix %#v is not an absolute path", request.OutputPathPrefix)
+	}
 	outputPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
 	if err := path.Resolve(request.OutputPathPrefix, scopeWalker); err != nil {
 		return nil, util.StatusWrap(err, "Failed to resolve output path prefix")
 	}
+	outputPathPrefix := outputPath.String()
 	outputPathSuffix, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
 	outputPath, scopeWalker = outputPath.Join(scopeWalker)
 	outputBaseID, ok := path.NewComponent(request.OutputBaseId)
@@ -271,7 +1985,8 @@ func (d *RemoteOutputServiceDirectory) StartBuild(ctx context.Context, request *
 	// Create a virtual root based on the output path and provided
 	// aliases. This will be used to properly resolve targets of
 	// symbolic links stored in the output path.
-	scopeWalkerFactory, err := path.NewVirtualRootScopeWalkerFactory(outputPath.String(), request.OutputPathAliases)
+	outputBasePath := outputPath.String()
+	scopeWalkerFactory, err := path.NewVirtualRootScopeWalkerFactory(outputBasePath, request.OutputPathAliases)
 	if err != nil {
 		return nil, err
 	}
@@ -280,447 +1995,4157 @@ func (d *RemoteOutputServiceDirectory) StartBuild(ctx context.Context, request *
 	if err != nil {
 		return nil, util.StatusWrapf(err, "Failed to parse instance name %#v", request.InstanceName)
 	}
+	// Don't provide an expected hash length, so that the digest
+	// function cannot be inferred. This causes requests that leave
+	// the digest function unset to be rejected, as opposed to
+	// silently picking a digest function that may not match the one
+	// used by the client.
 	digestFunction, err := instanceName.GetDigestFunction(request.DigestFunction, 0)
 	if err != nil {
 		return nil, err
 	}
+	seedDigest := digest.BadDigest
+	if snapshotDigest != nil {
+		seedDigest, err = digestFunction.NewDigestFromProto(snapshotDigest)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Invalid snapshot digest")
+		}
+	}
+
+	if err := d.discardCorruptedOutputPath(outputBaseID); err != nil {
+		return nil, err
+	}
+
+	response := &remoteoutputservice.StartBuildResponse{
+		// TODO: Fill in InitialOutputPathContents, so that the
+		// client can skip parts of its analysis. The protocol
+		// only permits returning the ID of a previous build, as
+		// opposed to the digest of the snapshot created by
+		// prepareOutputPath(). Use
+		// GetInitialOutputPathContents() for the latter.
+		OutputPathSuffix: outputPathSuffix.String(),
+	}
+
+	if gracePeriod := d.configuration.Builds.ConcurrentBuildGracePeriod; gracePeriod > 0 && !d.configuration.Builds.RejectConcurrentBuilds {
+		d.queuedStartBuildsCount.Add(1)
+		err := d.waitForConcurrentBuild(ctx, outputBaseID, request.BuildId, gracePeriod)
+		d.queuedStartBuildsCount.Add(-1)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	d.lock.Lock()
 	state, ok := d.buildIDs[request.BuildId]
-	if !ok {
-		state, ok = d.outputBaseIDs[outputBaseID]
+	if ok {
+		if existingPreparation := state.buildState.preparation; !existingPreparation.failed() {
+			// StartBuild() was called again for a build
+			// whose output path is still being prepared, or
+			// was prepared successfully. Reuse the results of
+			// the original call, so that the output path isn't
+			// scanned multiple times concurrently.
+			d.lock.Unlock()
+			if !asynchronous {
+				if err := existingPreparation.wait(ctx); err != nil {
+					return nil, err
+				}
+			}
+			return response, nil
+		}
+	} else {
+		if d.shuttingDown {
+			d.lock.Unlock()
+			return nil, getShuttingDownError()
+		}
+		state, ok = d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
 		if ok {
+			if state.outputBaseID != outputBaseID {
+				// Output base IDs are case insensitive,
+				// and another output base exists whose ID
+				// only differs in case.
+				d.lock.Unlock()
+				return nil, status.Errorf(codes.AlreadyExists, "Output base ID collides with that of existing output base %#v", state.outputBaseID.String())
+			}
+			if !forceReset {
+				// Switching to a different instance name
+				// or digest function causes all contents
+				// of the output path to be removed. This
+				// is likely a bug in the client.
+				if err := d.checkPinnedDigestFunction(state, digestFunction); err != nil {
+					d.lock.Unlock()
+					return nil, err
+				}
+			}
 			if buildState := state.buildState; buildState != nil {
 				// A previous build is running that wasn't
-				// finalized properly. Forcefully finalize it.
+				// finalized properly. Forcefully finalize it,
+				// unless builds are expected to overlap
+				// legitimately.
+				if d.configuration.Builds.RejectConcurrentBuilds {
+					d.lock.Unlock()
+					return nil, status.Errorf(codes.AlreadyExists, "Build %#v is still running against this output base", buildState.id)
+				}
 				delete(d.buildIDs, buildState.id)
 				state.buildState = nil
+				buildState.events.close()
 			}
 		} else {
 			// No previous builds have been run for this
 			// output base. Create a new output path.
-			//
-			// TODO: This should not use DefaultErrorLogger.
-			// Instead, we should capture errors, so that we
-			// can propagate them back to the build client.
-			// This allows the client to retry, or at least
-			// display the error immediately, so that users
-			// don't need to check logs.
-			errorLogger := util.DefaultErrorLogger
-			casFileFactory := virtual.NewStatelessHandleAllocatingCASFileFactory(
-				virtual.NewBlobAccessCASFileFactory(
-					context.Background(),
-					d.retryingContentAddressableStorage,
-					errorLogger),
-				d.handleAllocator.New())
-			state = &outputPathState{
-				rootDirectory:  d.outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, errorLogger),
-				casFileFactory: casFileFactory,
-
-				previous:     d.outputPaths.previous,
-				next:         &d.outputPaths,
-				cookie:       d.changeID,
-				outputBaseID: outputBaseID,
-			}
-			d.outputBaseIDs[outputBaseID] = state
-			state.previous.next = state
-			state.next.previous = state
-			d.changeID++
+			state = d.createOutputPath(outputBaseID, digestFunction)
+		}
+		if d.configuration.Builds.FreezeOutputPathsBetweenBuilds {
+			state.rootDirectory.Unfreeze()
 		}
+		state.digestFunction = digestFunction
 
 		// Allow BatchCreate() and BatchStat() requests for the
-		// new build ID.
+		// new build ID. Errors that occurred during previous
+		// builds should not be reported to this build.
+		state.errorLogger.reset()
 		state.buildState = &buildState{
-			id:                 request.BuildId,
-			digestFunction:     digestFunction,
-			scopeWalkerFactory: scopeWalkerFactory,
+			id:                    request.BuildId,
+			digestFunction:        digestFunction,
+			scopeWalkerFactory:    scopeWalkerFactory,
+			outputPathPrefix:      outputPathPrefix,
+			outputBasePath:        outputBasePath,
+			initialContentsDigest: digest.BadDigest,
+			initialStatistics:     state.statistics.get(),
+			events:                newBuildEventLog(),
 		}
 		d.buildIDs[request.BuildId] = state
 	}
+	d.markAccessed(state)
+	state.lastBuildStartTime = state.lastAccessTime
+	buildState := state.buildState
+	preparation := newBuildPreparation(buildState.events)
+	preparation.removedChildren = &buildState.removedChildren
+	buildState.preparation = preparation
+	warmup := state.preparedWarmup
+	state.preparedWarmup = nil
 	d.lock.Unlock()
 
-	// Call ContentAddressableStorage.FindMissingBlobs() on all of
-	// the files and tree objects contained within the output path,
-	// so that we have the certainty that they don't disappear
-	// during the build. Remove all of the files and directories
-	// that are missing, so that the client can detect their absence
-	// and rebuild them.
-	if err := d.filterMissingChildren(ctx, state.rootDirectory, digestFunction); err != nil {
-		return nil, util.StatusWrap(err, "Failed to filter contents of the output path")
+	if warmup != nil && (warmup.digestFunction != digestFunction || seedDigest != digest.BadDigest) {
+		// The output path was prepared for a different digest
+		// function, or its contents are about to be replaced.
+		warmup = nil
 	}
 
-	return &remoteoutputservice.StartBuildResponse{
-		// TODO: Fill in InitialOutputPathContents, so that the
-		// client can skip parts of its analysis. The easiest
-		// way to achieve this would be to freeze the contents
-		// of the output path between builds.
-		OutputPathSuffix: outputPathSuffix.String(),
-	}, nil
-}
-
-// getOutputPathAndBuildState returns the state objects associated with
-// a given build ID. This function is used by all gRPC methods that can
-// only be invoked as part of a build (e.g., BatchCreate(), BatchStat()).
-func (d *RemoteOutputServiceDirectory) getOutputPathAndBuildState(buildID string) (*outputPathState, *buildState, error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+	if asynchronous {
+		// The context of the request is canceled once this
+		// function returns, so it cannot be used.
+		go func() {
+			err := d.prepareOutputPath(context.Background(), state, request.BuildId, digestFunction, outputBaseID, seedDigest, preparation, warmup)
+			preparation.finish(err)
+			if err == nil {
+				d.startOutputPathRevalidation(state, buildState, outputBaseID)
+			}
+		}()
+		return response, nil
+	}
 
-	outputPathState, ok := d.buildIDs[buildID]
-	if !ok {
-		return nil, nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	err = d.prepareOutputPath(ctx, state, request.BuildId, digestFunction, outputBaseID, seedDigest, preparation, warmup)
+	preparation.finish(err)
+	if err != nil {
+		return nil, err
 	}
-	return outputPathState, outputPathState.buildState, nil
+	d.startOutputPathRevalidation(state, buildState, outputBaseID)
+	return response, nil
 }
 
-// directoryCreatingComponentWalker is an implementation of
-// ComponentWalker that is used by BatchCreate() to resolve the path
-// prefix under which all provided files, symbolic links and directories
-// should be created.
-//
-// This resolver forcefully creates all intermediate pathname
-// components, removing any non-directories that are in the way.
-type directoryCreatingComponentWalker struct {
-	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
+// checkPinnedDigestFunction returns an error if
+// PinDigestFunctionPerOutputBase is set, and an output path was last
+// built using a different instance name or digest function. This
+// method must be called with the directory lock held.
+func (d *RemoteOutputServiceDirectory) checkPinnedDigestFunction(state *outputPathState, digestFunction digest.Function) error {
+	if previousDigestFunction := state.digestFunction; d.configuration.Builds.PinDigestFunctionPerOutputBase && !d.areEquivalentDigestFunctions(previousDigestFunction, digestFunction) {
+		return status.Errorf(
+			codes.FailedPrecondition,
+			"Output base was last built using instance name %#v and digest function %s, while this build uses instance name %#v and digest function %s",
+			previousDigestFunction.GetInstanceName().String(),
+			previousDigestFunction.GetEnumValue(),
+			digestFunction.GetInstanceName().String(),
+			digestFunction.GetEnumValue())
+	}
+	return nil
 }
 
-func (cw *directoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
-	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(name)
-	if err != nil {
-		return nil, err
+// resolveInstanceNameAlias returns the instance name to which an
+// instance name refers, taking InstanceNameAliases into account.
+func (d *RemoteOutputServiceDirectory) resolveInstanceNameAlias(instanceName digest.InstanceName) string {
+	if target, ok := d.configuration.Builds.InstanceNameAliases[instanceName.String()]; ok {
+		return target
 	}
-	cw.stack.Push(child)
-	return path.GotDirectory{
-		Child:        cw,
-		IsReversible: true,
-	}, nil
+	return instanceName.String()
 }
 
-func (cw *directoryCreatingComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
-	return path.OnTerminalViaOnDirectory(cw, name)
+// areEquivalentDigestFunctions returns whether files stored in an
+// output path using one digest function remain valid when a build is
+// started using another digest function. This is the case if they use
+// the same hashing algorithm and their instance names are identical,
+// or refer to the same instance name.
+func (d *RemoteOutputServiceDirectory) areEquivalentDigestFunctions(a, b digest.Function) bool {
+	return a == b || (a.GetEnumValue() == b.GetEnumValue() &&
+		d.resolveInstanceNameAlias(a.GetInstanceName()) == d.resolveInstanceNameAlias(b.GetInstanceName()))
 }
 
-func (cw *directoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
+// usesEquivalentDigestFunction is identical to
+// Digest.UsesDigestFunction(), except that digests whose instance name
+// refers to the same instance name as the digest function are also
+// accepted.
+func (d *RemoteOutputServiceDirectory) usesEquivalentDigestFunction(blobDigest digest.Digest, digestFunction digest.Function) bool {
+	if blobDigest.UsesDigestFunction(digestFunction) {
+		return true
+	}
+	blobInstanceName := blobDigest.GetInstanceName()
+	if d.resolveInstanceNameAlias(blobInstanceName) != d.resolveInstanceNameAlias(digestFunction.GetInstanceName()) {
+		return false
+	}
+	aliasedDigestFunction, err := blobInstanceName.GetDigestFunction(digestFunction.GetEnumValue(), 0)
+	return err == nil && blobDigest.UsesDigestFunction(aliasedDigestFunction)
+}
+
+// PrepareOutputBase can be called ahead of StartBuild() to prepare the
+// output path of an output base for an upcoming build. It creates the
+// output path if it does not exist yet, and removes files and
+// directories that are absent from the Content Addressable Storage. If
+// StartBuild() is called using the same instance name and digest
+// function within PreparedOutputBaseTimeout, it does not need to
+// perform this work, allowing it to return quickly.
+//
+// If StartBuild() is called while preparation is still in progress, it
+// waits for preparation to complete. Once expired, StartBuild()
+// prepares the output path as usual.
+func (d *RemoteOutputServiceDirectory) PrepareOutputBase(ctx context.Context, outputBaseID, instanceName string, digestFunction remoteexecution.DigestFunction_Value) (err error) {
+	accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+		Method:       "PrepareOutputBase",
+		OutputBaseID: outputBaseID,
+	}, nil)
+	defer func() { accessLogRecord.finish(err) }()
+
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.PrepareOutputBase", trace.WithAttributes(
+		attribute.String("output_base_id", outputBaseID),
+		attribute.String("instance_name", instanceName),
+		attribute.String("digest_function", digestFunction.String()),
+	))
+	defer func() { endSpan(span, err) }()
+
+	timeout := d.configuration.Builds.PreparedOutputBaseTimeout
+	if timeout <= 0 {
+		return status.Error(codes.Unimplemented, "Preparing output bases ahead of builds is not enabled")
+	}
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+
+	outputBaseIDComponent, ok := path.NewComponent(outputBaseID)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
+	}
+	parsedInstanceName, err := digest.NewInstanceName(instanceName)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to parse instance name %#v", instanceName)
+	}
+	parsedDigestFunction, err := parsedInstanceName.GetDigestFunction(digestFunction, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := d.discardCorruptedOutputPath(outputBaseIDComponent); err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	if d.shuttingDown {
+		d.lock.Unlock()
+		return getShuttingDownError()
+	}
+	state, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseIDComponent)]
+	if ok {
+		if state.outputBaseID != outputBaseIDComponent {
+			d.lock.Unlock()
+			return status.Errorf(codes.AlreadyExists, "Output base ID collides with that of existing output base %#v", state.outputBaseID.String())
+		}
+		if buildState := state.buildState; buildState != nil {
+			// Filtering the contents of the output path
+			// would interfere with the running build.
+			d.lock.Unlock()
+			return status.Errorf(codes.FailedPrecondition, "Build %#v is still running against this output base", buildState.id)
+		}
+		if err := d.checkPinnedDigestFunction(state, parsedDigestFunction); err != nil {
+			d.lock.Unlock()
+			return err
+		}
+		if existingWarmup := state.preparedWarmup; existingWarmup != nil && existingWarmup.digestFunction == parsedDigestFunction {
+			select {
+			case <-existingWarmup.preparation.done:
+			default:
+				// The output path is already being
+				// prepared. Wait for it to complete.
+				d.lock.Unlock()
+				return existingWarmup.preparation.wait(ctx)
+			}
+		}
+	} else {
+		state = d.createOutputPath(outputBaseIDComponent, parsedDigestFunction)
+	}
+	warmup := &outputPathWarmup{
+		digestFunction: parsedDigestFunction,
+		preparation:    newBuildPreparation(newBuildEventLog()),
+	}
+	state.preparedWarmup = warmup
+	d.lock.Unlock()
+
+	state.trimmableDirectories.clear()
+	err = d.filterMissingChildren(ctx, state.rootDirectory, parsedDigestFunction, outputBaseIDComponent, warmup.preparation, &state.contentCounters)
+	state.markModified()
+	if err != nil {
+		d.detectCorruption(state, err)
+		warmup.preparation.finish(err)
+		return util.StatusWrap(err, "Failed to filter contents of the output path")
+	}
+	warmup.expirationTime = d.clock.Now().Add(timeout)
+	warmup.preparation.finish(nil)
+	return nil
+}
+
+// waitForConcurrentBuild waits for a build that is running against an
+// output base to be finalized, so that StartBuild() does not need to
+// finalize it forcefully. This gives builds that are slow to finalize
+// an opportunity to complete. If another build is started against the
+// output base while waiting, it is finalized forcefully.
+func (d *RemoteOutputServiceDirectory) waitForConcurrentBuild(ctx context.Context, outputBaseID path.Component, buildID string, gracePeriod time.Duration) error {
+	d.lock.Lock()
+	state, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
+	if !ok || state.outputBaseID != outputBaseID || state.buildState == nil || state.buildState.id == buildID {
+		d.lock.Unlock()
+		return nil
+	}
+	runningBuildID := state.buildState.id
+	done := state.buildState.events.done
+	d.lock.Unlock()
+
+	timer, t := d.clock.NewTimer(gracePeriod)
+	select {
+	case <-done:
+		timer.Stop()
+		return nil
+	case <-t:
+		return status.Errorf(codes.Unavailable, "Build %#v is still running against this output base, and was not finalized within %s", runningBuildID, gracePeriod)
+	case <-ctx.Done():
+		timer.Stop()
+		return util.StatusFromContext(ctx)
+	}
+}
+
+// prepareOutputPath is called by StartBuild() to ensure that the
+// contents of the output path can be used by the build, and to
+// optionally create a snapshot of it. If a seed digest is provided, the
+// contents of the output path are first replaced with those of the
+// snapshot.
+//
+// If the output path was prepared through PrepareOutputBase() and the
+// results have not expired, the contents of the output path are not
+// filtered.
+func (d *RemoteOutputServiceDirectory) prepareOutputPath(ctx context.Context, state *outputPathState, buildID string, digestFunction digest.Function, outputBaseID path.Component, seedDigest digest.Digest, preparation *buildPreparation, warmup *outputPathWarmup) error {
+	if seedDigest != digest.BadDigest {
+		state.contentsLock.Lock()
+		err := d.replaceOutputPathContents(ctx, state, digestFunction, seedDigest)
+		state.contentsLock.Unlock()
+		if err != nil {
+			d.detectCorruption(state, err)
+			return util.StatusWrap(err, "Failed to populate the output path from the snapshot")
+		}
+		preparation.events.append(BuildEvent{
+			Type: BuildEventDirectoryChanged,
+			Path: ".",
+		})
+	}
+
+	// Call ContentAddressableStorage.FindMissingBlobs() on all of
+	// the files and tree objects contained within the output path,
+	// so that we have the certainty that they don't disappear
+	// during the build. Remove all of the files and directories
+	// that are missing, so that the client can detect their absence
+	// and rebuild them.
+	if !d.isWarmupValid(ctx, warmup) {
+		state.trimmableDirectories.clear()
+		err := d.filterMissingChildren(ctx, state.rootDirectory, digestFunction, outputBaseID, preparation, &state.contentCounters)
+		state.markModified()
+		if err != nil {
+			d.detectCorruption(state, err)
+			return util.StatusWrap(err, "Failed to filter contents of the output path")
+		}
+	}
+
+	// If enabled, store a snapshot of the output path in the CAS.
+	// The output path is frozen while doing so, so that the
+	// snapshot isn't affected by concurrent calls to BatchCreate()
+	// belonging to a previous build.
+	if concurrency := d.configuration.Snapshots.UploadConcurrency; concurrency != nil {
+		ctxWithSpan, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.UploadOutputPathTree")
+		state.contentsLock.Lock()
+		initialContentsDigest, err := UploadOutputPathTree(ctxWithSpan, state.rootDirectory, d.contentAddressableStorage.Uploads, digestFunction, concurrency)
+		state.contentsLock.Unlock()
+		endSpan(span, err)
+		if err != nil {
+			d.detectCorruption(state, err)
+			return util.StatusWrap(err, "Failed to create snapshot of the output path")
+		}
+
+		d.lock.Lock()
+		if buildState := state.buildState; buildState != nil && buildState.id == buildID {
+			buildState.initialContentsDigest = initialContentsDigest
+		}
+		d.lock.Unlock()
+	}
+	return nil
+}
+
+// isWarmupValid returns whether the results of PrepareOutputBase() may
+// be used by a build, instead of filtering the contents of the output
+// path once more. If preparation is still in progress, it waits for it
+// to complete.
+func (d *RemoteOutputServiceDirectory) isWarmupValid(ctx context.Context, warmup *outputPathWarmup) bool {
+	if warmup == nil {
+		return false
+	}
+	select {
+	case <-warmup.preparation.done:
+	case <-ctx.Done():
+		return false
+	}
+	return warmup.preparation.err == nil && d.clock.Now().Before(warmup.expirationTime)
+}
+
+// startOutputPathRevalidation launches a goroutine that periodically
+// checks the contents of the output path for existence in the Content
+// Addressable Storage while a build is running, if
+// FindMissing.RevalidationInterval is set. The goroutine terminates when
+// the build ends.
+func (d *RemoteOutputServiceDirectory) startOutputPathRevalidation(state *outputPathState, buildState *buildState, outputBaseID path.Component) {
+	interval := d.configuration.FindMissing.RevalidationInterval
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		for {
+			timer, t := d.clock.NewTimer(interval)
+			select {
+			case <-t:
+			case <-buildState.events.done:
+				timer.Stop()
+				return
+			}
+			if err := d.revalidateOutputPath(state, buildState, outputBaseID); err != nil {
+				select {
+				case <-buildState.events.done:
+					// Revalidation was interrupted,
+					// because the build ended.
+					return
+				default:
+					state.errorLogger.Log(util.StatusWrap(err, "Failed to revalidate contents of the output path"))
+				}
+			}
+		}
+	}()
+}
+
+// buildContext is a Context that is cancelled as soon as a build ends.
+// It is used by revalidateOutputPath() to ensure that passes over large
+// output paths don't continue after the build has been finalized.
+type buildContext struct {
+	context.Context
+	done <-chan struct{}
+}
+
+func (ctx buildContext) Done() <-chan struct{} {
+	return ctx.done
+}
+
+func (ctx buildContext) Err() error {
+	select {
+	case <-ctx.done:
+		return context.Canceled
+	default:
+		return nil
+	}
+}
+
+// revalidateOutputPath performs a single pass over the contents of the
+// output path of a running build, calling FindMissingBlobs() on all
+// files and tree objects. Missing blobs are recorded, so that they can
+// be reported through GetMissingBlobs(). The pass is interrupted when
+// the build ends.
+func (d *RemoteOutputServiceDirectory) revalidateOutputPath(state *outputPathState, buildState *buildState, outputBaseID path.Component) (err error) {
+	ctx, span := d.tracer.Start(buildContext{
+		Context: context.Background(),
+		done:    buildState.events.done,
+	}, "RemoteOutputServiceDirectory.revalidateOutputPath", trace.WithAttributes(
+		attribute.String("build_id", buildState.id),
+		attribute.String("output_base_id", outputBaseID.String()),
+	))
+	defer func() { endSpan(span, err) }()
+
+	preparation := newBuildPreparation(buildState.events)
+	preparation.missingBlobs = &buildState.missingBlobs
+	preparation.retainMissingChildren = !d.configuration.FindMissing.RemoveChildrenMissingDuringBuild
+
+	// Removing children modifies the output path, meaning that it
+	// must not overlap with the creation of snapshots.
+	state.contentsLock.RLock()
+	defer state.contentsLock.RUnlock()
+	if !preparation.retainMissingChildren {
+		state.trimmableDirectories.clear()
+	}
+	err = d.filterMissingChildren(ctx, state.rootDirectory, buildState.digestFunction, outputBaseID, preparation, &state.contentCounters)
+	if preparation.childrenRemoved.Load() > 0 {
+		state.markModified()
+	}
+	if err != nil {
+		d.detectCorruption(state, err)
+		return err
+	}
+	return nil
+}
+
+// GetMissingBlobs returns the digests of blobs that were found to be
+// missing from the Content Addressable Storage while a build was
+// running. Blobs are only checked for existence while the build is
+// running if FindMissing.RevalidationInterval is set.
+func (d *RemoteOutputServiceDirectory) GetMissingBlobs(buildID string) (digest.Set, error) {
+	_, buildState, err := d.lookupBuild(buildID)
+	if err != nil {
+		return digest.EmptySet, err
+	}
+	return buildState.missingBlobs.get(), nil
+}
+
+// GetExecutableBitChanges returns the paths of files that were
+// replaced through BatchCreate() by files having the same digest, but
+// a different executable bit, as part of the build with a given build
+// ID. Paths are only tracked if TrackExecutableBitChanges is set.
+func (d *RemoteOutputServiceDirectory) GetExecutableBitChanges(buildID string) ([]string, error) {
+	_, buildState, err := d.lookupBuild(buildID)
+	if err != nil {
+		return nil, err
+	}
+	buildState.executableBitChangesLock.Lock()
+	defer buildState.executableBitChangesLock.Unlock()
+	return append([]string(nil), buildState.executableBitChanges...), nil
+}
+
+// GetInitialOutputPathContents returns the digest of a Tree object that
+// contains the contents of the output path at the time the build with
+// a given build ID was started. This can be used by clients to compute
+// which files changed, instead of inspecting the output path through
+// the virtual file system. Snapshots are only created if
+// Snapshots.UploadConcurrency is set.
+func (d *RemoteOutputServiceDirectory) GetInitialOutputPathContents(buildID string) (digest.Digest, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	outputPathState, ok := d.buildIDs[buildID]
+	if !ok {
+		return digest.BadDigest, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	initialContentsDigest := outputPathState.buildState.initialContentsDigest
+	if initialContentsDigest == digest.BadDigest {
+		return digest.BadDigest, status.Error(codes.NotFound, "No snapshot of the output path was created at the start of the build")
+	}
+	return initialContentsDigest, nil
+}
+
+// GetOutputPathTree converts the current contents of an output path
+// to a REv2 Tree object, stores it in the Content Addressable Storage,
+// and returns its digest. Unlike GetInitialOutputPathContents(), this
+// may be called at any point in time, which makes it useful for
+// debugging and for handing off the output path to other tools.
+//
+// If a build is running against the output path, calls to
+// BatchCreate() are blocked while the Tree object is being created.
+// This ensures that the Tree object reflects a consistent state of the
+// output path, even though it may not contain all outputs of the build.
+func (d *RemoteOutputServiceDirectory) GetOutputPathTree(ctx context.Context, outputBaseID string) (_ digest.Digest, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.GetOutputPathTree", trace.WithAttributes(
+		attribute.String("output_base_id", outputBaseID),
+	))
+	defer func() { endSpan(span, err) }()
+
+	outputBaseIDComponent, ok := path.NewComponent(outputBaseID)
+	if !ok {
+		return digest.BadDigest, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseIDComponent)]
+	if !ok {
+		d.lock.Unlock()
+		return digest.BadDigest, status.Errorf(codes.NotFound, "Output path %#v does not exist", outputBaseID)
+	}
+	if outputPathState.corrupted {
+		d.lock.Unlock()
+		return digest.BadDigest, getCorruptedOutputPathError(outputBaseIDComponent)
+	}
+	digestFunction := outputPathState.digestFunction
+	d.lock.Unlock()
+
+	outputPathState.contentsLock.Lock()
+	treeDigest, err := UploadOutputPathTree(ctx, outputPathState.rootDirectory, d.contentAddressableStorage.Uploads, digestFunction, d.onDemandSnapshotUploadConcurrency)
+	outputPathState.contentsLock.Unlock()
+	if err != nil {
+		d.detectCorruption(outputPathState, err)
+		return digest.BadDigest, util.StatusWrap(err, "Failed to create snapshot of the output path")
+	}
+	span.SetAttributes(attribute.String("tree_digest", treeDigest.String()))
+	return treeDigest, nil
+}
+
+// BuildPhase indicates how far StartBuild() has progressed preparing
+// the output path for a build.
+type BuildPhase int
+
+const (
+	// BuildPhaseFiltering indicates that files and directories
+	// that are absent from the Content Addressable Storage are
+	// still being removed from the output path.
+	BuildPhaseFiltering BuildPhase = iota
+	// BuildPhaseReady indicates that the output path has been
+	// prepared, meaning that BatchCreate() and BatchStat() may be
+	// called without blocking.
+	BuildPhaseReady
+	// BuildPhaseFailed indicates that the output path could not be
+	// prepared.
+	BuildPhaseFailed
+)
+
+// BuildStatus contains the progress of preparing the output path for a
+// build, as returned by GetBuildStatus().
+type BuildStatus struct {
+	Phase BuildPhase
+
+	// The number of files and directories in the output path that
+	// have been checked for existence in the Content Addressable
+	// Storage.
+	ChildrenScannedCount uint64
+
+	// The number of files and directories that have been removed
+	// from the output path, because they were absent from the
+	// Content Addressable Storage.
+	ChildrenRemovedCount uint64
+
+	// The error that caused the output path not to be prepared.
+	// Only set if Phase is BuildPhaseFailed.
+	Error error
+}
+
+// GetBuildStatus returns the progress of preparing the output path for
+// a build. This is primarily of use in combination with
+// StartBuildAsynchronously().
+func (d *RemoteOutputServiceDirectory) GetBuildStatus(buildID string) (BuildStatus, error) {
+	d.lock.Lock()
+	outputPathState, ok := d.buildIDs[buildID]
+	if !ok {
+		d.lock.Unlock()
+		return BuildStatus{}, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	preparation := outputPathState.buildState.preparation
+	d.lock.Unlock()
+
+	buildStatus := BuildStatus{
+		Phase:                BuildPhaseFiltering,
+		ChildrenScannedCount: preparation.childrenScanned.Load(),
+		ChildrenRemovedCount: preparation.childrenRemoved.Load(),
+	}
+	select {
+	case <-preparation.done:
+		if preparation.err == nil {
+			buildStatus.Phase = BuildPhaseReady
+		} else {
+			buildStatus.Phase = BuildPhaseFailed
+			buildStatus.Error = preparation.err
+		}
+	default:
+	}
+	return buildStatus, nil
+}
+
+// BuildEventStream is the subset of a gRPC server streaming server
+// that is used by WatchBuild() to send events.
+type BuildEventStream interface {
+	Context() context.Context
+	Send(*BuildEvent) error
+}
+
+// WatchBuild streams events describing changes to the output path that
+// were not made by the build client itself, such as files that were
+// removed at the start of the build, because they were absent from the
+// Content Addressable Storage. This allows clients that keep their own
+// model of the output path to invalidate it.
+//
+// Events are retained for the duration of the build, meaning that all
+// events are returned, regardless of when this method is called. Only
+// events with a change ID greater than afterChangeID are sent, so that
+// a client can resume watching after disconnecting. The stream
+// terminates successfully once the build is finalized or cancelled.
+//
+// Changes made through the virtual file system are not reported.
+func (d *RemoteOutputServiceDirectory) WatchBuild(buildID string, afterChangeID uint64, stream BuildEventStream) error {
+	d.lock.Lock()
+	outputPathState, ok := d.buildIDs[buildID]
+	if !ok {
+		d.lock.Unlock()
+		return status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	events := outputPathState.buildState.events
+	d.lock.Unlock()
+
+	ctx := stream.Context()
+	for {
+		newEvents, closed, err := events.wait(ctx, afterChangeID)
+		if err != nil {
+			return err
+		}
+		if closed {
+			return nil
+		}
+		for i := range newEvents {
+			if err := stream.Send(&newEvents[i]); err != nil {
+				return util.StatusWrap(err, "Failed to send event")
+			}
+		}
+		afterChangeID = newEvents[len(newEvents)-1].ChangeID
+	}
+}
+
+// WatchOutputPathRemovals streams events describing output paths that
+// are removed, either because Clean() was called or because they were
+// corrupted. This allows removals to be audited. Only output paths
+// that are removed after this method is called are reported. Output
+// bases whose output paths have not been accessed since startup are
+// not reported when cleaned.
+//
+// Events are buffered for every caller. Callers that don't receive
+// events quickly enough are disconnected with RESOURCE_EXHAUSTED. The
+// stream otherwise only terminates when its context is done.
+func (d *RemoteOutputServiceDirectory) WatchOutputPathRemovals(stream OutputPathRemovalEventStream) error {
+	d.lock.Lock()
+	events := d.removalSubscribers.subscribe()
+	d.lock.Unlock()
+	defer func() {
+		d.lock.Lock()
+		d.removalSubscribers.unsubscribe(events)
+		d.lock.Unlock()
+	}()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "Too many events were queued, as they were not received quickly enough")
+			}
+			if err := stream.Send(&event); err != nil {
+				return util.StatusWrap(err, "Failed to send event")
+			}
+		case <-ctx.Done():
+			return util.StatusFromContext(ctx)
+		}
+	}
+}
+
+// GetBuildErrors returns errors that occurred asynchronously while
+// the build with a given build ID was running, such as failures to
+// load the contents of files from the Content Addressable Storage.
+// Such errors may have caused files to be removed from the output
+// path, or reads against files to fail. Returning them allows the build
+// client to display them, without requiring users to inspect the logs
+// of bb_clientd.
+func (d *RemoteOutputServiceDirectory) GetBuildErrors(buildID string) ([]error, error) {
+	d.lock.Lock()
+	outputPathState, ok := d.buildIDs[buildID]
+	d.lock.Unlock()
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	return outputPathState.errorLogger.getErrors(), nil
+}
+
+// getOutputPathAndBuildState returns the state objects associated with
+// a given build ID. This function is used by all gRPC methods that can
+// only be invoked as part of a build (e.g., BatchCreate(), BatchStat()).
+// If StartBuild() is still preparing the output path, this function
+// blocks until it has completed.
+func (d *RemoteOutputServiceDirectory) getOutputPathAndBuildState(ctx context.Context, buildID string) (*outputPathState, *buildState, error) {
+	outputPathState, buildState, err := d.lookupBuild(buildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := buildState.preparation.wait(ctx); err != nil {
+		return nil, nil, err
+	}
+	return outputPathState, buildState, nil
+}
+
+// lookupBuild returns the state objects associated with a given build
+// ID. Unlike getOutputPathAndBuildState(), it does not wait for
+// StartBuild() to finish preparing the output path.
+func (d *RemoteOutputServiceDirectory) lookupBuild(buildID string) (*outputPathState, *buildState, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	outputPathState, ok := d.buildIDs[buildID]
+	if !ok {
+		return nil, nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	if outputPathState.corrupted {
+		return nil, nil, getCorruptedOutputPathError(outputPathState.outputBaseID)
+	}
+	return outputPathState, outputPathState.buildState, nil
+}
+
+// PingBuild can be called by a build client to check whether a build
+// is still running, e.g., after the connection to bb_clientd has been
+// interrupted. It returns FAILED_PRECONDITION if the build ID is not
+// associated with any running build, in which case the client may
+// call StartBuild() again. This method has no side effects, and does
+// not wait for StartBuild() to finish preparing the output path.
+func (d *RemoteOutputServiceDirectory) PingBuild(ctx context.Context, buildID string) error {
+	_, _, err := d.lookupBuild(buildID)
+	return err
+}
+
+// GetOutputBasePath returns the absolute path of the output path of a
+// running build, as resolved by StartBuild(). It corresponds to the
+// output path prefix provided to StartBuild() in normalized form,
+// joined with the output base ID. Clients may use this to pass paths
+// to subprocesses, instead of joining these themselves.
+func (d *RemoteOutputServiceDirectory) GetOutputBasePath(buildID string) (string, error) {
+	_, buildState, err := d.lookupBuild(buildID)
+	if err != nil {
+		return "", err
+	}
+	return buildState.outputBasePath, nil
+}
+
+// directoryCreatingComponentWalker is an implementation of
+// ComponentWalker that is used by BatchCreate() to resolve the path
+// prefix under which all provided files, symbolic links and directories
+// should be created.
+//
+// This resolver forcefully creates all intermediate pathname
+// components, removing any non-directories that are in the way.
+type directoryCreatingComponentWalker struct {
+	stack        util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	nameInterner *nameInterner
+
+	// The number of directories on the stack below the root of
+	// the output path, and the maximum permitted value.
+	depth        int
+	maximumDepth int
+}
+
+func (cw *directoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	if err := checkPathDepth(cw.depth+1, cw.maximumDepth); err != nil {
+		return nil, err
+	}
+	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(cw.nameInterner.internComponent(name))
+	if err != nil {
+		return nil, err
+	}
+	cw.stack.Push(child)
+	cw.depth++
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *directoryCreatingComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	return path.OnTerminalViaOnDirectory(cw, name)
+}
+
+func (cw *directoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
 	if _, ok := cw.stack.PopSingle(); !ok {
 		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
 	}
-	return cw, nil
-}
-
-func (cw *directoryCreatingComponentWalker) createChild(outputPath string, initialNode virtual.InitialNode) error {
-	outputParentCreator := parentDirectoryCreatingComponentWalker{
-		stack: cw.stack.Copy(),
+	cw.depth--
+	return cw, nil
+}
+
+// checkPathDepth returns an error if a path contains more components
+// than permitted by MaximumPathDepth.
+func checkPathDepth(depth, maximumDepth int) error {
+	if maximumDepth > 0 && depth > maximumDepth {
+		return status.Errorf(codes.InvalidArgument, "Path contains more than %d components", maximumDepth)
+	}
+	return nil
+}
+
+func (cw *directoryCreatingComponentWalker) createChild(outputPath string, initialNode virtual.InitialNode) error {
+	_, _, err := cw.createChildInParent(outputPath, initialNode)
+	return err
+}
+
+// createChildInParent is identical to createChild(), except that it
+// returns the directory in which the child was created, and the name
+// of the child.
+func (cw *directoryCreatingComponentWalker) createChildInParent(outputPath string, initialNode virtual.InitialNode) (virtual.PrepopulatedDirectory, path.Component, error) {
+	outputParentCreator := parentDirectoryCreatingComponentWalker{
+		stack:        cw.stack.Copy(),
+		nameInterner: cw.nameInterner,
+		depth:        cw.depth,
+		maximumDepth: cw.maximumDepth,
+	}
+	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&outputParentCreator)); err != nil {
+		return nil, path.Component{}, util.StatusWrap(err, "Failed to resolve path")
+	}
+	name := outputParentCreator.TerminalName
+	if name == nil {
+		return nil, path.Component{}, status.Errorf(codes.InvalidArgument, "Path resolves to a directory")
+	}
+	if err := checkPathDepth(outputParentCreator.depth+1, cw.maximumDepth); err != nil {
+		return nil, path.Component{}, util.StatusWrap(err, "Failed to resolve path")
+	}
+	parent := outputParentCreator.stack.Peek()
+	if err := parent.CreateChildren(
+		map[path.Component]virtual.InitialNode{
+			cw.nameInterner.internComponent(*name): initialNode,
+		},
+		true,
+	); err != nil {
+		// If a node of a different type is already present,
+		// report this in a way that allows clients to recover.
+		if isNodeTypeConflict(err) {
+			requestedNodeType := "directory"
+			if _, leaf := initialNode.GetPair(); leaf != nil {
+				requestedNodeType = getLeafNodeType(leaf)
+			}
+			if existingNodeType, ok := getExistingNodeType(parent, *name); ok && existingNodeType != requestedNodeType {
+				return nil, path.Component{}, newNodeTypeConflictError(outputParentCreator.getPath(*name), existingNodeType, requestedNodeType)
+			}
+		}
+		return nil, path.Component{}, err
+	}
+	return parent, *name, nil
+}
+
+// trackLazyDirectory adds a directory whose contents are loaded lazily
+// to the output path's set of lazy directories, so that
+// BatchStatExistence() does not cause its contents to be loaded.
+func (d *RemoteOutputServiceDirectory) trackLazyDirectory(outputPathState *outputPathState, parent virtual.PrepopulatedDirectory, name path.Component) {
+	if !d.configuration.TrackLazyDirectories {
+		return
+	}
+	// Looking up the child does not cause its contents to be
+	// loaded, as it's contained in the parent directory.
+	if child, err := parent.LookupChild(name); err == nil {
+		if directory, _ := child.GetPair(); directory != nil {
+			outputPathState.lazyDirectories.add(directory)
+		}
+	}
+}
+
+// isNodeTypeConflict returns whether an error returned by
+// PrepopulatedDirectory may have been caused by a node of a different
+// type being present at the location of the node to be created.
+func isNodeTypeConflict(err error) bool {
+	return err == syscall.EEXIST || err == syscall.EISDIR || err == syscall.ENOTDIR || err == syscall.ENOTEMPTY
+}
+
+// getLeafNodeType returns whether a leaf is a regular file or a
+// symbolic link.
+func getLeafNodeType(leaf virtual.NativeLeaf) string {
+	if _, err := leaf.Readlink(); err == syscall.EINVAL {
+		return "file"
+	}
+	return "symlink"
+}
+
+// getExistingNodeType returns the type of the node that is present
+// under a given name in a directory, if any.
+func getExistingNodeType(directory virtual.PrepopulatedDirectory, name path.Component) (string, bool) {
+	child, err := directory.LookupChild(name)
+	if err != nil {
+		return "", false
+	}
+	if _, leaf := child.GetPair(); leaf != nil {
+		return getLeafNodeType(leaf), true
+	}
+	return "directory", true
+}
+
+// newNodeTypeConflictError creates the error that is returned by
+// BatchCreate() when a file, directory or symbolic link cannot be
+// created, because a node of a different type is already present at a
+// given path. The error contains an ErrorInfo detail, so that clients
+// may recover from this without needing to parse the error message.
+func newNodeTypeConflictError(conflictingPath, existingNodeType, requestedNodeType string) error {
+	s, err := status.New(
+		codes.FailedPrecondition,
+		fmt.Sprintf("Cannot create %s at path %#v, as a %s is already present", requestedNodeType, conflictingPath, existingNodeType),
+	).WithDetails(&errdetails.ErrorInfo{
+		Reason: "NODE_TYPE_CONFLICT",
+		Domain: "github.com/buildbarn/bb-clientd",
+		Metadata: map[string]string{
+			"path":                conflictingPath,
+			"existing_node_type":  existingNodeType,
+			"requested_node_type": requestedNodeType,
+		},
+	})
+	if err != nil {
+		return util.StatusWrap(err, "Failed to attach error details")
+	}
+	return s.Err()
+}
+
+// parentDirectoryCreatingComponentWalker is an implementation of
+// ComponentWalker that is used by BatchCreate() to resolve the parent
+// directory of the path where a file, directory or symlink needs to be
+// created.
+type parentDirectoryCreatingComponentWalker struct {
+	path.TerminalNameTrackingComponentWalker
+	stack        util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	nameInterner *nameInterner
+
+	// Components of the path traversed so far, relative to the
+	// path prefix. Used to report conflicting paths in errors.
+	components []string
+
+	// The number of directories on the stack below the root of
+	// the output path, and the maximum permitted value.
+	depth        int
+	maximumDepth int
+}
+
+func (cw *parentDirectoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	if err := checkPathDepth(cw.depth+1, cw.maximumDepth); err != nil {
+		return nil, err
+	}
+	parent := cw.stack.Peek()
+	child, err := parent.CreateAndEnterPrepopulatedDirectory(cw.nameInterner.internComponent(name))
+	if err != nil {
+		if isNodeTypeConflict(err) {
+			if existingNodeType, ok := getExistingNodeType(parent, name); ok && existingNodeType != "directory" {
+				return nil, newNodeTypeConflictError(cw.getPath(name), existingNodeType, "directory")
+			}
+		}
+		return nil, err
+	}
+	cw.stack.Push(child)
+	cw.depth++
+	cw.components = append(cw.components, name.String())
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *parentDirectoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	cw.depth--
+	if l := len(cw.components); l > 0 && cw.components[l-1] != ".." {
+		cw.components = cw.components[:l-1]
+	} else {
+		cw.components = append(cw.components, "..")
+	}
+	return cw, nil
+}
+
+// getPath returns the path of a child of the current directory,
+// relative to the path prefix.
+func (cw *parentDirectoryCreatingComponentWalker) getPath(name path.Component) string {
+	return strings.Join(append(append([]string(nil), cw.components...), name.String()), "/")
+}
+
+// BatchCreate can be called by a build client to create files, symbolic
+// links and directories.
+//
+// Because files and directories are provided in the form of OutputFile
+// and OutputDirectory messages, this implementation is capable of
+// creating files and directories whose contents get loaded from the
+// Content Addressable Storage lazily.
+//
+// Entries replace any files, symbolic links or directories that are
+// already present at the same path. This is done through a single call
+// to CreateChildren() against the parent directory, which holds the
+// parent directory's lock while swapping the old and new entries. When
+// an output directory replaces an existing directory, lookups through
+// the virtual file system thus either observe the old directory or the
+// new directory in their entirety, but never an empty or missing
+// directory, or a mixture of the two. Processes that still hold a
+// handle to the old directory observe it as being removed. This does
+// not hold when clean_path_prefix is set, as the path prefix is then
+// cleaned before any entries are created.
+func (d *RemoteOutputServiceDirectory) BatchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (_ *emptypb.Empty, err error) {
+	if d.configuration.AccessLog != nil {
+		var paths []string
+		for _, entry := range request.Files {
+			paths = append(paths, entry.Path)
+		}
+		for _, entry := range request.Directories {
+			paths = append(paths, entry.Path)
+		}
+		for _, entry := range request.Symlinks {
+			paths = append(paths, entry.Path)
+		}
+		accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+			Method:           "BatchCreate",
+			BuildID:          request.BuildId,
+			FilesCount:       len(request.Files),
+			DirectoriesCount: len(request.Directories),
+			SymlinksCount:    len(request.Symlinks),
+			PathsCount:       len(paths),
+		}, paths)
+		defer func() { accessLogRecord.finish(err) }()
+	}
+
+	if err := d.batchCreate(ctx, request, nil, nil); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// BatchCreateWithIdempotencyToken is identical to BatchCreate(), except
+// that the client provides a token that uniquely identifies the
+// request. If a request having the same token was already processed
+// successfully as part of the same build, it is not executed again.
+// This permits clients to retry requests after network failures,
+// without causing files created in the meantime to be removed due to
+// clean_path_prefix being set. If a request having the same token is
+// still being processed, this method waits for it to complete and
+// returns its result.
+//
+// Results of failed requests are not retained, meaning that they are
+// executed again when retried. Clients must not use the same token
+// for requests having different contents.
+func (d *RemoteOutputServiceDirectory) BatchCreateWithIdempotencyToken(ctx context.Context, request *remoteoutputservice.BatchCreateRequest, idempotencyToken string) (*emptypb.Empty, error) {
+	if idempotencyToken == "" {
+		return d.BatchCreate(ctx, request)
+	}
+	_, buildState, err := d.lookupBuild(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, isNew := buildState.idempotencyTokens.lookupOrInsert(idempotencyToken, d.clock.Now(), d.configuration.BatchCreate.MaximumIdempotencyTokensPerBuild)
+	if !isNew {
+		if err := entry.wait(ctx); err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+	_, err = d.BatchCreate(ctx, request)
+	buildState.idempotencyTokens.finish(entry, err, d.clock.Now().Add(d.configuration.BatchCreate.IdempotencyTokenExpiration))
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// BatchCreateWithInlineTrees is identical to BatchCreate(), except that
+// the client may provide the Tree objects of the directories contained
+// in the request. This prevents the need for fetching these from the
+// Content Addressable Storage, which is wasteful for small directories
+// whose contents are already known to the client.
+//
+// The list of Tree objects is aligned with the list of directories in
+// the request. Directories whose Tree object is nil are loaded from the
+// Content Addressable Storage as usual. Tree objects are provided in
+// serialized form, as marshaling Protobuf messages is not canonical.
+// The serialized Tree objects are required to match the tree digest of
+// the directory.
+func (d *RemoteOutputServiceDirectory) BatchCreateWithInlineTrees(ctx context.Context, request *remoteoutputservice.BatchCreateRequest, inlineTrees [][]byte) (*emptypb.Empty, error) {
+	if len(inlineTrees) != len(request.Directories) {
+		return nil, status.Errorf(codes.InvalidArgument, "Request contains %d directories, while %d inline trees were provided", len(request.Directories), len(inlineTrees))
+	}
+	if err := d.batchCreate(ctx, request, inlineTrees, nil); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// BatchCreateResults contains the outcome of creating the individual
+// entries of a BatchCreate request. The lists of errors are aligned
+// with the lists of files, directories and symbolic links contained in
+// the request. Entries that were created successfully have their error
+// set to nil.
+type BatchCreateResults struct {
+	FileErrors      []error
+	DirectoryErrors []error
+	SymlinkErrors   []error
+}
+
+// BatchCreateContinuingOnError is identical to BatchCreate(), except
+// that failures to create individual entries don't cause the request to
+// be aborted. Instead, all entries that can be created are created, and
+// errors for the remaining entries are returned. This prevents clients
+// from needing to bisect large requests to find the offending entries.
+//
+// Failures to resolve or clean the path prefix still cause the request
+// to fail as a whole.
+func (d *RemoteOutputServiceDirectory) BatchCreateContinuingOnError(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*BatchCreateResults, error) {
+	results := &BatchCreateResults{
+		FileErrors:      make([]error, len(request.Files)),
+		DirectoryErrors: make([]error, len(request.Directories)),
+		SymlinkErrors:   make([]error, len(request.Symlinks)),
+	}
+	if err := d.batchCreate(ctx, request, nil, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (d *RemoteOutputServiceDirectory) batchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest, inlineTrees [][]byte, results *BatchCreateResults) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.BatchCreate", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+		attribute.String("path_prefix", request.PathPrefix),
+		attribute.Int("files_count", len(request.Files)),
+		attribute.Int("directories_count", len(request.Directories)),
+		attribute.Int("symlinks_count", len(request.Symlinks)),
+		attribute.Bool("continue_on_error", results != nil),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if err := d.checkBatchCreateEntriesCount(request); err != nil {
+		return err
+	}
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, request.BuildId)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	if d.configuration.BatchCreate.ValidatePaths {
+		if err := validateBatchCreateRequestPaths(request); err != nil {
+			return err
+		}
+	}
+
+	// Don't make any changes while a snapshot of the output path is
+	// being created.
+	outputPathState.contentsLock.RLock()
+	defer outputPathState.contentsLock.RUnlock()
+	defer outputPathState.markModified()
+
+	prefixCreator, err := d.createPathPrefix(outputPathState, request)
+	if err != nil {
+		return err
+	}
+	return d.createEntries(ctx, outputPathState, buildState, &prefixCreator, request, inlineTrees, results)
+}
+
+// checkRequestEntriesCount returns an error if the number of entries
+// contained in a request exceeds a configured maximum.
+func checkRequestEntriesCount(method, entryType string, count, maximumCount int) error {
+	if maximumCount > 0 && count > maximumCount {
+		return status.Errorf(codes.ResourceExhausted, "%s() request contains %d %s, which exceeds the maximum of %d", method, count, entryType, maximumCount)
+	}
+	return nil
+}
+
+// checkBatchCreateEntriesCount returns an error if a BatchCreate
+// request contains more entries than permitted.
+func (d *RemoteOutputServiceDirectory) checkBatchCreateEntriesCount(request *remoteoutputservice.BatchCreateRequest) error {
+	return checkRequestEntriesCount(
+		"BatchCreate",
+		"entries",
+		len(request.Files)+len(request.Directories)+len(request.Symlinks),
+		d.configuration.Limits.MaximumBatchCreateEntriesCount)
+}
+
+// validateRelativePath checks that a path provided to BatchCreate() is
+// relative and does not contain any ".." or empty components. Paths
+// that escape the output path are also rejected by path.Resolve(), but
+// only after the preceding components have been created.
+func validateRelativePath(p string) error {
+	if p == "" {
+		return status.Error(codes.InvalidArgument, "Path is empty")
+	}
+	if strings.HasPrefix(p, "/") {
+		return status.Error(codes.InvalidArgument, "Path is absolute")
+	}
+	for _, component := range strings.Split(p, "/") {
+		switch component {
+		case "":
+			return status.Error(codes.InvalidArgument, "Path contains an empty component")
+		case "..":
+			return status.Error(codes.InvalidArgument, "Path contains a \"..\" component")
+		}
+	}
+	return nil
+}
+
+// validateBatchCreateRequestPaths validates the path prefix and the
+// paths of all entries contained in a BatchCreate request, so that
+// requests containing unsafe paths can be rejected before any changes
+// to the output path are made.
+func validateBatchCreateRequestPaths(request *remoteoutputservice.BatchCreateRequest) error {
+	// An empty path prefix refers to the root of the output path.
+	if request.PathPrefix != "" {
+		if err := validateRelativePath(request.PathPrefix); err != nil {
+			return util.StatusWrapf(err, "Invalid path prefix %#v", request.PathPrefix)
+		}
+	}
+	for _, entry := range request.Files {
+		if err := validateRelativePath(entry.Path); err != nil {
+			return util.StatusWrapf(err, "Invalid path for file %#v", entry.Path)
+		}
+	}
+	for _, entry := range request.Directories {
+		if err := validateRelativePath(entry.Path); err != nil {
+			return util.StatusWrapf(err, "Invalid path for directory %#v", entry.Path)
+		}
+	}
+	for _, entry := range request.Symlinks {
+		if err := validateRelativePath(entry.Path); err != nil {
+			return util.StatusWrapf(err, "Invalid path for symbolic link %#v", entry.Path)
+		}
+	}
+	return nil
+}
+
+// createPathPrefix resolves the path prefix of a BatchCreate request,
+// creating directories as needed. Optionally, all of the contents of
+// the path prefix are removed.
+func (d *RemoteOutputServiceDirectory) createPathPrefix(outputPathState *outputPathState, request *remoteoutputservice.BatchCreateRequest) (directoryCreatingComponentWalker, error) {
+	prefixCreator := directoryCreatingComponentWalker{
+		stack:        util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		nameInterner: d.nameInterner,
+		maximumDepth: d.configuration.Limits.MaximumPathDepth,
+	}
+	if err := path.Resolve(request.PathPrefix, path.NewRelativeScopeWalker(&prefixCreator)); err != nil {
+		return directoryCreatingComponentWalker{}, util.StatusWrap(err, "Failed to create path prefix directory")
+	}
+	if request.CleanPathPrefix {
+		outputPathState.trimmableDirectories.invalidate(request.PathPrefix, "")
+		if err := prefixCreator.stack.Peek().RemoveAllChildren(false); err != nil {
+			return directoryCreatingComponentWalker{}, util.StatusWrap(err, "Failed to clean path prefix directory")
+		}
+		outputPathState.directoryMemory.release(request.PathPrefix, "")
+	}
+	return prefixCreator, nil
+}
+
+// createEntries creates the files, directories and symbolic links
+// contained in a BatchCreate request underneath the path prefix. If
+// provided, inlineTrees contains the serialized Tree objects of the
+// directories.
+//
+// If results is nil, processing stops at the first entry that cannot be
+// created. Otherwise, errors are stored in results, and processing
+// continues with the next entry.
+func (d *RemoteOutputServiceDirectory) createEntries(ctx context.Context, outputPathState *outputPathState, buildState *buildState, prefixCreator *directoryCreatingComponentWalker, request *remoteoutputservice.BatchCreateRequest, inlineTrees [][]byte, results *BatchCreateResults) error {
+	// Create requested files.
+	for i, entry := range request.Files {
+		outputPathState.trimmableDirectories.invalidate(request.PathPrefix, entry.Path)
+		if err := d.createFile(ctx, outputPathState, buildState, prefixCreator, request.PathPrefix, entry); err != nil {
+			if results == nil {
+				return err
+			}
+			d.detectCorruption(outputPathState, err)
+			results.FileErrors[i] = err
+		}
+	}
+
+	// Create requested directories.
+	for i, entry := range request.Directories {
+		outputPathState.trimmableDirectories.invalidate(request.PathPrefix, entry.Path)
+		var inlineTree []byte
+		if inlineTrees != nil {
+			inlineTree = inlineTrees[i]
+		}
+		if err := d.createDirectory(ctx, outputPathState, buildState, prefixCreator, request.PathPrefix, entry, inlineTree); err != nil {
+			if results == nil {
+				return err
+			}
+			d.detectCorruption(outputPathState, err)
+			results.DirectoryErrors[i] = err
+		}
+	}
+
+	// Create requested symbolic links.
+	for i, entry := range request.Symlinks {
+		outputPathState.trimmableDirectories.invalidate(request.PathPrefix, entry.Path)
+		if err := d.createSymlink(outputPathState, prefixCreator, request.PathPrefix, entry); err != nil {
+			if results == nil {
+				return err
+			}
+			d.detectCorruption(outputPathState, err)
+			results.SymlinkErrors[i] = err
+		}
+	}
+
+	return nil
+}
+
+func (d *RemoteOutputServiceDirectory) createFile(ctx context.Context, outputPathState *outputPathState, buildState *buildState, prefixCreator *directoryCreatingComponentWalker, pathPrefix string, entry *remoteexecution.OutputFile) error {
+	if entry.Path == "" {
+		return status.Error(codes.InvalidArgument, "File has an empty path")
+	}
+	if entry.Digest == nil {
+		return status.Errorf(codes.InvalidArgument, "File %#v has no digest", entry.Path)
+	}
+	childDigest, err := buildState.digestFunction.NewDigestFromProto(entry.Digest)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid digest for file %#v", entry.Path)
+	}
+	if d.configuration.BatchCreate.PreserveUnchangedFiles || d.configuration.BatchCreate.TrackExecutableBitChanges {
+		if existingLeaf, existingDigest := lookupExistingFile(prefixCreator.stack.Peek(), entry.Path, &buildState.digestFunction); existingLeaf != nil && existingDigest == childDigest {
+			if isExecutableLeaf(ctx, existingLeaf) == entry.IsExecutable {
+				if d.configuration.BatchCreate.PreserveUnchangedFiles {
+					return nil
+				}
+			} else if d.configuration.BatchCreate.TrackExecutableBitChanges {
+				filePath := entry.Path
+				if pathPrefix != "" {
+					filePath = pathPrefix + "/" + entry.Path
+				}
+				buildState.addExecutableBitChange(filePath)
+			}
+		}
+	}
+	leaf := outputPathState.casFileFactory.LookupFile(childDigest, entry.IsExecutable)
+	var releaseQuota func()
+	if maximumFilesCount, maximumSizeBytes := d.configuration.Limits.MaximumFilesCountPerOutputPath, d.configuration.Limits.MaximumSizeBytesPerOutputPath; maximumFilesCount > 0 || maximumSizeBytes > 0 {
+		// Account for the file, and ensure that its quota is
+		// released once the file is removed.
+		sizeBytes := childDigest.GetSizeBytes()
+		if err := outputPathState.usage.acquire(sizeBytes, maximumFilesCount, maximumSizeBytes); err != nil {
+			leaf.Unlink()
+			return util.StatusWrapf(err, "Failed to create file %#v", entry.Path)
+		}
+		releaseQuota = func() { outputPathState.usage.release(sizeBytes) }
+	}
+	if d.memoryBudget != nil {
+		if err := d.memoryBudget.acquire(memoryBudgetBytesPerNode); err != nil {
+			if releaseQuota != nil {
+				releaseQuota()
+			}
+			leaf.Unlink()
+			return util.StatusWrapf(err, "Failed to create file %#v", entry.Path)
+		}
+		releaseUsage := releaseQuota
+		releaseQuota = func() {
+			d.memoryBudget.release(memoryBudgetBytesPerNode)
+			if releaseUsage != nil {
+				releaseUsage()
+			}
+		}
+	}
+	if releaseQuota != nil {
+		leaf = newQuotaEnforcingLeaf(leaf, releaseQuota)
+	}
+	if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
+		leaf.Unlink()
+		return util.StatusWrapf(err, "Failed to create file %#v", entry.Path)
+	}
+	outputPathState.directoryMemory.release(pathPrefix, entry.Path)
+	outputPathState.contentCounters.addFile(childDigest.GetSizeBytes())
+	return nil
+}
+
+// lookupExistingFile returns a regular file that is present at a path
+// relative to a directory, together with its digest. Any errors that
+// occur are suppressed, as they cause the file to be recreated, which
+// reports errors accordingly.
+func lookupExistingFile(directory virtual.PrepopulatedDirectory, outputPath string, digestFunction *digest.Function) (virtual.NativeLeaf, digest.Digest) {
+	_, _, leaf, err := lookupChild(directory, outputPath)
+	if err != nil || leaf == nil {
+		return nil, digest.BadDigest
+	}
+	if _, err := leaf.Readlink(); err != syscall.EINVAL {
+		// Symbolic link.
+		return nil, digest.BadDigest
+	}
+	fileStatus, err := leaf.GetOutputServiceFileStatus(digestFunction)
+	if err != nil {
+		return nil, digest.BadDigest
+	}
+	existingDigest, err := digestFunction.NewDigestFromProto(fileStatus.GetFile().GetDigest())
+	if err != nil {
+		return nil, digest.BadDigest
+	}
+	return leaf, existingDigest
+}
+
+// isExecutableLeaf returns whether the executable bit of a regular
+// file is set. The Remote Output Service protocol does not report this
+// as part of FileStatus.
+func isExecutableLeaf(ctx context.Context, leaf virtual.NativeLeaf) bool {
+	var attributes virtual.Attributes
+	leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskPermissions, &attributes)
+	permissions, _ := attributes.GetPermissions()
+	return permissions&virtual.PermissionsExecute != 0
+}
+
+func (d *RemoteOutputServiceDirectory) createDirectory(ctx context.Context, outputPathState *outputPathState, buildState *buildState, prefixCreator *directoryCreatingComponentWalker, pathPrefix string, entry *remoteexecution.OutputDirectory, inlineTree []byte) error {
+	if entry.Path == "" {
+		return status.Error(codes.InvalidArgument, "Directory has an empty path")
+	}
+	if entry.TreeDigest == nil {
+		return status.Errorf(codes.InvalidArgument, "Directory %#v has no tree digest", entry.Path)
+	}
+	childDigest, err := buildState.digestFunction.NewDigestFromProto(entry.TreeDigest)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid digest for directory %#v", entry.Path)
+	}
+	// All Directory messages contained in an output directory are
+	// stored in a single Tree object, and are all loaded through
+	// TreeDirectoryWalker. Bounding the size of the Tree object thus
+	// also bounds the cumulative size of all nested directories.
+	if sizeBytes := childDigest.GetSizeBytes(); sizeBytes > d.configuration.MaximumTreeSizeBytes {
+		return status.Errorf(codes.InvalidArgument, "Directory %#v is %d bytes in size, which exceeds the permitted maximum of %d bytes", entry.Path, sizeBytes, d.configuration.MaximumTreeSizeBytes)
+	}
+
+	directoryWalker := cd_cas.NewTreeDirectoryWalker(outputPathState.directoryFetcher, childDigest)
+	if inlineTree != nil {
+		// The Tree object was provided by the client. Ensure it
+		// matches the tree digest, as the digest is reported by
+		// BatchStat() and used to check for its existence at the
+		// start of the next build.
+		generator := buildState.digestFunction.NewGenerator(int64(len(inlineTree)))
+		if _, err := generator.Write(inlineTree); err != nil {
+			return util.StatusWrapf(err, "Failed to compute digest of inline tree of directory %#v", entry.Path)
+		}
+		if inlineTreeDigest := generator.Sum(); inlineTreeDigest != childDigest {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v has an inline tree with digest %#v, while its tree digest is %#v", entry.Path, inlineTreeDigest.String(), childDigest.String())
+		}
+		var tree remoteexecution.Tree
+		if err := proto.Unmarshal(inlineTree, &tree); err != nil {
+			return util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to unmarshal inline tree of directory %#v", entry.Path)
+		}
+		directoryWalker, err = cd_cas.NewDecodedTreeDirectoryWalker(&tree, childDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to decode inline tree of directory %#v", entry.Path)
+		}
+	} else if d.configuration.BatchCreate.EagerlyFetchDirectories {
+		// Fetch the Tree object in its entirety, so that
+		// traversing the directory later on doesn't cause any
+		// further fetches against the CAS.
+		tree, err := d.contentAddressableStorage.TreeReads.Get(ctx, childDigest).ToProto(&remoteexecution.Tree{}, int(d.configuration.MaximumTreeSizeBytes))
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to fetch directory %#v", entry.Path)
+		}
+		outputPathState.statistics.directoryBytesFetched.Add(uint64(childDigest.GetSizeBytes()))
+		directoryWalker, err = cd_cas.NewDecodedTreeDirectoryWalker(tree.(*remoteexecution.Tree), childDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to decode directory %#v", entry.Path)
+		}
+	} else if d.configuration.BatchCreate.PrefetchRootDirectories {
+		// Only fetch the root directory of the Tree object, so
+		// that nonexistent Tree objects are detected right away.
+		// The contents of child directories are still loaded
+		// lazily.
+		if _, err := directoryWalker.GetDirectory(ctx); err != nil {
+			return util.StatusWrapf(err, "Failed to fetch root directory of directory %#v", entry.Path)
+		}
+	}
+
+	initialContentsFetcher := virtual.NewCASInitialContentsFetcher(
+		context.Background(),
+		directoryWalker,
+		outputPathState.casFileFactory,
+		d.symlinkFactory,
+		buildState.digestFunction)
+
+	// The size of the Tree object is used as an estimate of the
+	// amount of memory used by the contents of the directory once
+	// loaded. It is released when the directory is removed or
+	// replaced.
+	var memoryCharge *directoryMemoryCharge
+	if d.memoryBudget != nil {
+		memoryCharge = &directoryMemoryCharge{
+			budget:    d.memoryBudget,
+			sizeBytes: memoryBudgetBytesPerNode + childDigest.GetSizeBytes(),
+		}
+		if err := d.memoryBudget.acquire(memoryCharge.sizeBytes); err != nil {
+			return util.StatusWrapf(err, "Failed to create directory %#v", entry.Path)
+		}
+		initialContentsFetcher = &memoryChargingInitialContentsFetcher{
+			InitialContentsFetcher: initialContentsFetcher,
+			charge:                 memoryCharge,
+		}
+	}
+	parent, name, err := prefixCreator.createChildInParent(
+		entry.Path,
+		virtual.InitialNode{}.FromDirectory(initialContentsFetcher))
+	if err != nil {
+		if memoryCharge != nil {
+			memoryCharge.release()
+		}
+		return util.StatusWrapf(err, "Failed to create directory %#v", entry.Path)
+	}
+	if inlineTree == nil && !d.configuration.BatchCreate.EagerlyFetchDirectories {
+		d.trackLazyDirectory(outputPathState, parent, name)
+	}
+	if memoryCharge != nil {
+		outputPathState.directoryMemory.add(pathPrefix, entry.Path, memoryCharge)
+	} else {
+		outputPathState.directoryMemory.release(pathPrefix, entry.Path)
+	}
+	outputPathState.trimmableDirectories.add(pathPrefix, entry.Path, childDigest)
+	outputPathState.contentCounters.addDirectory()
+	return nil
+}
+
+func (d *RemoteOutputServiceDirectory) createSymlink(outputPathState *outputPathState, prefixCreator *directoryCreatingComponentWalker, pathPrefix string, entry *remoteexecution.OutputSymlink) error {
+	if entry.Path == "" {
+		return status.Error(codes.InvalidArgument, "Symbolic link has an empty path")
+	}
+	if entry.Target == "" {
+		return status.Errorf(codes.InvalidArgument, "Symbolic link %#v has an empty target", entry.Path)
+	}
+	if maximumLength := d.configuration.Limits.MaximumSymlinkTargetLengthBytes; len(entry.Target) > maximumLength {
+		return status.Errorf(codes.InvalidArgument, "Symbolic link %#v has a target of %d bytes in size, which exceeds the permitted maximum of %d bytes", entry.Path, len(entry.Target), maximumLength)
+	}
+	if strings.IndexByte(entry.Target, 0) >= 0 {
+		return status.Errorf(codes.InvalidArgument, "Symbolic link %#v has a target containing a null byte", entry.Path)
+	}
+	leaf := d.symlinkFactory.LookupSymlink(d.nameInterner.internSymlinkTarget([]byte(entry.Target)))
+	if d.memoryBudget != nil {
+		memoryBytes := memoryBudgetBytesPerNode + int64(len(entry.Target))
+		if err := d.memoryBudget.acquire(memoryBytes); err != nil {
+			leaf.Unlink()
+			return util.StatusWrapf(err, "Failed to create symbolic link %#v", entry.Path)
+		}
+		leaf = newQuotaEnforcingLeaf(leaf, func() { d.memoryBudget.release(memoryBytes) })
+	}
+	if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
+		leaf.Unlink()
+		return util.StatusWrapf(err, "Failed to create symbolic link %#v", entry.Path)
+	}
+	outputPathState.directoryMemory.release(pathPrefix, entry.Path)
+	outputPathState.contentCounters.addSymlink()
+	return nil
+}
+
+// BatchCreateRequestStream is the subset of a gRPC client streaming
+// server that is used by BatchCreateStream() to receive requests.
+type BatchCreateRequestStream interface {
+	Context() context.Context
+	Recv() (*remoteoutputservice.BatchCreateRequest, error)
+}
+
+// BatchCreateStream is a client streaming variant of BatchCreate(). It
+// can be used by build clients that emit large numbers of outputs, as
+// neither side needs to hold all of the entries in memory at once.
+//
+// The build ID, path prefix and the clean_path_prefix option are only
+// taken from the first request. Entries contained in all requests are
+// created underneath the same path prefix.
+func (d *RemoteOutputServiceDirectory) BatchCreateStream(stream BatchCreateRequestStream) (err error) {
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+	request, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "Stream does not contain any requests")
+	} else if err != nil {
+		return util.StatusWrap(err, "Failed to receive request")
+	}
+
+	ctx, span := d.tracer.Start(stream.Context(), "RemoteOutputServiceDirectory.BatchCreateStream", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+		attribute.String("path_prefix", request.PathPrefix),
+	))
+	requestsCount, filesCount, directoriesCount, symlinksCount := 0, 0, 0, 0
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("requests_count", requestsCount),
+			attribute.Int("files_count", filesCount),
+			attribute.Int("directories_count", directoriesCount),
+			attribute.Int("symlinks_count", symlinksCount))
+		endSpan(span, err)
+	}()
+
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, request.BuildId)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+	if d.configuration.BatchCreate.ValidatePaths {
+		if err := validateBatchCreateRequestPaths(request); err != nil {
+			return util.StatusWrap(err, "Request 1")
+		}
+	}
+
+	// Only hold the lock while processing individual requests, so
+	// that a slow client cannot block the creation of snapshots.
+	outputPathState.contentsLock.RLock()
+	prefixCreator, err := d.createPathPrefix(outputPathState, request)
+	outputPathState.markModified()
+	outputPathState.contentsLock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	for {
+		requestsCount++
+		filesCount += len(request.Files)
+		directoriesCount += len(request.Directories)
+		symlinksCount += len(request.Symlinks)
+
+		if err := d.checkBatchCreateEntriesCount(request); err != nil {
+			return util.StatusWrapf(err, "Request %d", requestsCount)
+		}
+
+		// The first request has already been validated, as its
+		// path prefix needed to be validated before creating it.
+		if d.configuration.BatchCreate.ValidatePaths && requestsCount > 1 {
+			if err := validateBatchCreateRequestPaths(request); err != nil {
+				return util.StatusWrapf(err, "Request %d", requestsCount)
+			}
+		}
+
+		outputPathState.contentsLock.RLock()
+		err := d.createEntries(ctx, outputPathState, buildState, &prefixCreator, request, nil, nil)
+		outputPathState.markModified()
+		outputPathState.contentsLock.RUnlock()
+		if err != nil {
+			return util.StatusWrapf(err, "Request %d", requestsCount)
+		}
+
+		request, err = stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return util.StatusWrap(err, "Failed to receive request")
+		}
+	}
+}
+
+// OutputLink describes a hard link that needs to be created by
+// BatchCreateLinks(). Both paths are relative to the root of the output
+// path.
+type OutputLink struct {
+	SourcePath      string
+	DestinationPath string
+}
+
+// errPathDoesNotExist is returned by parentDirectoryLookingUpComponentWalker
+// if one of the parent directories of a path does not exist.
+var errPathDoesNotExist = status.Error(codes.InvalidArgument, "Path does not exist")
+
+// parentDirectoryLookingUpComponentWalker is an implementation of
+// ComponentWalker that is used by BatchCreateLinks() to resolve the
+// parent directory of an existing file or symbolic link. Unlike
+// parentDirectoryCreatingComponentWalker, it does not create any
+// directories.
+type parentDirectoryLookingUpComponentWalker struct {
+	path.TerminalNameTrackingComponentWalker
+	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
+}
+
+func (cw *parentDirectoryLookingUpComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	child, err := cw.stack.Peek().LookupChild(name)
+	if err != nil {
+		if err == syscall.ENOENT {
+			return nil, errPathDoesNotExist
+		}
+		return nil, err
+	}
+	directory, _ := child.GetPair()
+	if directory == nil {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves through a file")
+	}
+	cw.stack.Push(directory)
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *parentDirectoryLookingUpComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	return cw, nil
+}
+
+// lookupChild resolves an existing file, directory or symbolic link in
+// the output path, without following symbolic links. Exactly one of the
+// returned directory and leaf is set upon success. The name of the
+// child is returned as well, unless the path resolves to a directory
+// without having a trailing component (e.g., "." or "foo/").
+func lookupChild(rootDirectory virtual.PrepopulatedDirectory, outputPath string) (*path.Component, virtual.PrepopulatedDirectory, virtual.NativeLeaf, error) {
+	parentLookup := parentDirectoryLookingUpComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](rootDirectory),
+	}
+	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&parentLookup)); err != nil {
+		return nil, nil, nil, util.StatusWrap(err, "Failed to resolve path")
+	}
+	name := parentLookup.TerminalName
+	if name == nil {
+		return nil, parentLookup.stack.Peek(), nil, nil
+	}
+	child, err := parentLookup.stack.Peek().LookupChild(*name)
+	if err != nil {
+		if err == syscall.ENOENT {
+			return nil, nil, nil, errPathDoesNotExist
+		}
+		return nil, nil, nil, err
+	}
+	directory, leaf := child.GetPair()
+	return name, directory, leaf, nil
+}
+
+// lookupLeaf resolves an existing file or symbolic link in the output
+// path, without following symbolic links.
+func lookupLeaf(rootDirectory virtual.PrepopulatedDirectory, outputPath string) (virtual.NativeLeaf, error) {
+	_, directory, leaf, err := lookupChild(rootDirectory, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	if directory != nil {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a directory")
+	}
+	return leaf, nil
+}
+
+// lookupDirectory resolves an existing directory in the output path,
+// without following symbolic links.
+func lookupDirectory(rootDirectory virtual.PrepopulatedDirectory, outputPath string) (virtual.PrepopulatedDirectory, error) {
+	_, directory, _, err := lookupChild(rootDirectory, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	if directory == nil {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a file")
+	}
+	return directory, nil
+}
+
+// BatchCreateLinks can be called by a build client to create hard links
+// between files or symbolic links that were created previously. This
+// permits build actions such as "cp" and "ln" to be emulated without
+// downloading or copying any file contents.
+func (d *RemoteOutputServiceDirectory) BatchCreateLinks(ctx context.Context, buildID string, links []OutputLink) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.BatchCreateLinks", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+		attribute.Int("links_count", len(links)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+
+	outputPathState, _, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	// Don't make any changes while a snapshot of the output path is
+	// being created.
+	outputPathState.contentsLock.RLock()
+	defer outputPathState.contentsLock.RUnlock()
+	defer outputPathState.markModified()
+
+	rootCreator := directoryCreatingComponentWalker{
+		stack:        util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		nameInterner: d.nameInterner,
+		maximumDepth: d.configuration.Limits.MaximumPathDepth,
+	}
+	for _, link := range links {
+		leaf, err := lookupLeaf(outputPathState.rootDirectory, link.SourcePath)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to look up source path %#v", link.SourcePath)
+		}
+		// Increase the link count of the leaf, so that it
+		// remains valid if it gets unlinked from its original
+		// location.
+		if s := leaf.Link(); s != virtual.StatusOK {
+			return status.Errorf(codes.InvalidArgument, "Failed to look up source path %#v: Path does not exist", link.SourcePath)
+		}
+		outputPathState.trimmableDirectories.invalidate("", link.DestinationPath)
+		if err := rootCreator.createChild(link.DestinationPath, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
+			leaf.Unlink()
+			return util.StatusWrapf(err, "Failed to create link %#v", link.DestinationPath)
+		}
+		outputPathState.directoryMemory.release("", link.DestinationPath)
+	}
+	return nil
+}
+
+// BatchRemove can be called by a build client to remove files,
+// symbolic links and directories from the output path, without
+// needing to clean the output path in its entirety. Paths are relative
+// to the root of the output path. Paths that do not exist are treated
+// as being removed successfully. Directories that are not empty are
+// only removed if recursive is set.
+//
+// Failures to remove individual paths don't cause the request to be
+// aborted. The errors that are returned are aligned with the list of
+// paths. Paths that were removed successfully have their error set to
+// nil.
+func (d *RemoteOutputServiceDirectory) BatchRemove(ctx context.Context, buildID string, paths []string, recursive bool) (_ []error, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.BatchRemove", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+		attribute.Int("paths_count", len(paths)),
+		attribute.Bool("recursive", recursive),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if err := checkRequestEntriesCount("BatchRemove", "paths", len(paths), d.configuration.Limits.MaximumBatchRemovePathsCount); err != nil {
+		return nil, err
+	}
+	if err := d.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	outputPathState, _, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+
+	// Don't make any changes while a snapshot of the output path is
+	// being created.
+	outputPathState.contentsLock.RLock()
+	defer outputPathState.contentsLock.RUnlock()
+	defer outputPathState.markModified()
+
+	errs := make([]error, len(paths))
+	for i, p := range paths {
+		if d.configuration.BatchCreate.ValidatePaths {
+			if err := validateRelativePath(p); err != nil {
+				errs[i] = util.StatusWrapf(err, "Invalid path %#v", p)
+				continue
+			}
+		}
+		outputPathState.trimmableDirectories.invalidate("", p)
+		if err := removePath(outputPathState.rootDirectory, p, recursive, &outputPathState.contentCounters); err != nil {
+			d.detectCorruption(outputPathState, err)
+			errs[i] = util.StatusWrapf(err, "Failed to remove path %#v", p)
+			continue
+		}
+		outputPathState.directoryMemory.release("", p)
+	}
+	return errs, nil
+}
+
+// removePath removes a single file, symbolic link or directory from
+// the output path. Symbolic links are not followed.
+func removePath(rootDirectory virtual.PrepopulatedDirectory, outputPath string, recursive bool, contentCounters *outputPathContentCounters) error {
+	parentLookup := parentDirectoryLookingUpComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](rootDirectory),
+	}
+	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&parentLookup)); err != nil {
+		if err == errPathDoesNotExist {
+			return nil
+		}
+		return util.StatusWrap(err, "Failed to resolve path")
+	}
+	name := parentLookup.TerminalName
+	if name == nil {
+		return status.Error(codes.InvalidArgument, "Path does not have a final component")
+	}
+
+	parent := parentLookup.stack.Peek()
+	child, err := parent.LookupChild(*name)
+	if err != nil {
+		if err == syscall.ENOENT {
+			return nil
+		}
+		return err
+	}
+	if directory, _ := child.GetPair(); directory != nil && recursive {
+		if err := directory.RemoveAllChildren(true); err != nil {
+			return err
+		}
+	}
+	if err := parent.Remove(*name); err != nil {
+		switch err {
+		case syscall.ENOENT:
+			return nil
+		case syscall.ENOTEMPTY:
+			return status.Error(codes.FailedPrecondition, "Directory is not empty")
+		}
+		return err
+	}
+	if _, leaf := child.GetPair(); leaf == nil {
+		contentCounters.removeDirectory()
+	} else if getLeafNodeType(leaf) == "symlink" {
+		contentCounters.removeSymlink()
+	} else {
+		contentCounters.removeFile(getDigestsSizeBytes(leaf.GetContainingDigests()))
+	}
+	return nil
+}
+
+// getDigestsSizeBytes returns the total size of the objects
+// referenced by a set of digests.
+func getDigestsSizeBytes(digests digest.Set) int64 {
+	var sizeBytes int64
+	for _, blobDigest := range digests.Items() {
+		sizeBytes += blobDigest.GetSizeBytes()
+	}
+	return sizeBytes
+}
+
+// TrimBuild can be called by a build client to reduce the amount of
+// memory used by the output path of a running build. Directories that
+// were created through BatchCreate() and whose contents have not been
+// modified since are replaced by directories whose contents are loaded
+// lazily, meaning that any contents that have been loaded into memory
+// are released. The number of directories that were trimmed is
+// returned.
+//
+// Note that only modifications made through RemoteOutputServiceDirectory
+// are tracked. Modifications made through the virtual file system are
+// not, meaning that they may be reverted by calling this method.
+func (d *RemoteOutputServiceDirectory) TrimBuild(ctx context.Context, buildID string) (_ int, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.TrimBuild", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if err := d.checkWritable(); err != nil {
+		return 0, err
+	}
+
+	outputPathState, _, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	// Prevent BatchCreate() from making changes while directories
+	// are being replaced, as that would cause these changes to be
+	// lost.
+	outputPathState.contentsLock.Lock()
+	defer outputPathState.contentsLock.Unlock()
+	defer outputPathState.markModified()
+
+	trimmedDirectoriesCount := 0
+	for outputPath, treeDigest := range outputPathState.trimmableDirectories.getAll() {
+		parentLookup := parentDirectoryLookingUpComponentWalker{
+			stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		}
+		if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&parentLookup)); err != nil {
+			if err == errPathDoesNotExist {
+				continue
+			}
+			return trimmedDirectoriesCount, util.StatusWrapf(err, "Failed to resolve directory %#v", outputPath)
+		}
+		name := parentLookup.TerminalName
+		if name == nil {
+			continue
+		}
+		parent := parentLookup.stack.Peek()
+		child, err := parent.LookupChild(*name)
+		if err != nil {
+			if err == syscall.ENOENT {
+				continue
+			}
+			return trimmedDirectoriesCount, util.StatusWrapf(err, "Failed to look up directory %#v", outputPath)
+		}
+		if directory, _ := child.GetPair(); directory == nil {
+			continue
+		}
+
+		initialContentsFetcher := virtual.NewCASInitialContentsFetcher(
+			context.Background(),
+			cd_cas.NewTreeDirectoryWalker(outputPathState.directoryFetcher, treeDigest),
+			outputPathState.casFileFactory,
+			d.symlinkFactory,
+			treeDigest.GetDigestFunction())
+		if memoryCharge := outputPathState.directoryMemory.get(outputPath); memoryCharge != nil {
+			// The trimmed directory keeps the memory charge
+			// of the directory that it replaces.
+			initialContentsFetcher = &memoryChargingInitialContentsFetcher{
+				InitialContentsFetcher: initialContentsFetcher,
+				charge:                 memoryCharge,
+			}
+		}
+		if err := parent.CreateChildren(
+			map[path.Component]virtual.InitialNode{
+				*name: virtual.InitialNode{}.FromDirectory(initialContentsFetcher),
+			},
+			true); err != nil {
+			return trimmedDirectoriesCount, util.StatusWrapf(err, "Failed to trim directory %#v", outputPath)
+		}
+		d.trackLazyDirectory(outputPathState, parent, *name)
+		trimmedDirectoriesCount++
+	}
+	return trimmedDirectoriesCount, nil
+}
+
+// ExportPath copies a file, directory or symbolic link contained in
+// the output path of a running build to a local directory. This can be
+// used by tools that are unable to access files through the virtual
+// file system. Directories are copied recursively. If the source path
+// refers to a directory, its contents are placed in the destination
+// directory directly. Otherwise, the file or symbolic link is placed in
+// the destination directory under its original name.
+//
+// Symbolic links are never followed. Those contained in the source
+// path are recreated as is, regardless of whether they point to
+// locations inside or outside the output path. Source paths that
+// traverse symbolic links are rejected.
+func (d *RemoteOutputServiceDirectory) ExportPath(ctx context.Context, buildID, sourcePath string, destination filesystem.Directory) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.ExportPath", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+		attribute.String("source_path", sourcePath),
+	))
+	defer func() { endSpan(span, err) }()
+
+	outputPathState, _, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	// Prevent the output path from being replaced while exporting,
+	// as that would cause a mixture of old and new files to be
+	// exported.
+	outputPathState.contentsLock.RLock()
+	defer outputPathState.contentsLock.RUnlock()
+
+	name, directory, leaf, err := lookupChild(outputPathState.rootDirectory, sourcePath)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up source path %#v", sourcePath)
+	}
+	exporter := outputPathExporter{
+		context: ctx,
+	}
+	if directory != nil {
+		return exporter.exportDirectoryRecursive(directory, destination, nil)
+	}
+	return exporter.exportLeaf(leaf, destination, *name, (*path.Trace)(nil).Append(*name))
+}
+
+// statWalker is an implementation of ScopeWalker and ComponentWalker
+// that is used by BatchStat() to resolve the file or directory
+// corresponding to a requested path. It is capable of expanding
+// symbolic links, if encountered.
+type statWalker struct {
+	context               context.Context
+	followSymlinks        bool
+	maximumSymlinkFollows int
+	digestFunction        *digest.Function
+	symlinkFollows        int
+
+	// If set, digests of files are only included if they are
+	// already known, meaning they don't need to be computed.
+	knownDigestFunction *digest.Function
+
+	// If set, digests of files that need to be computed are
+	// memoized.
+	fileDigestCache *fileDigestCache
+
+	// If set, the contents of files backed by the Content
+	// Addressable Storage are read from this BlobAccess, and
+	// checked against the digest that is reported.
+	verifyingContentAddressableStorage blobstore.BlobAccess
+
+	stack      util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	fileStatus *remoteoutputservice.FileStatus
+
+	// The number of directories on the stack below the root of
+	// the output path, and the maximum permitted value.
+	depth        int
+	maximumDepth int
+
+	// The regular file to which the path resolved, if any.
+	leaf virtual.NativeLeaf
+
+	// If resolution failed due to a file or directory being
+	// absent, the name of the component that could not be resolved
+	// and the reason why.
+	missingComponent path.Component
+	missingReason    string
+
+	// If set, children of directories contained in this set are not
+	// looked up, as that would cause their contents to be loaded.
+	// Resolution fails with errLazyDirectory instead.
+	lazyDirectories *lazyDirectorySet
+}
+
+// errLazyDirectory is returned by statWalker if resolution requires
+// looking up a child of a directory whose contents may not have been
+// loaded yet.
+var errLazyDirectory = status.Error(codes.Unavailable, "Path traverses into a directory whose contents have not been loaded")
+
+func (cw *statWalker) lookupChild(name path.Component) (virtual.PrepopulatedDirectoryChild, error) {
+	directory := cw.stack.Peek()
+	if cw.lazyDirectories != nil && cw.lazyDirectories.contains(directory) {
+		return virtual.PrepopulatedDirectoryChild{}, errLazyDirectory
+	}
+	return directory.LookupChild(name)
+}
+
+// followSymlink is called before returning a symbolic link to the
+// caller, which will cause it to be expanded. It enforces the maximum
+// number of symbolic links that may be followed.
+func (cw *statWalker) followSymlink() error {
+	if cw.maximumSymlinkFollows > 0 && cw.symlinkFollows >= cw.maximumSymlinkFollows {
+		return status.Errorf(codes.FailedPrecondition, "Path resolution requires more than %d symbolic links to be followed", cw.maximumSymlinkFollows)
+	}
+	cw.symlinkFollows++
+	return nil
+}
+
+func (cw *statWalker) OnScope(absolute bool) (path.ComponentWalker, error) {
+	if absolute {
+		cw.stack.PopAll()
+		cw.depth = 0
+	}
+	// Currently in a known directory.
+	cw.fileStatus = &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_Directory_{},
+	}
+	return cw, nil
+}
+
+// setMissingComponent records the component at which resolution failed
+// due to a file or directory being absent, so that it can be reported
+// by BatchStatWithMissingPathDetails().
+func (cw *statWalker) setMissingComponent(name path.Component, reason string) {
+	cw.missingComponent = name
+	cw.missingReason = reason
+}
+
+func (cw *statWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	if err := checkPathDepth(cw.depth+1, cw.maximumDepth); err != nil {
+		return nil, err
+	}
+	child, err := cw.lookupChild(name)
+	if err != nil {
+		if err == syscall.ENOENT {
+			cw.setMissingComponent(name, missingPathReasonParentNotFound)
+		}
+		return nil, err
+	}
+
+	directory, leaf := child.GetPair()
+	if directory != nil {
+		// Got a directory.
+		cw.stack.Push(directory)
+		cw.depth++
+		return path.GotDirectory{
+			Child:        cw,
+			IsReversible: true,
+		}, nil
+	}
+
+	target, err := leaf.Readlink()
+	if err == syscall.EINVAL {
+		cw.setMissingComponent(name, missingPathReasonParentNotDirectory)
+		return nil, syscall.ENOTDIR
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Got a symbolic link in the middle of a path. Those should
+	// always be followed.
+	if err := cw.followSymlink(); err != nil {
+		return nil, err
+	}
+	cw.fileStatus = &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_External_{},
+	}
+	return path.GotSymlink{
+		Parent: cw,
+		Target: target,
+	}, nil
+}
+
+func (cw *statWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	if err := checkPathDepth(cw.depth+1, cw.maximumDepth); err != nil {
+		return nil, err
+	}
+	child, err := cw.lookupChild(name)
+	if err != nil {
+		if err == syscall.ENOENT {
+			cw.setMissingComponent(name, missingPathReasonLeafNotFound)
+		}
+		return nil, err
+	}
+
+	directory, leaf := child.GetPair()
+	if directory != nil {
+		// Got a directory. The existing FileStatus is sufficient.
+		cw.stack.Push(directory)
+		cw.depth++
+		return nil, nil
+	}
+
+	if cw.followSymlinks {
+		target, err := leaf.Readlink()
+		if err == nil {
+			// Got a symbolic link, and we should follow it.
+			if err := cw.followSymlink(); err != nil {
+				return nil, err
+			}
+			cw.fileStatus = &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_External_{},
+			}
+			return &path.GotSymlink{
+				Parent: cw,
+				Target: target,
+			}, nil
+		}
+		if err != syscall.EINVAL {
+			return nil, err
+		}
+	}
+
+	// Got a regular file, or a symbolic link that we should not
+	// follow. For the latter, GetOutputServiceFileStatus() returns
+	// the target of the symbolic link.
+
+	digestFunction := cw.digestFunction
+	if knownDigestFunction := cw.knownDigestFunction; knownDigestFunction != nil && isFileDigestKnown(leaf, knownDigestFunction) {
+		digestFunction = knownDigestFunction
+	}
+	var fileStatus *remoteoutputservice.FileStatus
+	if cw.fileDigestCache != nil && digestFunction != nil && !isFileDigestKnown(leaf, digestFunction) {
+		fileStatus, err = cw.getFileStatusUsingCache(leaf, digestFunction)
+	} else {
+		fileStatus, err = leaf.GetOutputServiceFileStatus(digestFunction)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cw.verifyingContentAddressableStorage != nil && digestFunction != nil {
+		if digests := leaf.GetContainingDigests().Items(); len(digests) == 1 && digests[0].UsesDigestFunction(*digestFunction) {
+			if err := verifyFileContents(cw.context, cw.verifyingContentAddressableStorage, digests[0]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	cw.fileStatus = fileStatus
+	if fileStatus.GetFile() != nil {
+		cw.leaf = leaf
+	}
+	return nil, nil
+}
+
+// verifyFileContents reads the contents of a file from the Content
+// Addressable Storage, and checks whether they correspond to the
+// file's digest. This can be used to detect corruption of the Content
+// Addressable Storage.
+func verifyFileContents(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, fileDigest digest.Digest) error {
+	generator := fileDigest.GetDigestFunction().NewGenerator(fileDigest.GetSizeBytes())
+	if err := contentAddressableStorage.Get(ctx, fileDigest).IntoWriter(generator); err != nil {
+		return util.StatusWrapf(err, "Failed to read contents of file with digest %#v", fileDigest.String())
+	}
+	if actualDigest := generator.Sum(); actualDigest != fileDigest {
+		return markRemoteDataLoss(status.Errorf(codes.DataLoss, "Contents of file have digest %#v, while %#v was expected", actualDigest.String(), fileDigest.String()))
+	}
+	return nil
+}
+
+func getLeafChangeID(ctx context.Context, leaf virtual.NativeLeaf) uint64 {
+	var attributes virtual.Attributes
+	leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskChangeID, &attributes)
+	return attributes.GetChangeID()
+}
+
+// getFileStatusUsingCache obtains the status of a file whose digest
+// needs to be computed. The digest is obtained from the file digest
+// cache if the file has not been modified since it was last computed.
+func (cw *statWalker) getFileStatusUsingCache(leaf virtual.NativeLeaf, digestFunction *digest.Function) (*remoteoutputservice.FileStatus, error) {
+	key := fileDigestCacheKey{
+		leaf:           leaf,
+		changeID:       getLeafChangeID(cw.context, leaf),
+		digestFunction: *digestFunction,
+	}
+	if fileDigest, ok := cw.fileDigestCache.lookup(key); ok {
+		fileStatus, err := leaf.GetOutputServiceFileStatus(nil)
+		if err != nil {
+			return nil, err
+		}
+		if file := fileStatus.GetFile(); file != nil {
+			file.Digest = fileDigest.GetProto()
+		}
+		return fileStatus, nil
+	}
+
+	fileStatus, err := leaf.GetOutputServiceFileStatus(digestFunction)
+	if err != nil {
+		return nil, err
+	}
+	if file := fileStatus.GetFile(); file != nil && file.Digest != nil {
+		// Only cache the digest if the file was not modified
+		// while the digest was being computed, as the digest
+		// may otherwise not correspond to the change ID.
+		if fileDigest, err := digestFunction.NewDigestFromProto(file.Digest); err == nil && getLeafChangeID(cw.context, leaf) == key.changeID {
+			cw.fileDigestCache.insert(key, fileDigest)
+		}
+	}
+	return fileStatus, nil
+}
+
+// isFileDigestKnown returns whether the digest of a file is known
+// without needing to compute it. This is the case for files that are
+// backed by the Content Addressable Storage.
+func isFileDigestKnown(leaf virtual.NativeLeaf, digestFunction *digest.Function) bool {
+	digests := leaf.GetContainingDigests().Items()
+	return len(digests) == 1 && digests[0].UsesDigestFunction(*digestFunction)
+}
+
+func (cw *statWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		cw.fileStatus = &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		}
+		return path.VoidComponentWalker, nil
+	}
+	cw.depth--
+	return cw, nil
+}
+
+// DigestInclusionMode indicates whether the results of
+// BatchStatWithDigestInclusionMode() should include digests of files.
+type DigestInclusionMode int
+
+const (
+	// DigestInclusionModeNever causes digests of files to be
+	// omitted. This corresponds to calling BatchStat() with
+	// include_file_digest unset.
+	DigestInclusionModeNever DigestInclusionMode = iota
+	// DigestInclusionModeAlways causes digests of files to be
+	// included, computing them if needed. This corresponds to
+	// calling BatchStat() with include_file_digest set.
+	DigestInclusionModeAlways
+	// DigestInclusionModeIfKnown causes digests of files to be
+	// included, only if they are known without needing to be
+	// computed (e.g., for files backed by the Content Addressable
+	// Storage). Digests of files that were written locally are
+	// omitted.
+	DigestInclusionModeIfKnown
+	// DigestInclusionModeAlwaysVerified is identical to
+	// DigestInclusionModeAlways, except that the contents of files
+	// backed by the Content Addressable Storage are read, so that
+	// it can be verified that they correspond to the digest that is
+	// reported. Mismatches are reported as DATA_LOSS. This is
+	// expensive, as it causes all files to be downloaded.
+	DigestInclusionModeAlwaysVerified
+)
+
+func (m DigestInclusionMode) String() string {
+	switch m {
+	case DigestInclusionModeNever:
+		return "NEVER"
+	case DigestInclusionModeAlways:
+		return "ALWAYS"
+	case DigestInclusionModeIfKnown:
+		return "IF_KNOWN"
+	case DigestInclusionModeAlwaysVerified:
+		return "ALWAYS_VERIFIED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// BatchStat can be called by a build client to obtain the status of
+// files and directories.
+//
+// Calling this method over gRPC may be far more efficient than
+// obtaining this information through the FUSE file system, as batching
+// significantly reduces the amount of context switching. It also
+// prevents the computation of digests for files for which the digest is
+// already known.
+//
+// Paths that do not exist, or that have a regular file in place of one
+// of their parent directories, yield a StatResponse without a
+// FileStatus.
+func (d *RemoteOutputServiceDirectory) BatchStat(ctx context.Context, request *remoteoutputservice.BatchStatRequest) (*remoteoutputservice.BatchStatResponse, error) {
+	digestInclusionMode := DigestInclusionModeNever
+	if request.IncludeFileDigest {
+		digestInclusionMode = DigestInclusionModeAlways
+	}
+	return d.BatchStatWithDigestInclusionMode(ctx, request, digestInclusionMode)
+}
+
+// BatchStatWithDigestInclusionMode is identical to BatchStat(), except
+// that the include_file_digest field of the request is ignored. Whether
+// digests of files are included is determined by the provided
+// DigestInclusionMode instead.
+func (d *RemoteOutputServiceDirectory) BatchStatWithDigestInclusionMode(ctx context.Context, request *remoteoutputservice.BatchStatRequest, digestInclusionMode DigestInclusionMode) (*remoteoutputservice.BatchStatResponse, error) {
+	response, _, err := d.batchStat(ctx, request, digestInclusionMode, false, nil, nil)
+	return response, err
+}
+
+// BatchStatWithExecutableBits is identical to
+// BatchStatWithDigestInclusionMode(), except that it also returns
+// whether the executable bit of each regular file is set. The list is
+// aligned with the list of responses. Its entries are false for paths
+// that don't resolve to regular files in the output path. This permits
+// clients to detect files whose mode changed, even if their digest
+// remained the same.
+func (d *RemoteOutputServiceDirectory) BatchStatWithExecutableBits(ctx context.Context, request *remoteoutputservice.BatchStatRequest, digestInclusionMode DigestInclusionMode) (*remoteoutputservice.BatchStatResponse, []bool, error) {
+	executableBits := make([]bool, len(request.Paths))
+	response, _, err := d.batchStat(ctx, request, digestInclusionMode, false, executableBits, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, executableBits, nil
+}
+
+// BatchStatWithMissingPathDetails is identical to
+// BatchStatWithDigestInclusionMode(), except that it also returns why
+// paths that yield a StatResponse without a FileStatus are absent. The
+// list is aligned with the list of responses. Its entries are nil for
+// paths that exist, and NOT_FOUND errors for paths that are absent.
+// These errors contain an ErrorInfo detail indicating whether the final
+// component of the path or one of its parent directories is missing,
+// or whether one of its parents is not a directory. The path that was
+// resolved up to that point and the failing component are provided as
+// metadata, so that clients can give better diagnostics.
+func (d *RemoteOutputServiceDirectory) BatchStatWithMissingPathDetails(ctx context.Context, request *remoteoutputservice.BatchStatRequest, digestInclusionMode DigestInclusionMode) (*remoteoutputservice.BatchStatResponse, []error, error) {
+	missingPathErrors := make([]error, len(request.Paths))
+	response, _, err := d.batchStat(ctx, request, digestInclusionMode, false, nil, missingPathErrors)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, missingPathErrors, nil
+}
+
+// Reasons reported through the ErrorInfo detail of errors returned by
+// BatchStatWithMissingPathDetails().
+const (
+	missingPathReasonLeafNotFound       = "LEAF_NOT_FOUND"
+	missingPathReasonParentNotFound     = "PARENT_NOT_FOUND"
+	missingPathReasonParentNotDirectory = "PARENT_NOT_DIRECTORY"
+)
+
+// newMissingPathError creates the error that is returned by
+// BatchStatWithMissingPathDetails() for a path that is absent. The
+// error contains an ErrorInfo detail, so that clients may determine
+// where resolution failed without needing to parse the error message.
+func newMissingPathError(statPath, resolvedPath string, missingComponent path.Component, reason string) error {
+	var message string
+	switch reason {
+	case missingPathReasonLeafNotFound:
+		message = fmt.Sprintf("Path %#v does not exist, as %#v is not present in directory %#v", statPath, missingComponent.String(), resolvedPath)
+	case missingPathReasonParentNotFound:
+		message = fmt.Sprintf("Path %#v does not exist, as parent directory %#v is not present in directory %#v", statPath, missingComponent.String(), resolvedPath)
+	case missingPathReasonParentNotDirectory:
+		message = fmt.Sprintf("Path %#v does not exist, as %#v in directory %#v is not a directory", statPath, missingComponent.String(), resolvedPath)
+	default:
+		return status.Errorf(codes.NotFound, "Path %#v does not exist", statPath)
+	}
+	s, err := status.New(codes.NotFound, message).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: "github.com/buildbarn/bb-clientd",
+		Metadata: map[string]string{
+			"path":              statPath,
+			"resolved_path":     resolvedPath,
+			"missing_component": missingComponent.String(),
+		},
+	})
+	if err != nil {
+		return util.StatusWrap(err, "Failed to attach error details")
+	}
+	return s.Err()
+}
+
+// BatchStatPartial is identical to BatchStatWithDigestInclusionMode(),
+// except that it does not fail if the context is canceled or its
+// deadline is exceeded while paths are being processed (e.g., due to
+// directories needing to be loaded from the Content Addressable
+// Storage). Instead, it returns the responses of the paths processed
+// up to that point, together with the number of paths at the end of
+// the request that have not been processed. Clients may resume by
+// calling this method again with the remaining paths.
+func (d *RemoteOutputServiceDirectory) BatchStatPartial(ctx context.Context, request *remoteoutputservice.BatchStatRequest, digestInclusionMode DigestInclusionMode) (*remoteoutputservice.BatchStatResponse, int, error) {
+	return d.batchStat(ctx, request, digestInclusionMode, true, nil, nil)
+}
+
+func (d *RemoteOutputServiceDirectory) batchStat(ctx context.Context, request *remoteoutputservice.BatchStatRequest, digestInclusionMode DigestInclusionMode, allowPartial bool, executableBits []bool, missingPathErrors []error) (_ *remoteoutputservice.BatchStatResponse, unprocessedCount int, err error) {
+	accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+		Method:     "BatchStat",
+		BuildID:    request.BuildId,
+		PathsCount: len(request.Paths),
+	}, request.Paths)
+	defer func() { accessLogRecord.finish(err) }()
+
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.BatchStat", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+		attribute.Int("paths_count", len(request.Paths)),
+		attribute.Bool("follow_symlinks", request.FollowSymlinks),
+		attribute.String("digest_inclusion_mode", digestInclusionMode.String()),
+	))
+	defer func() {
+		if unprocessedCount > 0 {
+			span.SetAttributes(attribute.Int("unprocessed_paths_count", unprocessedCount))
+		}
+		endSpan(span, err)
+	}()
+
+	if err := checkRequestEntriesCount("BatchStat", "paths", len(request.Paths), d.configuration.Limits.MaximumBatchStatPathsCount); err != nil {
+		return nil, 0, err
+	}
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, request.BuildId)
+	if err != nil {
+		return nil, 0, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	response := remoteoutputservice.BatchStatResponse{
+		Responses: make([]*remoteoutputservice.StatResponse, 0, len(request.Paths)),
+	}
+	for i, statPath := range request.Paths {
+		if allowPartial && ctx.Err() != nil {
+			return &response, len(request.Paths) - i, nil
+		}
+
+		statWalker := statWalker{
+			context:               ctx,
+			followSymlinks:        request.FollowSymlinks,
+			maximumSymlinkFollows: d.configuration.Limits.MaximumSymlinkFollowsPerPath,
+			maximumDepth:          d.configuration.Limits.MaximumPathDepth,
+			stack:                 util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+			fileStatus: &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_External_{},
+			},
+		}
+		switch digestInclusionMode {
+		case DigestInclusionModeAlways:
+			statWalker.digestFunction = &buildState.digestFunction
+			statWalker.fileDigestCache = d.fileDigestCache
+		case DigestInclusionModeIfKnown:
+			statWalker.knownDigestFunction = &buildState.digestFunction
+		case DigestInclusionModeAlwaysVerified:
+			statWalker.digestFunction = &buildState.digestFunction
+			statWalker.fileDigestCache = d.fileDigestCache
+			statWalker.verifyingContentAddressableStorage = d.contentAddressableStorage.FileReads
+		}
+
+		resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+			buildState.scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
+		if err := path.Resolve(statPath, scopeWalker); err == syscall.ENOENT || err == syscall.ENOTDIR {
+			// Path does not exist, or one of its parent
+			// components is not a directory. Similar to
+			// stat(), both are reported as the path being
+			// absent, so that clients don't need to
+			// distinguish between them.
+			if missingPathErrors != nil {
+				missingPathErrors[i] = newMissingPathError(statPath, resolvedPath.String(), statWalker.missingComponent, statWalker.missingReason)
+			}
+			response.Responses = append(response.Responses, &remoteoutputservice.StatResponse{})
+		} else if err != nil {
+			if allowPartial && ctx.Err() != nil {
+				// Resolution likely failed due to the
+				// context being done. Leave this path
+				// to be retried by the client.
+				return &response, len(request.Paths) - i, nil
+			}
+			// Some other error occurred.
+			return nil, 0, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", statPath, resolvedPath.String())
+		} else {
+			switch fileType := statWalker.fileStatus.FileType.(type) {
+			case *remoteoutputservice.FileStatus_Directory_:
+				// For directories we need to provide the last
+				// modification time, as the client uses that to
+				// invalidate cached results.
+				fileType.Directory = &remoteoutputservice.FileStatus_Directory{
+					LastModifiedTime: d.getDirectoryLastModifiedTime(ctx, outputPathState, statWalker.stack.Peek()),
+				}
+			case *remoteoutputservice.FileStatus_External_:
+				// Path resolves to a location outside the file
+				// system. Return the resolved path back to the
+				// client, so it can stat() it manually.
+				fileType.External = &remoteoutputservice.FileStatus_External{
+					NextPath: resolvedPath.String(),
+				}
+			}
+			if executableBits != nil && statWalker.leaf != nil {
+				executableBits[i] = isExecutableLeaf(ctx, statWalker.leaf)
+			}
+			response.Responses = append(response.Responses, &remoteoutputservice.StatResponse{
+				FileStatus: statWalker.fileStatus,
+			})
+		}
+	}
+	return &response, 0, nil
+}
+
+// PathExistence indicates whether a path exists, as reported by
+// BatchStatExistence().
+type PathExistence int
+
+const (
+	// PathExistenceAbsent indicates that the path does not exist,
+	// or that one of its parent components is not a directory.
+	PathExistenceAbsent PathExistence = iota
+	// PathExistencePresent indicates that the path resolves to a
+	// file, directory or symbolic link in the output path.
+	PathExistencePresent
+	// PathExistenceUnknown indicates that it cannot be determined
+	// whether the path exists without loading the contents of a
+	// directory from the Content Addressable Storage, or that the
+	// path resolves to a location outside the output path.
+	PathExistenceUnknown
+)
+
+func (e PathExistence) String() string {
+	switch e {
+	case PathExistenceAbsent:
+		return "ABSENT"
+	case PathExistencePresent:
+		return "PRESENT"
+	case PathExistenceUnknown:
+		return "UNKNOWN"
+	default:
+		return "INVALID"
+	}
+}
+
+// BatchStatExistence can be called by a build client to determine
+// whether paths exist, without causing the contents of directories to
+// be loaded from the Content Addressable Storage. Paths are resolved
+// in the same way as BatchStat(), except that resolution stops as soon
+// as a child needs to be looked up in a directory whose contents may
+// not have been loaded yet. These are directories created through
+// BatchCreate() (unless EagerlyFetchDirectories is set),
+// StartBuildFromSnapshot() and TrimBuild(), and all directories
+// contained in them. Such paths are reported as PathExistenceUnknown,
+// even if the contents of the directory have been loaded in the
+// meantime. Paths referring to these directories themselves are
+// reported as PathExistencePresent.
+//
+// The include_file_digest field of the request is ignored. This method
+// can only be used if TrackLazyDirectories is set.
+func (d *RemoteOutputServiceDirectory) BatchStatExistence(ctx context.Context, request *remoteoutputservice.BatchStatRequest) (_ []PathExistence, err error) {
+	accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+		Method:     "BatchStatExistence",
+		BuildID:    request.BuildId,
+		PathsCount: len(request.Paths),
+	}, request.Paths)
+	defer func() { accessLogRecord.finish(err) }()
+
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.BatchStatExistence", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+		attribute.Int("paths_count", len(request.Paths)),
+		attribute.Bool("follow_symlinks", request.FollowSymlinks),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if !d.configuration.TrackLazyDirectories {
+		return nil, status.Error(codes.FailedPrecondition, "Tracking of lazy directories is not enabled")
+	}
+	if err := checkRequestEntriesCount("BatchStatExistence", "paths", len(request.Paths), d.configuration.Limits.MaximumBatchStatPathsCount); err != nil {
+		return nil, err
+	}
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	results := make([]PathExistence, 0, len(request.Paths))
+	for _, statPath := range request.Paths {
+		statWalker := statWalker{
+			context:               ctx,
+			followSymlinks:        request.FollowSymlinks,
+			maximumSymlinkFollows: d.configuration.Limits.MaximumSymlinkFollowsPerPath,
+			maximumDepth:          d.configuration.Limits.MaximumPathDepth,
+			stack:                 util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+			fileStatus: &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_External_{},
+			},
+			lazyDirectories: &outputPathState.lazyDirectories,
+		}
+		resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+			buildState.scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
+		if err := path.Resolve(statPath, scopeWalker); err == syscall.ENOENT || err == syscall.ENOTDIR {
+			results = append(results, PathExistenceAbsent)
+		} else if err == errLazyDirectory {
+			results = append(results, PathExistenceUnknown)
+		} else if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", statPath, resolvedPath.String())
+		} else if _, ok := statWalker.fileStatus.FileType.(*remoteoutputservice.FileStatus_External_); ok {
+			results = append(results, PathExistenceUnknown)
+		} else {
+			results = append(results, PathExistencePresent)
+		}
+	}
+	return results, nil
+}
+
+// DirectoryEntry contains the name and status of a single child of a
+// directory, as returned by ReadDirectory().
+type DirectoryEntry struct {
+	Name       path.Component
+	FileStatus *remoteoutputservice.FileStatus
+}
+
+// ReadDirectory can be called by a build client to obtain the names
+// and types of the children of a single directory contained in the
+// output path, without needing to call readdir() through the virtual
+// file system. The path is resolved the same way as done by
+// BatchStat(), with symbolic links being followed. If
+// includeFileDigests is set, the digests of regular files are included,
+// computing them if needed.
+//
+// Only the directory itself is loaded from the Content Addressable
+// Storage. Directories contained in it are not, meaning their entries
+// only indicate that they are directories. Entries are sorted by name.
+func (d *RemoteOutputServiceDirectory) ReadDirectory(ctx context.Context, buildID, directoryPath string, includeFileDigests bool) (_ []DirectoryEntry, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.ReadDirectory", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+		attribute.Bool("include_file_digests", includeFileDigests),
+	))
+	defer func() { endSpan(span, err) }()
+
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	statWalker := statWalker{
+		context:               ctx,
+		followSymlinks:        true,
+		maximumSymlinkFollows: d.configuration.Limits.MaximumSymlinkFollowsPerPath,
+		maximumDepth:          d.configuration.Limits.MaximumPathDepth,
+		stack:                 util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		fileStatus: &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		},
+	}
+	resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+		buildState.scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
+	if err := path.Resolve(directoryPath, scopeWalker); err == syscall.ENOENT || err == syscall.ENOTDIR {
+		return nil, status.Errorf(codes.NotFound, "Path %#v does not exist", directoryPath)
+	} else if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", directoryPath, resolvedPath.String())
+	}
+	if _, ok := statWalker.fileStatus.FileType.(*remoteoutputservice.FileStatus_Directory_); !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v does not resolve to a directory in the output path", directoryPath)
+	}
+
+	directories, leaves, err := statWalker.stack.Peek().LookupAllChildren()
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to read contents of directory %#v", directoryPath)
+	}
+	var digestFunction *digest.Function
+	if includeFileDigests {
+		digestFunction = &buildState.digestFunction
+	}
+	entries := make([]DirectoryEntry, 0, len(directories)+len(leaves))
+	for _, entry := range directories {
+		entries = append(entries, DirectoryEntry{
+			Name: entry.Name,
+			FileStatus: &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_Directory_{
+					Directory: &remoteoutputservice.FileStatus_Directory{},
+				},
+			},
+		})
+	}
+	for _, entry := range leaves {
+		var fileStatus *remoteoutputservice.FileStatus
+		if target, err := entry.Child.Readlink(); err == nil {
+			fileStatus = &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_Symlink_{
+					Symlink: &remoteoutputservice.FileStatus_Symlink{
+						Target: target,
+					},
+				},
+			}
+		} else if err != syscall.EINVAL {
+			return nil, util.StatusWrapf(err, "Failed to read target of symbolic link %#v", entry.Name.String())
+		} else if fileStatus, err = entry.Child.GetOutputServiceFileStatus(digestFunction); err != nil {
+			return nil, util.StatusWrapf(err, "Failed to obtain status of file %#v", entry.Name.String())
+		}
+		entries = append(entries, DirectoryEntry{
+			Name:       entry.Name,
+			FileStatus: fileStatus,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name.String() < entries[j].Name.String()
+	})
+	return entries, nil
+}
+
+// getDirectoryLastModifiedTime returns the last data modification time
+// of a directory, as reported by BatchStat(). If enabled, the value is
+// obtained from the output path's cache.
+func (d *RemoteOutputServiceDirectory) getDirectoryLastModifiedTime(ctx context.Context, outputPathState *outputPathState, directory virtual.PrepopulatedDirectory) *timestamppb.Timestamp {
+	var generation uint64
+	if d.configuration.Caching.CacheDirectoryAttributes {
+		lastModifiedTime, ok, currentGeneration := outputPathState.directoryAttributes.lookup(directory)
+		if ok {
+			return timestamppb.New(lastModifiedTime)
+		}
+		generation = currentGeneration
+	}
+
+	var attributes virtual.Attributes
+	directory.VirtualGetAttributes(ctx, virtual.AttributesMaskLastDataModificationTime, &attributes)
+	lastModifiedTime, ok := attributes.GetLastDataModificationTime()
+	if !ok {
+		panic("Directory did not provide a last data modification time, even though the Remote Output Service protocol requires it")
+	}
+	if d.configuration.Caching.CacheDirectoryAttributes {
+		outputPathState.directoryAttributes.insert(directory, generation, lastModifiedTime)
+	}
+	return timestamppb.New(lastModifiedTime)
+}
+
+// FileContents contains the result of GetFileContents() for a single
+// file. Exactly one of the fields is set.
+type FileContents struct {
+	// The contents of the file, if its size does not exceed the
+	// maximum provided to GetFileContents().
+	Contents []byte
+
+	// The digest of the file, if its size exceeds the maximum
+	// provided to GetFileContents(). The contents of the file may
+	// then be read through the virtual file system, or be
+	// downloaded from the Content Addressable Storage.
+	Digest *remoteexecution.Digest
+}
+
+// GetFileContents can be called by a build client to read the contents
+// of files contained in the output path. Paths are resolved the same
+// way as done by BatchStat(), with symbolic links being followed. This
+// is more efficient than reading small files (e.g., manifests) through
+// the virtual file system, as it doesn't require files to be opened
+// individually.
+//
+// Files whose size exceeds maximumSizeBytes are not returned inline.
+// Their digests are returned instead. Entries of paths that do not
+// exist are set to nil. Paths that resolve to directories or to
+// locations outside the output path cause this method to fail.
+func (d *RemoteOutputServiceDirectory) GetFileContents(ctx context.Context, buildID string, paths []string, maximumSizeBytes uint64) (_ []*FileContents, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.GetFileContents", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+		attribute.Int("paths_count", len(paths)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	results := make([]*FileContents, 0, len(paths))
+	for _, filePath := range paths {
+		leaf, err := d.resolveFile(ctx, outputPathState, buildState, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if leaf == nil {
+			results = append(results, nil)
+			continue
+		}
+
+		fileContents, err := getLeafContents(ctx, leaf, &buildState.digestFunction, maximumSizeBytes)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to read contents of file %#v", filePath)
+		}
+		results = append(results, fileContents)
+	}
+	return results, nil
+}
+
+// resolveFile resolves a path to a regular file in the output path,
+// following symbolic links. If the path does not exist, nil is
+// returned. Paths that resolve to directories or to locations outside
+// the output path cause an error to be returned.
+func (d *RemoteOutputServiceDirectory) resolveFile(ctx context.Context, outputPathState *outputPathState, buildState *buildState, filePath string) (virtual.NativeLeaf, error) {
+	statWalker := statWalker{
+		context:               ctx,
+		followSymlinks:        true,
+		maximumSymlinkFollows: d.configuration.Limits.MaximumSymlinkFollowsPerPath,
+		maximumDepth:          d.configuration.Limits.MaximumPathDepth,
+		stack:                 util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		fileStatus: &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		},
+	}
+	resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+		buildState.scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
+	if err := path.Resolve(filePath, scopeWalker); err == syscall.ENOENT || err == syscall.ENOTDIR {
+		return nil, nil
+	} else if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", filePath, resolvedPath.String())
+	}
+	if _, ok := statWalker.fileStatus.FileType.(*remoteoutputservice.FileStatus_File_); !ok || statWalker.leaf == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v does not resolve to a file in the output path", filePath)
+	}
+	return statWalker.leaf, nil
+}
+
+// ReadFileRange can be called by a build client to read a range of
+// bytes from a file contained in the output path, without reading the
+// file in its entirety. This is useful for tools that only need to
+// inspect part of a large file (e.g., its header). For files backed by
+// the Content Addressable Storage, only the requested range is
+// fetched. Paths are resolved the same way as done by
+// GetFileContents().
+//
+// Fewer bytes than requested are returned if the range extends past
+// the end of the file. The number of bytes that may be requested is
+// bounded by MaximumReadFileRangeSizeBytes.
+func (d *RemoteOutputServiceDirectory) ReadFileRange(ctx context.Context, buildID, filePath string, offset, sizeBytes uint64) (_ []byte, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.ReadFileRange", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+		attribute.Int64("offset", int64(offset)),
+		attribute.Int64("size_bytes", int64(sizeBytes)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if maximumSizeBytes := d.configuration.Limits.MaximumReadFileRangeSizeBytes; sizeBytes > maximumSizeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "Requested %d bytes, which exceeds the permitted maximum of %d bytes", sizeBytes, maximumSizeBytes)
+	}
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+	defer func() {
+		if err != nil {
+			d.detectCorruption(outputPathState, err)
+		}
+	}()
+
+	leaf, err := d.resolveFile(ctx, outputPathState, buildState, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if leaf == nil {
+		return nil, status.Errorf(codes.NotFound, "Path %#v does not exist", filePath)
+	}
+
+	if s := leaf.VirtualOpenSelf(ctx, virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, 0, &virtual.Attributes{}); s != virtual.StatusOK {
+		return nil, status.Error(codes.Internal, "Failed to open file")
+	}
+	defer leaf.VirtualClose(1)
+
+	contents := make([]byte, sizeBytes)
+	n := uint64(0)
+	for n < sizeBytes {
+		nRead, eof, s := leaf.VirtualRead(contents[n:], offset+n)
+		if s != virtual.StatusOK {
+			return nil, status.Errorf(codes.Internal, "Failed to read file at offset %d", offset+n)
+		}
+		n += uint64(nRead)
+		if eof || nRead == 0 {
+			break
+		}
+	}
+	return contents[:n], nil
+}
+
+// getLeafContents returns the contents of a regular file, or its digest
+// if the file is too large to be returned inline.
+func getLeafContents(ctx context.Context, leaf virtual.NativeLeaf, digestFunction *digest.Function, maximumSizeBytes uint64) (*FileContents, error) {
+	var attributes virtual.Attributes
+	leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskSizeBytes, &attributes)
+	sizeBytes, ok := attributes.GetSizeBytes()
+	if !ok {
+		return nil, status.Error(codes.Internal, "File did not provide a size")
+	}
+	if sizeBytes > maximumSizeBytes {
+		fileStatus, err := leaf.GetOutputServiceFileStatus(digestFunction)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to obtain digest")
+		}
+		return &FileContents{
+			Digest: fileStatus.GetFile().GetDigest(),
+		}, nil
+	}
+
+	if s := leaf.VirtualOpenSelf(ctx, virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, 0, &virtual.Attributes{}); s != virtual.StatusOK {
+		return nil, status.Error(codes.Internal, "Failed to open file")
+	}
+	defer leaf.VirtualClose(1)
+
+	// Files that are backed by local storage may be modified while
+	// being read. Never return more data than was announced, so
+	// that the maximum size is respected.
+	contents := make([]byte, sizeBytes)
+	offset := uint64(0)
+	for offset < sizeBytes {
+		n, eof, s := leaf.VirtualRead(contents[offset:], offset)
+		if s != virtual.StatusOK {
+			return nil, status.Errorf(codes.Internal, "Failed to read file at offset %d", offset)
+		}
+		offset += uint64(n)
+		if eof {
+			break
+		}
+	}
+	return &FileContents{
+		Contents: contents[:offset],
+	}, nil
+}
+
+// ResolveSymlink can be called by a build client to resolve an absolute
+// path, following all symbolic links. Whereas BatchStat() returns a
+// FileStatus_External if a path resolves to a location outside the
+// output path, this method continues resolution if the location is
+// contained in the output path of another output base under the same
+// output path prefix. This means that clients only need to resolve
+// paths themselves if they resolve to a location outside of bb_clientd
+// entirely, in which case a FileStatus_External is returned.
+//
+// Paths that do not exist yield a StatResponse without a FileStatus.
+func (d *RemoteOutputServiceDirectory) ResolveSymlink(ctx context.Context, buildID, absolutePath string) (_ *remoteoutputservice.StatResponse, err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.ResolveSymlink", trace.WithAttributes(
+		attribute.String("build_id", buildID),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if !strings.HasPrefix(absolutePath, "/") {
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v is not an absolute path", absolutePath)
+	}
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(ctx, buildID)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("output_base_id", outputPathState.outputBaseID.String()))
+
+	// Resolve the path against the output path of the build. If it
+	// resolves to a location in the output path of another output
+	// base, continue resolution there. Keep track of the paths at
+	// which resolution was restarted, so that cycles spanning
+	// multiple output paths can be detected.
+	scopeWalkerFactory := buildState.scopeWalkerFactory
+	currentPath := absolutePath
+	symlinkFollows := 0
+	visitedPaths := map[string]struct{}{}
+	for {
+		if _, ok := visitedPaths[currentPath]; ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "Path %#v resolves to a cycle of symbolic links spanning multiple output paths", absolutePath)
+		}
+		visitedPaths[currentPath] = struct{}{}
+
+		statWalker := statWalker{
+			context:               ctx,
+			followSymlinks:        true,
+			maximumSymlinkFollows: d.configuration.Limits.MaximumSymlinkFollowsPerPath,
+			maximumDepth:          d.configuration.Limits.MaximumPathDepth,
+			symlinkFollows:        symlinkFollows,
+			stack:                 util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+			fileStatus: &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_External_{},
+			},
+		}
+		resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+			scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
+		if err := path.Resolve(currentPath, scopeWalker); err == syscall.ENOENT || err == syscall.ENOTDIR {
+			return &remoteoutputservice.StatResponse{}, nil
+		} else if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", currentPath, resolvedPath.String())
+		}
+
+		switch fileType := statWalker.fileStatus.FileType.(type) {
+		case *remoteoutputservice.FileStatus_Directory_:
+			fileType.Directory = &remoteoutputservice.FileStatus_Directory{
+				LastModifiedTime: d.getDirectoryLastModifiedTime(ctx, outputPathState, statWalker.stack.Peek()),
+			}
+		case *remoteoutputservice.FileStatus_External_:
+			nextPath := resolvedPath.String()
+			nextOutputPathState, nextOutputPath, ok := d.lookupOutputPathByAbsolutePath(buildState.outputPathPrefix, nextPath)
+			if !ok {
+				// Path resolves to a location outside of
+				// any output path.
+				fileType.External = &remoteoutputservice.FileStatus_External{
+					NextPath: nextPath,
+				}
+				break
+			}
+			nextScopeWalkerFactory, err := path.NewVirtualRootScopeWalkerFactory(nextOutputPath, nil)
+			if err != nil {
+				return nil, util.StatusWrapf(err, "Failed to create virtual root for output path %#v", nextOutputPath)
+			}
+			outputPathState = nextOutputPathState
+			scopeWalkerFactory = nextScopeWalkerFactory
+			currentPath = nextPath
+			symlinkFollows = statWalker.symlinkFollows
+			continue
+		}
+		return &remoteoutputservice.StatResponse{
+			FileStatus: statWalker.fileStatus,
+		}, nil
+	}
+}
+
+// lookupOutputPathByAbsolutePath returns the state of the output path
+// in which an absolute path is contained, if any, and the absolute path
+// of the output path.
+func (d *RemoteOutputServiceDirectory) lookupOutputPathByAbsolutePath(outputPathPrefix, absolutePath string) (*outputPathState, string, bool) {
+	outputPathPrefix = strings.TrimSuffix(outputPathPrefix, "/") + "/"
+	if !strings.HasPrefix(absolutePath, outputPathPrefix) {
+		return nil, "", false
+	}
+	outputBaseIDString, _, _ := strings.Cut(absolutePath[len(outputPathPrefix):], "/")
+	outputBaseID, ok := path.NewComponent(outputBaseIDString)
+	if !ok {
+		return nil, "", false
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseID)]
+	if !ok || outputPathState.corrupted {
+		return nil, "", false
+	}
+	return outputPathState, outputPathPrefix + outputBaseIDString, true
+}
+
+// FinalizeBuild can be called by a build client to indicate the current
+// build has completed. This prevents successive BatchCreate() and
+// BatchStat() calls from being processed.
+func (d *RemoteOutputServiceDirectory) FinalizeBuild(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (_ *emptypb.Empty, err error) {
+	accessLogRecord := d.startAccessLogRecord(AccessLogEntry{
+		Method:  "FinalizeBuild",
+		BuildID: request.BuildId,
+	}, nil)
+	defer func() { accessLogRecord.finish(err) }()
+
+	if err := d.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	d.FinalizeBuildWithStatistics(ctx, request)
+	return &emptypb.Empty{}, nil
+}
+
+// FinalizeBuildWithStatistics is identical to FinalizeBuild(), except
+// that it returns the amount of data that was loaded from the Content
+// Addressable Storage while the build was running. This can be used
+// for cost attribution. Zero is returned for unknown build IDs.
+//
+// Data is attributed to the build that is running against the output
+// path at the time it is loaded. As the contents of files and
+// directories are loaded lazily, this may include data belonging to
+// outputs of previous builds.
+func (d *RemoteOutputServiceDirectory) FinalizeBuildWithStatistics(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) *BuildStatistics {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.FinalizeBuild", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+	))
+	defer span.End()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	// Silently ignore requests for unknown build IDs. This ensures
+	// that FinalizeBuild() remains idempotent.
+	var statistics BuildStatistics
+	if outputPathState, ok := d.buildIDs[request.BuildId]; ok {
+		buildState := outputPathState.buildState
+		if !outputPathState.corrupted {
+			// Don't finalize output paths that are
+			// corrupted, as that could cause their
+			// contents to be persisted.
+			outputPathState.rootDirectory.FinalizeBuild(ctx, buildState.digestFunction)
+			if d.configuration.Builds.FreezeOutputPathsBetweenBuilds {
+				outputPathState.rootDirectory.Freeze()
+			}
+		}
+		delete(d.buildIDs, buildState.id)
+		outputPathState.buildState = nil
+		buildState.events.close()
+
+		currentStatistics := outputPathState.statistics.get()
+		statistics = BuildStatistics{
+			CASFileBytesRead:      currentStatistics.CASFileBytesRead - buildState.initialStatistics.CASFileBytesRead,
+			DirectoryBytesFetched: currentStatistics.DirectoryBytesFetched - buildState.initialStatistics.DirectoryBytesFetched,
+		}
+	}
+	return &statistics
+}
+
+// FinalizeBuildWithRemovedChildren is identical to FinalizeBuild(),
+// except that it returns the children that were removed from the output
+// path at the start of the build. Clients may use this to determine
+// which outputs need to be marked dirty. No children are returned for
+// unknown build IDs, or if the contents of the output path were
+// filtered by PrepareOutputBase().
+func (d *RemoteOutputServiceDirectory) FinalizeBuildWithRemovedChildren(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (RemovedChildren, error) {
+	if err := d.checkWritable(); err != nil {
+		return RemovedChildren{}, err
+	}
+
+	var removedChildren *removedChildList
+	d.lock.Lock()
+	if outputPathState, ok := d.buildIDs[request.BuildId]; ok {
+		removedChildren = &outputPathState.buildState.removedChildren
+	}
+	d.lock.Unlock()
+
+	d.FinalizeBuildWithStatistics(ctx, request)
+	if removedChildren == nil {
+		return RemovedChildren{}, nil
+	}
+	return removedChildren.get(), nil
+}
+
+// FinalizeBuildWithSnapshot is identical to FinalizeBuild(), except
+// that the contents of the output path are stored in the Content
+// Addressable Storage in the form of a Tree object prior to finalizing
+// the build. A reference to the Tree object is stored in the configured
+// SnapshotStore under the name provided, so that the output path can
+// be repopulated with the results of this build by calling
+// RestoreSnapshot(). The build is not finalized if creating the
+// snapshot fails, permitting the client to retry.
+func (d *RemoteOutputServiceDirectory) FinalizeBuildWithSnapshot(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest, snapshotName string) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.FinalizeBuildWithSnapshot", trace.WithAttributes(
+		attribute.String("build_id", request.BuildId),
+		attribute.String("snapshot_name", snapshotName),
+	))
+	defer func() { endSpan(span, err) }()
+
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+	snapshotStore := d.configuration.Snapshots.Store
+	if snapshotStore == nil {
+		return status.Error(codes.FailedPrecondition, "No snapshot store has been configured")
+	}
+	outputPathState, buildState, err := d.lookupBuild(request.BuildId)
+	if err != nil {
+		return err
+	}
+
+	outputPathState.contentsLock.Lock()
+	treeDigest, err := UploadOutputPathTree(ctx, outputPathState.rootDirectory, d.contentAddressableStorage.Uploads, buildState.digestFunction, d.onDemandSnapshotUploadConcurrency)
+	outputPathState.contentsLock.Unlock()
+	if err != nil {
+		d.detectCorruption(outputPathState, err)
+		return util.StatusWrap(err, "Failed to create snapshot of the output path")
+	}
+	span.SetAttributes(attribute.String("tree_digest", treeDigest.String()))
+
+	if err := snapshotStore.Put(ctx, snapshotName, Snapshot{
+		OutputBaseID:    outputPathState.outputBaseID.String(),
+		BuildID:         buildState.id,
+		TreeDigest:      treeDigest,
+		CreationTime:    d.clock.Now(),
+		BuildSuccessful: request.BuildSuccessful,
+	}); err != nil {
+		return util.StatusWrapf(err, "Failed to store snapshot %#v", snapshotName)
+	}
+
+	d.FinalizeBuildWithStatistics(ctx, request)
+	return nil
+}
+
+// RestoreSnapshot is identical to StartBuild(), except that the
+// contents of the output path are replaced with those of a snapshot
+// that was previously stored by calling FinalizeBuildWithSnapshot().
+// The snapshot may have been created for a different output base.
+func (d *RemoteOutputServiceDirectory) RestoreSnapshot(ctx context.Context, request *remoteoutputservice.StartBuildRequest, snapshotName string) (*remoteoutputservice.StartBuildResponse, error) {
+	if err := d.checkWritable(); err != nil {
+		return nil, err
+	}
+	snapshotStore := d.configuration.Snapshots.Store
+	if snapshotStore == nil {
+		return nil, status.Error(codes.FailedPrecondition, "No snapshot store has been configured")
+	}
+	snapshot, err := snapshotStore.Get(ctx, snapshotName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to obtain snapshot %#v", snapshotName)
+	}
+	return d.StartBuildFromSnapshot(ctx, request, snapshot.TreeDigest.GetProto())
+}
+
+// Checkpoint persists the state of all output paths, as opposed to
+// waiting for builds running against them to be finalized. This can be
+// called prior to a planned restart, so that no state is lost. Output
+// paths are frozen while their state is persisted, so that concurrent
+// calls to BatchCreate() don't cause inconsistent state to be written.
+// Output paths that are corrupted are skipped. The number of output
+// paths that were checkpointed is returned.
+//
+// Whether state is actually written to disk depends on the
+// OutputPathFactory in use. For output paths that are only stored in
+// memory, this method has no effect.
+func (d *RemoteOutputServiceDirectory) Checkpoint(ctx context.Context) (_ int, err error) {
+	_, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.Checkpoint")
+	defer func() { endSpan(span, err) }()
+
+	d.lock.Lock()
+	var outputPathStates []*outputPathState
+	for outputPathState := d.outputPaths.next; outputPathState != &d.outputPaths; outputPathState = outputPathState.next {
+		if !outputPathState.corrupted {
+			outputPathStates = append(outputPathStates, outputPathState)
+		}
+	}
+	d.lock.Unlock()
+
+	for i, outputPathState := range outputPathStates {
+		outputPathState.contentsLock.Lock()
+		err := outputPathState.rootDirectory.Checkpoint()
+		outputPathState.contentsLock.Unlock()
+		if err != nil {
+			return i, util.StatusWrapf(err, "Failed to checkpoint output path %#v", outputPathState.outputBaseID.String())
+		}
+	}
+	span.SetAttributes(attribute.Int("output_paths_count", len(outputPathStates)))
+	return len(outputPathStates), nil
+}
+
+// getShuttingDownError returns the error that is returned when
+// attempting to start a build after Shutdown() has been called.
+func getShuttingDownError() error {
+	return status.Error(codes.Unavailable, "Remote Output Service is shutting down")
+}
+
+// Shutdown can be called when bb_clientd is about to terminate. It
+// causes successive attempts to start builds to fail with UNAVAILABLE,
+// and waits for all builds that are still running to be finalized.
+// Afterwards, the state of all output paths is persisted by calling
+// Checkpoint(). If the context is canceled before all builds are
+// finalized, Shutdown() stops waiting, but still persists the state of
+// all output paths, so that no state is lost.
+//
+// Builds that are still running may continue to call BatchCreate()
+// and BatchStat() until they are finalized.
+func (d *RemoteOutputServiceDirectory) Shutdown(ctx context.Context) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.Shutdown")
+	defer func() { endSpan(span, err) }()
+
+	d.lock.Lock()
+	d.shuttingDown = true
+	buildsDone := make([]<-chan struct{}, 0, len(d.buildIDs))
+	for _, outputPathState := range d.buildIDs {
+		buildsDone = append(buildsDone, outputPathState.buildState.events.done)
+	}
+	d.lock.Unlock()
+
+	var waitErr error
+	for _, buildDone := range buildsDone {
+		select {
+		case <-buildDone:
+			continue
+		case <-ctx.Done():
+		}
+		runningBuildsCount := 0
+		for _, buildDone := range buildsDone {
+			select {
+			case <-buildDone:
+			default:
+				runningBuildsCount++
+			}
+		}
+		if runningBuildsCount > 0 {
+			waitErr = util.StatusWrapf(util.StatusFromContext(ctx), "%d builds were not finalized", runningBuildsCount)
+		}
+		break
+	}
+
+	// Don't let cancelation of the context prevent state from
+	// being persisted.
+	if _, err := d.Checkpoint(trace.ContextWithSpan(context.Background(), span)); err != nil {
+		return err
+	}
+	return waitErr
+}
+
+// CancelBuild can be called by a build client to indicate that the
+// current build was aborted. Like FinalizeBuild(), it prevents
+// successive BatchCreate() and BatchStat() calls from being processed.
+// Unlike FinalizeBuild(), the contents of the output path are not
+// finalized.
+//
+// If revertOutputPath is set, the contents of the output path are
+// reverted to the snapshot that was created at the start of the build,
+// thereby discarding any changes made by BatchCreate(). This requires
+// that Snapshots.UploadConcurrency is set.
+func (d *RemoteOutputServiceDirectory) CancelBuild(ctx context.Context, buildID string, revertOutputPath bool) error {
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+	d.lock.Lock()
+	outputPathState, ok := d.buildIDs[buildID]
+	if !ok {
+		// Silently ignore requests for unknown build IDs, for
+		// consistency with FinalizeBuild().
+		d.lock.Unlock()
+		return nil
+	}
+	buildState := outputPathState.buildState
+	if revertOutputPath && buildState.initialContentsDigest == digest.BadDigest {
+		d.lock.Unlock()
+		return status.Error(codes.FailedPrecondition, "No snapshot of the output path was created at the start of the build, meaning it cannot be reverted")
+	}
+	delete(d.buildIDs, buildState.id)
+	outputPathState.buildState = nil
+	buildState.events.close()
+	d.lock.Unlock()
+
+	if revertOutputPath {
+		outputPathState.contentsLock.Lock()
+		defer outputPathState.contentsLock.Unlock()
+
+		// Don't revert the output path if another build was
+		// started in the meantime, as that would discard
+		// changes made by that build.
+		d.lock.Lock()
+		buildStarted := outputPathState.buildState != nil
+		d.lock.Unlock()
+		if buildStarted {
+			return status.Error(codes.Aborted, "Another build was started against the output path, meaning it can no longer be reverted")
+		}
+
+		if err := d.replaceOutputPathContents(ctx, outputPathState, buildState.digestFunction, buildState.initialContentsDigest); err != nil {
+			d.detectCorruption(outputPathState, err)
+			return util.StatusWrap(err, "Failed to revert the output path to its snapshot")
+		}
+	}
+	return nil
+}
+
+// replaceOutputPathContents replaces the contents of an output path
+// with the contents of a snapshot created by UploadOutputPathTree().
+// Directories contained in the snapshot are loaded from the Content
+// Addressable Storage lazily. The caller must hold the output path's
+// contentsLock exclusively.
+func (d *RemoteOutputServiceDirectory) replaceOutputPathContents(ctx context.Context, outputPathState *outputPathState, digestFunction digest.Function, snapshotDigest digest.Digest) (err error) {
+	ctx, span := d.tracer.Start(ctx, "RemoteOutputServiceDirectory.replaceOutputPathContents", trace.WithAttributes(
+		attribute.String("output_base_id", outputPathState.outputBaseID.String()),
+		attribute.String("snapshot_digest", snapshotDigest.String()),
+	))
+	defer func() { endSpan(span, err) }()
+	defer outputPathState.markModified()
+
+	directoryWalker := cd_cas.NewTreeDirectoryWalker(outputPathState.directoryFetcher, snapshotDigest)
+	contents, err := directoryWalker.GetDirectory(ctx)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to fetch root directory of the snapshot")
+	}
+
+	// Ensure that leaves are properly unlinked if this method fails.
+	initialNodes := map[path.Component]virtual.InitialNode{}
+	defer func() {
+		for _, initialNode := range initialNodes {
+			if _, leaf := initialNode.GetPair(); leaf != nil {
+				leaf.Unlink()
+			}
+		}
+	}()
+
+	for _, entry := range contents.Directories {
+		component, ok := path.NewComponent(entry.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v has an invalid name", entry.Name)
+		}
+		if _, ok := initialNodes[component]; ok {
+			return status.Errorf(codes.InvalidArgument, "Directory contains multiple children named %#v", entry.Name)
+		}
+		childDigest, err := digestFunction.NewDigestFromProto(entry.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid digest for directory %#v", entry.Name)
+		}
+		initialNodes[component] = virtual.InitialNode{}.FromDirectory(
+			virtual.NewCASInitialContentsFetcher(
+				context.Background(),
+				directoryWalker.GetChild(childDigest),
+				outputPathState.casFileFactory,
+				d.symlinkFactory,
+				digestFunction))
+	}
+	for _, entry := range contents.Files {
+		component, ok := path.NewComponent(entry.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "File %#v has an invalid name", entry.Name)
+		}
+		if _, ok := initialNodes[component]; ok {
+			return status.Errorf(codes.InvalidArgument, "Directory contains multiple children named %#v", entry.Name)
+		}
+		childDigest, err := digestFunction.NewDigestFromProto(entry.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid digest for file %#v", entry.Name)
+		}
+		initialNodes[component] = virtual.InitialNode{}.FromLeaf(outputPathState.casFileFactory.LookupFile(childDigest, entry.IsExecutable))
+	}
+	for _, entry := range contents.Symlinks {
+		component, ok := path.NewComponent(entry.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Symlink %#v has an invalid name", entry.Name)
+		}
+		if _, ok := initialNodes[component]; ok {
+			return status.Errorf(codes.InvalidArgument, "Directory contains multiple children named %#v", entry.Name)
+		}
+		initialNodes[d.nameInterner.internComponent(component)] = virtual.InitialNode{}.FromLeaf(d.symlinkFactory.LookupSymlink(d.nameInterner.internSymlinkTarget([]byte(entry.Target))))
+	}
+
+	rootDirectory := outputPathState.rootDirectory
+	outputPathState.trimmableDirectories.clear()
+	if err := rootDirectory.RemoveAllChildren(false); err != nil {
+		return util.StatusWrap(err, "Failed to remove contents of the output path")
+	}
+	outputPathState.lazyDirectories.clear()
+	d.releaseDirectoryMemory(outputPathState)
+	if err := rootDirectory.CreateChildren(initialNodes, true); err != nil {
+		outputPathState.contentCounters.reset(0, 0, 0, 0)
+		return util.StatusWrap(err, "Failed to create contents of the output path")
+	}
+	initialNodes = nil
+	for _, entry := range contents.Directories {
+		d.trackLazyDirectory(outputPathState, rootDirectory, path.MustNewComponent(entry.Name))
+	}
+
+	// Only the children of the root directory are accounted for,
+	// as the contents of directories are loaded lazily.
+	var filesSizeBytes int64
+	for _, entry := range contents.Files {
+		filesSizeBytes += entry.Digest.GetSizeBytes()
+	}
+	outputPathState.contentCounters.reset(int64(len(contents.Files)), int64(len(contents.Directories)), int64(len(contents.Symlinks)), filesSizeBytes)
+	return nil
+}
+
+// CloneOutputPath creates a copy of the output path belonging to one
+// output base, and stores it under another output base. This can be
+// used to let many output bases start off with identical contents,
+// without requiring each of them to be populated by a build.
+//
+// The source output path is converted to a snapshot, which is stored
+// in the Content Addressable Storage. The destination output path is
+// then populated with the contents of the snapshot. This means that
+// both output paths share the same immutable files, while changes made
+// to one of them afterwards don't affect the other.
+//
+// If the destination output path already exists, this function fails,
+// unless overwrite is set. Output paths against which a build is
+// running cannot be overwritten.
+func (d *RemoteOutputServiceDirectory) CloneOutputPath(ctx context.Context, sourceOutputBaseID, destinationOutputBaseID string, overwrite bool) error {
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+	sourceComponent, ok := path.NewComponent(sourceOutputBaseID)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "Source output base ID is not a valid filename")
+	}
+	destinationComponent, ok := path.NewComponent(destinationOutputBaseID)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "Destination output base ID is not a valid filename")
+	}
+	if d.getOutputBaseIDKey(sourceComponent) == d.getOutputBaseIDKey(destinationComponent) {
+		return status.Error(codes.InvalidArgument, "Source and destination output base IDs are identical")
+	}
+
+	d.lock.Lock()
+	sourceState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(sourceComponent)]
+	if !ok {
+		d.lock.Unlock()
+		return status.Errorf(codes.NotFound, "Output path %#v does not exist", sourceOutputBaseID)
+	}
+	if sourceState.corrupted {
+		d.lock.Unlock()
+		return getCorruptedOutputPathError(sourceComponent)
+	}
+	digestFunction := sourceState.digestFunction
+	if destinationState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(destinationComponent)]; ok {
+		if !overwrite {
+			d.lock.Unlock()
+			return status.Errorf(codes.AlreadyExists, "Output path %#v already exists", destinationOutputBaseID)
+		}
+		if destinationState.buildState != nil {
+			d.lock.Unlock()
+			return status.Errorf(codes.FailedPrecondition, "Output path %#v cannot be overwritten, as a build is running against it", destinationOutputBaseID)
+		}
+	}
+	d.lock.Unlock()
+
+	// Create a snapshot of the source output path. Freeze its
+	// contents while doing so.
+	sourceState.contentsLock.Lock()
+	snapshotDigest, err := UploadOutputPathTree(ctx, sourceState.rootDirectory, d.contentAddressableStorage.Uploads, digestFunction, d.onDemandSnapshotUploadConcurrency)
+	sourceState.contentsLock.Unlock()
+	if err != nil {
+		d.detectCorruption(sourceState, err)
+		return util.StatusWrapf(err, "Failed to create snapshot of output path %#v", sourceOutputBaseID)
+	}
+
+	// Look up the destination output path once more, as it may
+	// have been created or removed in the meantime.
+	d.lock.Lock()
+	destinationState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(destinationComponent)]
+	if ok {
+		if !overwrite {
+			d.lock.Unlock()
+			return status.Errorf(codes.AlreadyExists, "Output path %#v already exists", destinationOutputBaseID)
+		}
+		if destinationState.buildState != nil {
+			d.lock.Unlock()
+			return status.Errorf(codes.FailedPrecondition, "Output path %#v cannot be overwritten, as a build is running against it", destinationOutputBaseID)
+		}
+		destinationState.digestFunction = digestFunction
+	} else {
+		destinationState = d.createOutputPath(destinationComponent, digestFunction)
+	}
+	d.lock.Unlock()
+
+	destinationState.contentsLock.Lock()
+	defer destinationState.contentsLock.Unlock()
+	if d.configuration.Builds.FreezeOutputPathsBetweenBuilds {
+		// Freeze the destination output path afterwards, unless
+		// a build has been started against it in the meantime.
+		destinationState.rootDirectory.Unfreeze()
+		defer func() {
+			d.lock.Lock()
+			if destinationState.buildState == nil {
+				destinationState.rootDirectory.Freeze()
+			}
+			d.lock.Unlock()
+		}()
+	}
+	if err := d.replaceOutputPathContents(ctx, destinationState, digestFunction, snapshotDigest); err != nil {
+		d.detectCorruption(destinationState, err)
+		return util.StatusWrapf(err, "Failed to populate output path %#v", destinationOutputBaseID)
+	}
+	return nil
+}
+
+// RenameOutputBase moves the output path belonging to one output base,
+// so that it is stored under another output base. As opposed to
+// CloneOutputPath(), this does not require the creation of a snapshot,
+// as the contents of the output path are retained as is.
+//
+// Output paths against which a build is running cannot be renamed, as
+// the build client would otherwise end up writing outputs into a
+// directory that no longer exists. If the destination output path
+// already exists, this function fails.
+func (d *RemoteOutputServiceDirectory) RenameOutputBase(ctx context.Context, sourceOutputBaseID, destinationOutputBaseID string) error {
+	if err := d.checkWritable(); err != nil {
+		return err
+	}
+	sourceComponent, ok := path.NewComponent(sourceOutputBaseID)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "Source output base ID is not a valid filename")
+	}
+	destinationComponent, ok := path.NewComponent(destinationOutputBaseID)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "Destination output base ID is not a valid filename")
+	}
+	if d.getOutputBaseIDKey(sourceComponent) == d.getOutputBaseIDKey(destinationComponent) {
+		return status.Error(codes.InvalidArgument, "Source and destination output base IDs are identical")
+	}
+
+	d.lock.Lock()
+	state, ok := d.outputBaseIDs[d.getOutputBaseIDKey(sourceComponent)]
+	if !ok {
+		d.lock.Unlock()
+		return status.Errorf(codes.NotFound, "Output path %#v does not exist", sourceOutputBaseID)
+	}
+	if state.corrupted {
+		d.lock.Unlock()
+		return getCorruptedOutputPathError(sourceComponent)
+	}
+	if state.buildState != nil {
+		d.lock.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "Output path %#v cannot be renamed, as a build is running against it", sourceOutputBaseID)
 	}
-	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&outputParentCreator)); err != nil {
-		return util.StatusWrap(err, "Failed to resolve path")
+	if _, ok := d.outputBaseIDs[d.getOutputBaseIDKey(destinationComponent)]; ok {
+		d.lock.Unlock()
+		return status.Errorf(codes.AlreadyExists, "Output path %#v already exists", destinationOutputBaseID)
 	}
-	name := outputParentCreator.TerminalName
-	if name == nil {
-		return status.Errorf(codes.InvalidArgument, "Path resolves to a directory")
+
+	// Use the output base ID with which the output path was
+	// created, as it may differ in case.
+	sourceComponent = state.outputBaseID
+
+	// Store the output path under its new output base ID. Move it
+	// to the end of the directory listing, so that partial reads
+	// performed by VirtualReadDir() continue to observe
+	// monotonically increasing cookies.
+	d.sortedOutputPaths.remove(state)
+	delete(d.outputBaseIDs, d.getOutputBaseIDKey(sourceComponent))
+	d.outputBaseIDs[d.getOutputBaseIDKey(destinationComponent)] = state
+	state.outputBaseID = destinationComponent
+	state.previous.next = state.next
+	state.next.previous = state.previous
+	state.previous = d.outputPaths.previous
+	state.next = &d.outputPaths
+	state.cookie = d.changeID
+	state.previous.next = state
+	state.next.previous = state
+	d.sortedOutputPaths.insert(state)
+	d.changeID++
+	d.markAccessed(state)
+	d.lock.Unlock()
+
+	// There is no need to notify the virtual file system about the
+	// creation of the destination, as it is picked up through the
+	// directory's change ID.
+	d.handle.NotifyRemoval(sourceComponent)
+
+	// Ensure that any persistent state is stored under the new
+	// output base ID, so that it gets reloaded after a restart.
+	// Prevent the output path from being modified while doing so.
+	state.contentsLock.Lock()
+	defer state.contentsLock.Unlock()
+	if err := state.rootDirectory.Rename(destinationComponent); err != nil {
+		d.detectCorruption(state, err)
+		return util.StatusWrapf(err, "Failed to rename output path %#v to %#v", sourceOutputBaseID, destinationOutputBaseID)
 	}
-	return outputParentCreator.stack.Peek().CreateChildren(
-		map[path.Component]virtual.InitialNode{
-			*name: initialNode,
-		},
-		true)
+	return nil
 }
 
-// parentDirectoryCreatingComponentWalker is an implementation of
-// ComponentWalker that is used by BatchCreate() to resolve the parent
-// directory of the path where a file, directory or symlink needs to be
-// created.
-type parentDirectoryCreatingComponentWalker struct {
-	path.TerminalNameTrackingComponentWalker
-	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
-}
+// SelfTestOutputBaseID is the output base ID of the output path that is
+// used by DiagnoseSelfTest(). Build clients should not use it.
+const SelfTestOutputBaseID = "bb_clientd_self_test"
 
-func (cw *parentDirectoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
-	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(name)
-	if err != nil {
-		return nil, err
+const (
+	selfTestBuildID  = "bb_clientd-self-test"
+	selfTestFilePath = "self_test.txt"
+)
+
+var selfTestFileContents = []byte("This file was created by the bb_clientd self-test.\n")
+
+// SelfTestStage identifies one of the stages that are performed by
+// DiagnoseSelfTest().
+type SelfTestStage int
+
+const (
+	// SelfTestStageUpload uploads a small blob into the Content
+	// Addressable Storage.
+	SelfTestStageUpload SelfTestStage = iota
+	// SelfTestStageStartBuild starts a build against the output
+	// path having output base ID SelfTestOutputBaseID.
+	SelfTestStageStartBuild
+	// SelfTestStageBatchCreate creates a file in the output path
+	// that refers to the blob that was uploaded.
+	SelfTestStageBatchCreate
+	// SelfTestStageBatchStat checks that the file is reported as
+	// having the digest of the blob that was uploaded.
+	SelfTestStageBatchStat
+	// SelfTestStageRead reads the contents of the file, causing
+	// them to be loaded from the Content Addressable Storage.
+	SelfTestStageRead
+	// SelfTestStageClean finalizes the build and cleans the output
+	// path.
+	SelfTestStageClean
+)
+
+func (s SelfTestStage) String() string {
+	switch s {
+	case SelfTestStageUpload:
+		return "UPLOAD"
+	case SelfTestStageStartBuild:
+		return "START_BUILD"
+	case SelfTestStageBatchCreate:
+		return "BATCH_CREATE"
+	case SelfTestStageBatchStat:
+		return "BATCH_STAT"
+	case SelfTestStageRead:
+		return "READ"
+	case SelfTestStageClean:
+		return "CLEAN"
+	default:
+		return "UNKNOWN"
 	}
-	cw.stack.Push(child)
-	return path.GotDirectory{
-		Child:        cw,
-		IsReversible: true,
-	}, nil
 }
 
-func (cw *parentDirectoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
-	if _, ok := cw.stack.PopSingle(); !ok {
-		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
-	}
-	return cw, nil
+// SelfTestStageResult contains the outcome of a single stage performed
+// by DiagnoseSelfTest(). Err is nil if the stage passed.
+type SelfTestStageResult struct {
+	Stage SelfTestStage
+	Err   error
 }
 
-// BatchCreate can be called by a build client to create files, symbolic
-// links and directories.
+// DiagnoseSelfTest can be used to validate a deployment, by exercising
+// the full pipeline of storing build outputs: a small blob is uploaded
+// into the Content Addressable Storage, after which a build is started
+// against a reserved output base. A file referring to the blob is then
+// created, stat()ed and read back.
 //
-// Because files and directories are provided in the form of OutputFile
-// and OutputDirectory messages, this implementation is capable of
-// creating files and directories whose contents get loaded from the
-// Content Addressable Storage lazily.
-func (d *RemoteOutputServiceDirectory) BatchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*emptypb.Empty, error) {
-	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+// Results are returned for every stage that was performed. Stages
+// following the first stage that failed are skipped, except for the
+// final stage that cleans the reserved output base, which is performed
+// if the build was started successfully. Only one self-test is
+// performed at a time.
+func (d *RemoteOutputServiceDirectory) DiagnoseSelfTest(ctx context.Context, instanceName string, digestFunction remoteexecution.DigestFunction_Value) ([]SelfTestStageResult, error) {
+	parsedInstanceName, err := digest.NewInstanceName(instanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to parse instance name %#v", instanceName)
+	}
+	parsedDigestFunction, err := parsedInstanceName.GetDigestFunction(digestFunction, 0)
 	if err != nil {
 		return nil, err
 	}
-
-	// Resolve the path prefix. Optionally, remove all of its contents.
-	prefixCreator := directoryCreatingComponentWalker{
-		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	generator := parsedDigestFunction.NewGenerator(int64(len(selfTestFileContents)))
+	if _, err := generator.Write(selfTestFileContents); err != nil {
+		return nil, err
 	}
-	if err := path.Resolve(request.PathPrefix, path.NewRelativeScopeWalker(&prefixCreator)); err != nil {
-		return nil, util.StatusWrap(err, "Failed to create path prefix directory")
+	fileDigest := generator.Sum()
+
+	d.selfTestLock.Lock()
+	defer d.selfTestLock.Unlock()
+
+	var results []SelfTestStageResult
+	runStage := func(stage SelfTestStage, f func() error) bool {
+		err := f()
+		results = append(results, SelfTestStageResult{
+			Stage: stage,
+			Err:   err,
+		})
+		return err == nil
 	}
-	if request.CleanPathPrefix {
-		if err := prefixCreator.stack.Peek().RemoveAllChildren(false); err != nil {
-			return nil, util.StatusWrap(err, "Failed to clean path prefix directory")
-		}
+
+	if !runStage(SelfTestStageUpload, func() error {
+		return d.contentAddressableStorage.Uploads.Put(ctx, fileDigest, buffer.NewValidatedBufferFromByteSlice(selfTestFileContents))
+	}) || !runStage(SelfTestStageStartBuild, func() error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     SelfTestOutputBaseID,
+			BuildId:          selfTestBuildID,
+			InstanceName:     instanceName,
+			DigestFunction:   digestFunction,
+			OutputPathPrefix: "/",
+		})
+		return err
+	}) {
+		return results, nil
 	}
 
-	// Create requested files.
-	for _, entry := range request.Files {
-		childDigest, err := buildState.digestFunction.NewDigestFromProto(entry.Digest)
+	_ = runStage(SelfTestStageBatchCreate, func() error {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: selfTestBuildID,
+			Files: []*remoteexecution.OutputFile{{
+				Path:   selfTestFilePath,
+				Digest: fileDigest.GetProto(),
+			}},
+		})
+		return err
+	}) && runStage(SelfTestStageBatchStat, func() error {
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           selfTestBuildID,
+			IncludeFileDigest: true,
+			Paths:             []string{selfTestFilePath},
+		})
 		if err != nil {
-			return nil, util.StatusWrapf(err, "Invalid digest for file %#v", entry.Path)
+			return err
 		}
-		leaf := outputPathState.casFileFactory.LookupFile(childDigest, entry.IsExecutable)
-		if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
-			leaf.Unlink()
-			return nil, util.StatusWrapf(err, "Failed to create file %#v", entry.Path)
+		file := response.Responses[0].GetFileStatus().GetFile()
+		if file == nil {
+			return status.Errorf(codes.NotFound, "Path %#v does not resolve to a file", selfTestFilePath)
 		}
-	}
-
-	// Create requested directories.
-	for _, entry := range request.Directories {
-		childDigest, err := buildState.digestFunction.NewDigestFromProto(entry.TreeDigest)
-		if err != nil {
-			return nil, util.StatusWrapf(err, "Invalid digest for directory %#v", entry.Path)
+		if reportedDigest, err := parsedDigestFunction.NewDigestFromProto(file.Digest); err != nil || reportedDigest != fileDigest {
+			return status.Errorf(codes.Internal, "File %#v is not reported as having digest %#v", selfTestFilePath, fileDigest.String())
 		}
-		if sizeBytes := childDigest.GetSizeBytes(); sizeBytes > d.maximumTreeSizeBytes {
-			return nil, status.Errorf(codes.InvalidArgument, "Directory %#v is %d bytes in size, which exceeds the permitted maximum of %d bytes", entry.Path, sizeBytes, d.maximumTreeSizeBytes)
+		return nil
+	}) && runStage(SelfTestStageRead, func() error {
+		contents, err := d.ReadFileRange(ctx, selfTestBuildID, selfTestFilePath, 0, uint64(len(selfTestFileContents)))
+		if err != nil {
+			return err
 		}
-		if err := prefixCreator.createChild(
-			entry.Path,
-			virtual.InitialNode{}.FromDirectory(
-				virtual.NewCASInitialContentsFetcher(
-					context.Background(),
-					cd_cas.NewTreeDirectoryWalker(d.directoryFetcher, childDigest),
-					outputPathState.casFileFactory,
-					d.symlinkFactory,
-					buildState.digestFunction))); err != nil {
-			return nil, util.StatusWrapf(err, "Failed to create directory %#v", entry.Path)
+		if !bytes.Equal(contents, selfTestFileContents) {
+			return status.Errorf(codes.DataLoss, "Contents of file %#v differ from the contents that were uploaded", selfTestFilePath)
 		}
-	}
+		return nil
+	})
 
-	// Create requested symbolic links.
-	for _, entry := range request.Symlinks {
-		leaf := d.symlinkFactory.LookupSymlink([]byte(entry.Target))
-		if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
-			leaf.Unlink()
-			return nil, util.StatusWrapf(err, "Failed to create symbolic link %#v", entry.Path)
+	runStage(SelfTestStageClean, func() error {
+		if _, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: selfTestBuildID,
+		}); err != nil {
+			return util.StatusWrap(err, "Failed to finalize build")
 		}
-	}
-
-	return &emptypb.Empty{}, nil
+		if _, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: SelfTestOutputBaseID,
+		}); err != nil {
+			return util.StatusWrap(err, "Failed to clean output path")
+		}
+		return nil
+	})
+	return results, nil
 }
 
-// statWalker is an implementation of ScopeWalker and ComponentWalker
-// that is used by BatchStat() to resolve the file or directory
-// corresponding to a requested path. It is capable of expanding
-// symbolic links, if encountered.
-type statWalker struct {
-	followSymlinks bool
-	digestFunction *digest.Function
+// RuntimeStatistics contains counters describing the current state of
+// a RemoteOutputServiceDirectory.
+type RuntimeStatistics struct {
+	// The number of output paths exposed through the virtual file
+	// system.
+	OutputPathsCount int
 
-	stack      util.NonEmptyStack[virtual.PrepopulatedDirectory]
-	fileStatus *remoteoutputservice.FileStatus
-}
+	// The number of builds that have been started, but not yet
+	// finalized.
+	RunningBuildsCount int
 
-func (cw *statWalker) OnScope(absolute bool) (path.ComponentWalker, error) {
-	if absolute {
-		cw.stack.PopAll()
-	}
-	// Currently in a known directory.
-	cw.fileStatus = &remoteoutputservice.FileStatus{
-		FileType: &remoteoutputservice.FileStatus_Directory_{},
-	}
-	return cw, nil
-}
+	// The number of output paths that are corrupted, and need to be
+	// cleaned before they can be used again.
+	CorruptedOutputPathsCount int
 
-func (cw *statWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
-	child, err := cw.stack.Peek().LookupChild(name)
-	if err != nil {
-		return nil, err
-	}
+	// The number of calls to StartBuild() that are waiting, either
+	// for a build running against the same output base to be
+	// finalized, or for other builds to finish checking the
+	// existence of their outputs, as limited by
+	// FindMissing.OutputPathsConcurrency.
+	QueuedStartBuildsCount int64
 
-	directory, leaf := child.GetPair()
-	if directory != nil {
-		// Got a directory.
-		cw.stack.Push(directory)
-		return path.GotDirectory{
-			Child:        cw,
-			IsReversible: true,
-		}, nil
-	}
+	// The number of output paths whose contents are currently being
+	// checked for existence in the Content Addressable Storage.
+	// When FindMissing.OutputPathsConcurrency is set, this is
+	// bounded by its capacity.
+	FilteringOutputPathsCount int64
 
-	target, err := leaf.Readlink()
-	if err == syscall.EINVAL {
-		return nil, syscall.ENOTDIR
-	} else if err != nil {
-		return nil, err
-	}
+	// The number of calls against the Content Addressable Storage
+	// that are in flight, including the loading of Directory
+	// messages. Reads are in flight until their data is consumed.
+	CASCallsInFlightCount int64
 
-	// Got a symbolic link in the middle of a path. Those should
-	// always be followed.
-	cw.fileStatus = &remoteoutputservice.FileStatus{
-		FileType: &remoteoutputservice.FileStatus_External_{},
-	}
-	return path.GotSymlink{
-		Parent: cw,
-		Target: target,
-	}, nil
+	// The number of lookups against the file digest cache that
+	// were hit and missed, and the number of entries stored in it.
+	// These are zero if FileDigestCacheSize is not set.
+	FileDigestCacheHits         uint64
+	FileDigestCacheMisses       uint64
+	FileDigestCacheEntriesCount int
+
+	// The number of filenames and symbolic link targets that were
+	// found and not found in the name interner, and the number of
+	// names stored in it. These are zero if
+	// MaximumInternedNamesCount is not set.
+	NameInternerHits   uint64
+	NameInternerMisses uint64
+	InternedNamesCount int
+
+	// Estimates of the amount of memory used by the contents of
+	// output paths created through BatchCreate(), the file digest
+	// cache and the name interner. Contents of output paths are only
+	// accounted for if MaximumEstimatedMemoryUsageBytes is set.
+	EstimatedOutputPathsMemoryUsageBytes     int64
+	EstimatedFileDigestCacheMemoryUsageBytes int64
+	EstimatedNameInternerMemoryUsageBytes    int64
 }
 
-func (cw *statWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
-	child, err := cw.stack.Peek().LookupChild(name)
-	if err != nil {
-		return nil, err
-	}
+// GetRuntimeStatistics returns counters describing the current state
+// of the Remote Output Service, which may be displayed by operational
+// dashboards.
+func (d *RemoteOutputServiceDirectory) GetRuntimeStatistics() RuntimeStatistics {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 
-	directory, leaf := child.GetPair()
-	if directory != nil {
-		// Got a directory. The existing FileStatus is sufficient.
-		cw.stack.Push(directory)
-		return nil, nil
+	statistics := RuntimeStatistics{
+		OutputPathsCount:          len(d.outputBaseIDs),
+		RunningBuildsCount:        len(d.buildIDs),
+		QueuedStartBuildsCount:    d.queuedStartBuildsCount.Load(),
+		FilteringOutputPathsCount: d.filteringOutputPathsCount.Load(),
+		CASCallsInFlightCount:     d.casCallsInFlightCount.Load(),
 	}
-
-	if cw.followSymlinks {
-		target, err := leaf.Readlink()
-		if err == nil {
-			// Got a symbolic link, and we should follow it.
-			cw.fileStatus = &remoteoutputservice.FileStatus{
-				FileType: &remoteoutputservice.FileStatus_External_{},
-			}
-			return &path.GotSymlink{
-				Parent: cw,
-				Target: target,
-			}, nil
-		}
-		if err != syscall.EINVAL {
-			return nil, err
+	for _, outputPathState := range d.outputBaseIDs {
+		if outputPathState.corrupted {
+			statistics.CorruptedOutputPathsCount++
 		}
 	}
-
-	fileStatus, err := leaf.GetOutputServiceFileStatus(cw.digestFunction)
-	if err != nil {
-		return nil, err
+	if d.fileDigestCache != nil {
+		statistics.FileDigestCacheEntriesCount, statistics.FileDigestCacheHits, statistics.FileDigestCacheMisses = d.fileDigestCache.getStatistics()
+		statistics.EstimatedFileDigestCacheMemoryUsageBytes = int64(statistics.FileDigestCacheEntriesCount) * fileDigestCacheBytesPerEntry
 	}
-	cw.fileStatus = fileStatus
-	return nil, nil
-}
-
-func (cw *statWalker) OnUp() (path.ComponentWalker, error) {
-	if _, ok := cw.stack.PopSingle(); !ok {
-		cw.fileStatus = &remoteoutputservice.FileStatus{
-			FileType: &remoteoutputservice.FileStatus_External_{},
-		}
-		return path.VoidComponentWalker, nil
+	if d.nameInterner != nil {
+		var sizeBytes int64
+		statistics.InternedNamesCount, sizeBytes, statistics.NameInternerHits, statistics.NameInternerMisses = d.nameInterner.getStatistics()
+		statistics.EstimatedNameInternerMemoryUsageBytes = sizeBytes + int64(statistics.InternedNamesCount)*nameInternerBytesPerEntry
 	}
-	return cw, nil
+	if d.memoryBudget != nil {
+		statistics.EstimatedOutputPathsMemoryUsageBytes = d.memoryBudget.getUsedBytes()
+	}
+	return statistics
 }
 
-// BatchStat can be called by a build client to obtain the status of
-// files and directories.
-//
-// Calling this method over gRPC may be far more efficient than
-// obtaining this information through the FUSE file system, as batching
-// significantly reduces the amount of context switching. It also
-// prevents the computation of digests for files for which the digest is
-// already known.
-func (d *RemoteOutputServiceDirectory) BatchStat(ctx context.Context, request *remoteoutputservice.BatchStatRequest) (*remoteoutputservice.BatchStatResponse, error) {
-	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
-	if err != nil {
-		return nil, err
+// GetOutputPathLastAccessTime returns the last time a build was
+// started against the output path of a given output base, or the
+// output path was accessed through the virtual file system. This can
+// be used to determine whether output paths are still in use.
+func (d *RemoteOutputServiceDirectory) GetOutputPathLastAccessTime(outputBaseID string) (time.Time, error) {
+	outputBaseIDComponent, ok := path.NewComponent(outputBaseID)
+	if !ok {
+		return time.Time{}, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
 	}
 
-	response := remoteoutputservice.BatchStatResponse{
-		Responses: make([]*remoteoutputservice.StatResponse, 0, len(request.Paths)),
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseIDComponent)]
+	if !ok {
+		return time.Time{}, status.Errorf(codes.NotFound, "Output path %#v does not exist", outputBaseID)
 	}
-	for _, statPath := range request.Paths {
-		statWalker := statWalker{
-			followSymlinks: request.FollowSymlinks,
-			stack:          util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
-			fileStatus: &remoteoutputservice.FileStatus{
-				FileType: &remoteoutputservice.FileStatus_External_{},
-			},
-		}
-		if request.IncludeFileDigest {
-			statWalker.digestFunction = &buildState.digestFunction
-		}
+	return outputPathState.lastAccessTime, nil
+}
 
-		resolvedPath, scopeWalker := path.EmptyBuilder.Join(
-			buildState.scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
-		if err := path.Resolve(statPath, scopeWalker); err == syscall.ENOENT {
-			// Path does not exist.
-			response.Responses = append(response.Responses, &remoteoutputservice.StatResponse{})
-		} else if err != nil {
-			// Some other error occurred.
-			return nil, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", statPath, resolvedPath.String())
-		} else {
-			switch fileType := statWalker.fileStatus.FileType.(type) {
-			case *remoteoutputservice.FileStatus_Directory_:
-				// For directories we need to provide the last
-				// modification time, as the client uses that to
-				// invalidate cached results.
-				var attributes virtual.Attributes
-				statWalker.stack.Peek().VirtualGetAttributes(ctx, virtual.AttributesMaskLastDataModificationTime, &attributes)
-				lastModifiedTime, ok := attributes.GetLastDataModificationTime()
-				if !ok {
-					panic("Directory did not provide a last data modification time, even though the Remote Output Service protocol requires it")
-				}
-				fileType.Directory = &remoteoutputservice.FileStatus_Directory{
-					LastModifiedTime: timestamppb.New(lastModifiedTime),
-				}
-			case *remoteoutputservice.FileStatus_External_:
-				// Path resolves to a location outside the file
-				// system. Return the resolved path back to the
-				// client, so it can stat() it manually.
-				fileType.External = &remoteoutputservice.FileStatus_External{
-					NextPath: resolvedPath.String(),
-				}
-			}
-			response.Responses = append(response.Responses, &remoteoutputservice.StatResponse{
-				FileStatus: statWalker.fileStatus,
-			})
-		}
+// GetOutputBaseStatistics returns aggregate statistics about the
+// contents of the output path of a given output base, such as the
+// number of files it contains. These may be displayed by dashboards.
+func (d *RemoteOutputServiceDirectory) GetOutputBaseStatistics(outputBaseID string) (*OutputBaseStatistics, error) {
+	outputBaseIDComponent, ok := path.NewComponent(outputBaseID)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
 	}
-	return &response, nil
-}
 
-// FinalizeBuild can be called by a build client to indicate the current
-// build has completed. This prevents successive BatchCreate() and
-// BatchStat() calls from being processed.
-func (d *RemoteOutputServiceDirectory) FinalizeBuild(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (*emptypb.Empty, error) {
 	d.lock.Lock()
-	defer d.lock.Unlock()
-
-	// Silently ignore requests for unknown build IDs. This ensures
-	// that FinalizeBuild() remains idempotent.
-	if outputPathState, ok := d.buildIDs[request.BuildId]; ok {
-		buildState := outputPathState.buildState
-		outputPathState.rootDirectory.FinalizeBuild(ctx, buildState.digestFunction)
-		delete(d.buildIDs, buildState.id)
-		outputPathState.buildState = nil
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(outputBaseIDComponent)]
+	if !ok {
+		d.lock.Unlock()
+		return nil, status.Errorf(codes.NotFound, "Output path %#v does not exist", outputBaseID)
 	}
-	return &emptypb.Empty{}, nil
+	statistics := &OutputBaseStatistics{
+		LastBuildStartTime: outputPathState.lastBuildStartTime,
+	}
+	d.lock.Unlock()
+
+	outputPathState.contentCounters.get(statistics)
+	return statistics, nil
 }
 
 // VirtualGetAttributes returns the attributes of the root directory of
 // the Remote Output Service.
 func (d *RemoteOutputServiceDirectory) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
 	attributes.SetFileType(filesystem.FileTypeDirectory)
-	attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsExecute)
+	attributes.SetPermissions(d.configuration.RootDirectoryPermissions)
 	attributes.SetSizeBytes(0)
 	if requested&(virtual.AttributesMaskChangeID|virtual.AttributesMaskLinkCount) != 0 {
 		d.lock.Lock()
@@ -735,13 +6160,16 @@ func (d *RemoteOutputServiceDirectory) VirtualGetAttributes(ctx context.Context,
 // path for a given output base.
 func (d *RemoteOutputServiceDirectory) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
 	d.lock.Lock()
-	outputPathState, ok := d.outputBaseIDs[name]
+	outputPathState, ok := d.outputBaseIDs[d.getOutputBaseIDKey(name)]
+	if ok {
+		d.markAccessed(outputPathState)
+	}
 	d.lock.Unlock()
 	if !ok {
 		return virtual.DirectoryChild{}, virtual.StatusErrNoEnt
 	}
-	outputPathState.rootDirectory.VirtualGetAttributes(ctx, requested, out)
-	return virtual.DirectoryChild{}.FromDirectory(outputPathState.rootDirectory), virtual.StatusOK
+	outputPathState.getRootDirectoryAttributes(ctx, requested, out)
+	return virtual.DirectoryChild{}.FromDirectory(outputPathState.exposedDirectory), virtual.StatusOK
 }
 
 // VirtualOpenChild can be used to open or create a file in the root
@@ -749,7 +6177,7 @@ func (d *RemoteOutputServiceDirectory) VirtualLookup(ctx context.Context, name p
 // contains any files, this function is guaranteed to fail.
 func (d *RemoteOutputServiceDirectory) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
 	d.lock.Lock()
-	_, ok := d.outputBaseIDs[name]
+	_, ok := d.outputBaseIDs[d.getOutputBaseIDKey(name)]
 	d.lock.Unlock()
 	if ok {
 		return virtual.ReadOnlyDirectoryOpenChildWrongFileType(existingOptions, virtual.StatusErrIsDir)
@@ -763,6 +6191,15 @@ func (d *RemoteOutputServiceDirectory) VirtualReadDir(ctx context.Context, first
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
+	if d.sortedOutputPaths != nil {
+		for _, outputPathState := range d.sortedOutputPaths.getOutputPathsAfterCookie(firstCookie) {
+			if !d.reportOutputPath(ctx, outputPathState, requested, reporter) {
+				break
+			}
+		}
+		return virtual.StatusOK
+	}
+
 	// Find the first output path past the provided cookie.
 	outputPathState := d.outputPaths.next
 	for {
@@ -777,12 +6214,22 @@ func (d *RemoteOutputServiceDirectory) VirtualReadDir(ctx context.Context, first
 
 	// Return information for the remaining output paths.
 	for ; outputPathState != &d.outputPaths; outputPathState = outputPathState.next {
-		child := outputPathState.rootDirectory
-		var attributes virtual.Attributes
-		child.VirtualGetAttributes(ctx, requested, &attributes)
-		if !reporter.ReportEntry(outputPathState.cookie+1, outputPathState.outputBaseID, virtual.DirectoryChild{}.FromDirectory(child), &attributes) {
+		if !d.reportOutputPath(ctx, outputPathState, requested, reporter) {
 			break
 		}
 	}
 	return virtual.StatusOK
 }
+
+// reportOutputPath reports a single output path as part of
+// VirtualReadDir(). This method must be called with the directory lock
+// held.
+func (d *RemoteOutputServiceDirectory) reportOutputPath(ctx context.Context, outputPathState *outputPathState, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) bool {
+	var attributes virtual.Attributes
+	outputPathState.getRootDirectoryAttributes(ctx, requested, &attributes)
+	if !reporter.ReportEntry(outputPathState.cookie+1, outputPathState.outputBaseID, virtual.DirectoryChild{}.FromDirectory(outputPathState.exposedDirectory), &attributes) {
+		return false
+	}
+	d.markAccessed(outputPathState)
+	return true
+}
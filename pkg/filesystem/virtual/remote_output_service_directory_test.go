@@ -2,6 +2,14 @@ package virtual_test
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -11,38 +19,102 @@ import (
 	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
 	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/buildbarn/bb-storage/pkg/util"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheus_testutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
-	ctrl, ctx := gomock.WithContext(context.Background(), t)
+// remoteOutputServiceDirectoryFixture contains the mocks backing a
+// RemoteOutputServiceDirectory created by
+// newRemoteOutputServiceDirectoryFixture().
+type remoteOutputServiceDirectoryFixture struct {
+	handleAllocator                   *mock.MockStatefulHandleAllocator
+	outputPathFactory                 *mock.MockOutputPathFactory
+	bareContentAddressableStorage     *mock.MockBlobAccess
+	retryingContentAddressableStorage *mock.MockBlobAccess
+	directoryFetcher                  *mock.MockDirectoryFetcher
+	symlinkFactory                    *mock.MockSymlinkFactory
+	dHandle                           *mock.MockStatefulDirectoryHandle
+}
 
-	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
-	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
-	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
-	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+// newRemoteOutputServiceDirectoryFixture creates a
+// RemoteOutputServiceDirectory that is backed by mocks. File reads are
+// performed against retryingContentAddressableStorage, while all other
+// operations against the Content Addressable Storage are performed
+// against bareContentAddressableStorage.
+func newRemoteOutputServiceDirectoryFixture(ctrl *gomock.Controller, configuration *cd_vfs.RemoteOutputServiceDirectoryConfiguration) (*cd_vfs.RemoteOutputServiceDirectory, *remoteOutputServiceDirectoryFixture) {
+	f := &remoteOutputServiceDirectoryFixture{
+		handleAllocator:                   mock.NewMockStatefulHandleAllocator(ctrl),
+		outputPathFactory:                 mock.NewMockOutputPathFactory(ctrl),
+		bareContentAddressableStorage:     mock.NewMockBlobAccess(ctrl),
+		retryingContentAddressableStorage: mock.NewMockBlobAccess(ctrl),
+		directoryFetcher:                  mock.NewMockDirectoryFetcher(ctrl),
+		symlinkFactory:                    mock.NewMockSymlinkFactory(ctrl),
+		dHandle:                           mock.NewMockStatefulDirectoryHandle(ctrl),
+	}
 	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(dHandleAllocation)
-	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
-	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	f.handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(f.dHandle)
 	d := cd_vfs.NewRemoteOutputServiceDirectory(
-		handleAllocator,
-		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
-		directoryFetcher,
-		symlinkFactory,
-		/* maximumTreeSizeBytes = */ 10000)
+		f.handleAllocator,
+		f.outputPathFactory,
+		cd_vfs.ContentAddressableStorageRoles{
+			FileReads:   f.retryingContentAddressableStorage,
+			TreeReads:   f.bareContentAddressableStorage,
+			FindMissing: f.bareContentAddressableStorage,
+			Uploads:     f.bareContentAddressableStorage,
+		},
+		f.directoryFetcher,
+		f.symlinkFactory,
+		trace.NewNoopTracerProvider(),
+		configuration)
+	return d, f
+}
+
+// remoteOutputServiceDirectoryRequireOutputPathRoot requires that a
+// child returned by VirtualLookup() or VirtualReadDir() refers to the
+// root directory of a given output path. Root directories are returned
+// in wrapped form, so this is done by checking that obtaining its
+// attributes is forwarded to the output path.
+func remoteOutputServiceDirectoryRequireOutputPathRoot(ctx context.Context, t *testing.T, outputPath *mock.MockOutputPath, child re_vfs.DirectoryChild) {
+	directory, leaf := child.GetPair()
+	require.NotNil(t, directory)
+	require.Nil(t, leaf)
+
+	outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskFileType, gomock.Any()).
+		Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+			attributes.SetFileType(filesystem.FileTypeDirectory)
+		})
+	var attributes re_vfs.Attributes
+	directory.VirtualGetAttributes(ctx, re_vfs.AttributesMaskFileType, &attributes)
+	require.Equal(t, *(&re_vfs.Attributes{}).SetFileType(filesystem.FileTypeDirectory), attributes)
+}
+
+func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
 
 	t.Run("InvalidOutputBaseID", func(t *testing.T) {
 		// The output base ID must be a valid directory name.
@@ -58,7 +130,7 @@ func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 		// Ensure this persistent data is removed, to ensure
 		// that the next call to StartBuild() yields an empty
 		// output path.
-		outputPathFactory.EXPECT().Clean(path.MustNewComponent("9e6defb5a0a8a7af63077e0623279b78"))
+		f.outputPathFactory.EXPECT().Clean(path.MustNewComponent("9e6defb5a0a8a7af63077e0623279b78"))
 
 		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
 			OutputBaseId: "9e6defb5a0a8a7af63077e0623279b78",
@@ -69,11 +141,11 @@ func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 	t.Run("ExistentOutputPath", func(t *testing.T) {
 		// Create an output path.
 		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
 		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
 		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
 		outputPath := mock.NewMockOutputPath(ctrl)
-		outputPathFactory.EXPECT().StartInitialBuild(
+		f.outputPathFactory.EXPECT().StartInitialBuild(
 			path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"),
 			gomock.Any(),
 			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
@@ -106,7 +178,7 @@ func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 
 		// A second removal attempt succeeds.
 		outputPath.EXPECT().RemoveAllChildren(true)
-		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
 		_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
 			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
 		})
@@ -114,35 +186,318 @@ func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 
 		// Successive attempts should request the removal of
 		// persistent state.
-		outputPathFactory.EXPECT().Clean(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
+		f.outputPathFactory.EXPECT().Clean(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
 		_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
 			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
 		})
 		require.NoError(t, err)
 	})
+
+	t.Run("ExistentOutputPathWithProgress", func(t *testing.T) {
+		// Create an output path.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("e3b7d2a1b1d6f0f4f1a64e1a4fd1c1c0"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "e3b7d2a1b1d6f0f4f1a64e1a4fd1c1c0",
+			BuildId:          "3e0e6a4c-43d0-4bbb-9e9c-0fd3d9b9a6e5",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// When cleaning with progress reporting enabled, the
+		// contents of the output path should be removed one
+		// entry at a time.
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("dir"), Child: subDirectory},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file1"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+		subDirectory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file2"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+		subDirectory.EXPECT().Remove(path.MustNewComponent("file2"))
+		outputPath.EXPECT().Remove(path.MustNewComponent("dir"))
+		outputPath.EXPECT().Remove(path.MustNewComponent("file1"))
+		outputPath.EXPECT().RemoveAllChildren(true)
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("e3b7d2a1b1d6f0f4f1a64e1a4fd1c1c0"))
+
+		var progress []uint64
+		_, err = d.CleanWithProgress(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "e3b7d2a1b1d6f0f4f1a64e1a4fd1c1c0",
+		}, func(removedLeavesCount uint64) {
+			progress = append(progress, removedLeavesCount)
+		})
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2}, progress)
+	})
+
+	t.Run("ExistentOutputPathWithProgressCancelled", func(t *testing.T) {
+		// Create an output path.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("0c9b1c5bd2b45e1ba16d2c6e3a7bf9f0"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "0c9b1c5bd2b45e1ba16d2c6e3a7bf9f0",
+			BuildId:          "b1a44c4a-4c0e-4a8e-9f6e-2a4d6f0d1c3b",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Let the output path consist of nested directories
+		// that don't contain any files. Cancelling the request
+		// while traversing these directories should cause
+		// traversal to stop, even though no files are removed.
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+		defer cancel()
+		subDirectory1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("dir1"), Child: subDirectory1},
+			},
+			nil,
+			nil)
+		subDirectory2 := mock.NewMockPrepopulatedDirectory(ctrl)
+		subDirectory1.EXPECT().LookupAllChildren().DoAndReturn(
+			func() ([]re_vfs.DirectoryPrepopulatedDirEntry, []re_vfs.LeafPrepopulatedDirEntry, error) {
+				cancel()
+				return []re_vfs.DirectoryPrepopulatedDirEntry{
+					{Name: path.MustNewComponent("dir2"), Child: subDirectory2},
+				}, nil, nil
+			})
+
+		_, err = d.CleanWithProgress(ctxWithCancel, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "0c9b1c5bd2b45e1ba16d2c6e3a7bf9f0",
+		}, func(removedLeavesCount uint64) {
+			t.Error("No files should have been removed")
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryCleanDryRun(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := d.CleanDryRun(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "",
+		}, 10)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"), err)
+	})
+
+	t.Run("NonexistentOutputPath", func(t *testing.T) {
+		// Output paths that haven't been accessed since startup
+		// don't contain any files. Persistent state should not
+		// be removed.
+		result, err := d.CleanDryRun(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9e6defb5a0a8a7af63077e0623279b78",
+		}, 10)
+		require.NoError(t, err)
+		require.Equal(t, &cd_vfs.CleanDryRunResult{}, result)
+	})
+
+	// Create an output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "a448da900e7bd4b025ab91da2aba6244",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("LookupAllChildrenFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupAllChildren().Return(nil, nil, status.Error(codes.Internal, "Disk on fire"))
+
+		_, err := d.CleanDryRun(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, 10)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to look up children of directory \".\": Disk on fire"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The dry run should only inspect the contents of the
+		// output path. Any attempt to remove children would
+		// cause the mocks to fail.
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("subdirectory"), Child: subDirectory},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file1"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+		subDirectory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file2"), Child: mock.NewMockNativeLeaf(ctrl)},
+				{Name: path.MustNewComponent("symlink"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+
+		result, err := d.CleanDryRun(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, 2)
+		require.NoError(t, err)
+		require.Equal(t, &cd_vfs.CleanDryRunResult{
+			DirectoriesCount: 1,
+			LeavesCount:      3,
+			SampleLeafPaths:  []string{"subdirectory/file2", "subdirectory/symlink"},
+		}, result)
+	})
+
+	// The output path should still exist, meaning that a successive
+	// call to Clean() needs to remove its contents.
+	outputPath.EXPECT().RemoveAllChildren(true)
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+	})
+	require.NoError(t, err)
+}
+
+func TestRemoteOutputServiceDirectoryCleanPathPrefix(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := d.CleanPathPrefix(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "",
+		}, "bazel-out/k8-fastbuild")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"), err)
+	})
+
+	t.Run("NonexistentOutputPath", func(t *testing.T) {
+		// Persistent state of output paths can only be removed
+		// in its entirety.
+		_, err := d.CleanPathPrefix(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9e6defb5a0a8a7af63077e0623279b78",
+		}, "bazel-out/k8-fastbuild")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output path \"9e6defb5a0a8a7af63077e0623279b78\" does not exist"), err)
+	})
+
+	// Create an output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "a448da900e7bd4b025ab91da2aba6244",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("NonexistentPathPrefix", func(t *testing.T) {
+		bazelOutDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(bazelOutDirectory), nil)
+		bazelOutDirectory.EXPECT().LookupChild(path.MustNewComponent("k8-fastbuild")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		_, err := d.CleanPathPrefix(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, "bazel-out/k8-fastbuild")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to look up path prefix \"bazel-out/k8-fastbuild\": Path does not exist"), err)
+	})
+
+	t.Run("PathPrefixIsFile", func(t *testing.T) {
+		bazelOutDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(bazelOutDirectory), nil)
+		bazelOutDirectory.EXPECT().LookupChild(path.MustNewComponent("k8-fastbuild")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(mock.NewMockNativeLeaf(ctrl)), nil)
+
+		_, err := d.CleanPathPrefix(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, "bazel-out/k8-fastbuild")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to look up path prefix \"bazel-out/k8-fastbuild\": Path resolves to a file"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Only the contents of the directory should be removed.
+		// The output path itself should remain present, meaning
+		// that no removal notification should be sent for it.
+		bazelOutDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(bazelOutDirectory), nil)
+		fastbuildDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		bazelOutDirectory.EXPECT().LookupChild(path.MustNewComponent("k8-fastbuild")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(fastbuildDirectory), nil)
+		fastbuildDirectory.EXPECT().RemoveAllChildren(false)
+
+		_, err := d.CleanPathPrefix(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, "bazel-out/k8-fastbuild")
+		require.NoError(t, err)
+	})
+
+	// The build should still be running against the output path.
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
 }
 
 func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
-	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
-	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
-	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
-	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(dHandleAllocation)
-	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
-	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
-		handleAllocator,
-		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
-		directoryFetcher,
-		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
 
 	t.Run("InvalidOutputBaseID", func(t *testing.T) {
 		// The output base ID must be a valid directory name.
@@ -169,7 +524,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 				"/home/bob/.cache/bazel/_bazel_bob/a448da900e7bd4b025ab91da2aba6244/execroot/myproject/bazel-out": ".",
 			},
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve output path prefix: Path is relative, while an absolute path was expected"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path prefix \"relative/path\" is not an absolute path"), err)
 	})
 
 	t.Run("InvalidOutputPathAliases", func(t *testing.T) {
@@ -204,11 +559,11 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 		// An initial successful call should create the output
 		// path directory.
 		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
 		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
 		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
 		outputPath := mock.NewMockOutputPath(ctrl)
-		outputPathFactory.EXPECT().StartInitialBuild(
+		f.outputPathFactory.EXPECT().StartInitialBuild(
 			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
 			gomock.Any(),
 			digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256),
@@ -252,6 +607,32 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 			testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to filter contents of the output path: Failed to read directory contents"), err)
 		})
 
+		t.Run("ContextCanceled", func(t *testing.T) {
+			// If the client cancels the request, traversal
+			// of the output path should stop immediately,
+			// without calling FindMissing().
+			canceledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+				child := mock.NewMockNativeLeaf(ctrl)
+				remover := mock.NewMockChildRemover(ctrl)
+				require.False(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+				return nil
+			})
+
+			_, err = d.StartBuild(canceledCtx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+				InstanceName:     "my-cluster",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+				OutputPathAliases: map[string]string{
+					"/home/bob/.cache/bazel/_bazel_bob/a448da900e7bd4b025ab91da2aba6244/execroot/myproject/bazel-out": ".",
+				},
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Failed to filter contents of the output path: context canceled"), err)
+		})
+
 		t.Run("DirectoryGetContainingDigestsFailure", func(t *testing.T) {
 			// Simulate the case where we can't check the
 			// completeness of an uninitialized directory,
@@ -259,7 +640,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 			// the Content Addressable Storage.
 			outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
 				child := mock.NewMockInitialContentsFetcher(ctrl)
-				child.EXPECT().GetContainingDigests(ctx).Return(digest.EmptySet, status.Error(codes.Unavailable, "Tree \"4fb75adebd02251c9663125582e51102\": CAS unavailable"))
+				child.EXPECT().GetContainingDigests(gomock.Any()).Return(digest.EmptySet, status.Error(codes.Unavailable, "Tree \"4fb75adebd02251c9663125582e51102\": CAS unavailable"))
 				remover := mock.NewMockChildRemover(ctrl)
 				require.False(t, childFilter(re_vfs.InitialNode{}.FromDirectory(child), remover.Call))
 				return nil
@@ -285,7 +666,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 			// fails due to local storage errors.
 			outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
 				child := mock.NewMockInitialContentsFetcher(ctrl)
-				child.EXPECT().GetContainingDigests(ctx).Return(digest.EmptySet, status.Error(codes.NotFound, "Tree \"4fb75adebd02251c9663125582e51102\": Object not found"))
+				child.EXPECT().GetContainingDigests(gomock.Any()).Return(digest.EmptySet, status.Error(codes.NotFound, "Tree \"4fb75adebd02251c9663125582e51102\": Object not found"))
 				remover := mock.NewMockChildRemover(ctrl)
 				remover.EXPECT().Call().Return(status.Error(codes.Internal, "Disk on fire"))
 				require.False(t, childFilter(re_vfs.InitialNode{}.FromDirectory(child), remover.Call))
@@ -345,7 +726,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 				require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
 				return nil
 			})
-			bareContentAddressableStorage.EXPECT().FindMissing(ctx, digests).
+			f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).
 				Return(digest.EmptySet, status.Error(codes.Unavailable, "CAS unavailable"))
 
 			_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
@@ -373,7 +754,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 				require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
 				return nil
 			})
-			bareContentAddressableStorage.EXPECT().FindMissing(ctx, digests).Return(digests, nil)
+			f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).Return(digests, nil)
 			remover.EXPECT().Call().Return(status.Error(codes.Internal, "Disk on fire"))
 
 			_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
@@ -428,14 +809,14 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 				// A directory that no longer exists. It
 				// should be removed immediately.
 				child5 := mock.NewMockInitialContentsFetcher(ctrl)
-				child5.EXPECT().GetContainingDigests(ctx).Return(digest.EmptySet, status.Error(codes.NotFound, "Tree \"4fb75adebd02251c9663125582e51102\": Object not found"))
+				child5.EXPECT().GetContainingDigests(gomock.Any()).Return(digest.EmptySet, status.Error(codes.NotFound, "Tree \"4fb75adebd02251c9663125582e51102\": Object not found"))
 				remover5 := mock.NewMockChildRemover(ctrl)
 				remover5.EXPECT().Call()
 				require.True(t, childFilter(re_vfs.InitialNode{}.FromDirectory(child5), remover5.Call))
 
 				// A directory for which all files exist.
 				child6 := mock.NewMockInitialContentsFetcher(ctrl)
-				child6.EXPECT().GetContainingDigests(ctx).Return(
+				child6.EXPECT().GetContainingDigests(gomock.Any()).Return(
 					digest.NewSetBuilder().
 						Add(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "23fef0c2a3414dd562ca70e4a4717609", 5)).
 						Add(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "a60ffc49592e5045a61a8c99f3c86b4f", 6)).
@@ -447,7 +828,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 				// A directory for which one file does not
 				// exist. It should be removed later on.
 				child7 := mock.NewMockInitialContentsFetcher(ctrl)
-				child7.EXPECT().GetContainingDigests(ctx).Return(
+				child7.EXPECT().GetContainingDigests(gomock.Any()).Return(
 					digest.NewSetBuilder().
 						Add(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "2c0f843d40e00603f0d71e0d11a6e045", 7)).
 						Add(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "6b9105a7125cb9f190a3e44ab5f22663", 8)).
@@ -462,8 +843,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 			// removed immediately. The file and directory
 			// corresponding to the ones reported as missing
 			// should be removed afterwards.
-			bareContentAddressableStorage.EXPECT().FindMissing(
-				ctx,
+			f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(),
 				digest.NewSetBuilder().
 					Add(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "a32ea15346cf1848ab49e0913ff07531", 3)).
 					Add(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4)).
@@ -496,644 +876,9130 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 	})
 }
 
-func TestRemoteOutputServiceDirectoryBatchCreate(t *testing.T) {
+func TestRemoteOutputServiceDirectoryFindMissingMetrics(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
-	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
-	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
-	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
-	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(dHandleAllocation)
-	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
-	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
-		handleAllocator,
-		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
-		directoryFetcher,
-		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
-
-	t.Run("InvalidBuildID", func(t *testing.T) {
-		// StartBuild() should be called first.
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	registry := prometheus.NewPedanticRegistry()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		MetricsRegisterer:    registry,
 	})
 
-	// Let the remainder of the tests assume that a build is running.
+	// Start a build against an output path containing three files.
+	// One of them is absent from the Content Addressable Storage,
+	// while another one uses a different digest function.
 	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
-	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
-	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
 	outputPath := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
-		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
 		gomock.Any(),
-		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
 		gomock.Any(),
 	).Return(outputPath)
-	outputPath.EXPECT().FilterChildren(gomock.Any())
+	remover2 := mock.NewMockChildRemover(ctrl)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		child1 := mock.NewMockNativeLeaf(ctrl)
+		child1.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "a32ea15346cf1848ab49e0913ff07531", 3).ToSingletonSet())
+		remover1 := mock.NewMockChildRemover(ctrl)
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child1), remover1.Call))
 
-	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
-		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-		InstanceName:     "my-cluster",
+		child2 := mock.NewMockNativeLeaf(ctrl)
+		child2.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4).ToSingletonSet())
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child2), remover2.Call))
+
+		child3 := mock.NewMockNativeLeaf(ctrl)
+		child3.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "f11999245771a5c184b62dc5380e0d8b42df67b4", 2).ToSingletonSet())
+		remover3 := mock.NewMockChildRemover(ctrl)
+		remover3.EXPECT().Call()
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child3), remover3.Call))
+		return nil
+	})
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(),
+		digest.NewSetBuilder().
+			Add(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "a32ea15346cf1848ab49e0913ff07531", 3)).
+			Add(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4)).
+			Build(),
+	).Return(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4).ToSingletonSet(), nil)
+	remover2.EXPECT().Call()
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
 		DigestFunction:   remoteexecution.DigestFunction_MD5,
 		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
-		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/c6adef0d5ca1888a4aa847fb51229a8c/execroot/myproject/bazel-out": ".",
-		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
-	}, response)
 
-	// Tests for the path_prefix and clean_path_prefix options.
+	// Metrics should be reported through the registry that was
+	// provided, labeled with the output base ID.
+	require.NoError(t, prometheus_testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP buildbarn_clientd_remote_output_service_directory_find_missing_batches_flushed_total Number of FindMissingBlobs() calls performed at the start of builds.
+# TYPE buildbarn_clientd_remote_output_service_directory_find_missing_batches_flushed_total counter
+buildbarn_clientd_remote_output_service_directory_find_missing_batches_flushed_total{output_base_id="9da951b8cb759233037166e28f7ea186"} 1
+# HELP buildbarn_clientd_remote_output_service_directory_find_missing_children_removed_total Number of files and directories removed from output paths at the start of builds, because they were absent from the Content Addressable Storage, or used a different instance name or digest function.
+# TYPE buildbarn_clientd_remote_output_service_directory_find_missing_children_removed_total counter
+buildbarn_clientd_remote_output_service_directory_find_missing_children_removed_total{output_base_id="9da951b8cb759233037166e28f7ea186"} 2
+# HELP buildbarn_clientd_remote_output_service_directory_find_missing_digests_queued_total Number of digests of files and directories in output paths that were queued for FindMissingBlobs() at the start of builds.
+# TYPE buildbarn_clientd_remote_output_service_directory_find_missing_digests_queued_total counter
+buildbarn_clientd_remote_output_service_directory_find_missing_digests_queued_total{output_base_id="9da951b8cb759233037166e28f7ea186"} 2
+`),
+		"buildbarn_clientd_remote_output_service_directory_find_missing_batches_flushed_total",
+		"buildbarn_clientd_remote_output_service_directory_find_missing_children_removed_total",
+		"buildbarn_clientd_remote_output_service_directory_find_missing_digests_queued_total"))
 
-	t.Run("InvalidPathPrefix", func(t *testing.T) {
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix: "/etc",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path is absolute, while a relative path was expected"), err)
+	// Removing the output path should cause the series associated
+	// with its output base ID to be deleted, so that the number of
+	// series does not grow without bounds.
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_MD5))
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		BuildSuccessful: true,
 	})
-
-	t.Run("PathPrefixCreationFailure", func(t *testing.T) {
-		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("some")).
-			Return(child1, nil)
-		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("sub")).
-			Return(nil, status.Error(codes.Internal, "Disk failure"))
-
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix: "some/sub/directory",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create path prefix directory: Disk failure"), err)
+	require.NoError(t, err)
+	outputPath.EXPECT().RemoveAllChildren(true)
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"))
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "9da951b8cb759233037166e28f7ea186",
 	})
+	require.NoError(t, err)
 
-	t.Run("PathPrefixCleanFailure", func(t *testing.T) {
-		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory")).
-			Return(child1, nil)
-		child1.EXPECT().RemoveAllChildren(false).Return(status.Error(codes.Internal, "Disk failure"))
+	count, err := prometheus_testutil.GatherAndCount(
+		registry,
+		"buildbarn_clientd_remote_output_service_directory_find_missing_batch_duration_seconds",
+		"buildbarn_clientd_remote_output_service_directory_find_missing_batches_flushed_total",
+		"buildbarn_clientd_remote_output_service_directory_find_missing_children_removed_total",
+		"buildbarn_clientd_remote_output_service_directory_find_missing_digests_queued_total")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix:      "directory",
-			CleanPathPrefix: true,
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to clean path prefix directory: Disk failure"), err)
-	})
+func TestRemoteOutputServiceDirectoryStartBuildOutputPathPrefixNormalization(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	t.Run("SymlinkCreationFailure", func(t *testing.T) {
-		symlink := mock.NewMockNativeLeaf(ctrl)
-		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
-		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
-			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
-		}, true).Return(status.Error(codes.Internal, "I/O error"))
-		symlink.EXPECT().Unlink()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			Symlinks: []*remoteexecution.OutputSymlink{
-				{
-					Path:   "foo",
-					Target: "target",
-				},
-			},
+	t.Run("Empty", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:   "9da951b8cb759233037166e28f7ea186",
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction: remoteexecution.DigestFunction_SHA256,
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create symbolic link \"foo\": I/O error"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path prefix \"\" is not an absolute path"), err)
 	})
 
-	t.Run("DirectoryTooBig", func(t *testing.T) {
-		// We should forbid the creation of directories in the
-		// output directory that are too big, as attempting to
-		// access those would fail anyway.
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			Directories: []*remoteexecution.OutputDirectory{
-				{
-					Path: "large_directory",
-					TreeDigest: &remoteexecution.Digest{
-						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
-						SizeBytes: 9999999,
-					},
-				},
-			},
+	t.Run("DotSlash", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "./home/bob/bb_clientd/outputs",
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Directory \"large_directory\" is 9999999 bytes in size, which exceeds the permitted maximum of 10000 bytes"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path prefix \"./home/bob/bb_clientd/outputs\" is not an absolute path"), err)
 	})
 
-	// The creation of actual files and directories is hard to test,
-	// as the InitialNode arguments provided to CreateChildren()
-	// contain objects that are hard to compare. At least provide a
-	// test for the success case.
-
-	t.Run("Success", func(t *testing.T) {
-		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).
-			Return(child1, nil)
-
-		child2 := mock.NewMockPrepopulatedDirectory(ctrl)
-		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).
-			Return(child2, nil).Times(3)
-
-		// Creation of "file".
-		casFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
-		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(casFileHandleAllocation)
-		file := mock.NewMockNativeLeaf(ctrl)
-		casFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
-		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
-			path.MustNewComponent("file"): re_vfs.InitialNode{}.FromLeaf(file),
-		}, true)
-
-		// Creation of "directory".
-		child2.EXPECT().CreateChildren(gomock.Any(), true)
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
 
-		// Creation of "symlink".
-		symlink := mock.NewMockNativeLeaf(ctrl)
-		symlinkFactory.EXPECT().LookupSymlink([]byte("file")).Return(symlink)
-		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
-			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(symlink),
-		}, true)
+	for i, outputPathPrefix := range []string{
+		"/home/bob//bb_clientd/outputs",
+		"/home/bob/./bb_clientd/outputs",
+		"//home/bob/bb_clientd/../bb_clientd/outputs",
+	} {
+		t.Run(outputPathPrefix, func(t *testing.T) {
+			// Regardless of how the output path prefix is
+			// spelled, the output path suffix should only
+			// contain the output base ID. Symbolic links
+			// containing the normalized output path should
+			// resolve to locations inside the output path.
+			buildID := fmt.Sprintf("37f5dbef-b117-4fb6-bce8-5c147cb603b%d", i)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
+			response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          buildID,
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: outputPathPrefix,
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+				OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+			}, response)
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix: "a",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path:         "b/file",
-					IsExecutable: true,
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
+			symlink := mock.NewMockNativeLeaf(ctrl)
+			outputPath.EXPECT().LookupChild(path.MustNewComponent("symlink")).
+				Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlink), nil)
+			symlink.EXPECT().Readlink().Return("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/file", nil)
+			file := mock.NewMockNativeLeaf(ctrl)
+			outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+				Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+			file.EXPECT().Readlink().Return("", syscall.EINVAL)
+			file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_File_{
+					File: &remoteoutputservice.FileStatus_File{},
 				},
-			},
-			Directories: []*remoteexecution.OutputDirectory{
-				{
-					Path: "b/directory",
-					TreeDigest: &remoteexecution.Digest{
-						Hash:      "8e1554fc1ad824a6e9180c7b145790d2",
-						SizeBytes: 123,
+			}, nil)
+
+			statResponse, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+				BuildId:        buildID,
+				FollowSymlinks: true,
+				Paths:          []string{"symlink"},
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+				Responses: []*remoteoutputservice.StatResponse{
+					{
+						FileStatus: &remoteoutputservice.FileStatus{
+							FileType: &remoteoutputservice.FileStatus_File_{
+								File: &remoteoutputservice.FileStatus_File{},
+							},
+						},
 					},
 				},
-			},
-			Symlinks: []*remoteexecution.OutputSymlink{
-				{
-					Path:   "b/symlink",
-					Target: "file",
-				},
-			},
+			}, statResponse)
 		})
-		require.NoError(t, err)
-	})
+	}
 }
 
-func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
+func TestRemoteOutputServiceDirectoryStartBuildConcurrentFindMissing(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
-	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
-	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
-	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
-	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(dHandleAllocation)
-	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
-	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
-		handleAllocator,
-		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
-		directoryFetcher,
-		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
-
-	t.Run("InvalidBuildID", func(t *testing.T) {
-		// StartBuild() should be called first.
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
-			Paths:   []string{"foo.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			Concurrency: 2,
+		},
 	})
 
-	// Let the remainder of the tests assume that a build is running.
 	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
 	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
 	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
 	outputPath := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
+	f.outputPathFactory.EXPECT().StartInitialBuild(
 		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
 		gomock.Any(),
 		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
 		gomock.Any(),
 	).Return(outputPath)
-	outputPath.EXPECT().FilterChildren(gomock.Any())
 
-	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+	// Let the output path contain more files than fit in a single
+	// call to FindMissing(). This causes the digests to be split
+	// up into two batches, which may be processed concurrently.
+	remover := mock.NewMockChildRemover(ctrl)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		for i := 0; i < 15000; i++ {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().
+				Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, fmt.Sprintf("%032x", i), 1).ToSingletonSet())
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		}
+		return nil
+	})
+
+	// Report a single file in every batch as missing. Both of
+	// these files should be removed.
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+			return digests.Items()[0].ToSingletonSet(), nil
+		}).
+		Times(2)
+	remover.EXPECT().Call().Times(2)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
 		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
 		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
 		InstanceName:     "my-cluster",
 		DigestFunction:   remoteexecution.DigestFunction_MD5,
 		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out": ".",
+			"/home/bob/.cache/bazel/_bazel_bob/a448da900e7bd4b025ab91da2aba6244/execroot/myproject/bazel-out": ".",
 		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
-	}, response)
+}
 
-	t.Run("Noop", func(t *testing.T) {
-		// Requests that don't contain any paths shouldn't cause
-		// any I/O against the output path.
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-		})
-		require.NoError(t, err)
-	})
+func TestRemoteOutputServiceDirectoryStartBuildFilterMissingConcurrency(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	t.Run("OnDirectoryLookupFailure", func(t *testing.T) {
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			OutputPathsConcurrency: semaphore.NewWeighted(2),
+		},
+	})
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"stdio/printf.o"},
+	// Start builds against four different output bases. Let
+	// filtering of the output paths block, so that we can observe
+	// how many of them run concurrently.
+	filteringStarted := make(chan struct{}, 4)
+	filteringBlocked := make(chan struct{})
+	expectStartInitialBuild := func(outputBaseID string) {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			filteringStarted <- struct{}{}
+			<-filteringBlocked
+			return nil
+		}).MaxTimes(1)
+	}
+	startBuild := func(ctx context.Context, outputBaseID, buildID string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
-	})
+		return err
+	}
 
-	t.Run("OnDirectoryReadlinkFailure", func(t *testing.T) {
-		leaf := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
-		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+	errs := make(chan error, 3)
+	for i, buildID := range []string{
+		"37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		"d7c3b3d5-9b1c-4e43-8c9f-57e5ac3ff1a4",
+		"2840d789-16ff-4fe4-9639-3245f9bb9106",
+	} {
+		outputBaseID := fmt.Sprintf("%032x", i)
+		expectStartInitialBuild(outputBaseID)
+		go func(buildID string) {
+			errs <- startBuild(ctx, outputBaseID, buildID)
+		}(buildID)
+	}
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"stdio/printf.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
-	})
+	// Only two of the builds should be filtering their output
+	// paths at any given time.
+	<-filteringStarted
+	<-filteringStarted
+	select {
+	case <-filteringStarted:
+		t.Fatal("More output paths are being filtered than permitted")
+	case <-time.After(100 * time.Millisecond):
+	}
 
-	t.Run("OnTerminalLookupFailure", func(t *testing.T) {
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+	// Builds that need to wait should respect the deadline of the
+	// context of the request.
+	expectStartInitialBuild("00000000000000000000000000000003")
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := startBuild(ctxWithTimeout, "00000000000000000000000000000003", "4a8e0cbb-8c9a-4bb1-90c4-8ad3f05e8bd7")
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"printf.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	// Once filtering is unblocked, all remaining builds should be
+	// able to complete.
+	close(filteringBlocked)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildFindMissingBatchSize(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			BatchSize: 100,
+		},
 	})
 
-	t.Run("OnTerminalReadlinkFailure", func(t *testing.T) {
-		leaf := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
-		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			FollowSymlinks: true,
-			Paths:          []string{"printf.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	// Let the output path contain 250 files. With a batch size of
+	// 100, this should cause three calls to FindMissing() to be
+	// made, the last of which only contains the remaining files.
+	remover := mock.NewMockChildRemover(ctrl)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		for i := 0; i < 250; i++ {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().
+				Return(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, fmt.Sprintf("%032x", i), 1).ToSingletonSet())
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		}
+		return nil
 	})
 
-	t.Run("OnTerminalGetOutputServiceFileStatusFailure", func(t *testing.T) {
-		leaf := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
-		leaf.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(nil, status.Error(codes.Internal, "Disk failure"))
+	var batchSizes []int
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+			batchSizes = append(batchSizes, digests.Length())
+			return digest.EmptySet, nil
+		}).
+		Times(3)
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"printf.o"},
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{100, 100, 50}, batchSizes)
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildFindMissingFailure(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			BatchSize: 1,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+
+	// Let the output path contain three files. The first batch
+	// fails only after the third file has been queued. This means
+	// that the third file is part of the final batch, which should
+	// not be processed.
+	digest1 := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "00000000000000000000000000000001", 1)
+	digest2 := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "00000000000000000000000000000002", 1)
+	digest3 := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "00000000000000000000000000000003", 1)
+	firstBatchBlocked := make(chan struct{})
+	remover := mock.NewMockChildRemover(ctrl)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		child1 := mock.NewMockNativeLeaf(ctrl)
+		child1.EXPECT().GetContainingDigests().Return(digest1.ToSingletonSet())
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child1), remover.Call))
+		child2 := mock.NewMockNativeLeaf(ctrl)
+		child2.EXPECT().GetContainingDigests().Return(digest2.ToSingletonSet())
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child2), remover.Call))
+		child3 := mock.NewMockNativeLeaf(ctrl)
+		child3.EXPECT().GetContainingDigests().DoAndReturn(func() digest.Set {
+			close(firstBatchBlocked)
+			return digest3.ToSingletonSet()
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child3), remover.Call))
+		return nil
 	})
 
-	t.Run("Success", func(t *testing.T) {
-		// Lookup of "file", pointing to directly to a file.
-		leaf1 := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf1), nil)
-		leaf1.EXPECT().Readlink().Return("", syscall.EINVAL)
-		leaf1.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
-			FileType: &remoteoutputservice.FileStatus_File_{
-				File: &remoteoutputservice.FileStatus_File{
-					Digest: &remoteexecution.Digest{
-						Hash:      "ad17450bb18953f249532a478d2150ba",
-						SizeBytes: 72,
-					},
-				},
-			},
-		}, nil)
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest1.ToSingletonSet()).
+		DoAndReturn(func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+			<-firstBatchBlocked
+			return digest.EmptySet, status.Error(codes.InvalidArgument, "Server failure")
+		})
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest2.ToSingletonSet()).
+		Return(digest.EmptySet, nil)
 
-		// Lookup of "directory". pointing directly to a directory.
-		directory1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory1), nil)
-		directory1.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
-			})
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
 
-		// Lookup of "nested/symlink_internal_relative_file",
-		// being a symlink that points to a file.
-		directory2 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory2), nil)
-		leaf2 := mock.NewMockNativeLeaf(ctrl)
-		directory2.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf2), nil)
-		leaf2.EXPECT().Readlink().Return("../target", nil)
-		leaf3 := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("target")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf3), nil)
-		leaf3.EXPECT().Readlink().Return("", syscall.EINVAL)
-		leaf3.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
-			FileType: &remoteoutputservice.FileStatus_File_{
-				File: &remoteoutputservice.FileStatus_File{
-					Digest: &remoteexecution.Digest{
-						Hash:      "166d6efee3489f73be1c3c2304e50bca",
-						SizeBytes: 85,
-					},
-				},
+func TestRemoteOutputServiceDirectoryStartBuildFindMissingRetries(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			Retry: &cd_vfs.FindMissingRetryConfiguration{
+				MaximumAttempts: 3,
+				InitialBackoff:  time.Second,
+				MaximumBackoff:  1500 * time.Millisecond,
 			},
-		}, nil)
+		},
+	})
 
-		// Lookup of "nested/symlink_internal_relative_directory",
-		// being a symlink that points to a directory.
-		directory3 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory3), nil)
-		leaf4 := mock.NewMockNativeLeaf(ctrl)
-		directory3.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf4), nil)
-		leaf4.EXPECT().Readlink().Return("..", nil)
-		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1001, 0))
-			})
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
 
-		// Lookup of "nested/symlink_internal_absolute_path",
-		// being a symlink containing an absolute path starting
-		// with the output path.
-		directory4 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory4), nil)
-		leaf5 := mock.NewMockNativeLeaf(ctrl)
-		directory4.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_path")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf5), nil)
-		leaf5.EXPECT().Readlink().Return("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/hello", nil)
-		directory5 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory5), nil)
-		directory5.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1002, 0))
-			})
+	// Every build below lets the output path contain a single file.
+	digests := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "338db227a0de09b4309e928cdbb7d40a", 42).ToSingletonSet()
+	expectFilterChildren := func() {
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(digests)
+			remover := mock.NewMockChildRemover(ctrl)
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+			return nil
+		})
+	}
+	expectTimer := func(duration time.Duration) {
+		timer := mock.NewMockTimer(ctrl)
+		timerWakeup := make(chan time.Time, 1)
+		timerWakeup <- time.Unix(1000, 0)
+		clock.EXPECT().NewTimer(duration).Return(timer, timerWakeup)
+	}
+	startBuild := func(buildID string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
 
-		// Lookup of "nested/symlink_internal_absolute_alias",
-		// being a symlink containing an absolute path starting
-		// with one of the output path aliases.
-		directory6 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory6), nil)
-		leaf6 := mock.NewMockNativeLeaf(ctrl)
-		directory6.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_alias")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf6), nil)
-		leaf6.EXPECT().Readlink().Return("/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out/hello", nil)
-		directory7 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory7), nil)
-		directory7.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1003, 0))
-			})
+	t.Run("Success", func(t *testing.T) {
+		// The CAS fails twice, but succeeds on the third
+		// attempt. This should not cause the build to fail.
+		expectFilterChildren()
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).
+			Return(digest.EmptySet, status.Error(codes.Unavailable, "CAS unavailable"))
+		expectTimer(time.Second)
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).
+			Return(digest.EmptySet, status.Error(codes.DeadlineExceeded, "CAS timed out"))
+		expectTimer(1500 * time.Millisecond)
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).
+			Return(digest.EmptySet, nil)
 
-		// Lookup of "nested/symlink_external", being a symlink
-		// containing an absolute path that doesn't start with
-		// any known prefix.
-		directory8 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory8), nil)
-		leaf7 := mock.NewMockNativeLeaf(ctrl)
-		directory8.EXPECT().LookupChild(path.MustNewComponent("symlink_external")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf7), nil)
-		leaf7.EXPECT().Readlink().Return("/etc/passwd", nil)
+		require.NoError(t, startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	})
 
-		// Lookup of "nonexistent".
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+	t.Run("NonRetriableError", func(t *testing.T) {
+		// Errors that are not transient should be returned
+		// immediately.
+		expectFilterChildren()
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).
+			Return(digest.EmptySet, status.Error(codes.PermissionDenied, "Not authorized"))
 
-		// Lookup of ".".
-		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1004, 0))
-			})
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.PermissionDenied, "Failed to filter contents of the output path: Failed to find missing blobs: Not authorized"),
+			startBuild("2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339"))
+	})
 
-		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			IncludeFileDigest: true,
-			FollowSymlinks:    true,
-			Paths: []string{
-				"file",
-				"directory",
-				"nested/symlink_internal_relative_file",
-				"nested/symlink_internal_relative_directory",
-				"nested/symlink_internal_absolute_path",
-				"nested/symlink_internal_absolute_alias",
-				"nested/symlink_external",
-				"../foo",
-				"/etc/passwd",
-				"nonexistent",
-				".",
-			},
+	t.Run("AttemptsExhausted", func(t *testing.T) {
+		// Once the maximum number of attempts is reached, the
+		// last error should be returned.
+		expectFilterChildren()
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).
+			Return(digest.EmptySet, status.Error(codes.Unavailable, "CAS unavailable")).
+			Times(3)
+		expectTimer(time.Second)
+		expectTimer(1500 * time.Millisecond)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Failed to filter contents of the output path: Failed to find missing blobs: CAS unavailable"),
+			startBuild("8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildAsynchronously(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+
+	t.Run("Success", func(t *testing.T) {
+		// Let filtering of the output path block until we
+		// permit it to continue. It contains a single file
+		// that is missing.
+		unblockFilterChildren := make(chan struct{})
+		remover := mock.NewMockChildRemover(ctrl)
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			<-unblockFilterChildren
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().
+				Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4).ToSingletonSet())
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+			return nil
+		})
+		f.bareContentAddressableStorage.EXPECT().FindMissing(
+			gomock.Any(),
+			digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4).ToSingletonSet(),
+		).Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "9435918583fd2e37882751bbc51f4085", 4).ToSingletonSet(), nil)
+		remover.EXPECT().Call()
+
+		// StartBuildAsynchronously() should return immediately.
+		response, err := d.StartBuildAsynchronously(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 		})
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
-			Responses: []*remoteoutputservice.StatResponse{
-				// "file".
-				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_File_{
-							File: &remoteoutputservice.FileStatus_File{
-								Digest: &remoteexecution.Digest{
-									Hash:      "ad17450bb18953f249532a478d2150ba",
-									SizeBytes: 72,
-								},
-							},
-						},
-					},
-				},
-				// "directory".
-				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1000},
-							},
-						},
-					},
-				},
-				// "nested/symlink_internal_relative_file".
-				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_File_{
-							File: &remoteoutputservice.FileStatus_File{
-								Digest: &remoteexecution.Digest{
-									Hash:      "166d6efee3489f73be1c3c2304e50bca",
-									SizeBytes: 85,
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+		}, response)
+
+		buildStatus, err := d.GetBuildStatus("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.BuildStatus{
+			Phase: cd_vfs.BuildPhaseFiltering,
+		}, buildStatus)
+
+		// BatchStat() should block while filtering is in
+		// progress.
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = d.BatchStat(canceledCtx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+
+		// Once filtering completes, BatchStat() should succeed.
+		close(unblockFilterChildren)
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+
+		buildStatus, err = d.GetBuildStatus("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.BuildStatus{
+			Phase:                cd_vfs.BuildPhaseReady,
+			ChildrenScannedCount: 1,
+			ChildrenRemovedCount: 1,
+		}, buildStatus)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// Errors that occur while filtering should be returned
+		// by GetBuildStatus() and BatchStat().
+		outputPath.EXPECT().FilterChildren(gomock.Any()).Return(status.Error(codes.Internal, "Failed to read directory contents"))
+
+		_, err := d.StartBuildAsynchronously(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to prepare the output path at the start of the build: Failed to filter contents of the output path: Failed to read directory contents"), err)
+
+		buildStatus, err := d.GetBuildStatus("2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339")
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.BuildPhaseFailed, buildStatus.Phase)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to filter contents of the output path: Failed to read directory contents"), buildStatus.Error)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildDeduplication(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+
+	request := &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		// Let two identical calls to StartBuild() run
+		// concurrently. The output path should only be
+		// filtered once, with both calls succeeding.
+		filterChildrenStarted := make(chan struct{})
+		unblockFilterChildren := make(chan struct{})
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			close(filterChildrenStarted)
+			<-unblockFilterChildren
+			return nil
+		})
+
+		errs := make(chan error, 2)
+		go func() {
+			_, err := d.StartBuild(ctx, request)
+			errs <- err
+		}()
+		<-filterChildrenStarted
+		go func() {
+			_, err := d.StartBuild(ctx, request)
+			errs <- err
+		}()
+		close(unblockFilterChildren)
+		require.NoError(t, <-errs)
+		require.NoError(t, <-errs)
+	})
+
+	t.Run("RetryAfterSuccess", func(t *testing.T) {
+		// Retrying StartBuild() after it completed successfully
+		// should not cause the output path to be filtered again.
+		response, err := d.StartBuild(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+		}, response)
+	})
+
+	t.Run("RetryAfterFailure", func(t *testing.T) {
+		// If preparing the output path fails, retrying
+		// StartBuild() should cause it to be prepared again.
+		request.BuildId = "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339"
+		outputPath.EXPECT().FilterChildren(gomock.Any()).Return(status.Error(codes.Internal, "Failed to read directory contents"))
+		_, err := d.StartBuild(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to filter contents of the output path: Failed to read directory contents"), err)
+
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		_, err = d.StartBuild(ctx, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildConcurrentBuilds(t *testing.T) {
+	for _, rejectConcurrentBuilds := range []bool{false, true} {
+		t.Run(fmt.Sprintf("RejectConcurrentBuilds=%t", rejectConcurrentBuilds), func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+				MaximumTreeSizeBytes: 10000,
+				Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+					RejectConcurrentBuilds: rejectConcurrentBuilds,
+				},
+			})
+
+			// Start a first build.
+			casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+			f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+			casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+			outputPath := mock.NewMockOutputPath(ctrl)
+			f.outputPathFactory.EXPECT().StartInitialBuild(
+				path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+				gomock.Any(),
+				digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+				gomock.Any(),
+			).Return(outputPath)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
+
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(t, err)
+
+			// Start a second build against the same output
+			// base, while the first build is still running.
+			if rejectConcurrentBuilds {
+				_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+					OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+					BuildId:          "a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1",
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+				})
+				testutil.RequireEqualStatus(t, status.Error(codes.AlreadyExists, "Build \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\" is still running against this output base"), err)
+
+				// The first build should not be affected.
+				_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+					BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				})
+				require.NoError(t, err)
+			} else {
+				outputPath.EXPECT().FilterChildren(gomock.Any())
+				_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+					OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+					BuildId:          "a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1",
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+				})
+				require.NoError(t, err)
+
+				// The first build should have been
+				// finalized forcefully.
+				_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+					BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				})
+				testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+			}
+		})
+	}
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildConcurrentBuildGracePeriod(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+			ConcurrentBuildGracePeriod: 5 * time.Second,
+		},
+	})
+
+	// Start a first build. As no other builds are running against
+	// the output base, there is no need to wait.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	startBuild := func(buildID string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
+	require.NoError(t, startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+
+	t.Run("FinalizedWithinGracePeriod", func(t *testing.T) {
+		// Let the first build be finalized while the second
+		// build is waiting. The second build should start
+		// without the timer expiring.
+		timer := mock.NewMockTimer(ctrl)
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		clock.EXPECT().NewTimer(5*time.Second).
+			Do(func(duration time.Duration) {
+				_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+					BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				})
+				require.NoError(t, err)
+			}).
+			Return(timer, make(chan time.Time))
+		timer.EXPECT().Stop().Return(true)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		require.NoError(t, startBuild("a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1"))
+	})
+
+	t.Run("GracePeriodElapsed", func(t *testing.T) {
+		// The second build is not finalized in time, meaning
+		// that the third build should fail to start.
+		timerWakeup := make(chan time.Time, 1)
+		timerWakeup <- time.Unix(1005, 0)
+		clock.EXPECT().NewTimer(5*time.Second).Return(mock.NewMockTimer(ctrl), timerWakeup)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Build \"a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1\" is still running against this output base, and was not finalized within 5s"),
+			startBuild("c4c1b7ee-0d2f-4a5e-9a83-6d9b7c1f30e2"))
+
+		// The second build should not be affected.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryPrepareOutputBase(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	now := time.Unix(1000, 0)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+			PreparedOutputBaseTimeout: time.Minute,
+		},
+	})
+
+	startBuild := func(buildID string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
+	prepareOutputBase := func() error {
+		return d.PrepareOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "", remoteexecution.DigestFunction_SHA256)
+	}
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"),
+			d.PrepareOutputBase(ctx, "..", "", remoteexecution.DigestFunction_SHA256))
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+
+	t.Run("Warmed", func(t *testing.T) {
+		// Preparing the output base should create the output
+		// path and filter its contents. A build that is started
+		// afterwards should not need to filter them again.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		require.NoError(t, prepareOutputBase())
+
+		now = now.Add(30 * time.Second)
+		require.NoError(t, startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	})
+
+	t.Run("BuildRunning", func(t *testing.T) {
+		// Output bases cannot be prepared while a build is
+		// running against them, as removing files would
+		// interfere with the build.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\" is still running against this output base"),
+			prepareOutputBase())
+
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		// If no build is started before the timeout elapses,
+		// the results of the preparation may no longer be
+		// accurate. StartBuild() should filter the contents of
+		// the output path once more.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		require.NoError(t, prepareOutputBase())
+
+		now = now.Add(2 * time.Minute)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		require.NoError(t, startBuild("a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1"))
+
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("DifferentDigestFunction", func(t *testing.T) {
+		// Results of preparing the output base for one digest
+		// function cannot be used by builds using another.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		require.NoError(t, d.PrepareOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "", remoteexecution.DigestFunction_SHA1))
+
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		require.NoError(t, startBuild("c4c1b7ee-0d2f-4a5e-9a83-6d9b7c1f30e2"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildPinDigestFunction(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+			PinDigestFunctionPerOutputBase: true,
+		},
+	})
+
+	// Start an initial build, which is always permitted.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("Matching", func(t *testing.T) {
+		// Successive builds using the same instance name and
+		// digest function should be permitted.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("MismatchingInstanceName", func(t *testing.T) {
+		// Switching to another instance name should be
+		// rejected, without touching the output path. The
+		// previous build should continue to run.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4",
+			InstanceName:     "other-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base was last built using instance name \"my-cluster\" and digest function SHA256, while this build uses instance name \"other-cluster\" and digest function SHA256"), err)
+
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("MismatchingDigestFunction", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base was last built using instance name \"my-cluster\" and digest function SHA256, while this build uses instance name \"my-cluster\" and digest function MD5"), err)
+	})
+
+	t.Run("MismatchingWithForce", func(t *testing.T) {
+		// Switching should be permitted if explicitly
+		// requested. Successive builds should then be pinned
+		// to the new instance name.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuildForcingReset(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4",
+			InstanceName:     "other-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "4b3e1f36-0e3e-4f0b-a5a4-d6b5d7c1b0c9",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base was last built using instance name \"other-cluster\" and digest function SHA256, while this build uses instance name \"my-cluster\" and digest function SHA256"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryInstanceNameAliases(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+			PinDigestFunctionPerOutputBase: true,
+			InstanceNameAliases: map[string]string{
+				"my-cluster-alias": "my-cluster",
+			},
+		},
+	})
+
+	// Start an initial build using the canonical instance name.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	fileDigests := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5).ToSingletonSet()
+
+	t.Run("Aliased", func(t *testing.T) {
+		// Switching to an instance name that is an alias of
+		// the previous one should be permitted, even though
+		// the instance name is pinned. Files created using the
+		// previous instance name should be retained, as long
+		// as they are present in the Content Addressable
+		// Storage.
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(fileDigests)
+			remover := mock.NewMockChildRemover(ctrl)
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+			return nil
+		})
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), fileDigests).Return(digest.EmptySet, nil)
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+			InstanceName:     "my-cluster-alias",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Switching back should also be permitted.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("NotAliased", func(t *testing.T) {
+		// Switching to an unrelated instance name should still
+		// be rejected.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "4b3e1f36-0e3e-4f0b-a5a4-d6b5d7c1b0c9",
+			InstanceName:     "other-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base was last built using instance name \"my-cluster\" and digest function SHA256, while this build uses instance name \"other-cluster\" and digest function SHA256"), err)
+
+		// When forced, files created using the previous
+		// instance name should be removed without consulting
+		// the Content Addressable Storage.
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(fileDigests)
+			remover := mock.NewMockChildRemover(ctrl)
+			remover.EXPECT().Call()
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+			return nil
+		})
+
+		_, err = d.StartBuildForcingReset(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "4b3e1f36-0e3e-4f0b-a5a4-d6b5d7c1b0c9",
+			InstanceName:     "other-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildWithDigestFunctions(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	request := &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	}
+
+	t.Run("NoneSupported", func(t *testing.T) {
+		_, _, err := d.StartBuildWithDigestFunctions(ctx, request, []remoteexecution.DigestFunction_Value{
+			remoteexecution.DigestFunction_UNKNOWN,
+			remoteexecution.DigestFunction_MURMUR3,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "None of the 2 provided digest functions are supported"), err)
+	})
+
+	t.Run("FirstUnsupported", func(t *testing.T) {
+		// The first digest function is not supported, meaning
+		// the build should be started using the second one.
+		// The digest function in the request should be ignored.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		response, digestFunction, err := d.StartBuildWithDigestFunctions(ctx, request, []remoteexecution.DigestFunction_Value{
+			remoteexecution.DigestFunction_MURMUR3,
+			remoteexecution.DigestFunction_SHA256,
+			remoteexecution.DigestFunction_SHA1,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+		}, response)
+		require.Equal(t, remoteexecution.DigestFunction_SHA256, digestFunction)
+
+		// The request provided by the caller should be left
+		// untouched.
+		require.Equal(t, remoteexecution.DigestFunction_MD5, request.DigestFunction)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildFromSnapshot(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidSnapshotDigest", func(t *testing.T) {
+		_, err := d.StartBuildFromSnapshot(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		}, &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 0,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid snapshot digest: Hash has length 32, while 64 characters were expected"), err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	snapshotDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "7b5bb1e1a3e1e3ff9a8b2c5f5aa4b1e6d41b6a3c5d8e7f1029384756abcdef01", 200)
+
+	t.Run("FetchFailure", func(t *testing.T) {
+		// Failures loading the root directory of the snapshot
+		// should cause the build to fail to start.
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), snapshotDigest).
+			Return(nil, status.Error(codes.Unavailable, "Server offline"))
+
+		_, err := d.StartBuildFromSnapshot(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		}, snapshotDigest.GetProto())
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to populate the output path from the snapshot: Failed to fetch root directory of the snapshot: Server offline"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The output path should be populated with the contents
+		// of the snapshot. Afterwards, the contents should be
+		// filtered like those of any other build, meaning that
+		// files that are absent remotely are removed.
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), snapshotDigest).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "hello.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		file := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
+		outputPath.EXPECT().RemoveAllChildren(false)
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+			func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 1)
+				_, leaf := children[path.MustNewComponent("hello.txt")].GetPair()
+				require.Equal(t, file, leaf)
+				return nil
+			})
+
+		fileDigests := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5).ToSingletonSet()
+		remover := mock.NewMockChildRemover(ctrl)
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			file.EXPECT().GetContainingDigests().Return(fileDigests)
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(file), remover.Call))
+			return nil
+		})
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), fileDigests).Return(fileDigests, nil)
+		remover.EXPECT().Call()
+
+		response, err := d.StartBuildFromSnapshot(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		}, snapshotDigest.GetProto())
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreate(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/c6adef0d5ca1888a4aa847fb51229a8c/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
+	}, response)
+
+	// Tests for the path_prefix and clean_path_prefix options.
+
+	t.Run("InvalidPathPrefix", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "/etc",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path is absolute, while a relative path was expected"), err)
+	})
+
+	t.Run("PathPrefixCreationFailure", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("some")).
+			Return(child1, nil)
+		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("sub")).
+			Return(nil, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "some/sub/directory",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create path prefix directory: Disk failure"), err)
+	})
+
+	t.Run("PathPrefixCleanFailure", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory")).
+			Return(child1, nil)
+		child1.EXPECT().RemoveAllChildren(false).Return(status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix:      "directory",
+			CleanPathPrefix: true,
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to clean path prefix directory: Disk failure"), err)
+	})
+
+	t.Run("SymlinkCreationFailure", func(t *testing.T) {
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		symlink.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create symbolic link \"foo\": I/O error"), err)
+	})
+
+	t.Run("MalformedEntries", func(t *testing.T) {
+		// Entries that lack fields that are required to create
+		// them should be rejected with a precise error, before
+		// any attempt is made to create them.
+		for name, testCase := range map[string]struct {
+			request *remoteoutputservice.BatchCreateRequest
+			err     error
+		}{
+			"FileEmptyPath": {
+				request: &remoteoutputservice.BatchCreateRequest{
+					Files: []*remoteexecution.OutputFile{{
+						Digest: &remoteexecution.Digest{
+							Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+							SizeBytes: 123,
+						},
+					}},
+				},
+				err: status.Error(codes.InvalidArgument, "File has an empty path"),
+			},
+			"FileNoDigest": {
+				request: &remoteoutputservice.BatchCreateRequest{
+					Files: []*remoteexecution.OutputFile{{
+						Path: "foo.o",
+					}},
+				},
+				err: status.Error(codes.InvalidArgument, "File \"foo.o\" has no digest"),
+			},
+			"DirectoryEmptyPath": {
+				request: &remoteoutputservice.BatchCreateRequest{
+					Directories: []*remoteexecution.OutputDirectory{{
+						TreeDigest: &remoteexecution.Digest{
+							Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
+							SizeBytes: 123,
+						},
+					}},
+				},
+				err: status.Error(codes.InvalidArgument, "Directory has an empty path"),
+			},
+			"DirectoryNoTreeDigest": {
+				request: &remoteoutputservice.BatchCreateRequest{
+					Directories: []*remoteexecution.OutputDirectory{{
+						Path: "objs",
+					}},
+				},
+				err: status.Error(codes.InvalidArgument, "Directory \"objs\" has no tree digest"),
+			},
+			"SymlinkEmptyPath": {
+				request: &remoteoutputservice.BatchCreateRequest{
+					Symlinks: []*remoteexecution.OutputSymlink{{
+						Target: "target",
+					}},
+				},
+				err: status.Error(codes.InvalidArgument, "Symbolic link has an empty path"),
+			},
+			"SymlinkEmptyTarget": {
+				request: &remoteoutputservice.BatchCreateRequest{
+					Symlinks: []*remoteexecution.OutputSymlink{{
+						Path: "foo",
+					}},
+				},
+				err: status.Error(codes.InvalidArgument, "Symbolic link \"foo\" has an empty target"),
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				testCase.request.BuildId = "ad778a53-48e6-4ae1-b1f5-01b84a508f5f"
+				_, err := d.BatchCreate(ctx, testCase.request)
+				testutil.RequireEqualStatus(t, testCase.err, err)
+			})
+		}
+	})
+
+	t.Run("DirectoryTooBig", func(t *testing.T) {
+		// We should forbid the creation of directories in the
+		// output directory that are too big, as attempting to
+		// access those would fail anyway.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "large_directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
+						SizeBytes: 9999999,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Directory \"large_directory\" is 9999999 bytes in size, which exceeds the permitted maximum of 10000 bytes"), err)
+	})
+
+	t.Run("ContinueOnError", func(t *testing.T) {
+		// Entries that cannot be created should not prevent
+		// the other entries from being created. Errors should
+		// be returned in the same order as the entries.
+		validFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(validFileHandleAllocation)
+		validFile := mock.NewMockNativeLeaf(ctrl)
+		validFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(validFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("valid.o"): re_vfs.InitialNode{}.FromLeaf(validFile),
+		}, true)
+
+		brokenFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(brokenFileHandleAllocation)
+		brokenFile := mock.NewMockNativeLeaf(ctrl)
+		brokenFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(brokenFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("broken.o"): re_vfs.InitialNode{}.FromLeaf(brokenFile),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		brokenFile.EXPECT().Unlink()
+
+		validSymlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("valid.o")).Return(validSymlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("valid_symlink"): re_vfs.InitialNode{}.FromLeaf(validSymlink),
+		}, true)
+
+		outsideSymlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("valid.o")).Return(outsideSymlink)
+		outsideSymlink.EXPECT().Unlink()
+
+		results, err := d.BatchCreateContinuingOnError(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "valid.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+				{
+					Path: "invalid_digest.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "This is not a valid hash",
+						SizeBytes: 123,
+					},
+				},
+				{
+					Path: "broken.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "4e2c9af76e5ac40a4a5f7d5a5f2ad8a5",
+						SizeBytes: 456,
+					},
+				},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "large_directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
+						SizeBytes: 9999999,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "valid_symlink",
+					Target: "valid.o",
+				},
+				{
+					Path:   "../outside_symlink",
+					Target: "valid.o",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, results.FileErrors, 3)
+		require.NoError(t, results.FileErrors[0])
+		require.Equal(t, codes.InvalidArgument, status.Code(results.FileErrors[1]))
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create file \"broken.o\": I/O error"), results.FileErrors[2])
+		require.Len(t, results.DirectoryErrors, 1)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Directory \"large_directory\" is 9999999 bytes in size, which exceeds the permitted maximum of 10000 bytes"), results.DirectoryErrors[0])
+		require.Len(t, results.SymlinkErrors, 2)
+		require.NoError(t, results.SymlinkErrors[0])
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create symbolic link \"../outside_symlink\": Failed to resolve path: Path resolves to a location outside the output path"), results.SymlinkErrors[1])
+	})
+
+	t.Run("ContinueOnErrorPathPrefixFailure", func(t *testing.T) {
+		// Failures to create the path prefix should still cause
+		// the request as a whole to fail.
+		_, err := d.BatchCreateContinuingOnError(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "/etc",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path is absolute, while a relative path was expected"), err)
+	})
+
+	// The creation of actual files and directories is hard to test,
+	// as the InitialNode arguments provided to CreateChildren()
+	// contain objects that are hard to compare. At least provide a
+	// test for the success case.
+
+	t.Run("Success", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).
+			Return(child1, nil)
+
+		child2 := mock.NewMockPrepopulatedDirectory(ctrl)
+		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).
+			Return(child2, nil).Times(3)
+
+		// Creation of "file".
+		casFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(casFileHandleAllocation)
+		file := mock.NewMockNativeLeaf(ctrl)
+		casFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
+		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("file"): re_vfs.InitialNode{}.FromLeaf(file),
+		}, true)
+
+		// Creation of "directory".
+		child2.EXPECT().CreateChildren(gomock.Any(), true)
+
+		// Creation of "symlink".
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("file")).Return(symlink)
+		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:         "b/file",
+					IsExecutable: true,
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "b/directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "8e1554fc1ad824a6e9180c7b145790d2",
+						SizeBytes: 123,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "b/symlink",
+					Target: "file",
+				},
+			},
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateValidatePaths(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		BatchCreate: cd_vfs.BatchCreateConfiguration{
+			ValidatePaths: true,
+		},
+	})
+
+	// Start a build. None of the requests below should cause the
+	// output path to be modified.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	fileDigest := &remoteexecution.Digest{
+		Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+		SizeBytes: 5,
+	}
+
+	t.Run("InvalidPathPrefix", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out/../..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path prefix \"bazel-out/../..\": Path contains a \"..\" component"), err)
+	})
+
+	t.Run("AbsolutePath", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "/etc/passwd",
+					Digest: fileDigest,
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for file \"/etc/passwd\": Path is absolute"), err)
+	})
+
+	t.Run("DotDotComponent", func(t *testing.T) {
+		// The first entry is valid, but should not be created,
+		// as the second entry is invalid.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "bazel-out/k8-fastbuild/bin/hello",
+					Digest: fileDigest,
+				},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "bazel-out/../../escape",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "f11a2a6b9e3ba42b26f0e2e8d2f3bb3d7d03ee0ebb1e6f4ae9bbbbc68e1b1c39",
+						SizeBytes: 42,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for directory \"bazel-out/../../escape\": Path contains a \"..\" component"), err)
+	})
+
+	t.Run("EmptyComponent", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "bazel-out//latest",
+					Target: "k8-fastbuild",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for symbolic link \"bazel-out//latest\": Path contains an empty component"), err)
+	})
+
+	t.Run("EmptyPath", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "",
+					Digest: fileDigest,
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for file \"\": Path is empty"), err)
+	})
+
+	t.Run("ContinuingOnError", func(t *testing.T) {
+		// Invalid paths should cause the request to be rejected
+		// as a whole, even if the client requested that errors
+		// are reported per entry.
+		_, err := d.BatchCreateContinuingOnError(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "hello",
+					Digest: fileDigest,
+				},
+				{
+					Path:   "hello/",
+					Digest: fileDigest,
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for file \"hello/\": Path contains an empty component"), err)
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		stream := mock.NewMockBatchCreateRequestStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		stream.EXPECT().Recv().Return(&remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "../hello",
+					Digest: fileDigest,
+				},
+			},
+		}, nil)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Request 1: Invalid path for file \"../hello\": Path contains a \"..\" component"), d.BatchCreateStream(stream))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreatePreserveUnchangedFiles(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		BatchCreate: cd_vfs.BatchCreateConfiguration{
+			PreserveUnchangedFiles: true,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	request := &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path: "hello.txt",
+				Digest: &remoteexecution.Digest{
+					Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+					SizeBytes: 5,
+				},
+			},
+		},
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		// A file with the same contents is already present.
+		// It should be left in place, so that its modification
+		// time remains stable.
+		existingFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(existingFile), nil)
+		existingFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		existingFile.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+		existingFile.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead)
+			})
+
+		_, err := d.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("ExecutableBitChanged", func(t *testing.T) {
+		// The file has the same contents, but is executable.
+		// It should be replaced.
+		existingFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(existingFile), nil)
+		existingFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		existingFile.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+		existingFile.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsExecute)
+			})
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		newFile := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("hello.txt"): re_vfs.InitialNode{}.FromLeaf(newFile),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("ContentsChanged", func(t *testing.T) {
+		// The file has different contents. It should be
+		// replaced, causing its modification time to change.
+		existingFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(existingFile), nil)
+		existingFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		existingFile.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						SizeBytes: 0,
+					},
+				},
+			},
+		}, nil)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		newFile := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("hello.txt"): re_vfs.InitialNode{}.FromLeaf(newFile),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("Nonexistent", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		newFile := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("hello.txt"): re_vfs.InitialNode{}.FromLeaf(newFile),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryExecutableBits(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		BatchCreate: cd_vfs.BatchCreateConfiguration{
+			TrackExecutableBitChanges: true,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	fileStatus := &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_File_{
+			File: &remoteoutputservice.FileStatus_File{
+				Digest: &remoteexecution.Digest{
+					Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+					SizeBytes: 5,
+				},
+			},
+		},
+	}
+	expectExistingFile := func(name string, permissions re_vfs.Permissions) *mock.MockNativeLeaf {
+		existingFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent(name)).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(existingFile), nil)
+		existingFile.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(fileStatus, nil)
+		existingFile.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(permissions)
+			})
+		return existingFile
+	}
+
+	t.Run("BatchStatWithExecutableBits", func(t *testing.T) {
+		// Two files having the same digest, but a different
+		// executable bit. Their FileStatus messages are
+		// identical, meaning the executable bit needs to be
+		// returned separately.
+		expectExistingFile("hello", re_vfs.PermissionsRead)
+		expectExistingFile("hello.sh", re_vfs.PermissionsRead|re_vfs.PermissionsExecute)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, executableBits, err := d.BatchStatWithExecutableBits(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"hello", "hello.sh", "nonexistent"},
+		}, cd_vfs.DigestInclusionModeAlways)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{FileStatus: fileStatus},
+				{FileStatus: fileStatus},
+				{},
+			},
+		}, response)
+		require.Equal(t, []bool{false, true, false}, executableBits)
+	})
+
+	t.Run("TrackExecutableBitChanges", func(t *testing.T) {
+		// Replacing a file by one having the same digest, but a
+		// different executable bit should be reported.
+		// Replacing it by one that is identical should not.
+		expectExistingFile("hello", re_vfs.PermissionsRead).EXPECT().Readlink().Return("", syscall.EINVAL)
+		expectExistingFile("hello.sh", re_vfs.PermissionsRead|re_vfs.PermissionsExecute).EXPECT().Readlink().Return("", syscall.EINVAL)
+		for _, name := range []string{"hello", "hello.sh"} {
+			fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+			casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+			newFile := mock.NewMockNativeLeaf(ctrl)
+			fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+			outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+				path.MustNewComponent(name): re_vfs.InitialNode{}.FromLeaf(newFile),
+			}, true)
+		}
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:         "hello",
+					Digest:       fileStatus.GetFile().Digest,
+					IsExecutable: true,
+				},
+				{
+					Path:         "hello.sh",
+					Digest:       fileStatus.GetFile().Digest,
+					IsExecutable: true,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		paths, err := d.GetExecutableBitChanges("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		require.NoError(t, err)
+		require.Equal(t, []string{"hello"}, paths)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateNodeTypeConflict(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	newNodeTypeConflictError := func(message, conflictingPath, existingNodeType, requestedNodeType string) error {
+		s, err := status.New(codes.FailedPrecondition, message).WithDetails(&errdetails.ErrorInfo{
+			Reason: "NODE_TYPE_CONFLICT",
+			Domain: "github.com/buildbarn/bb-clientd",
+			Metadata: map[string]string{
+				"path":                conflictingPath,
+				"existing_node_type":  existingNodeType,
+				"requested_node_type": requestedNodeType,
+			},
+		})
+		require.NoError(t, err)
+		return s.Err()
+	}
+
+	t.Run("FileOverDirectory", func(t *testing.T) {
+		// A directory is present at the location where a file
+		// needs to be created.
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		newFile := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("hello.txt"): re_vfs.InitialNode{}.FromLeaf(newFile),
+		}, true).Return(syscall.EISDIR)
+		newFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		existingDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(existingDirectory), nil)
+		newFile.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "hello.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(
+			t,
+			newNodeTypeConflictError("Failed to create file \"hello.txt\": Cannot create file at path \"hello.txt\", as a directory is already present", "hello.txt", "directory", "file"),
+			err)
+	})
+
+	t.Run("DirectoryOverFile", func(t *testing.T) {
+		// A file is present at the location where a directory
+		// needs to be created.
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).Return(syscall.ENOTDIR)
+		existingFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("foo")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(existingFile), nil)
+		existingFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "foo",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "ec75a3a9fbd4f9d4ff3c2a4dac6b0d4e8e13bd1b1bb24d4fc5c8ef2bd2e6e6d5",
+						SizeBytes: 123,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(
+			t,
+			newNodeTypeConflictError("Failed to create directory \"foo\": Cannot create directory at path \"foo\", as a file is already present", "foo", "file", "directory"),
+			err)
+	})
+
+	t.Run("ParentDirectoryOverSymlink", func(t *testing.T) {
+		// A symbolic link is present at the location where a
+		// parent directory of a file needs to be created.
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		newFile := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("foo")).Return(nil, syscall.ENOTDIR)
+		existingSymlink := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("foo")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(existingSymlink), nil)
+		existingSymlink.EXPECT().Readlink().Return("target", nil)
+		newFile.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo/hello.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(
+			t,
+			newNodeTypeConflictError("Failed to create file \"foo/hello.txt\": Failed to resolve path: Cannot create directory at path \"foo\", as a symlink is already present", "foo", "symlink", "directory"),
+			err)
+	})
+
+	t.Run("OtherError", func(t *testing.T) {
+		// Errors that are not caused by conflicting node types
+		// should be returned as is.
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		newFile := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(newFile)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("hello.txt"): re_vfs.InitialNode{}.FromLeaf(newFile),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		newFile.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "hello.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create file \"hello.txt\": I/O error"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateReplaceDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Let the output path already contain directory "objs", which
+	// is rebuilt. The new directory should replace the existing one
+	// through a single call to CreateChildren() with overwriting
+	// enabled. As the output path is a strict mock, this also
+	// asserts that the existing directory is not looked up, emptied
+	// or removed beforehand, which would allow processes to observe
+	// an intermediate state in which the directory is empty or
+	// absent.
+	outputPath.EXPECT().CreateChildren(gomock.Any(), true).
+		DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+			require.Len(t, children, 1)
+			fetcher, leaf := children[path.MustNewComponent("objs")].GetPair()
+			require.NotNil(t, fetcher)
+			require.Nil(t, leaf)
+			return nil
+		})
+
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Directories: []*remoteexecution.OutputDirectory{
+			{
+				Path: "objs",
+				TreeDigest: &remoteexecution.Digest{
+					Hash:      "8e1554fc1ad824a6e9180c7b145790d2",
+					SizeBytes: 123,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateMemoryBudget(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumEstimatedMemoryUsageBytes: 600,
+		},
+	})
+
+	// Start builds against two separate output bases, which share
+	// the same memory budget.
+	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
+	casFileHandleAllocator1 := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator1)
+	outputPath1 := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath1)
+	outputPath1.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
+	casFileHandleAllocator2 := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator2)
+	outputPath2 := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath2)
+	outputPath2.EXPECT().FilterChildren(gomock.Any())
+
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	newRequest := func(buildID, filePath string) *remoteoutputservice.BatchCreateRequest {
+		return &remoteoutputservice.BatchCreateRequest{
+			BuildId: buildID,
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: filePath,
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}
+	}
+
+	// Create a single file in both output paths. Both of these
+	// should fit in the budget.
+	fileHandleAllocation1 := mock.NewMockStatelessHandleAllocation(ctrl)
+	casFileHandleAllocator1.EXPECT().New(gomock.Any()).Return(fileHandleAllocation1)
+	file1 := mock.NewMockNativeLeaf(ctrl)
+	fileHandleAllocation1.EXPECT().AsNativeLeaf(gomock.Any()).Return(file1)
+	var createdLeaf1 re_vfs.NativeLeaf
+	outputPath1.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+		func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+			_, createdLeaf1 = children[path.MustNewComponent("file1")].GetPair()
+			return nil
+		})
+
+	_, err = d.BatchCreate(ctx, newRequest("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "file1"))
+	require.NoError(t, err)
+
+	fileHandleAllocation2 := mock.NewMockStatelessHandleAllocation(ctrl)
+	casFileHandleAllocator2.EXPECT().New(gomock.Any()).Return(fileHandleAllocation2)
+	file2 := mock.NewMockNativeLeaf(ctrl)
+	fileHandleAllocation2.EXPECT().AsNativeLeaf(gomock.Any()).Return(file2)
+	outputPath2.EXPECT().CreateChildren(gomock.Any(), true)
+
+	_, err = d.BatchCreate(ctx, newRequest("ad778a53-48e6-4ae1-b1f5-01b84a508f5f", "file2"))
+	require.NoError(t, err)
+
+	// Creating another file should exceed the budget, even though
+	// the second output path only contains a single file.
+	fileHandleAllocation3 := mock.NewMockStatelessHandleAllocation(ctrl)
+	casFileHandleAllocator2.EXPECT().New(gomock.Any()).Return(fileHandleAllocation3)
+	file3 := mock.NewMockNativeLeaf(ctrl)
+	fileHandleAllocation3.EXPECT().AsNativeLeaf(gomock.Any()).Return(file3)
+	file3.EXPECT().Unlink()
+
+	_, err = d.BatchCreate(ctx, newRequest("ad778a53-48e6-4ae1-b1f5-01b84a508f5f", "file3"))
+	testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to create file \"file3\": Output paths are estimated to use 512 bytes of memory, meaning an additional 256 bytes would exceed the permitted maximum of 600 bytes"), err)
+
+	// Removing the file from the first output path (e.g., because
+	// it was absent from the CAS at the start of the next build)
+	// should release its memory, permitting the file to be created.
+	file1.EXPECT().Unlink()
+	createdLeaf1.Unlink()
+
+	fileHandleAllocation4 := mock.NewMockStatelessHandleAllocation(ctrl)
+	casFileHandleAllocator2.EXPECT().New(gomock.Any()).Return(fileHandleAllocation4)
+	file4 := mock.NewMockNativeLeaf(ctrl)
+	fileHandleAllocation4.EXPECT().AsNativeLeaf(gomock.Any()).Return(file4)
+	outputPath2.EXPECT().CreateChildren(gomock.Any(), true)
+
+	_, err = d.BatchCreate(ctx, newRequest("ad778a53-48e6-4ae1-b1f5-01b84a508f5f", "file3"))
+	require.NoError(t, err)
+}
+
+func TestRemoteOutputServiceDirectoryBatchRemoveMemoryBudget(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumEstimatedMemoryUsageBytes: 600,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	newRequest := func(directoryPath string) *remoteoutputservice.BatchCreateRequest {
+		return &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: directoryPath,
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "bb68c9ab6b7a3b2f6bb1eb0cfc4b0a8e4a5c0a2e9d3d9d0d5e1f1f3c5d9a6e7b",
+						SizeBytes: 300,
+					},
+				},
+			},
+		}
+	}
+
+	// The first directory fits in the budget, while the second
+	// one doesn't.
+	outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+	_, err = d.BatchCreate(ctx, newRequest("dir1"))
+	require.NoError(t, err)
+
+	_, err = d.BatchCreate(ctx, newRequest("dir2"))
+	testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to create directory \"dir2\": Output paths are estimated to use 556 bytes of memory, meaning an additional 556 bytes would exceed the permitted maximum of 600 bytes"), err)
+
+	// Removing the first directory should release its memory,
+	// permitting the second directory to be created.
+	directory := mock.NewMockPrepopulatedDirectory(ctrl)
+	outputPath.EXPECT().LookupChild(path.MustNewComponent("dir1")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+	directory.EXPECT().RemoveAllChildren(true)
+	outputPath.EXPECT().Remove(path.MustNewComponent("dir1"))
+
+	errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"dir1"}, true)
+	require.NoError(t, err)
+	require.Equal(t, []error{nil}, errs)
+	require.Equal(t, int64(0), d.GetRuntimeStatistics().EstimatedOutputPathsMemoryUsageBytes)
+
+	outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+	_, err = d.BatchCreate(ctx, newRequest("dir2"))
+	require.NoError(t, err)
+	require.Equal(t, int64(556), d.GetRuntimeStatistics().EstimatedOutputPathsMemoryUsageBytes)
+}
+
+func TestRemoteOutputServiceDirectoryAccessLog(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	accessLogger := mock.NewMockAccessLogger(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		AccessLog: &cd_vfs.AccessLogConfiguration{
+			Logger:            accessLogger,
+			MaximumPathsCount: 1,
+		},
+	})
+
+	// Start a build. This should also cause an entry to be logged.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+	// The clock is also used to track when the output path was
+	// last accessed.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(3)
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	accessLogger.EXPECT().LogAccess(&cd_vfs.AccessLogEntry{
+		Method:       "StartBuild",
+		BuildID:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		OutputBaseID: "9da951b8cb759233037166e28f7ea186",
+		Code:         codes.OK,
+		Latency:      time.Second,
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("BatchCreateSuccess", func(t *testing.T) {
+		// The output base ID should be obtained from the
+		// running build. Only a single path should be logged.
+		symlink1 := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target1")).Return(symlink1)
+		symlink2 := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target2")).Return(symlink2)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink1"): re_vfs.InitialNode{}.FromLeaf(symlink1),
+		}, true)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink2"): re_vfs.InitialNode{}.FromLeaf(symlink2),
+		}, true)
+		clock.EXPECT().Now().Return(time.Unix(1002, 0))
+		clock.EXPECT().Now().Return(time.Unix(1002, 250000000))
+		accessLogger.EXPECT().LogAccess(&cd_vfs.AccessLogEntry{
+			Method:        "BatchCreate",
+			BuildID:       "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			OutputBaseID:  "9da951b8cb759233037166e28f7ea186",
+			SymlinksCount: 2,
+			PathsCount:    2,
+			SamplePaths:   []string{"symlink1"},
+			Code:          codes.OK,
+			Latency:       250 * time.Millisecond,
+		})
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "symlink1",
+					Target: "target1",
+				},
+				{
+					Path:   "symlink2",
+					Target: "target2",
+				},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("BatchStatFailure", func(t *testing.T) {
+		// Calls against unknown builds should be logged with
+		// the resulting status code.
+		clock.EXPECT().Now().Return(time.Unix(1003, 0))
+		clock.EXPECT().Now().Return(time.Unix(1003, 0))
+		accessLogger.EXPECT().LogAccess(&cd_vfs.AccessLogEntry{
+			Method:      "BatchStat",
+			BuildID:     "c7cc6e5b-1e2b-4a8f-9c9c-5fd1a0a7d1a2",
+			PathsCount:  1,
+			SamplePaths: []string{"symlink1"},
+			Code:        codes.FailedPrecondition,
+		})
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "c7cc6e5b-1e2b-4a8f-9c9c-5fd1a0a7d1a2",
+			Paths:   []string{"symlink1"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateNameInterning(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+			MaximumInternedNamesCount: 3,
+		},
+	})
+
+	// Start builds against two separate output bases, which share
+	// the same pool of interned names.
+	outputBaseIDs := []string{"9da951b8cb759233037166e28f7ea186", "c6adef0d5ca1888a4aa847fb51229a8c"}
+	buildIDs := []string{"37f5dbef-b117-4fb6-bce8-5c147cb603b4", "ad778a53-48e6-4ae1-b1f5-01b84a508f5f"}
+	var outputPaths []*mock.MockOutputPath
+	for i, outputBaseID := range outputBaseIDs {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildIDs[i],
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		outputPaths = append(outputPaths, outputPath)
+	}
+
+	createSymlink := func(i int, symlinkPath, target string) (path.Component, []byte) {
+		var createdTarget []byte
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink(gomock.Any()).DoAndReturn(
+			func(target []byte) re_vfs.NativeLeaf {
+				createdTarget = target
+				return leaf
+			})
+		childDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPaths[i].EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("dir")).Return(childDirectory, nil)
+		var createdName path.Component
+		childDirectory.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+			func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 1)
+				for name := range children {
+					createdName = name
+				}
+				return nil
+			})
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: buildIDs[i],
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   symlinkPath,
+					Target: target,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return createdName, createdTarget
+	}
+
+	t.Run("SharedAcrossOutputPaths", func(t *testing.T) {
+		// Creating the same symbolic link in both output paths
+		// should cause both to share the same target. Interning
+		// should not alter any of the names or targets.
+		name1, target1 := createSymlink(0, "dir/link", "../target")
+		name2, target2 := createSymlink(1, "dir/link", "../target")
+		require.Equal(t, path.MustNewComponent("link"), name1)
+		require.Equal(t, path.MustNewComponent("link"), name2)
+		require.Equal(t, []byte("../target"), target1)
+		require.Equal(t, []byte("../target"), target2)
+		require.True(t, &target1[0] == &target2[0])
+	})
+
+	t.Run("PoolFull", func(t *testing.T) {
+		// The pool now contains "dir", "link" and "../target".
+		// Names that have not been interned before should be
+		// returned as is.
+		name1, target1 := createSymlink(0, "dir/other", "../other")
+		name2, target2 := createSymlink(1, "dir/other", "../other")
+		require.Equal(t, path.MustNewComponent("other"), name1)
+		require.Equal(t, path.MustNewComponent("other"), name2)
+		require.Equal(t, []byte("../other"), target1)
+		require.Equal(t, []byte("../other"), target2)
+		require.False(t, &target1[0] == &target2[0])
+	})
+}
+
+func BenchmarkRemoteOutputServiceDirectoryBatchCreateSymlinks(b *testing.B) {
+	for _, maximumInternedNamesCount := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("MaximumInternedNamesCount=%d", maximumInternedNamesCount), func(b *testing.B) {
+			ctrl, ctx := gomock.WithContext(context.Background(), b)
+
+			d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+				MaximumTreeSizeBytes: 10000,
+				Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+					MaximumInternedNamesCount: maximumInternedNamesCount,
+				},
+			})
+
+			casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+			f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+			casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+			outputPath := mock.NewMockOutputPath(ctrl)
+			f.outputPathFactory.EXPECT().StartInitialBuild(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(outputPath)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
+
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(b, err)
+
+			// Retain all names and targets of the symbolic
+			// links that are created, as an output path
+			// would. Each request contains the same set of
+			// symbolic links, similar to how many packages
+			// in a build contain files having the same names.
+			var retainedChildren []map[path.Component]re_vfs.InitialNode
+			var retainedTargets [][]byte
+			leaf := mock.NewMockNativeLeaf(ctrl)
+			f.symlinkFactory.EXPECT().LookupSymlink(gomock.Any()).DoAndReturn(
+				func(target []byte) re_vfs.NativeLeaf {
+					retainedTargets = append(retainedTargets, target)
+					return leaf
+				}).AnyTimes()
+			outputPath.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+				func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+					retainedChildren = append(retainedChildren, children)
+					return nil
+				}).AnyTimes()
+			request := &remoteoutputservice.BatchCreateRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			}
+			for i := 0; i < 100; i++ {
+				request.Symlinks = append(request.Symlinks, &remoteexecution.OutputSymlink{
+					Path:   fmt.Sprintf("symlink_with_a_reasonably_long_name_%d", i),
+					Target: fmt.Sprintf("../../external/some_repository/some/package/target_%d", i),
+				})
+			}
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := d.BatchCreate(ctx, request)
+				require.NoError(b, err)
+			}
+			b.StopTimer()
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(b.N), "retained-B/op")
+			runtime.KeepAlive(retainedChildren)
+			runtime.KeepAlive(retainedTargets)
+		})
+	}
+}
+
+func TestRemoteOutputServiceDirectoryContentAddressableStorageRoles(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// Provide a distinct backend for every role, so that we can
+	// verify that each of them is used for the right purpose.
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	fileReadsContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	treeReadsContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	findMissingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	uploadsContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	d := cd_vfs.NewRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		cd_vfs.ContentAddressableStorageRoles{
+			FileReads:   fileReadsContentAddressableStorage,
+			TreeReads:   treeReadsContentAddressableStorage,
+			FindMissing: findMissingContentAddressableStorage,
+			Uploads:     uploadsContentAddressableStorage,
+		},
+		directoryFetcher,
+		symlinkFactory,
+		trace.NewNoopTracerProvider(),
+		&cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+			MaximumTreeSizeBytes: 10000,
+			BatchCreate: cd_vfs.BatchCreateConfiguration{
+				EagerlyFetchDirectories: true,
+			},
+		})
+
+	t.Run("FindMissing", func(t *testing.T) {
+		// Checking for the existence of files stored in the
+		// output path at the start of the build should be
+		// performed against the FindMissing backend.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		var casFileFactory re_vfs.CASFileFactory
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).DoAndReturn(func(outputBaseID path.Component, ff re_vfs.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) cd_vfs.OutputPath {
+			casFileFactory = ff
+			return outputPath
+		})
+		digests := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5).ToSingletonSet()
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(digests)
+			remover := mock.NewMockChildRemover(ctrl)
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+			return nil
+		})
+		findMissingContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digests).Return(digest.EmptySet, nil)
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		t.Run("FileReads", func(t *testing.T) {
+			// Reading the contents of files should be
+			// performed against the FileReads backend.
+			fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+			fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+			casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+			fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+			file := casFileFactory.LookupFile(fileDigest, false)
+			fileReadsContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+			var buf [10]byte
+			n, eof, s := file.VirtualRead(buf[:], 0)
+			require.Equal(t, re_vfs.StatusOK, s)
+			require.True(t, eof)
+			require.Equal(t, []byte("Hello"), buf[:n])
+		})
+
+		t.Run("TreeReads", func(t *testing.T) {
+			// Eagerly fetching directories created through
+			// BatchCreate() should be performed against the
+			// TreeReads backend.
+			treeDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "5e2d3e9d2a0ef8f9e7d2e6b6ae6a3d4b42cc6b4e0c8c5f2a4b9d6c3e1f0a7b8c", 123)
+			treeReadsContentAddressableStorage.EXPECT().Get(gomock.Any(), treeDigest).
+				Return(buffer.NewProtoBufferFromProto(&remoteexecution.Tree{
+					Root: &remoteexecution.Directory{},
+				}, buffer.UserProvided))
+			outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+			_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Directories: []*remoteexecution.OutputDirectory{
+					{
+						Path:       "directory",
+						TreeDigest: treeDigest.GetProto(),
+					},
+				},
+			})
+			require.NoError(t, err)
+		})
+
+		t.Run("Uploads", func(t *testing.T) {
+			// Creating a snapshot of the output path should
+			// upload its contents to the Uploads backend.
+			outputPath.EXPECT().LookupAllChildren().Return(nil, nil, nil)
+			uploadsContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.EmptySet).Return(digest.EmptySet, nil).AnyTimes()
+			uploadsContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+					b.Discard()
+					return nil
+				})
+
+			_, err := d.GetOutputPathTree(ctx, "9da951b8cb759233037166e28f7ea186")
+			require.NoError(t, err)
+		})
+	})
+}
+
+func TestRemoteOutputServiceDirectoryRequestEntriesCountLimits(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumBatchCreateEntriesCount: 2,
+			MaximumBatchStatPathsCount:     2,
+			MaximumBatchRemovePathsCount:   2,
+		},
+	})
+
+	// Requests that exceed the limits should be rejected before
+	// the build ID is even looked up.
+	t.Run("BatchCreate", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{Path: "file"},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
+				{Path: "directory"},
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "symlink", Target: "target"},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "BatchCreate() request contains 3 entries, which exceeds the maximum of 2"), err)
+	})
+
+	t.Run("BatchStat", func(t *testing.T) {
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"a", "b", "c"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "BatchStat() request contains 3 paths, which exceeds the maximum of 2"), err)
+
+		// Requests at the limit should be processed as usual.
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"a", "b"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("BatchRemove", func(t *testing.T) {
+		_, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"a", "b", "c"}, false)
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "BatchRemove() request contains 3 paths, which exceeds the maximum of 2"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateSymlinkTargetLength(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	newDirectory := func(maximumSymlinkTargetLengthBytes int) (*cd_vfs.RemoteOutputServiceDirectory, *mock.MockOutputPath, *mock.MockSymlinkFactory) {
+		d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+			MaximumTreeSizeBytes: 10000,
+			Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+				MaximumSymlinkTargetLengthBytes: maximumSymlinkTargetLengthBytes,
+			},
+		})
+
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		return d, outputPath, f.symlinkFactory
+	}
+	newRequest := func(target string) *remoteoutputservice.BatchCreateRequest {
+		return &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "symlink",
+					Target: target,
+				},
+			},
+		}
+	}
+
+	d, outputPath, symlinkFactory := newDirectory(10)
+
+	t.Run("BelowLimit", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("../target")).Return(leaf)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(leaf),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, newRequest("../target"))
+		require.NoError(t, err)
+	})
+
+	t.Run("AtLimit", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("../target1")).Return(leaf)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(leaf),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, newRequest("../target1"))
+		require.NoError(t, err)
+	})
+
+	t.Run("AboveLimit", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, newRequest("../target12"))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link \"symlink\" has a target of 11 bytes in size, which exceeds the permitted maximum of 10 bytes"), err)
+	})
+
+	t.Run("NullByte", func(t *testing.T) {
+		// Targets containing null bytes cannot be returned
+		// through readlink(), and should thus be rejected.
+		_, err := d.BatchCreate(ctx, newRequest("foo\x00bar"))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link \"symlink\" has a target containing a null byte"), err)
+	})
+
+	t.Run("DefaultLimit", func(t *testing.T) {
+		// If no limit is configured, the default limit should
+		// be applied.
+		d, _, _ := newDirectory(0)
+		_, err := d.BatchCreate(ctx, newRequest(strings.Repeat("a/", 2049)))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link \"symlink\" has a target of 4098 bytes in size, which exceeds the permitted maximum of 4096 bytes"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateEagerlyFetchDirectories(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		BatchCreate: cd_vfs.BatchCreateConfiguration{
+			EagerlyFetchDirectories: true,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	treeDigest := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "8e1554fc1ad824a6e9180c7b145790d2", 123)
+	request := &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Directories: []*remoteexecution.OutputDirectory{
+			{
+				Path:       "directory",
+				TreeDigest: treeDigest.GetProto(),
+			},
+		},
+	}
+
+	t.Run("FetchFailure", func(t *testing.T) {
+		// The Tree object should be fetched as part of
+		// BatchCreate(), meaning that errors are propagated
+		// immediately.
+		f.bareContentAddressableStorage.EXPECT().Get(gomock.Any(), treeDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.Unavailable, "CAS unavailable")))
+
+		_, err := d.BatchCreate(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to fetch directory \"directory\": CAS unavailable"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Once the Tree object has been fetched, the directory
+		// should be created without calling into the
+		// DirectoryFetcher.
+		f.bareContentAddressableStorage.EXPECT().Get(gomock.Any(), treeDigest).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.Tree{
+				Root: &remoteexecution.Directory{
+					Symlinks: []*remoteexecution.SymlinkNode{
+						{
+							Name:   "symlink",
+							Target: "target",
+						},
+					},
+				},
+			}, buffer.UserProvided))
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+		_, err := d.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateWithInlineTrees(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Compute the digest of the Tree object that is provided
+	// inline.
+	inlineTree, err := proto.Marshal(&remoteexecution.Tree{
+		Root: &remoteexecution.Directory{
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{
+					Name:   "symlink",
+					Target: "target",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	generator := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256).NewGenerator(int64(len(inlineTree)))
+	_, err = generator.Write(inlineTree)
+	require.NoError(t, err)
+	treeDigest := generator.Sum()
+
+	t.Run("InlineTreesCountMismatch", func(t *testing.T) {
+		_, err := d.BatchCreateWithInlineTrees(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path:       "directory",
+					TreeDigest: treeDigest.GetProto(),
+				},
+			},
+		}, nil)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Request contains 1 directories, while 0 inline trees were provided"), err)
+	})
+
+	t.Run("DigestMismatch", func(t *testing.T) {
+		// The Tree object must match the tree digest of the
+		// directory. Otherwise, the digest reported by
+		// BatchStat() would not correspond to its contents.
+		otherTreeDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "8e1554fc1ad824a6e9180c7b145790d2a3a3c1f3c5e1c1cbf1e3d2a0e9c4d7b6", 123)
+		_, err := d.BatchCreateWithInlineTrees(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path:       "directory",
+					TreeDigest: otherTreeDigest.GetProto(),
+				},
+			},
+		}, [][]byte{inlineTree})
+		testutil.RequireEqualStatus(t, status.Errorf(codes.InvalidArgument, "Directory \"directory\" has an inline tree with digest %#v, while its tree digest is %#v", treeDigest.String(), otherTreeDigest.String()), err)
+	})
+
+	t.Run("MalformedTree", func(t *testing.T) {
+		// Tree objects that match the tree digest, but cannot
+		// be unmarshaled should be rejected.
+		malformedTree := []byte("Hello")
+		malformedTreeDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+		_, err := d.BatchCreateWithInlineTrees(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path:       "directory",
+					TreeDigest: malformedTreeDigest.GetProto(),
+				},
+			},
+		}, [][]byte{malformedTree})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The directory should be created without fetching the
+		// Tree object from the Content Addressable Storage,
+		// neither during BatchCreate() nor when its contents
+		// are accessed afterwards.
+		var fetcher re_vfs.InitialContentsFetcher
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).
+			DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 1)
+				fetcher, _ = children[path.MustNewComponent("directory")].GetPair()
+				require.NotNil(t, fetcher)
+				return nil
+			})
+
+		_, err := d.BatchCreateWithInlineTrees(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path:       "directory",
+					TreeDigest: treeDigest.GetProto(),
+				},
+			},
+		}, [][]byte{inlineTree})
+		require.NoError(t, err)
+
+		digests, err := fetcher.GetContainingDigests(ctx)
+		require.NoError(t, err)
+		require.Equal(t, treeDigest.ToSingletonSet(), digests)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateWithIdempotencyToken(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	now := time.Unix(1000, 0)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		BatchCreate: cd_vfs.BatchCreateConfiguration{
+			IdempotencyTokenExpiration: time.Minute,
+		},
+	})
+
+	request := &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "latest",
+				Target: "bin/hello",
+			},
+		},
+	}
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.BatchCreateWithIdempotencyToken(ctx, request, "4a1f0b55")
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	expectCreateSymlink := func(err error) {
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/hello")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("latest"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true).Return(err)
+		if err != nil {
+			symlink.EXPECT().Unlink()
+		}
+	}
+
+	t.Run("RepeatedToken", func(t *testing.T) {
+		// Sending the same request twice should only cause the
+		// symbolic link to be created once.
+		expectCreateSymlink(nil)
+		_, err := d.BatchCreateWithIdempotencyToken(ctx, request, "4a1f0b55")
+		require.NoError(t, err)
+
+		now = now.Add(30 * time.Second)
+		_, err = d.BatchCreateWithIdempotencyToken(ctx, request, "4a1f0b55")
+		require.NoError(t, err)
+	})
+
+	t.Run("ExpiredToken", func(t *testing.T) {
+		// Once the token has expired, the request should be
+		// executed again.
+		now = now.Add(time.Minute)
+		expectCreateSymlink(nil)
+		_, err := d.BatchCreateWithIdempotencyToken(ctx, request, "4a1f0b55")
+		require.NoError(t, err)
+	})
+
+	t.Run("DifferentToken", func(t *testing.T) {
+		expectCreateSymlink(nil)
+		_, err := d.BatchCreateWithIdempotencyToken(ctx, request, "96e27c3d")
+		require.NoError(t, err)
+	})
+
+	t.Run("RetryAfterFailure", func(t *testing.T) {
+		// Results of failed requests should not be retained,
+		// so that retrying them has a chance to succeed.
+		expectCreateSymlink(status.Error(codes.Internal, "Disk on fire"))
+		_, err := d.BatchCreateWithIdempotencyToken(ctx, request, "c0ffee00")
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create symbolic link \"latest\": Disk on fire"), err)
+
+		expectCreateSymlink(nil)
+		_, err = d.BatchCreateWithIdempotencyToken(ctx, request, "c0ffee00")
+		require.NoError(t, err)
+
+		_, err = d.BatchCreateWithIdempotencyToken(ctx, request, "c0ffee00")
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreatePrefetchRootDirectories(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		BatchCreate: cd_vfs.BatchCreateConfiguration{
+			PrefetchRootDirectories: true,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	treeDigest := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "8e1554fc1ad824a6e9180c7b145790d2", 123)
+	request := &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Directories: []*remoteexecution.OutputDirectory{
+			{
+				Path:       "directory",
+				TreeDigest: treeDigest.GetProto(),
+			},
+		},
+	}
+
+	t.Run("MissingTree", func(t *testing.T) {
+		// The root directory of the Tree object should be
+		// fetched as part of BatchCreate(), meaning that
+		// nonexistent Tree objects are reported immediately.
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).
+			Return(nil, status.Error(codes.NotFound, "Object not found"))
+
+		_, err := d.BatchCreate(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to fetch root directory of directory \"directory\": Object not found"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Once the root directory has been fetched, the
+		// directory should be created. Child directories
+		// should not be fetched.
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).
+			Return(&remoteexecution.Directory{
+				Directories: []*remoteexecution.DirectoryNode{
+					{
+						Name: "child",
+						Digest: &remoteexecution.Digest{
+							Hash:      "4df5f448a5e6b3c41e6aae7a8a9832aa",
+							SizeBytes: 42,
+						},
+					},
+				},
+			}, nil)
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+		_, err := d.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateStream(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("EmptyStream", func(t *testing.T) {
+		stream := mock.NewMockBatchCreateRequestStream(ctrl)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Stream does not contain any requests"), d.BatchCreateStream(stream))
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		stream := mock.NewMockBatchCreateRequestStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		stream.EXPECT().Recv().Return(&remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		}, nil)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), d.BatchCreateStream(stream))
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/c6adef0d5ca1888a4aa847fb51229a8c/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
+	}, response)
+
+	t.Run("ReceiveFailure", func(t *testing.T) {
+		// Entries contained in requests that were received
+		// before the failure should have been created.
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true)
+
+		stream := mock.NewMockBatchCreateRequestStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		gomock.InOrder(
+			stream.EXPECT().Recv().Return(&remoteoutputservice.BatchCreateRequest{
+				BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				Symlinks: []*remoteexecution.OutputSymlink{
+					{
+						Path:   "foo",
+						Target: "target",
+					},
+				},
+			}, nil),
+			stream.EXPECT().Recv().Return(nil, status.Error(codes.Unavailable, "Connection reset by peer")))
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to receive request: Connection reset by peer"), d.BatchCreateStream(stream))
+	})
+
+	t.Run("CreationFailure", func(t *testing.T) {
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		symlink.EXPECT().Unlink()
+
+		stream := mock.NewMockBatchCreateRequestStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		stream.EXPECT().Recv().Return(&remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		}, nil)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Request 1: Failed to create symbolic link \"foo\": I/O error"), d.BatchCreateStream(stream))
+	})
+
+	t.Run("IdenticalToUnary", func(t *testing.T) {
+		// Creating a large number of symbolic links through a
+		// stream should yield the same results as creating them
+		// through a single call to BatchCreate().
+		const entriesCount = 5000
+		const entriesPerRequest = 100
+		var symlinks []*remoteexecution.OutputSymlink
+		for i := 0; i < entriesCount; i++ {
+			symlinks = append(symlinks, &remoteexecution.OutputSymlink{
+				Path:   fmt.Sprintf("b/symlink%d", i),
+				Target: fmt.Sprintf("target%d", i),
+			})
+		}
+
+		symlinkTargets := map[re_vfs.NativeLeaf]string{}
+		f.symlinkFactory.EXPECT().LookupSymlink(gomock.Any()).DoAndReturn(
+			func(target []byte) re_vfs.NativeLeaf {
+				leaf := mock.NewMockNativeLeaf(ctrl)
+				symlinkTargets[leaf] = string(target)
+				return leaf
+			}).Times(2 * entriesCount)
+
+		// The path prefix should only be created and cleaned
+		// once, even though every request in the stream sets
+		// these options.
+		expectCreation := func(created map[string]string) {
+			directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+			outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+			directoryA.EXPECT().RemoveAllChildren(false)
+			directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+			directoryA.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).
+				Return(directoryB, nil).
+				Times(entriesCount)
+			directoryB.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+				func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+					for name, child := range children {
+						_, leaf := child.GetPair()
+						created[name.String()] = symlinkTargets[leaf]
+					}
+					return nil
+				}).Times(entriesCount)
+		}
+
+		unaryCreated := map[string]string{}
+		expectCreation(unaryCreated)
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix:      "a",
+			CleanPathPrefix: true,
+			Symlinks:        symlinks,
+		})
+		require.NoError(t, err)
+
+		streamCreated := map[string]string{}
+		expectCreation(streamCreated)
+		stream := mock.NewMockBatchCreateRequestStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		var recvCalls []*gomock.Call
+		for i := 0; i < entriesCount; i += entriesPerRequest {
+			recvCalls = append(recvCalls, stream.EXPECT().Recv().Return(&remoteoutputservice.BatchCreateRequest{
+				BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				PathPrefix:      "a",
+				CleanPathPrefix: true,
+				Symlinks:        symlinks[i : i+entriesPerRequest],
+			}, nil))
+		}
+		recvCalls = append(recvCalls, stream.EXPECT().Recv().Return(nil, io.EOF))
+		gomock.InOrder(recvCalls...)
+		require.NoError(t, d.BatchCreateStream(stream))
+
+		require.Len(t, unaryCreated, entriesCount)
+		require.Equal(t, unaryCreated, streamCreated)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryFreezeOutputPathsBetweenBuilds(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+			FreezeOutputPathsBetweenBuilds: true,
+		},
+	})
+
+	// Starting a build against a new output path should unfreeze it.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().Unfreeze()
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	startBuildRequest := &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	}
+	_, err := d.StartBuild(ctx, startBuildRequest)
+	require.NoError(t, err)
+
+	// Finalizing the build should freeze the output path.
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5))
+	outputPath.EXPECT().Freeze()
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		BuildSuccessful: true,
+	})
+	require.NoError(t, err)
+
+	t.Run("BatchCreateOutsideBuild", func(t *testing.T) {
+		// Attempting to modify the output path after the build
+		// has been finalized should fail.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("NextBuild", func(t *testing.T) {
+		// Starting the next build should unfreeze the output
+		// path, so that BatchCreate() can be used once again.
+		outputPath.EXPECT().Unfreeze()
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		startBuildRequest.BuildId = "f9c06b9b-fd8d-4c8c-b8e6-a7b7a0b1e4f2"
+		_, err := d.StartBuild(ctx, startBuildRequest)
+		require.NoError(t, err)
+
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true)
+
+		_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "f9c06b9b-fd8d-4c8c-b8e6-a7b7a0b1e4f2",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5))
+		outputPath.EXPECT().Freeze()
+		_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "f9c06b9b-fd8d-4c8c-b8e6-a7b7a0b1e4f2",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Clean", func(t *testing.T) {
+		// Cleaning a frozen output path should unfreeze it, as
+		// its contents could not be removed otherwise.
+		outputPath.EXPECT().Unfreeze()
+		outputPath.EXPECT().RemoveAllChildren(true)
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"))
+
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "c6adef0d5ca1888a4aa847fb51229a8c",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryQuotas(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumFilesCountPerOutputPath: 2,
+			MaximumSizeBytesPerOutputPath:  300,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	expectLookupFile := func() *mock.MockNativeLeaf {
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		file := mock.NewMockNativeLeaf(ctrl)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
+		return file
+	}
+	expectCreateChildren := func(name string) *re_vfs.NativeLeaf {
+		var createdLeaf re_vfs.NativeLeaf
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+			func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 1)
+				_, createdLeaf = children[path.MustNewComponent(name)].GetPair()
+				require.NotNil(t, createdLeaf)
+				return nil
+			})
+		return &createdLeaf
+	}
+	createFile := func(name, hash string, sizeBytes int64) error {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: name,
+					Digest: &remoteexecution.Digest{
+						Hash:      hash,
+						SizeBytes: sizeBytes,
+					},
+				},
+			},
+		})
+		return err
+	}
+
+	// Creating files up to the limit should succeed.
+	file1 := expectLookupFile()
+	createdFile1 := expectCreateChildren("file1")
+	require.NoError(t, createFile("file1", "1b2ae1c6f4bd3a8d7a1d4b52ef2cd2c3", 100))
+	expectLookupFile()
+	expectCreateChildren("file2")
+	require.NoError(t, createFile("file2", "8d16d1a8c1cd3b82a8fbbdc4316fa4e3", 150))
+
+	t.Run("FilesCountExceeded", func(t *testing.T) {
+		file3 := expectLookupFile()
+		file3.EXPECT().Unlink()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.ResourceExhausted, "Failed to create file \"file3\": Output path already contains 2 files, which is the permitted maximum"),
+			createFile("file3", "5b6e2c1a6f5d4e3b2a1908f7e6d5c4b3", 10))
+	})
+
+	// Removing a file should release its quota.
+	file1.EXPECT().Unlink()
+	(*createdFile1).Unlink()
+
+	t.Run("SizeBytesExceeded", func(t *testing.T) {
+		file3 := expectLookupFile()
+		file3.EXPECT().Unlink()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.ResourceExhausted, "Failed to create file \"file3\": Output path already contains 150 bytes of files, meaning a file of 250 bytes would exceed the permitted maximum of 300 bytes"),
+			createFile("file3", "5b6e2c1a6f5d4e3b2a1908f7e6d5c4b3", 250))
+	})
+
+	t.Run("CreationFailure", func(t *testing.T) {
+		// Failing to create a file should release its quota.
+		file4 := expectLookupFile()
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).Return(status.Error(codes.Internal, "I/O error"))
+		file4.EXPECT().Unlink()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to create file \"file4\": I/O error"),
+			createFile("file4", "0a1b2c3d4e5f60718293a4b5c6d7e8f9", 10))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		expectLookupFile()
+		expectCreateChildren("file3")
+
+		require.NoError(t, createFile("file3", "5b6e2c1a6f5d4e3b2a1908f7e6d5c4b3", 150))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateLinks(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		err := d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "foo.o", DestinationPath: "bar.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/c6adef0d5ca1888a4aa847fb51229a8c/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
+	}, response)
+
+	t.Run("SourceNonexistent", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("foo.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		err := d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "foo.o", DestinationPath: "bar.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to look up source path \"foo.o\": Path does not exist"), err)
+	})
+
+	t.Run("SourceParentNonexistent", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("objs")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		err := d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "objs/foo.o", DestinationPath: "bar.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to look up source path \"objs/foo.o\": Failed to resolve path: Path does not exist"), err)
+	})
+
+	t.Run("SourceDirectory", func(t *testing.T) {
+		// Hard links can only be created for files and
+		// symbolic links.
+		childDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("objs")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(childDirectory), nil)
+
+		err := d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "objs", DestinationPath: "bar.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to look up source path \"objs\": Path resolves to a directory"), err)
+	})
+
+	t.Run("SourceStale", func(t *testing.T) {
+		// The leaf may have been removed from the output path
+		// in the meantime.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("foo.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Link().Return(re_vfs.StatusErrStale)
+
+		err := d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "foo.o", DestinationPath: "bar.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to look up source path \"foo.o\": Path does not exist"), err)
+	})
+
+	t.Run("DestinationCreationFailure", func(t *testing.T) {
+		// If the link cannot be created, the link count of the
+		// leaf that was incremented should be decremented.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("foo.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Link().Return(re_vfs.StatusOK)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("bar.o"): re_vfs.InitialNode{}.FromLeaf(leaf),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		leaf.EXPECT().Unlink()
+
+		err := d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "foo.o", DestinationPath: "bar.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create link \"bar.o\": I/O error"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Link "objs/foo.o" to "bin/foo.o". Both paths should
+		// refer to the same leaf, meaning that they observe the
+		// same contents. As the link count of the leaf is
+		// incremented, removing either path keeps the other
+		// one intact.
+		objsDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("objs")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(objsDirectory), nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		objsDirectory.EXPECT().LookupChild(path.MustNewComponent("foo.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Link().Return(re_vfs.StatusOK)
+		binDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("bin")).Return(binDirectory, nil)
+		binDirectory.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo.o"): re_vfs.InitialNode{}.FromLeaf(leaf),
+		}, true)
+
+		require.NoError(t, d.BatchCreateLinks(ctx, "ad778a53-48e6-4ae1-b1f5-01b84a508f5f", []cd_vfs.OutputLink{
+			{SourcePath: "objs/foo.o", DestinationPath: "bin/foo.o"},
+		}))
+
+		// Both paths should report the same file status.
+		fileStatus := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+		}
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("objs")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(objsDirectory), nil)
+		objsDirectory.EXPECT().LookupChild(path.MustNewComponent("foo.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bin")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(binDirectory), nil)
+		binDirectory.EXPECT().LookupChild(path.MustNewComponent("foo.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(fileStatus, nil).Times(2)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			IncludeFileDigest: true,
+			Paths:             []string{"objs/foo.o", "bin/foo.o"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{FileStatus: fileStatus},
+				{FileStatus: fileStatus},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchRemove(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"hello.txt"}, false)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("File", func(t *testing.T) {
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		file := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		directory.EXPECT().Remove(path.MustNewComponent("hello.txt"))
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet)
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"dir/hello.txt"}, false)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil}, errs)
+	})
+
+	t.Run("Nonexistent", func(t *testing.T) {
+		// Paths that don't exist, either because the child or
+		// one of its parent directories is absent, should be
+		// treated as being removed successfully.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"hello.txt", "dir/hello.txt"}, false)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil, nil}, errs)
+	})
+
+	t.Run("EmptyDirectory", func(t *testing.T) {
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		outputPath.EXPECT().Remove(path.MustNewComponent("dir"))
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"dir"}, false)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil}, errs)
+	})
+
+	t.Run("NonEmptyDirectory", func(t *testing.T) {
+		// Directories that are not empty may only be removed
+		// if recursive removal is requested. Failures should
+		// not prevent other paths from being removed.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		outputPath.EXPECT().Remove(path.MustNewComponent("dir")).Return(syscall.ENOTEMPTY)
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		outputPath.EXPECT().Remove(path.MustNewComponent("hello.txt"))
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet)
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"dir", "hello.txt"}, false)
+		require.NoError(t, err)
+		require.Len(t, errs, 2)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to remove path \"dir\": Directory is not empty"), errs[0])
+		require.NoError(t, errs[1])
+	})
+
+	t.Run("RecursiveDirectory", func(t *testing.T) {
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().RemoveAllChildren(true)
+		outputPath.EXPECT().Remove(path.MustNewComponent("dir"))
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"dir"}, true)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil}, errs)
+	})
+
+	t.Run("RecursiveFile", func(t *testing.T) {
+		// Recursive removal should have no effect on files.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		outputPath.EXPECT().Remove(path.MustNewComponent("hello.txt"))
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet)
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"hello.txt"}, true)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil}, errs)
+	})
+
+	t.Run("RootDirectory", func(t *testing.T) {
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"."}, true)
+		require.NoError(t, err)
+		require.Len(t, errs, 1)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to remove path \".\": Path does not have a final component"), errs[0])
+	})
+}
+
+func TestRemoteOutputServiceDirectoryTrimBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.TrimBuild(ctx, "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71")
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("NothingToTrim", func(t *testing.T) {
+		trimmedDirectoriesCount, err := d.TrimBuild(ctx, "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71")
+		require.NoError(t, err)
+		require.Equal(t, 0, trimmedDirectoriesCount)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Create a directory through BatchCreate(). Trimming
+		// it should cause it to be replaced by a directory
+		// whose contents are loaded from the Tree object once
+		// more.
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71",
+			Directories: []*remoteexecution.OutputDirectory{{
+				Path: "dir",
+				TreeDigest: &remoteexecution.Digest{
+					Hash:      "7f1ea7bb6b2b1e9e0e2f4a28e8a6e5b7ffa6a51a1cbc5ec5ff6eeec1a23cd2f3",
+					SizeBytes: 123,
+				},
+			}},
+		})
+		require.NoError(t, err)
+
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		var fetcher re_vfs.InitialContentsFetcher
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).
+			DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 1)
+				fetcher, _ = children[path.MustNewComponent("dir")].GetPair()
+				require.NotNil(t, fetcher)
+				return nil
+			})
+
+		trimmedDirectoriesCount, err := d.TrimBuild(ctx, "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71")
+		require.NoError(t, err)
+		require.Equal(t, 1, trimmedDirectoriesCount)
+
+		treeDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "7f1ea7bb6b2b1e9e0e2f4a28e8a6e5b7ffa6a51a1cbc5ec5ff6eeec1a23cd2f3", 123)
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), treeDigest).Return(&remoteexecution.Directory{}, nil)
+		digests, err := fetcher.GetContainingDigests(ctx)
+		require.NoError(t, err)
+		require.Equal(t, treeDigest.ToSingletonSet(), digests)
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		// Directories whose contents have been modified
+		// through the Remote Output Service should no longer
+		// be trimmed.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().LookupChild(path.MustNewComponent("hello.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		errs, err := d.BatchRemove(ctx, "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71", []string{"dir/hello.txt"}, false)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil}, errs)
+
+		trimmedDirectoriesCount, err := d.TrimBuild(ctx, "3a3ab1e4-3d0e-4d59-a8e5-d1e7b6bd4b71")
+		require.NoError(t, err)
+		require.Equal(t, 0, trimmedDirectoriesCount)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryCorruption(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Builds: cd_vfs.RemoteOutputServiceBuildsConfiguration{
+			CleanCorruptedOutputPaths: true,
+		},
+	})
+
+	// Start a build in an output path.
+	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
+	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath1 := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath1)
+	outputPath1.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8",
+		BuildId:          "f4a1e5a5-e9a2-4d8b-ae52-b3e0dd0bb3e4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// DATA_LOSS errors returned by the Content Addressable Storage
+	// don't indicate that the output path itself is corrupted. They
+	// should be propagated without marking the output path.
+	file := mock.NewMockNativeLeaf(ctrl)
+	outputPath1.EXPECT().LookupChild(path.MustNewComponent("file")).
+		Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+	file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_File_{
+			File: &remoteoutputservice.FileStatus_File{
+				Digest: fileDigest.GetProto(),
+			},
+		},
+	}, nil)
+	file.EXPECT().GetContainingDigests().Return(fileDigest.ToSingletonSet())
+	f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+		Return(buffer.NewBufferFromError(status.Error(codes.DataLoss, "Blob is corrupted")))
+
+	_, err = d.BatchStatWithDigestInclusionMode(ctx, &remoteoutputservice.BatchStatRequest{
+		BuildId: "f4a1e5a5-e9a2-4d8b-ae52-b3e0dd0bb3e4",
+		Paths:   []string{"file"},
+	}, cd_vfs.DigestInclusionModeAlwaysVerified)
+	expectedStatus, detailsErr := status.New(codes.DataLoss, fmt.Sprintf("Failed to resolve path \"file\" beyond \".\": Failed to read contents of file with digest %#v: Blob is corrupted", fileDigest.String())).
+		WithDetails(&errdetails.ErrorInfo{
+			Reason: "REMOTE_DATA_LOSS",
+			Domain: "github.com/buildbarn/bb-clientd",
+		})
+	require.NoError(t, detailsErr)
+	testutil.RequireEqualStatus(t, expectedStatus.Err(), err)
+	require.Equal(t, cd_vfs.RuntimeStatistics{
+		OutputPathsCount:   1,
+		RunningBuildsCount: 1,
+	}, d.GetRuntimeStatistics())
+
+	// Let the output path report that its contents are corrupted.
+	outputPath1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("foo")).
+		Return(nil, status.Error(codes.DataLoss, "Checksum mismatch"))
+
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "f4a1e5a5-e9a2-4d8b-ae52-b3e0dd0bb3e4",
+		PathPrefix: "foo",
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.DataLoss, "Failed to create path prefix directory: Checksum mismatch"), err)
+
+	// Successive operations against the output path should fail
+	// immediately, without accessing the output path.
+	_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+		BuildId: "f4a1e5a5-e9a2-4d8b-ae52-b3e0dd0bb3e4",
+		Paths:   []string{"foo"},
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.DataLoss, "Output path \"4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8\" is corrupted, and needs to be cleaned"), err)
+	require.Equal(t, cd_vfs.RuntimeStatistics{
+		OutputPathsCount:          1,
+		RunningBuildsCount:        1,
+		CorruptedOutputPathsCount: 1,
+	}, d.GetRuntimeStatistics())
+
+	// Starting the next build should cause the corrupted output
+	// path to be discarded, and a new one to be created.
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8"))
+	outputPath1.EXPECT().RemoveAllChildren(true).Return(status.Error(codes.DataLoss, "Checksum mismatch"))
+	f.outputPathFactory.EXPECT().Clean(path.MustNewComponent("4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8"))
+	casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
+	casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath2 := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath2)
+	outputPath2.EXPECT().FilterChildren(gomock.Any())
+
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "4ad4bcc4a5d17a3b1e4bbe3e7d6b8ef8",
+		BuildId:          "0d1d9a18-9f44-4b52-8ff0-6f0e7b8e2c3a",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	require.Equal(t, cd_vfs.RuntimeStatistics{
+		OutputPathsCount:   1,
+		RunningBuildsCount: 1,
+	}, d.GetRuntimeStatistics())
+}
+
+func TestRemoteOutputServiceDirectoryRuntimeStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumEstimatedMemoryUsageBytes: 10000,
+		},
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			OutputPathsConcurrency: semaphore.NewWeighted(1),
+		},
+		Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+			FileDigestCacheSize:       10,
+			MaximumInternedNamesCount: 10,
+		},
+	})
+
+	// Without any output paths, all statistics should be zero.
+	require.Equal(t, cd_vfs.RuntimeStatistics{}, d.GetRuntimeStatistics())
+
+	expectStartInitialBuild := func(outputBaseID string) *mock.MockOutputPath {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		return outputPath
+	}
+	startBuild := func(outputBaseID, buildID string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
+
+	// Start a build whose output path contains a single file. While
+	// the output path is being filtered, start a second build. As
+	// FindMissing.OutputPathsConcurrency only permits a single
+	// output path to be filtered at a time, the second build should
+	// be queued.
+	fileDigests := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5).ToSingletonSet()
+	outputPath1 := expectStartInitialBuild("9da951b8cb759233037166e28f7ea186")
+	outputPath2 := expectStartInitialBuild("c6adef0d5ca1888a4aa847fb51229a8c")
+	errs := make(chan error, 1)
+	outputPath1.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		statistics := d.GetRuntimeStatistics()
+		require.Equal(t, int64(0), statistics.QueuedStartBuildsCount)
+		require.Equal(t, int64(1), statistics.FilteringOutputPathsCount)
+
+		go func() {
+			errs <- startBuild("c6adef0d5ca1888a4aa847fb51229a8c", "ad778a53-48e6-4ae1-b1f5-01b84a508f5f")
+		}()
+		require.Eventually(t, func() bool {
+			return d.GetRuntimeStatistics().QueuedStartBuildsCount == 1
+		}, 10*time.Second, time.Millisecond)
+
+		child := mock.NewMockNativeLeaf(ctrl)
+		child.EXPECT().GetContainingDigests().Return(fileDigests)
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), mock.NewMockChildRemover(ctrl).Call))
+		return nil
+	})
+	var findMissingStatistics cd_vfs.RuntimeStatistics
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), fileDigests).DoAndReturn(
+		func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+			findMissingStatistics = d.GetRuntimeStatistics()
+			return digest.EmptySet, nil
+		})
+	outputPath2.EXPECT().FilterChildren(gomock.Any())
+
+	require.NoError(t, startBuild("9da951b8cb759233037166e28f7ea186", "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	require.NoError(t, <-errs)
+
+	// Calls against the CAS should be reported while they are in
+	// flight.
+	require.Equal(t, int64(1), findMissingStatistics.CASCallsInFlightCount)
+	require.Equal(t, int64(1), findMissingStatistics.FilteringOutputPathsCount)
+
+	// Once both builds have started, nothing should be queued or
+	// in flight.
+	require.Equal(t, cd_vfs.RuntimeStatistics{
+		OutputPathsCount:   2,
+		RunningBuildsCount: 2,
+	}, d.GetRuntimeStatistics())
+
+	t.Run("NameInternerAndMemoryUsage", func(t *testing.T) {
+		// Creating the same symbolic link twice should cause
+		// its name and target to be interned the first time,
+		// and found in the name interner the second time. Both
+		// symbolic links are accounted for in the memory budget,
+		// as the output path is mocked.
+		for i := 0; i < 2; i++ {
+			f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(mock.NewMockNativeLeaf(ctrl))
+			outputPath1.EXPECT().CreateChildren(gomock.Any(), true)
+
+			_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Symlinks: []*remoteexecution.OutputSymlink{
+					{
+						Path:   "link",
+						Target: "target",
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		statistics := d.GetRuntimeStatistics()
+		require.Equal(t, uint64(2), statistics.NameInternerHits)
+		require.Equal(t, uint64(2), statistics.NameInternerMisses)
+		require.Equal(t, 2, statistics.InternedNamesCount)
+		require.Equal(t, int64(2*(256+6)), statistics.EstimatedOutputPathsMemoryUsageBytes)
+		require.Equal(t, int64(4+6+2*64), statistics.EstimatedNameInternerMemoryUsageBytes)
+	})
+
+	t.Run("FileDigestCache", func(t *testing.T) {
+		// Request the digest of a locally written file twice.
+		// The first request should miss the file digest cache,
+		// while the second request should hit it.
+		digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath1.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil).
+			Times(2)
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet).AnyTimes()
+		file.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskChangeID, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetChangeID(1)
+			}).
+			Times(3)
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		for i := 0; i < 2; i++ {
+			_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+				BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				IncludeFileDigest: true,
+				Paths:             []string{"file"},
+			})
+			require.NoError(t, err)
+		}
+
+		statistics := d.GetRuntimeStatistics()
+		require.Equal(t, uint64(1), statistics.FileDigestCacheHits)
+		require.Equal(t, uint64(1), statistics.FileDigestCacheMisses)
+		require.Equal(t, 1, statistics.FileDigestCacheEntriesCount)
+		require.Equal(t, int64(256), statistics.EstimatedFileDigestCacheMemoryUsageBytes)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryCancelBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Snapshots: cd_vfs.SnapshotConfiguration{
+			UploadConcurrency: semaphore.NewWeighted(1),
+		},
+	})
+
+	// Every build creates a snapshot of the output path, which is
+	// empty at the time builds are started.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).AnyTimes()
+	outputPath.EXPECT().LookupAllChildren().Return(nil, nil, nil).AnyTimes()
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.EmptySet).Return(digest.EmptySet, nil).AnyTimes()
+	var snapshotDigest digest.Digest
+	f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+			snapshotDigest = blobDigest
+			b.Discard()
+			return nil
+		}).AnyTimes()
+
+	startBuild := func(buildID string) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		// Requests for unknown build IDs should be ignored, so
+		// that CancelBuild() is idempotent.
+		require.NoError(t, d.CancelBuild(ctx, "a1bc2d35-ebb8-4a53-8b7e-0e6a1b3ab27a", true))
+	})
+
+	t.Run("WithoutRevert", func(t *testing.T) {
+		// Cancelling the build should cause successive calls
+		// to fail. The output path should not be finalized.
+		startBuild("2d1c0a8e-4a3f-4ba0-9d8c-6a3cf6aa5c52")
+		require.NoError(t, d.CancelBuild(ctx, "2d1c0a8e-4a3f-4ba0-9d8c-6a3cf6aa5c52", false))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "2d1c0a8e-4a3f-4ba0-9d8c-6a3cf6aa5c52",
+			Paths:   []string{"foo"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+		require.Equal(t, cd_vfs.RuntimeStatistics{
+			OutputPathsCount: 1,
+		}, d.GetRuntimeStatistics())
+	})
+
+	t.Run("RevertFailure", func(t *testing.T) {
+		// Failures loading the snapshot should be propagated.
+		// The build should still be cancelled.
+		startBuild("4b3e1f36-0e3e-4f0b-a5a4-d6b5d7c1b0c9")
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), snapshotDigest).
+			Return(nil, status.Error(codes.Unavailable, "Server offline"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Failed to revert the output path to its snapshot: Failed to fetch root directory of the snapshot: Server offline"),
+			d.CancelBuild(ctx, "4b3e1f36-0e3e-4f0b-a5a4-d6b5d7c1b0c9", true))
+		require.Equal(t, cd_vfs.RuntimeStatistics{
+			OutputPathsCount: 1,
+		}, d.GetRuntimeStatistics())
+	})
+
+	t.Run("RevertSuccess", func(t *testing.T) {
+		// Reverting the output path should cause its contents
+		// to be replaced with the ones stored in the snapshot.
+		startBuild("8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4")
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), snapshotDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{
+					Name: "bin",
+					Digest: &remoteexecution.Digest{
+						Hash:      "f11a2a6b9e3ba42b26f0e2e8d2f3bb3d7d03ee0ebb1e6f4ae9bbbbc68e1b1c39",
+						SizeBytes: 42,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{
+					Name:   "latest",
+					Target: "bin/hello",
+				},
+			},
+		}, nil)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/hello")).Return(symlink)
+		outputPath.EXPECT().RemoveAllChildren(false)
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+			func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 2)
+				directory, _ := children[path.MustNewComponent("bin")].GetPair()
+				require.NotNil(t, directory)
+				_, leaf := children[path.MustNewComponent("latest")].GetPair()
+				require.Equal(t, symlink, leaf)
+				return nil
+			})
+
+		require.NoError(t, d.CancelBuild(ctx, "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4", true))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryCloneOutputPath(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	// Create a source output path containing a single symbolic link.
+	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
+	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath1 := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath1)
+	outputPath1.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Destination output base ID is not a valid filename"),
+			d.CloneOutputPath(ctx, "9da951b8cb759233037166e28f7ea186", "//////", false))
+	})
+
+	t.Run("NonexistentSource", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Output path \"5e3bb7c1e8b4d8cba2a4e6b3c5f1a9d2\" does not exist"),
+			d.CloneOutputPath(ctx, "5e3bb7c1e8b4d8cba2a4e6b3c5f1a9d2", "1b4f0e9870cd2f49b9e9eb4b0d6b3b79", false))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The source output path should be uploaded into the
+		// CAS, so that the destination output path can be
+		// populated with its contents.
+		symlink1 := mock.NewMockNativeLeaf(ctrl)
+		outputPath1.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("latest"), Child: symlink1},
+			},
+			nil)
+		symlink1.EXPECT().Readlink().Return("bin/hello", nil)
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.EmptySet).Return(digest.EmptySet, nil).AnyTimes()
+		var snapshotDigest digest.Digest
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				snapshotDigest = blobDigest
+				b.Discard()
+				return nil
+			})
+
+		casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
+		casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath2 := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("1b4f0e9870cd2f49b9e9eb4b0d6b3b79"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath2)
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+				require.Equal(t, snapshotDigest, treeDigest)
+				return &remoteexecution.Directory{
+					Symlinks: []*remoteexecution.SymlinkNode{
+						{
+							Name:   "latest",
+							Target: "bin/hello",
+						},
+					},
+				}, nil
+			})
+		symlink2 := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/hello")).Return(symlink2)
+		outputPath2.EXPECT().RemoveAllChildren(false)
+		outputPath2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("latest"): re_vfs.InitialNode{}.FromLeaf(symlink2),
+		}, true)
+
+		require.NoError(t, d.CloneOutputPath(ctx, "9da951b8cb759233037166e28f7ea186", "1b4f0e9870cd2f49b9e9eb4b0d6b3b79", false))
+		require.Equal(t, cd_vfs.RuntimeStatistics{
+			OutputPathsCount:   2,
+			RunningBuildsCount: 1,
+		}, d.GetRuntimeStatistics())
+
+		// Cloning once more should fail, as the destination
+		// output path already exists.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.AlreadyExists, "Output path \"1b4f0e9870cd2f49b9e9eb4b0d6b3b79\" already exists"),
+			d.CloneOutputPath(ctx, "9da951b8cb759233037166e28f7ea186", "1b4f0e9870cd2f49b9e9eb4b0d6b3b79", false))
+
+		// Changes made to the destination output path should
+		// not affect the source output path.
+		outputPath2.EXPECT().FilterChildren(gomock.Any())
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "1b4f0e9870cd2f49b9e9eb4b0d6b3b79",
+			BuildId:          "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		symlink3 := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/goodbye")).Return(symlink3)
+		outputPath2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("latest"): re_vfs.InitialNode{}.FromLeaf(symlink3),
+		}, true)
+
+		_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "8b9a0a4c-4c5d-4f0e-9d33-0b2f5e0ce1f4",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "latest",
+					Target: "bin/goodbye",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		// The destination output path can't be overwritten
+		// while a build is running against it.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Output path \"1b4f0e9870cd2f49b9e9eb4b0d6b3b79\" cannot be overwritten, as a build is running against it"),
+			d.CloneOutputPath(ctx, "9da951b8cb759233037166e28f7ea186", "1b4f0e9870cd2f49b9e9eb4b0d6b3b79", true))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryRenameOutputBase(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	// Create two output paths, and finalize the builds running
+	// against them.
+	var outputPaths []*mock.MockOutputPath
+	for _, request := range []*remoteoutputservice.StartBuildRequest{
+		{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		},
+		{
+			OutputBaseId:     "1b4f0e9870cd2f49b9e9eb4b0d6b3b79",
+			BuildId:          "d7c3b3d5-9b1c-4e43-8c9f-57e5ac3ff1a4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		},
+	} {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(request.OutputBaseId),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, request)
+		require.NoError(t, err)
+		outputPaths = append(outputPaths, outputPath)
+	}
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Destination output base ID is not a valid filename"),
+			d.RenameOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "//////"))
+	})
+
+	t.Run("NonexistentSource", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Output path \"5e3bb7c1e8b4d8cba2a4e6b3c5f1a9d2\" does not exist"),
+			d.RenameOutputBase(ctx, "5e3bb7c1e8b4d8cba2a4e6b3c5f1a9d2", "4c1fa5b2c0e5a1a0f3e1b7c8d9e2f3a4"))
+	})
+
+	t.Run("BuildRunning", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Output path \"9da951b8cb759233037166e28f7ea186\" cannot be renamed, as a build is running against it"),
+			d.RenameOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "4c1fa5b2c0e5a1a0f3e1b7c8d9e2f3a4"))
+	})
+
+	for i, buildID := range []string{
+		"37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		"d7c3b3d5-9b1c-4e43-8c9f-57e5ac3ff1a4",
+	} {
+		outputPaths[i].EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: buildID,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("DestinationExists", func(t *testing.T) {
+		// Renaming should not cause existing output paths to be
+		// overwritten.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.AlreadyExists, "Output path \"1b4f0e9870cd2f49b9e9eb4b0d6b3b79\" already exists"),
+			d.RenameOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "1b4f0e9870cd2f49b9e9eb4b0d6b3b79"))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"))
+		outputPaths[0].EXPECT().Rename(path.MustNewComponent("4c1fa5b2c0e5a1a0f3e1b7c8d9e2f3a4"))
+
+		require.NoError(t, d.RenameOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "4c1fa5b2c0e5a1a0f3e1b7c8d9e2f3a4"))
+
+		// The output path should only be accessible under its
+		// new name.
+		var out1 re_vfs.Attributes
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), 0, &out1)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+
+		outputPaths[0].EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(101)
+			})
+
+		var out2 re_vfs.Attributes
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("4c1fa5b2c0e5a1a0f3e1b7c8d9e2f3a4"), re_vfs.AttributesMaskInodeNumber, &out2)
+		require.Equal(t, re_vfs.StatusOK, s)
+		remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPaths[0], child)
+
+		// Renaming the output path once more should fail, as
+		// it can no longer be found under its original name.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Output path \"9da951b8cb759233037166e28f7ea186\" does not exist"),
+			d.RenameOutputBase(ctx, "9da951b8cb759233037166e28f7ea186", "4c1fa5b2c0e5a1a0f3e1b7c8d9e2f3a4"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryDiagnoseSelfTest(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "5bd438902f7463c882921be24c6cffa6a3da28c52d9a8df72428f6d3ca925518", 51)
+	fileContents := []byte("This file was created by the bb_clientd self-test.\n")
+
+	t.Run("InvalidInstanceName", func(t *testing.T) {
+		_, err := d.DiagnoseSelfTest(ctx, "blobs", remoteexecution.DigestFunction_SHA256)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to parse instance name \"blobs\": Instance name contains reserved keyword \"blobs\""), err)
+	})
+
+	t.Run("UploadFailure", func(t *testing.T) {
+		// If the blob cannot be uploaded, none of the other
+		// stages should be performed.
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), fileDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Unavailable, "Server unavailable")
+			})
+
+		results, err := d.DiagnoseSelfTest(ctx, "", remoteexecution.DigestFunction_SHA256)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, cd_vfs.SelfTestStageUpload, results[0].Stage)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Server unavailable"), results[0].Err)
+	})
+
+	// Expectations for the stages up to the point where the
+	// contents of the file are read.
+	expectStagesUntilRead := func() *mock.MockOutputPath {
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), fileDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(100)
+				require.NoError(t, err)
+				require.Equal(t, fileContents, data)
+				return nil
+			})
+
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(cd_vfs.SelfTestOutputBaseID),
+			gomock.Any(),
+			digestFunction,
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+		var file re_vfs.NativeLeaf
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).DoAndReturn(
+			func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 1)
+				_, file = children[path.MustNewComponent("self_test.txt")].GetPair()
+				require.NotNil(t, file)
+				return nil
+			})
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("self_test.txt")).
+			DoAndReturn(func(name path.Component) (re_vfs.PrepopulatedDirectoryChild, error) {
+				return re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil
+			}).
+			Times(2)
+		return outputPath
+	}
+	expectClean := func(outputPath *mock.MockOutputPath) {
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digestFunction)
+		outputPath.EXPECT().RemoveAllChildren(true)
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent(cd_vfs.SelfTestOutputBaseID))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		outputPath := expectStagesUntilRead()
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice(fileContents))
+		expectClean(outputPath)
+
+		results, err := d.DiagnoseSelfTest(ctx, "", remoteexecution.DigestFunction_SHA256)
+		require.NoError(t, err)
+		require.Equal(t, []cd_vfs.SelfTestStageResult{
+			{Stage: cd_vfs.SelfTestStageUpload},
+			{Stage: cd_vfs.SelfTestStageStartBuild},
+			{Stage: cd_vfs.SelfTestStageBatchCreate},
+			{Stage: cd_vfs.SelfTestStageBatchStat},
+			{Stage: cd_vfs.SelfTestStageRead},
+			{Stage: cd_vfs.SelfTestStageClean},
+		}, results)
+	})
+
+	t.Run("ReadFailure", func(t *testing.T) {
+		// If the blob can be uploaded, but not be read back,
+		// the read stage should fail. The output path should
+		// still be cleaned afterwards.
+		outputPath := expectStagesUntilRead()
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.Unavailable, "Server unavailable")))
+		expectClean(outputPath)
+
+		results, err := d.DiagnoseSelfTest(ctx, "", remoteexecution.DigestFunction_SHA256)
+		require.NoError(t, err)
+		require.Len(t, results, 6)
+		for i, stage := range []cd_vfs.SelfTestStage{
+			cd_vfs.SelfTestStageUpload,
+			cd_vfs.SelfTestStageStartBuild,
+			cd_vfs.SelfTestStageBatchCreate,
+			cd_vfs.SelfTestStageBatchStat,
+		} {
+			require.Equal(t, stage, results[i].Stage)
+			require.NoError(t, results[i].Err)
+		}
+		require.Equal(t, cd_vfs.SelfTestStageRead, results[4].Stage)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to read file at offset 0"), results[4].Err)
+		require.Equal(t, cd_vfs.SelfTestStageClean, results[5].Stage)
+		require.NoError(t, results[5].Err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputPathTree(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := d.GetOutputPathTree(ctx, "//////")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"), err)
+	})
+
+	t.Run("NonexistentOutputPath", func(t *testing.T) {
+		_, err := d.GetOutputPathTree(ctx, "9da951b8cb759233037166e28f7ea186")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output path \"9da951b8cb759233037166e28f7ea186\" does not exist"), err)
+	})
+
+	// Run a build that creates a symbolic link in the output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	symlink := mock.NewMockNativeLeaf(ctrl)
+	f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/hello")).Return(symlink)
+	outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+		path.MustNewComponent("latest"): re_vfs.InitialNode{}.FromLeaf(symlink),
+	}, true)
+
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "latest",
+				Target: "bin/hello",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	t.Run("UploadFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("latest"), Child: symlink},
+			},
+			nil)
+		symlink.EXPECT().Readlink().Return("bin/hello", nil)
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.EmptySet).Return(digest.EmptySet, nil).AnyTimes()
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Unavailable, "CAS unavailable")
+			})
+
+		_, err := d.GetOutputPathTree(ctx, "9da951b8cb759233037166e28f7ea186")
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to create snapshot of the output path: Failed to upload tree: CAS unavailable"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The Tree object that is uploaded should contain the
+		// symbolic link that was created by the build.
+		outputPath.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("latest"), Child: symlink},
+			},
+			nil)
+		symlink.EXPECT().Readlink().Return("bin/hello", nil)
+		var uploadedDigest digest.Digest
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(10000)
+				require.NoError(t, err)
+				var tree remoteexecution.Tree
+				require.NoError(t, proto.Unmarshal(data, &tree))
+				testutil.RequireEqualProto(t, &remoteexecution.Tree{
+					Root: &remoteexecution.Directory{
+						Symlinks: []*remoteexecution.SymlinkNode{
+							{
+								Name:   "latest",
+								Target: "bin/hello",
+							},
+						},
+					},
+				}, &tree)
+				uploadedDigest = blobDigest
+				return nil
+			})
+
+		treeDigest, err := d.GetOutputPathTree(ctx, "9da951b8cb759233037166e28f7ea186")
+		require.NoError(t, err)
+		require.Equal(t, uploadedDigest, treeDigest)
+	})
+}
+
+func TestRemoteOutputServiceDirectorySnapshotStore(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	snapshotStore := cd_vfs.NewInMemorySnapshotStore(2)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		Snapshots: cd_vfs.SnapshotConfiguration{
+			Store: snapshotStore,
+		},
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		err := d.FinalizeBuildWithSnapshot(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		}, "latest")
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("NonexistentSnapshot", func(t *testing.T) {
+		_, err := d.RestoreSnapshot(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		}, "latest")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to obtain snapshot \"latest\": Snapshot \"latest\" does not exist"), err)
+	})
+
+	// Run a build that creates a symbolic link in the output path.
+	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
+	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath1 := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath1)
+	outputPath1.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	symlink1 := mock.NewMockNativeLeaf(ctrl)
+	f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/hello")).Return(symlink1)
+	outputPath1.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+		path.MustNewComponent("latest"): re_vfs.InitialNode{}.FromLeaf(symlink1),
+	}, true)
+
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "latest",
+				Target: "bin/hello",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.EmptySet).Return(digest.EmptySet, nil).AnyTimes()
+
+	t.Run("UploadFailure", func(t *testing.T) {
+		// If the snapshot cannot be created, the build should
+		// not be finalized, so that the client may retry.
+		outputPath1.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("latest"), Child: symlink1},
+			},
+			nil)
+		symlink1.EXPECT().Readlink().Return("bin/hello", nil)
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Unavailable, "CAS unavailable")
+			})
+
+		err := d.FinalizeBuildWithSnapshot(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		}, "latest")
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to create snapshot of the output path: Failed to upload tree: CAS unavailable"), err)
+		require.NoError(t, d.PingBuild(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	})
+
+	var snapshotDigest digest.Digest
+
+	t.Run("SaveSuccess", func(t *testing.T) {
+		// The Tree object containing the symbolic link should
+		// be uploaded, and a reference to it should be stored
+		// in the snapshot store prior to finalizing the build.
+		outputPath1.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("latest"), Child: symlink1},
+			},
+			nil)
+		symlink1.EXPECT().Readlink().Return("bin/hello", nil)
+		f.bareContentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				snapshotDigest = blobDigest
+				return nil
+			})
+		outputPath1.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+
+		require.NoError(t, d.FinalizeBuildWithSnapshot(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		}, "latest"))
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.PingBuild(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+
+		snapshot, err := snapshotStore.Get(ctx, "latest")
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.Snapshot{
+			OutputBaseID:    "9da951b8cb759233037166e28f7ea186",
+			BuildID:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			TreeDigest:      snapshotDigest,
+			CreationTime:    time.Unix(1000, 0),
+			BuildSuccessful: true,
+		}, snapshot)
+	})
+
+	t.Run("RestoreSuccess", func(t *testing.T) {
+		// Restoring the snapshot into another output base should
+		// cause it to be populated with the symbolic link.
+		casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
+		casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath2 := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("e2a9f6e0ef8cb3d6f7bd2a1f3e94d2b8"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath2)
+		f.directoryFetcher.EXPECT().GetTreeRootDirectory(gomock.Any(), snapshotDigest).Return(&remoteexecution.Directory{
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{
+					Name:   "latest",
+					Target: "bin/hello",
+				},
+			},
+		}, nil)
+		symlink2 := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("bin/hello")).Return(symlink2)
+		outputPath2.EXPECT().RemoveAllChildren(false)
+		outputPath2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("latest"): re_vfs.InitialNode{}.FromLeaf(symlink2),
+		}, true)
+		outputPath2.EXPECT().FilterChildren(gomock.Any())
+
+		response, err := d.RestoreSnapshot(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "e2a9f6e0ef8cb3d6f7bd2a1f3e94d2b8",
+			BuildId:          "bd4d4e08-a3f5-4d7c-94e8-2a4c6f0c5e9b",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		}, "latest")
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "e2a9f6e0ef8cb3d6f7bd2a1f3e94d2b8",
+		}, response)
+	})
+}
+
+// openLocalDirectory opens a directory on the local file system to
+// which ExportPath() may write. The directory is closed when the test
+// completes.
+func openLocalDirectory(t *testing.T, directoryPath string) filesystem.Directory {
+	directory, err := filesystem.NewLocalDirectory(directoryPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, directory.Close()) })
+	return directory
+}
+
+func TestRemoteOutputServiceDirectoryExportPath(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.ExportPath(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", ".", openLocalDirectory(t, t.TempDir())))
+	})
+
+	// Start a build, so that files can be exported.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("NonexistentPath", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Failed to look up source path \"nonexistent\": Path does not exist"),
+			d.ExportPath(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "nonexistent", openLocalDirectory(t, t.TempDir())))
+	})
+
+	t.Run("PathThroughSymlink", func(t *testing.T) {
+		// Symbolic links should not be followed while resolving
+		// the source path.
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("latest")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlink), nil)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Failed to look up source path \"latest/hello\": Failed to resolve path: Path resolves through a file"),
+			d.ExportPath(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "latest/hello", openLocalDirectory(t, t.TempDir())))
+	})
+
+	t.Run("ReadFailure", func(t *testing.T) {
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().VirtualOpenSelf(ctx, re_vfs.ShareMaskRead, &re_vfs.OpenExistingOptions{}, re_vfs.AttributesMaskPermissions, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, shareAccess re_vfs.ShareMask, options *re_vfs.OpenExistingOptions, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) re_vfs.Status {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsWrite)
+				return re_vfs.StatusOK
+			})
+		file.EXPECT().VirtualRead(gomock.Any(), uint64(0)).Return(0, false, re_vfs.StatusErrIO)
+		file.EXPECT().VirtualClose(uint(1))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to read file \"hello\" at offset 0"),
+			d.ExportPath(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "hello", openLocalDirectory(t, t.TempDir())))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Export the full output path, containing a symbolic
+		// link pointing outside the output path and an
+		// executable in a subdirectory.
+		binDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		executable := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("bin"), Child: binDirectory},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("latest"), Child: symlink},
+			},
+			nil)
+		binDirectory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("hello"), Child: executable},
+			},
+			nil)
+		executable.EXPECT().Readlink().Return("", syscall.EINVAL)
+		executable.EXPECT().VirtualOpenSelf(ctx, re_vfs.ShareMaskRead, &re_vfs.OpenExistingOptions{}, re_vfs.AttributesMaskPermissions, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, shareAccess re_vfs.ShareMask, options *re_vfs.OpenExistingOptions, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) re_vfs.Status {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsExecute)
+				return re_vfs.StatusOK
+			})
+		executable.EXPECT().VirtualRead(gomock.Any(), uint64(0)).DoAndReturn(
+			func(buf []byte, offset uint64) (int, bool, re_vfs.Status) {
+				return copy(buf, "Hello"), false, re_vfs.StatusOK
+			})
+		executable.EXPECT().VirtualRead(gomock.Any(), uint64(5)).DoAndReturn(
+			func(buf []byte, offset uint64) (int, bool, re_vfs.Status) {
+				return copy(buf, " world"), true, re_vfs.StatusOK
+			})
+		executable.EXPECT().VirtualClose(uint(1))
+		symlink.EXPECT().Readlink().Return("/etc/passwd", nil)
+
+		destinationPath := t.TempDir()
+		require.NoError(t, d.ExportPath(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", ".", openLocalDirectory(t, destinationPath)))
+
+		data, err := os.ReadFile(filepath.Join(destinationPath, "bin", "hello"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello world"), data)
+		fileInfo, err := os.Lstat(filepath.Join(destinationPath, "bin", "hello"))
+		require.NoError(t, err)
+		require.NotZero(t, fileInfo.Mode()&0o100)
+
+		target, err := os.Readlink(filepath.Join(destinationPath, "latest"))
+		require.NoError(t, err)
+		require.Equal(t, "/etc/passwd", target)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryReadFileRange(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumReadFileRangeSizeBytes: 8,
+		},
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "manifest", 0, 4)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	var casFileFactory re_vfs.CASFileFactory
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).DoAndReturn(func(outputBaseID path.Component, ff re_vfs.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) cd_vfs.OutputPath {
+		casFileFactory = ff
+		return outputPath
+	})
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Create a file backed by the Content Addressable Storage that
+	// is used by all of the tests below.
+	fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882", 10)
+	fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+	casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+	fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+	file := casFileFactory.LookupFile(fileDigest, false)
+
+	t.Run("TooLarge", func(t *testing.T) {
+		_, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "file.txt", 0, 9)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Requested 9 bytes, which exceeds the permitted maximum of 8 bytes"), err)
+	})
+
+	t.Run("Nonexistent", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		_, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "nonexistent", 0, 4)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Path \"nonexistent\" does not exist"), err)
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(mock.NewMockPrepopulatedDirectory(ctrl)), nil)
+
+		_, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "bazel-out", 0, 4)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"bazel-out\" does not resolve to a file in the output path"), err)
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("0123456789")))
+
+		contents, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "file.txt", 0, 4)
+		require.NoError(t, err)
+		require.Equal(t, []byte("0123"), contents)
+	})
+
+	t.Run("Middle", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("0123456789")))
+
+		contents, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "file.txt", 3, 4)
+		require.NoError(t, err)
+		require.Equal(t, []byte("3456"), contents)
+	})
+
+	t.Run("ShortRead", func(t *testing.T) {
+		// Ranges that extend past the end of the file should
+		// only return the data up to the end of the file.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("0123456789")))
+
+		contents, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "file.txt", 7, 8)
+		require.NoError(t, err)
+		require.Equal(t, []byte("789"), contents)
+	})
+
+	t.Run("PastEOF", func(t *testing.T) {
+		// Ranges that start past the end of the file should
+		// not cause any data to be fetched.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file.txt")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+
+		contents, err := d.ReadFileRange(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "file.txt", 20, 4)
+		require.NoError(t, err)
+		require.Empty(t, contents)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetFileContents(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.GetFileContents(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"manifest"}, 1<<20)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("Directory", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(mock.NewMockPrepopulatedDirectory(ctrl)), nil)
+
+		_, err := d.GetFileContents(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"bazel-out"}, 1<<20)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"bazel-out\" does not resolve to a file in the output path"), err)
+	})
+
+	t.Run("ReadFailure", func(t *testing.T) {
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("manifest")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+		file.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskSizeBytes, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetSizeBytes(5)
+			})
+		file.EXPECT().VirtualOpenSelf(ctx, re_vfs.ShareMaskRead, &re_vfs.OpenExistingOptions{}, re_vfs.AttributesMask(0), gomock.Any()).Return(re_vfs.StatusOK)
+		file.EXPECT().VirtualRead(gomock.Any(), uint64(0)).Return(0, false, re_vfs.StatusErrIO)
+		file.EXPECT().VirtualClose(uint(1))
+
+		_, err := d.GetFileContents(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"manifest"}, 1<<20)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to read contents of file \"manifest\": Failed to read file at offset 0"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Small files should be returned inline, while the
+		// digests of large files should be returned. Paths that
+		// don't exist should yield an empty result.
+		smallFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("manifest")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(smallFile), nil)
+		smallFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		smallFile.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+		smallFile.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskSizeBytes, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetSizeBytes(5)
+			})
+		smallFile.EXPECT().VirtualOpenSelf(ctx, re_vfs.ShareMaskRead, &re_vfs.OpenExistingOptions{}, re_vfs.AttributesMask(0), gomock.Any()).Return(re_vfs.StatusOK)
+		smallFile.EXPECT().VirtualRead(gomock.Any(), uint64(0)).DoAndReturn(
+			func(buf []byte, offset uint64) (int, bool, re_vfs.Status) {
+				return copy(buf, "Hello"), true, re_vfs.StatusOK
+			})
+		smallFile.EXPECT().VirtualClose(uint(1))
+
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		// Paths that traverse a file should also yield an
+		// empty result, as done by BatchStat().
+		parentFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(parentFile), nil)
+		parentFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+
+		largeFile := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("large.bin")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(largeFile), nil)
+		largeFile.EXPECT().Readlink().Return("", syscall.EINVAL)
+		largeFile.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+		largeFile.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskSizeBytes, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetSizeBytes(2 << 20)
+			})
+		digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+		largeFile.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "5b2b4a6ad8e5da36eb6e6b7a1a7bb4a0a4e0d4e5b8e0e9d5c4f7e2a1b3c6d9f0",
+						SizeBytes: 2 << 20,
+					},
+				},
+			},
+		}, nil)
+
+		results, err := d.GetFileContents(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"manifest", "nonexistent", "file/child", "large.bin"}, 1<<20)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		require.Equal(t, &cd_vfs.FileContents{Contents: []byte("Hello")}, results[0])
+		require.Nil(t, results[1])
+		require.Nil(t, results[2])
+		require.Nil(t, results[3].Contents)
+		testutil.RequireEqualProto(t, &remoteexecution.Digest{
+			Hash:      "5b2b4a6ad8e5da36eb6e6b7a1a7bb4a0a4e0d4e5b8e0e9d5c4f7e2a1b3c6d9f0",
+			SizeBytes: 2 << 20,
+		}, results[3].Digest)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryReadDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.ReadDirectory(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "bazel-out", false)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("NonexistentPath", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		_, err := d.ReadDirectory(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "nonexistent", false)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Path \"nonexistent\" does not exist"), err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("manifest")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		_, err := d.ReadDirectory(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "manifest", false)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"manifest\" does not resolve to a directory in the output path"), err)
+	})
+
+	t.Run("InMemoryDirectory", func(t *testing.T) {
+		// Directory that was created through BatchCreate(),
+		// containing a locally written file and a symbolic
+		// link. Entries should be sorted by name.
+		bazelOutDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(bazelOutDirectory), nil)
+		file := mock.NewMockNativeLeaf(ctrl)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		bazelOutDirectory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("stable-status.txt"), Child: file},
+				{Name: path.MustNewComponent("bin"), Child: symlink},
+			},
+			nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+		symlink.EXPECT().Readlink().Return("k8-fastbuild/bin", nil)
+
+		entries, err := d.ReadDirectory(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "bazel-out", false)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, path.MustNewComponent("bin"), entries[0].Name)
+		testutil.RequireEqualProto(t, &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_Symlink_{
+				Symlink: &remoteoutputservice.FileStatus_Symlink{
+					Target: "k8-fastbuild/bin",
+				},
+			},
+		}, entries[0].FileStatus)
+		require.Equal(t, path.MustNewComponent("stable-status.txt"), entries[1].Name)
+		testutil.RequireEqualProto(t, &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, entries[1].FileStatus)
+	})
+
+	t.Run("LazyCASDirectory", func(t *testing.T) {
+		// Directory that is backed by the Content Addressable
+		// Storage. Only the directory itself should be loaded.
+		// Its subdirectory should not be accessed, as that
+		// would cause its contents to be loaded as well.
+		// Digests of files should be included if requested.
+		libDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("lib")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(libDirectory), nil)
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		file := mock.NewMockNativeLeaf(ctrl)
+		libDirectory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("include"), Child: subDirectory},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("libhello.so"), Child: file},
+			},
+			nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+
+		entries, err := d.ReadDirectory(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", "lib", true)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, path.MustNewComponent("include"), entries[0].Name)
+		testutil.RequireEqualProto(t, &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_Directory_{
+				Directory: &remoteoutputservice.FileStatus_Directory{},
+			},
+		}, entries[0].FileStatus)
+		require.Equal(t, path.MustNewComponent("libhello.so"), entries[1].Name)
+		testutil.RequireEqualProto(t, &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, entries[1].FileStatus)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryFinalizeBuildWithStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		require.Equal(t, &cd_vfs.BuildStatistics{}, d.FinalizeBuildWithStatistics(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		}))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Start a build, capturing the CAS file factory that is
+		// provided to the output path.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		var casFileFactory re_vfs.CASFileFactory
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).DoAndReturn(func(outputBaseID path.Component, ff re_vfs.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) cd_vfs.OutputPath {
+			casFileFactory = ff
+			return outputPath
+		})
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Read the contents of a file backed by the CAS. This
+		// should cause the number of bytes read to be counted.
+		fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+		file := casFileFactory.LookupFile(fileDigest, false)
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		var buf [10]byte
+		n, eof, s := file.VirtualRead(buf[:], 0)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.True(t, eof)
+		require.Equal(t, []byte("Hello"), buf[:n])
+
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		require.Equal(t, &cd_vfs.BuildStatistics{
+			CASFileBytesRead: 5,
+		}, d.FinalizeBuildWithStatistics(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		}))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryFinalizeBuildWithRemovedChildren(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		removedChildren, err := d.FinalizeBuildWithRemovedChildren(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.RemovedChildren{}, removedChildren)
+	})
+
+	// startBuild starts a build against an output path containing
+	// a given number of files. Only the first file is present in
+	// the Content Addressable Storage.
+	startBuild := func(outputBaseID, buildID string, filesCount int) *mock.MockOutputPath {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+			gomock.Any(),
+		).Return(outputPath)
+		remover := mock.NewMockChildRemover(ctrl)
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			for i := 0; i < filesCount; i++ {
+				child := mock.NewMockNativeLeaf(ctrl)
+				child.EXPECT().GetContainingDigests().
+					Return(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, fmt.Sprintf("%032x", i), 1).ToSingletonSet())
+				require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+			}
+			return nil
+		})
+		f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+				present := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, fmt.Sprintf("%032x", 0), 1)
+				missing, _, _ := digest.GetDifferenceAndIntersection(digests, present.ToSingletonSet())
+				return missing, nil
+			}).
+			AnyTimes()
+		remover.EXPECT().Call().Times(filesCount - 1)
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		return outputPath
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// The file that is missing should be reported as being
+		// removed.
+		outputPath := startBuild("9da951b8cb759233037166e28f7ea186", "37f5dbef-b117-4fb6-bce8-5c147cb603b4", 2)
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_MD5))
+
+		removedChildren, err := d.FinalizeBuildWithRemovedChildren(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.RemovedChildren{
+			Digests: []digest.Digest{
+				digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "00000000000000000000000000000001", 1),
+			},
+		}, removedChildren)
+
+		// Finalizing the build once more should not cause the
+		// removed children to be reported again.
+		removedChildren, err = d.FinalizeBuildWithRemovedChildren(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.RemovedChildren{}, removedChildren)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		// Only a limited number of removed children is reported.
+		outputPath := startBuild("a1ed3d2bd6a3db4b1b2b3b5d8f2c5d54", "6fc4ee9c-5c4f-4dc6-a37f-49ba2a68a1c5", 10002)
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_MD5))
+
+		removedChildren, err := d.FinalizeBuildWithRemovedChildren(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "6fc4ee9c-5c4f-4dc6-a37f-49ba2a68a1c5",
+		})
+		require.NoError(t, err)
+		require.Len(t, removedChildren.Digests, 10000)
+		require.True(t, removedChildren.Truncated)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryWatchOutputPathRemovals(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+	})
+
+	// Start watching for removals of output paths. Wait for the
+	// subscription to be registered before removing anything.
+	watchCtx, cancel := context.WithCancel(ctx)
+	stream := mock.NewMockOutputPathRemovalEventStream(ctrl)
+	subscribed := make(chan struct{})
+	stream.EXPECT().Context().DoAndReturn(func() context.Context {
+		close(subscribed)
+		return watchCtx
+	})
+	events := make(chan *cd_vfs.OutputPathRemovalEvent, 1)
+	stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(event *cd_vfs.OutputPathRemovalEvent) error {
+		events <- event
+		return nil
+	})
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- d.WatchOutputPathRemovals(stream)
+	}()
+	<-subscribed
+
+	// Create an output path and clean it. This should cause an
+	// event to be sent.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	outputPath.EXPECT().RemoveAllChildren(true)
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"))
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &cd_vfs.OutputPathRemovalEvent{
+		OutputBaseID:   "9da951b8cb759233037166e28f7ea186",
+		Reason:         cd_vfs.OutputPathRemovalReasonClean,
+		LastAccessTime: time.Unix(1000, 0),
+	}, <-events)
+
+	// Cancelling the context should cause the stream to terminate.
+	cancel()
+	testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), <-watchErr)
+}
+
+func TestRemoteOutputServiceDirectoryPingBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.PingBuild(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("ActiveBuildID", func(t *testing.T) {
+		// Pinging the build should not have any side effects,
+		// meaning that it can be called repeatedly.
+		require.NoError(t, d.PingBuild(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+		require.NoError(t, d.PingBuild(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	})
+
+	t.Run("FinalizedBuildID", func(t *testing.T) {
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.PingBuild(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputBasePath(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		_, err := d.GetOutputBasePath("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	// The output path prefix provided to StartBuild() is not
+	// normalized. The path returned should be.
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob//bb_clientd/./outputs/",
+	})
+	require.NoError(t, err)
+
+	t.Run("ActiveBuildID", func(t *testing.T) {
+		outputBasePath, err := d.GetOutputBasePath("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		require.NoError(t, err)
+		require.Equal(t, "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186", outputBasePath)
+	})
+
+	t.Run("FinalizedBuildID", func(t *testing.T) {
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+
+		_, err = d.GetOutputBasePath("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryWatchBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		stream := mock.NewMockBuildEventStream(ctrl)
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.WatchBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", 0, stream))
+	})
+
+	// Start a build, where one of the files in the output path is
+	// removed, as it is absent from the Content Addressable Storage.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+	remover := mock.NewMockChildRemover(ctrl)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		child := mock.NewMockNativeLeaf(ctrl)
+		child.EXPECT().GetContainingDigests().Return(fileDigest.ToSingletonSet())
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		return nil
+	})
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), fileDigest.ToSingletonSet()).Return(fileDigest.ToSingletonSet(), nil)
+	remover.EXPECT().Call()
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		// When resuming after the last event, no events should
+		// be sent. The call should block until the client
+		// disconnects.
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		stream := mock.NewMockBuildEventStream(ctrl)
+		stream.EXPECT().Context().Return(canceledCtx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Canceled, "context canceled"),
+			d.WatchBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", 1, stream))
+	})
+
+	t.Run("SendFailure", func(t *testing.T) {
+		stream := mock.NewMockBuildEventStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		stream.EXPECT().Send(gomock.Any()).Return(status.Error(codes.Unavailable, "Connection reset by peer"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Failed to send event: Connection reset by peer"),
+			d.WatchBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", 0, stream))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The removal performed by StartBuild() should be
+		// reported, even though it happened before WatchBuild()
+		// was called. Finalizing the build should cause the
+		// stream to terminate.
+		stream := mock.NewMockBuildEventStream(ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+		stream.EXPECT().Send(&cd_vfs.BuildEvent{
+			ChangeID: 1,
+			Type:     cd_vfs.BuildEventChildRemoved,
+			Digest:   fileDigest,
+		}).DoAndReturn(func(event *cd_vfs.BuildEvent) error {
+			outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+			_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			})
+			require.NoError(t, err)
+			return nil
+		})
+
+		require.NoError(t, d.WatchBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", 0, stream))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryFindMissingRevalidation(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			RevalidationInterval: 10 * time.Minute,
+		},
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		_, err := d.GetMissingBlobs("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			err)
+	})
+
+	// Start a build, where the only file in the output path is
+	// still present in the Content Addressable Storage. This should
+	// cause the output path to be revalidated periodically.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+	remover := mock.NewMockChildRemover(ctrl)
+	filterChildren := func(childFilter re_vfs.ChildFilter) error {
+		child := mock.NewMockNativeLeaf(ctrl)
+		child.EXPECT().GetContainingDigests().Return(fileDigest.ToSingletonSet())
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		return nil
+	}
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(filterChildren)
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), fileDigest.ToSingletonSet()).Return(digest.EmptySet, nil)
+	timerWakeup := make(chan time.Time, 1)
+	timerCreated := make(chan struct{})
+	clock.EXPECT().NewTimer(10*time.Minute).
+		Do(func(duration time.Duration) { close(timerCreated) }).
+		Return(mock.NewMockTimer(ctrl), timerWakeup)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	<-timerCreated
+
+	missingBlobs, err := d.GetMissingBlobs("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+	require.NoError(t, err)
+	require.Empty(t, missingBlobs.Items())
+
+	// Let the file be evicted from the Content Addressable
+	// Storage while the build is running. The next pass should
+	// detect its absence. As RemoveChildrenMissingDuringBuild is
+	// not set, the file should be left in place.
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(filterChildren)
+	f.bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), fileDigest.ToSingletonSet()).Return(fileDigest.ToSingletonSet(), nil)
+	timer := mock.NewMockTimer(ctrl)
+	passCompleted := make(chan struct{})
+	clock.EXPECT().NewTimer(10*time.Minute).
+		Do(func(duration time.Duration) { close(passCompleted) }).
+		Return(timer, make(chan time.Time))
+	timerWakeup <- time.Unix(1600, 0)
+	<-passCompleted
+
+	missingBlobs, err = d.GetMissingBlobs("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+	require.NoError(t, err)
+	require.Equal(t, fileDigest.ToSingletonSet(), missingBlobs)
+
+	// The missing blob should also be reported through
+	// WatchBuild(). Finalizing the build should cause revalidation
+	// to stop.
+	stream := mock.NewMockBuildEventStream(ctrl)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Send(&cd_vfs.BuildEvent{
+		ChangeID: 1,
+		Type:     cd_vfs.BuildEventBlobMissing,
+		Digest:   fileDigest,
+	}).DoAndReturn(func(event *cd_vfs.BuildEvent) error {
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		return nil
+	})
+	revalidationStopped := make(chan struct{})
+	timer.EXPECT().Stop().DoAndReturn(func() bool {
+		close(revalidationStopped)
+		return true
+	})
+
+	require.NoError(t, d.WatchBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", 0, stream))
+	<-revalidationStopped
+}
+
+func TestRemoteOutputServiceDirectoryFindMissingRevalidationInterrupted(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+		FindMissing: cd_vfs.FindMissingConfiguration{
+			RevalidationInterval: 10 * time.Minute,
+		},
+	})
+
+	// Start a build against an empty output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+	timerWakeup := make(chan time.Time, 1)
+	timerCreated := make(chan struct{})
+	clock.EXPECT().NewTimer(10*time.Minute).
+		Do(func(duration time.Duration) { close(timerCreated) }).
+		Return(mock.NewMockTimer(ctrl), timerWakeup)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	<-timerCreated
+
+	// Finalize the build while a revalidation pass is running.
+	// This should cause the pass to stop traversing the output
+	// path. Directories should not be expanded, and no further
+	// passes should be scheduled.
+	passCompleted := make(chan struct{})
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		defer close(passCompleted)
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+
+		child := mock.NewMockInitialContentsFetcher(ctrl)
+		require.False(t, childFilter(re_vfs.InitialNode{}.FromDirectory(child), mock.NewMockChildRemover(ctrl).Call))
+		return nil
+	})
+	timerWakeup <- time.Unix(1600, 0)
+	<-passCompleted
+}
+
+func TestRemoteOutputServiceDirectoryGetBuildErrors(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.GetBuildErrors("3cbaf0bb-8a6e-4b09-8b73-b0f1a6f2a8f7")
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Capture the error logger that is provided to the output path.
+	// The output path uses it to report errors that occur
+	// asynchronously, such as failures to read files from the CAS.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	var errorLogger util.ErrorLogger
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).DoAndReturn(func(outputBaseID path.Component, casFileFactory re_vfs.CASFileFactory, digestFunction digest.Function, el util.ErrorLogger) cd_vfs.OutputPath {
+		errorLogger = el
+		return outputPath
+	})
+	outputPath.EXPECT().FilterChildren(gomock.Any()).Times(2)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "e4b5a8c9-23f0-4e0c-9a3a-5a3e1e4f5d61",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Initially, no errors should be reported.
+	errs, err := d.GetBuildErrors("e4b5a8c9-23f0-4e0c-9a3a-5a3e1e4f5d61")
+	require.NoError(t, err)
+	require.Empty(t, errs)
+
+	// Errors reported by the output path should be returned.
+	errorLogger.Log(status.Error(codes.Internal, "Failed to read from 3e25960a79dbc69b674cd4ec67a72c62-11: Disk on fire"))
+	errs, err = d.GetBuildErrors("e4b5a8c9-23f0-4e0c-9a3a-5a3e1e4f5d61")
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to read from 3e25960a79dbc69b674cd4ec67a72c62-11: Disk on fire"), errs[0])
+
+	// Errors should not be carried over to successive builds.
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "9b7c0a35-e4b6-4f09-a5c7-3f7b8c4d2e10",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	errs, err = d.GetBuildErrors("9b7c0a35-e4b6-4f09-a5c7-3f7b8c4d2e10")
+	require.NoError(t, err)
+	require.Empty(t, errs)
+}
+
+func TestRemoteOutputServiceDirectoryCheckpoint(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("NoOutputPaths", func(t *testing.T) {
+		outputPathsCount, err := d.Checkpoint(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, outputPathsCount)
+	})
+
+	// Create two output paths. Let a build still be running
+	// against the second one.
+	var outputPaths []*mock.MockOutputPath
+	for i, outputBaseID := range []string{"9da951b8cb759233037166e28f7ea186", "f5c9bbf6d0aab8d2b1e7d7e2dd0b3c41"} {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		outputPaths = append(outputPaths, outputPath)
+
+		buildID := fmt.Sprintf("37f5dbef-b117-4fb6-bce8-5c147cb603b%d", i)
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		if i == 0 {
+			outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+			_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+				BuildId: buildID,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("Failure", func(t *testing.T) {
+		outputPaths[0].EXPECT().Checkpoint()
+		outputPaths[1].EXPECT().Checkpoint().Return(status.Error(codes.Internal, "Disk failure"))
+
+		outputPathsCount, err := d.Checkpoint(ctx)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to checkpoint output path \"f5c9bbf6d0aab8d2b1e7d7e2dd0b3c41\": Disk failure"), err)
+		require.Equal(t, 1, outputPathsCount)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// All output paths should be checkpointed, regardless
+		// of whether builds are running against them.
+		outputPaths[0].EXPECT().Checkpoint()
+		outputPaths[1].EXPECT().Checkpoint()
+
+		outputPathsCount, err := d.Checkpoint(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, outputPathsCount)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryShutdown(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("Timeout", func(t *testing.T) {
+		// If the build is not finalized before the context is
+		// canceled, Shutdown() should fail. The state of the
+		// output path should still be persisted.
+		outputPath.EXPECT().Checkpoint()
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Canceled, "1 builds were not finalized: context canceled"),
+			d.Shutdown(canceledCtx))
+	})
+
+	t.Run("StartBuildRejected", func(t *testing.T) {
+		// Once shutdown has been initiated, no new builds may
+		// be started.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "a0f5d0b8-6ab5-4ec3-a0e1-8e6e4a2ba3b1",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Remote Output Service is shutting down"), err)
+	})
+
+	t.Run("Finalized", func(t *testing.T) {
+		// The build that is already running should still be
+		// permitted to complete. Shutdown() should only return
+		// once it has been finalized.
+		shutdownErr := make(chan error, 1)
+		go func() {
+			shutdownErr <- d.Shutdown(ctx)
+		}()
+
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+		outputPath.EXPECT().Checkpoint()
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		require.NoError(t, <-shutdownErr)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryTracing(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	spanRecorder := tracetest.NewSpanRecorder()
+	d := cd_vfs.NewRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		cd_vfs.ContentAddressableStorageRoles{
+			FileReads:   retryingContentAddressableStorage,
+			TreeReads:   bareContentAddressableStorage,
+			FindMissing: bareContentAddressableStorage,
+			Uploads:     bareContentAddressableStorage,
+		},
+		directoryFetcher,
+		symlinkFactory,
+		sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)),
+		&cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+			MaximumTreeSizeBytes: 10000,
+		})
+
+	// Start a build against an output path containing a single
+	// file, which is present in the CAS.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		child := mock.NewMockNativeLeaf(ctrl)
+		child.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "a32ea15346cf1848ab49e0913ff07531", 3).ToSingletonSet())
+		remover := mock.NewMockChildRemover(ctrl)
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		return nil
+	})
+	bareContentAddressableStorage.EXPECT().FindMissing(
+		gomock.Any(),
+		digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "a32ea15346cf1848ab49e0913ff07531", 3).ToSingletonSet(),
+	).Return(digest.EmptySet, nil)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Call BatchStat() with an unknown build ID, which should
+	// cause the span to be marked as failed.
+	_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+		BuildId: "0b1b3a52-5e9c-4b0f-9a0e-4b5ea4b6e9b8",
+		Paths:   []string{"foo"},
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5))
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 4)
+
+	require.Equal(t, "RemoteOutputServiceDirectory.findMissingAndRemove", spans[0].Name())
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int("digests_count", 1),
+		attribute.Int("missing_digests_count", 0),
+	}, spans[0].Attributes())
+
+	require.Equal(t, "RemoteOutputServiceDirectory.StartBuild", spans[1].Name())
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("build_id", "37f5dbef-b117-4fb6-bce8-5c147cb603b4"),
+		attribute.String("output_base_id", "9da951b8cb759233037166e28f7ea186"),
+		attribute.String("instance_name", "my-cluster"),
+		attribute.String("digest_function", "MD5"),
+		attribute.Bool("asynchronous", false),
+	}, spans[1].Attributes())
+	require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	require.Equal(t, "RemoteOutputServiceDirectory.BatchStat", spans[2].Name())
+	require.Equal(t, otelcodes.Error, spans[2].Status().Code)
+
+	require.Equal(t, "RemoteOutputServiceDirectory.FinalizeBuild", spans[3].Name())
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("build_id", "37f5dbef-b117-4fb6-bce8-5c147cb603b4"),
+	}, spans[3].Attributes())
+}
+
+func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+			Paths:   []string{"foo.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+	}, response)
+
+	t.Run("Noop", func(t *testing.T) {
+		// Requests that don't contain any paths shouldn't cause
+		// any I/O against the output path.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OnDirectoryLookupFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"stdio/printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnDirectoryReadlinkFailure", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"stdio/printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnTerminalLookupFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnTerminalReadlinkFailure", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			FollowSymlinks: true,
+			Paths:          []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnTerminalGetOutputServiceFileStatusFailure", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(nil, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Lookup of "file", pointing to directly to a file.
+		leaf1 := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf1), nil)
+		leaf1.EXPECT().Readlink().Return("", syscall.EINVAL)
+		leaf1.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "ad17450bb18953f249532a478d2150ba",
+						SizeBytes: 72,
+					},
+				},
+			},
+		}, nil)
+
+		// Lookup of "directory". pointing directly to a directory.
+		directory1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory1), nil)
+		directory1.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+
+		// Lookup of "nested/symlink_internal_relative_file",
+		// being a symlink that points to a file.
+		directory2 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory2), nil)
+		leaf2 := mock.NewMockNativeLeaf(ctrl)
+		directory2.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf2), nil)
+		leaf2.EXPECT().Readlink().Return("../target", nil)
+		leaf3 := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("target")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf3), nil)
+		leaf3.EXPECT().Readlink().Return("", syscall.EINVAL)
+		leaf3.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "166d6efee3489f73be1c3c2304e50bca",
+						SizeBytes: 85,
+					},
+				},
+			},
+		}, nil)
+
+		// Lookup of "nested/symlink_internal_relative_directory",
+		// being a symlink that points to a directory.
+		directory3 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory3), nil)
+		leaf4 := mock.NewMockNativeLeaf(ctrl)
+		directory3.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf4), nil)
+		leaf4.EXPECT().Readlink().Return("..", nil)
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1001, 0))
+			})
+
+		// Lookup of "nested/symlink_internal_absolute_path",
+		// being a symlink containing an absolute path starting
+		// with the output path.
+		directory4 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory4), nil)
+		leaf5 := mock.NewMockNativeLeaf(ctrl)
+		directory4.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_path")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf5), nil)
+		leaf5.EXPECT().Readlink().Return("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/hello", nil)
+		directory5 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory5), nil)
+		directory5.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1002, 0))
+			})
+
+		// Lookup of "nested/symlink_internal_absolute_alias",
+		// being a symlink containing an absolute path starting
+		// with one of the output path aliases.
+		directory6 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory6), nil)
+		leaf6 := mock.NewMockNativeLeaf(ctrl)
+		directory6.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_alias")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf6), nil)
+		leaf6.EXPECT().Readlink().Return("/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out/hello", nil)
+		directory7 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory7), nil)
+		directory7.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1003, 0))
+			})
+
+		// Lookup of "nested/symlink_external", being a symlink
+		// containing an absolute path that doesn't start with
+		// any known prefix.
+		directory8 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory8), nil)
+		leaf7 := mock.NewMockNativeLeaf(ctrl)
+		directory8.EXPECT().LookupChild(path.MustNewComponent("symlink_external")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf7), nil)
+		leaf7.EXPECT().Readlink().Return("/etc/passwd", nil)
+
+		// Lookup of "nonexistent".
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		// Lookup of ".".
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1004, 0))
+			})
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludeFileDigest: true,
+			FollowSymlinks:    true,
+			Paths: []string{
+				"file",
+				"directory",
+				"nested/symlink_internal_relative_file",
+				"nested/symlink_internal_relative_directory",
+				"nested/symlink_internal_absolute_path",
+				"nested/symlink_internal_absolute_alias",
+				"nested/symlink_external",
+				"../foo",
+				"/etc/passwd",
+				"nonexistent",
+				".",
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				// "file".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: &remoteexecution.Digest{
+									Hash:      "ad17450bb18953f249532a478d2150ba",
+									SizeBytes: 72,
+								},
+							},
+						},
+					},
+				},
+				// "directory".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1000},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_relative_file".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: &remoteexecution.Digest{
+									Hash:      "166d6efee3489f73be1c3c2304e50bca",
+									SizeBytes: 85,
+								},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_relative_directory".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1001},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_absolute_path".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1002},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_absolute_alias".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1003},
+							},
+						},
+					},
+				},
+				// "nested/symlink_external".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "/etc/passwd",
+							},
+						},
+					},
+				},
+				// "../foo".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "../foo",
+							},
+						},
+					},
+				},
+				// "/etc/passwd".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "/etc/passwd",
+							},
+						},
+					},
+				},
+				// "nonexistent".
+				{},
+				// ".".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1004},
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("SuccessWithoutFollowingSymlinks", func(t *testing.T) {
+		// Lookup of "symlink_internal", being a symlink that
+		// points to a file inside the output path.
+		leaf1 := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("symlink_internal")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf1), nil)
+		leaf1.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_Symlink_{
+				Symlink: &remoteoutputservice.FileStatus_Symlink{
+					Target: "nested/target",
+				},
+			},
+		}, nil)
+
+		// Lookup of "symlink_external", being a symlink that
+		// points to a file outside the output path.
+		leaf2 := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("symlink_external")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf2), nil)
+		leaf2.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_Symlink_{
+				Symlink: &remoteoutputservice.FileStatus_Symlink{
+					Target: "/etc/passwd",
+				},
+			},
+		}, nil)
+
+		// Symbolic links should be returned as is, without
+		// resolving their targets.
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths: []string{
+				"symlink_internal",
+				"symlink_external",
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				// "symlink_internal".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Symlink_{
+							Symlink: &remoteoutputservice.FileStatus_Symlink{
+								Target: "nested/target",
+							},
+						},
+					},
+				},
+				// "symlink_external".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Symlink_{
+							Symlink: &remoteoutputservice.FileStatus_Symlink{
+								Target: "/etc/passwd",
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatMaximumSymlinkFollows(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumSymlinkFollowsPerPath: 3,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Let the output path contain a chain of symbolic links, where
+	// "link0" points to "link1", and so on. The final symbolic link
+	// "link3" points to a regular file.
+	expectSymlinkChain := func(first int) {
+		for i := first; i < 4; i++ {
+			symlink := mock.NewMockNativeLeaf(ctrl)
+			outputPath.EXPECT().LookupChild(path.MustNewComponent(fmt.Sprintf("link%d", i))).
+				Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlink), nil)
+			target := fmt.Sprintf("link%d", i+1)
+			if i == 3 {
+				target = "file"
+			}
+			symlink.EXPECT().Readlink().Return(target, nil)
+		}
+	}
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		// Resolving "link1" requires three symbolic links to
+		// be followed, which is permitted.
+		expectSymlinkChain(1)
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			FollowSymlinks: true,
+			Paths:          []string{"link1"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("ExceedingLimit", func(t *testing.T) {
+		// Resolving "link0" requires four symbolic links to be
+		// followed, which should be rejected, even though the
+		// chain does not contain any cycles.
+		expectSymlinkChain(0)
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			FollowSymlinks: true,
+			Paths:          []string{"link0"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to resolve path \"link0\" beyond \".\": Path resolution requires more than 3 symbolic links to be followed"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryMaximumPathDepth(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Limits: cd_vfs.RemoteOutputServiceLimitsConfiguration{
+			MaximumPathDepth: 3,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("BatchCreateAtLimit", func(t *testing.T) {
+		// The components of the path prefix count towards the
+		// depth of the path.
+		directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryA.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).Return(directoryB, nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(leaf)
+		directoryB.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("c"): re_vfs.InitialNode{}.FromLeaf(leaf),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "a",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "b/c",
+					Target: "target",
+				},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("BatchCreateBeyondLimit", func(t *testing.T) {
+		// Resolution should stop before creating directories
+		// beyond the maximum depth.
+		directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryA.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).Return(directoryB, nil)
+		directoryC := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryB.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("c")).Return(directoryC, nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(leaf)
+		leaf.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "a",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "b/c/d",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create symbolic link \"b/c/d\": Failed to resolve path: Path contains more than 3 components"), err)
+	})
+
+	t.Run("BatchCreatePathPrefixBeyondLimit", func(t *testing.T) {
+		directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryA.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).Return(directoryB, nil)
+		directoryC := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryB.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("c")).Return(directoryC, nil)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "a/b/c/d",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "e",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path contains more than 3 components"), err)
+	})
+
+	t.Run("BatchStatAtLimit", func(t *testing.T) {
+		directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryA), nil)
+		directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryA.EXPECT().LookupChild(path.MustNewComponent("b")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryB), nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		directoryB.EXPECT().LookupChild(path.MustNewComponent("c")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		fileStatus := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}
+		leaf.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(fileStatus, nil)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"a/b/c"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{FileStatus: fileStatus},
+			},
+		}, response)
+	})
+
+	t.Run("BatchStatBeyondLimit", func(t *testing.T) {
+		directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryA), nil)
+		directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryA.EXPECT().LookupChild(path.MustNewComponent("b")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryB), nil)
+		directoryC := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryB.EXPECT().LookupChild(path.MustNewComponent("c")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryC), nil)
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"a/b/c/d"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"a/b/c/d\" beyond \"a/b/c\": Path contains more than 3 components"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatWithDigestInclusionMode(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	digestFunction := digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5)
+	request := &remoteoutputservice.BatchStatRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Paths:   []string{"file"},
+	}
+
+	t.Run("IfKnownBackedByCAS", func(t *testing.T) {
+		// Files backed by the Content Addressable Storage know
+		// their digest, meaning it can be returned without
+		// any additional cost.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5).ToSingletonSet())
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+
+		response, err := d.BatchStatWithDigestInclusionMode(ctx, request, cd_vfs.DigestInclusionModeIfKnown)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: &remoteexecution.Digest{
+									Hash:      "8b1a9953c4611296a827abf8c47804d7",
+									SizeBytes: 5,
+								},
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("IfKnownWrittenLocally", func(t *testing.T) {
+		// Files that were written locally would need to be
+		// hashed to obtain their digest. It should be omitted.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		response, err := d.BatchStatWithDigestInclusionMode(ctx, request, cd_vfs.DigestInclusionModeIfKnown)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("Always", func(t *testing.T) {
+		// When digests are always requested, they should be
+		// computed for locally written files as well.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+
+		_, err := d.BatchStatWithDigestInclusionMode(ctx, request, cd_vfs.DigestInclusionModeAlways)
+		require.NoError(t, err)
+	})
+
+	t.Run("AlwaysVerifiedSuccess", func(t *testing.T) {
+		// When verification is requested, the contents of files
+		// backed by the Content Addressable Storage should be
+		// downloaded and hashed.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		fileDigest := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: fileDigest.GetProto(),
+				},
+			},
+		}, nil)
+		file.EXPECT().GetContainingDigests().Return(fileDigest.ToSingletonSet())
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		_, err := d.BatchStatWithDigestInclusionMode(ctx, request, cd_vfs.DigestInclusionModeAlwaysVerified)
+		require.NoError(t, err)
+	})
+
+	t.Run("AlwaysVerifiedWrittenLocally", func(t *testing.T) {
+		// Files that were written locally have their digest
+		// computed from their actual contents. There is no
+		// need to verify them.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet)
+
+		_, err := d.BatchStatWithDigestInclusionMode(ctx, request, cd_vfs.DigestInclusionModeAlwaysVerified)
+		require.NoError(t, err)
+	})
+
+	t.Run("AlwaysVerifiedMismatch", func(t *testing.T) {
+		// If the Content Addressable Storage returns data that
+		// does not correspond to the digest of the file, the
+		// Content Addressable Storage is corrupted.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		fileDigest := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: fileDigest.GetProto(),
+				},
+			},
+		}, nil)
+		file.EXPECT().GetContainingDigests().Return(fileDigest.ToSingletonSet())
+		f.retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Jello")))
+
+		_, err := d.BatchStatWithDigestInclusionMode(ctx, request, cd_vfs.DigestInclusionModeAlwaysVerified)
+		expectedStatus, detailsErr := status.New(codes.DataLoss, "Failed to resolve path \"file\" beyond \".\": Contents of file have digest \"3-bedad9eef4de4b391cc5aeb8ddbe6387-5-my-cluster\", while \"3-8b1a9953c4611296a827abf8c47804d7-5-my-cluster\" was expected").
+			WithDetails(&errdetails.ErrorInfo{
+				Reason: "REMOTE_DATA_LOSS",
+				Domain: "github.com/buildbarn/bb-clientd",
+			})
+		require.NoError(t, detailsErr)
+		testutil.RequireEqualStatus(t, expectedStatus.Err(), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatPartial(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		// Loading the second directory takes longer than the
+		// deadline of the request. Instead of failing, the
+		// response of the first path should be returned.
+		ctxWithDeadline, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("slow")).
+			DoAndReturn(func(name path.Component) (re_vfs.PrepopulatedDirectoryChild, error) {
+				<-ctxWithDeadline.Done()
+				return re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.DeadlineExceeded, "Failed to load directory contents from the Content Addressable Storage")
+			})
+
+		response, unprocessedCount, err := d.BatchStatPartial(ctxWithDeadline, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"nonexistent", "slow/file", "other"},
+		}, cd_vfs.DigestInclusionModeNever)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}},
+		}, response)
+		require.Equal(t, 2, unprocessedCount)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		// The client may resume by resubmitting the paths that
+		// were not processed.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("slow")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("other")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, unprocessedCount, err := d.BatchStatPartial(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"slow/file", "other"},
+		}, cd_vfs.DigestInclusionModeNever)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}, {}},
+		}, response)
+		require.Equal(t, 0, unprocessedCount)
+	})
+
+	t.Run("OtherError", func(t *testing.T) {
+		// Errors that occur while the context is not done
+		// should still cause the request to fail.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("broken")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk on fire"))
+
+		_, _, err := d.BatchStatPartial(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"broken/file"},
+		}, cd_vfs.DigestInclusionModeNever)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"broken/file\" beyond \".\": Disk on fire"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryResolveSymlink(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	// Start builds against two separate output bases. Symbolic
+	// links in one output path may point into the other.
+	outputBaseIDs := []string{"9da951b8cb759233037166e28f7ea186", "c6adef0d5ca1888a4aa847fb51229a8c"}
+	buildIDs := []string{"37f5dbef-b117-4fb6-bce8-5c147cb603b4", "ad778a53-48e6-4ae1-b1f5-01b84a508f5f"}
+	var outputPaths []*mock.MockOutputPath
+	for i, outputBaseID := range outputBaseIDs {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildIDs[i],
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		outputPaths = append(outputPaths, outputPath)
+	}
+
+	newSymlink := func(target string) re_vfs.PrepopulatedDirectoryChild {
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		symlink.EXPECT().Readlink().Return(target, nil)
+		return re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlink)
+	}
+	fileStatus := &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_File_{
+			File: &remoteoutputservice.FileStatus_File{},
+		},
+	}
+	newFile := func() re_vfs.PrepopulatedDirectoryChild {
+		file := mock.NewMockNativeLeaf(ctrl)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(fileStatus, nil)
+		return re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file)
+	}
+
+	t.Run("RelativePath", func(t *testing.T) {
+		_, err := d.ResolveSymlink(ctx, buildIDs[0], "link")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"link\" is not an absolute path"), err)
+	})
+
+	t.Run("IntraOutputBase", func(t *testing.T) {
+		// Chains of symbolic links that remain within the same
+		// output path should be followed.
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("link1")).Return(newSymlink("link2"), nil)
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("link2")).Return(newSymlink("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/dir/file"), nil)
+		dir := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(dir), nil)
+		dir.EXPECT().LookupChild(path.MustNewComponent("file")).Return(newFile(), nil)
+
+		response, err := d.ResolveSymlink(ctx, buildIDs[0], "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/link1")
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StatResponse{
+			FileStatus: fileStatus,
+		}, response)
+	})
+
+	t.Run("CrossOutputBase", func(t *testing.T) {
+		// Symbolic links pointing into the output path of
+		// another output base should be followed as well.
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("cross")).Return(newSymlink("/home/bob/bb_clientd/outputs/c6adef0d5ca1888a4aa847fb51229a8c/dir/link"), nil)
+		dir := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPaths[1].EXPECT().LookupChild(path.MustNewComponent("dir")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(dir), nil).Times(2)
+		dir.EXPECT().LookupChild(path.MustNewComponent("link")).Return(newSymlink("/home/bob/bb_clientd/outputs/c6adef0d5ca1888a4aa847fb51229a8c/dir/file"), nil)
+		dir.EXPECT().LookupChild(path.MustNewComponent("file")).Return(newFile(), nil)
+
+		response, err := d.ResolveSymlink(ctx, buildIDs[0], "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/cross")
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StatResponse{
+			FileStatus: fileStatus,
+		}, response)
+	})
+
+	t.Run("External", func(t *testing.T) {
+		// Paths resolving to locations outside of any output
+		// path should be returned to the client.
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("cross")).Return(newSymlink("/home/bob/bb_clientd/outputs/c6adef0d5ca1888a4aa847fb51229a8c/external"), nil)
+		outputPaths[1].EXPECT().LookupChild(path.MustNewComponent("external")).Return(newSymlink("/etc/passwd"), nil)
+
+		response, err := d.ResolveSymlink(ctx, buildIDs[0], "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/cross")
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StatResponse{
+			FileStatus: &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_External_{
+					External: &remoteoutputservice.FileStatus_External{
+						NextPath: "/etc/passwd",
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("Nonexistent", func(t *testing.T) {
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("cross")).Return(newSymlink("/home/bob/bb_clientd/outputs/c6adef0d5ca1888a4aa847fb51229a8c/nonexistent"), nil)
+		outputPaths[1].EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.ResolveSymlink(ctx, buildIDs[0], "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/cross")
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StatResponse{}, response)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		// Cycles spanning multiple output paths cannot be
+		// detected while resolving a single output path.
+		outputPaths[0].EXPECT().LookupChild(path.MustNewComponent("loop")).Return(newSymlink("/home/bob/bb_clientd/outputs/c6adef0d5ca1888a4aa847fb51229a8c/loop"), nil)
+		outputPaths[1].EXPECT().LookupChild(path.MustNewComponent("loop")).Return(newSymlink("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/loop"), nil)
+
+		_, err := d.ResolveSymlink(ctx, buildIDs[0], "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/loop")
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Path \"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/loop\" resolves to a cycle of symbolic links spanning multiple output paths"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatExistence(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		TrackLazyDirectories: true,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Create a directory whose contents are loaded lazily. As the
+	// mock directory has no expectations on it, any attempt to
+	// look up its children causes the test to fail.
+	lazyDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+	outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+	outputPath.EXPECT().LookupChild(path.MustNewComponent("lazy")).
+		Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(lazyDirectory), nil)
+
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Directories: []*remoteexecution.OutputDirectory{
+			{
+				Path: "lazy",
+				TreeDigest: &remoteexecution.Digest{
+					Hash:      "5e2d3e9d2a0ef8f9e7d2e6b6ae6a3d4b42cc6b4e0c8c5f2a4b9d6c3e1f0a7b8c",
+					SizeBytes: 123,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("Disabled", func(t *testing.T) {
+		dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(dHandleAllocation)
+		dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(mock.NewMockStatefulDirectoryHandle(ctrl))
+		d := cd_vfs.NewRemoteOutputServiceDirectory(
+			f.handleAllocator,
+			f.outputPathFactory,
+			cd_vfs.ContentAddressableStorageRoles{},
+			f.directoryFetcher,
+			f.symlinkFactory,
+			trace.NewNoopTracerProvider(),
+			&cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+				MaximumTreeSizeBytes: 10000,
+			})
+
+		_, err := d.BatchStatExistence(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"lazy"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Tracking of lazy directories is not enabled"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The lazy directory itself can be reported as being
+		// present, as that doesn't require loading its
+		// contents. Paths inside of it can't be resolved
+		// without fetching, and are thus reported as unknown.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("lazy")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(lazyDirectory), nil).
+			Times(3)
+
+		// Directories that are not loaded lazily can be
+		// traversed as usual.
+		materializedDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("bazel-out")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(materializedDirectory), nil).
+			Times(2)
+		materializedDirectory.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		file := mock.NewMockNativeLeaf(ctrl)
+		materializedDirectory.EXPECT().LookupChild(path.MustNewComponent("file.o")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		results, err := d.BatchStatExistence(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths: []string{
+				"lazy",
+				"lazy/file.o",
+				"lazy/subdirectory/file.o",
+				"bazel-out/nonexistent",
+				"bazel-out/file.o",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []cd_vfs.PathExistence{
+			cd_vfs.PathExistencePresent,
+			cd_vfs.PathExistenceUnknown,
+			cd_vfs.PathExistenceUnknown,
+			cd_vfs.PathExistenceAbsent,
+			cd_vfs.PathExistencePresent,
+		}, results)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatParentIsFile(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("TopLevel", func(t *testing.T) {
+		// Paths that have a regular file in place of one of
+		// their parent directories don't exist. This should be
+		// reported in the same way as nonexistent paths.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"file/child"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}},
+		}, response)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		// The same should hold for files that are located
+		// further down the path. Other paths in the same
+		// request should not be affected.
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(subDirectory), nil).
+			Times(2)
+		file := mock.NewMockNativeLeaf(ctrl)
+		subDirectory.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil).
+			Times(2)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"dir/file/a/b", "dir/file"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{},
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatWithMissingPathDetails(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	newMissingPathError := func(message, reason, statPath, resolvedPath, missingComponent string) error {
+		s, err := status.New(codes.NotFound, message).WithDetails(&errdetails.ErrorInfo{
+			Reason: reason,
+			Domain: "github.com/buildbarn/bb-clientd",
+			Metadata: map[string]string{
+				"path":              statPath,
+				"resolved_path":     resolvedPath,
+				"missing_component": missingComponent,
+			},
+		})
+		require.NoError(t, err)
+		return s.Err()
+	}
+
+	t.Run("MissingLeaf", func(t *testing.T) {
+		// The parent directory exists, but the final component
+		// of the path does not.
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(subDirectory), nil)
+		subDirectory.EXPECT().LookupChild(path.MustNewComponent("missing")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, missingPathErrors, err := d.BatchStatWithMissingPathDetails(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"dir/missing"},
+		}, cd_vfs.DigestInclusionModeNever)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}},
+		}, response)
+		require.Len(t, missingPathErrors, 1)
+		testutil.RequireEqualStatus(
+			t,
+			newMissingPathError(
+				"Path \"dir/missing\" does not exist, as \"missing\" is not present in directory \"dir\"",
+				"LEAF_NOT_FOUND",
+				"dir/missing",
+				"dir",
+				"missing"),
+			missingPathErrors[0])
+	})
+
+	t.Run("MissingIntermediateDirectory", func(t *testing.T) {
+		// One of the parent directories of the path does not
+		// exist.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, missingPathErrors, err := d.BatchStatWithMissingPathDetails(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"nonexistent/file"},
+		}, cd_vfs.DigestInclusionModeNever)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}},
+		}, response)
+		require.Len(t, missingPathErrors, 1)
+		testutil.RequireEqualStatus(
+			t,
+			newMissingPathError(
+				"Path \"nonexistent/file\" does not exist, as parent directory \"nonexistent\" is not present in directory \".\"",
+				"PARENT_NOT_FOUND",
+				"nonexistent/file",
+				".",
+				"nonexistent"),
+			missingPathErrors[0])
+	})
+
+	t.Run("FileInPath", func(t *testing.T) {
+		// A regular file is present in place of one of the
+		// parent directories of the path. Entries for paths
+		// that exist should be left unset.
+		subDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("dir")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(subDirectory), nil).
+			Times(2)
+		file := mock.NewMockNativeLeaf(ctrl)
+		subDirectory.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil).
+			Times(2)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		response, missingPathErrors, err := d.BatchStatWithMissingPathDetails(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"dir/file/child", "dir/file"},
+		}, cd_vfs.DigestInclusionModeNever)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{},
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{},
+						},
+					},
+				},
+			},
+		}, response)
+		require.Len(t, missingPathErrors, 2)
+		testutil.RequireEqualStatus(
+			t,
+			newMissingPathError(
+				"Path \"dir/file/child\" does not exist, as \"file\" in directory \"dir\" is not a directory",
+				"PARENT_NOT_DIRECTORY",
+				"dir/file/child",
+				"dir",
+				"file"),
+			missingPathErrors[0])
+		require.NoError(t, missingPathErrors[1])
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatFileDigestCache(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+			FileDigestCacheSize: 1,
+		},
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Let the output path contain two files that were written
+	// locally, meaning their digests need to be computed.
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_MD5)
+	files := map[string]*mock.MockNativeLeaf{}
+	for _, name := range []string{"file1", "file2"} {
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent(name)).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil).
+			AnyTimes()
+		file.EXPECT().GetContainingDigests().Return(digest.EmptySet).AnyTimes()
+		files[name] = file
+	}
+	expectChangeID := func(file *mock.MockNativeLeaf, changeID uint64, times int) {
+		file.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskChangeID, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetChangeID(changeID)
+			}).
+			Times(times)
+	}
+	expectComputedDigest := func(file *mock.MockNativeLeaf, hash string) {
+		file.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      hash,
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+	}
+	expectCachedDigest := func(file *mock.MockNativeLeaf) {
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+	}
+	requireDigest := func(t *testing.T, name, hash string) {
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludeFileDigest: true,
+			Paths:             []string{name},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: &remoteexecution.Digest{
+									Hash:      hash,
+									SizeBytes: 5,
 								},
 							},
 						},
 					},
 				},
-				// "nested/symlink_internal_relative_directory".
+			},
+		}, response)
+	}
+
+	t.Run("Miss", func(t *testing.T) {
+		// The first time the file is requested, its digest
+		// needs to be computed.
+		expectChangeID(files["file1"], 1, 2)
+		expectComputedDigest(files["file1"], "8b1a9953c4611296a827abf8c47804d7")
+
+		requireDigest(t, "file1", "8b1a9953c4611296a827abf8c47804d7")
+	})
+
+	t.Run("Hit", func(t *testing.T) {
+		// Successive requests should use the cached digest, as
+		// the file has not been modified.
+		expectChangeID(files["file1"], 1, 1)
+		expectCachedDigest(files["file1"])
+
+		requireDigest(t, "file1", "8b1a9953c4611296a827abf8c47804d7")
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		// Modifying the file causes its change ID to be
+		// incremented, meaning the cached digest may no longer
+		// be used.
+		expectChangeID(files["file1"], 2, 2)
+		expectComputedDigest(files["file1"], "5d41402abc4b2a76b9719d911017c592")
+
+		requireDigest(t, "file1", "5d41402abc4b2a76b9719d911017c592")
+	})
+
+	t.Run("ModifiedWhileComputing", func(t *testing.T) {
+		// If the file is modified while its digest is being
+		// computed, the digest should not be cached, as it
+		// may not correspond to the change ID.
+		expectChangeID(files["file2"], 1, 1)
+		expectComputedDigest(files["file2"], "7d793037a0760186574b0282f2f435e7")
+		expectChangeID(files["file2"], 2, 1)
+
+		requireDigest(t, "file2", "7d793037a0760186574b0282f2f435e7")
+
+		expectChangeID(files["file2"], 2, 2)
+		expectComputedDigest(files["file2"], "7d793037a0760186574b0282f2f435e7")
+
+		requireDigest(t, "file2", "7d793037a0760186574b0282f2f435e7")
+	})
+
+	t.Run("Evicted", func(t *testing.T) {
+		// The cache can only hold a single entry. Caching the
+		// digest of the second file should have caused the
+		// digest of the first file to be evicted.
+		expectChangeID(files["file1"], 2, 2)
+		expectComputedDigest(files["file1"], "5d41402abc4b2a76b9719d911017c592")
+
+		requireDigest(t, "file1", "5d41402abc4b2a76b9719d911017c592")
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatCacheDirectoryAttributes(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+			CacheDirectoryAttributes: true,
+		},
+	})
+
+	// Start a build.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	directory := mock.NewMockPrepopulatedDirectory(ctrl)
+	statDirectory := func(t *testing.T, expectedLastModifiedTime time.Time) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"directory"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
 				{
 					FileStatus: &remoteoutputservice.FileStatus{
 						FileType: &remoteoutputservice.FileStatus_Directory_{
 							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1001},
+								LastModifiedTime: timestamppb.New(expectedLastModifiedTime),
 							},
 						},
 					},
 				},
-				// "nested/symlink_internal_absolute_path".
-				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1002},
-							},
+			},
+		}, response)
+	}
+
+	t.Run("RepeatedStat", func(t *testing.T) {
+		// Only the first call to BatchStat() should cause the
+		// attributes of the directory to be obtained.
+		directory.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+
+		statDirectory(t, time.Unix(1000, 0))
+		statDirectory(t, time.Unix(1000, 0))
+	})
+
+	t.Run("InvalidationByBatchCreate", func(t *testing.T) {
+		// Creating a symbolic link inside the directory changes
+		// its modification time. This should cause the cached
+		// value to be discarded.
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory")).Return(directory, nil)
+		directory.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "directory/symlink",
+					Target: "target",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		directory.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1001, 0))
+			})
+
+		statDirectory(t, time.Unix(1001, 0))
+		statDirectory(t, time.Unix(1001, 0))
+	})
+}
+
+func BenchmarkRemoteOutputServiceDirectoryBatchStatDirectories(b *testing.B) {
+	for _, cacheDirectoryAttributes := range []bool{false, true} {
+		b.Run(fmt.Sprintf("CacheDirectoryAttributes=%t", cacheDirectoryAttributes), func(b *testing.B) {
+			ctrl, ctx := gomock.WithContext(context.Background(), b)
+
+			d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+				MaximumTreeSizeBytes: 10000,
+				Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+					CacheDirectoryAttributes: cacheDirectoryAttributes,
+				},
+			})
+
+			casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+			f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+			casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+			outputPath := mock.NewMockOutputPath(ctrl)
+			f.outputPathFactory.EXPECT().StartInitialBuild(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(outputPath)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
+
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(b, err)
+
+			// Create a tree of 100 directories, all of which
+			// are stat()ed by every call to BatchStat().
+			paths := make([]string, 0, 100)
+			for i := 0; i < 100; i++ {
+				name := path.MustNewComponent(fmt.Sprintf("directory%d", i))
+				directory := mock.NewMockPrepopulatedDirectory(ctrl)
+				outputPath.EXPECT().LookupChild(name).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil).AnyTimes()
+				directory.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+					Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+						attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+					}).
+					AnyTimes()
+				paths = append(paths, name.String())
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+					BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+					Paths:   paths,
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func BenchmarkRemoteOutputServiceDirectoryBatchStatFileDigests(b *testing.B) {
+	for _, fileDigestCacheSize := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("FileDigestCacheSize=%d", fileDigestCacheSize), func(b *testing.B) {
+			ctrl, ctx := gomock.WithContext(context.Background(), b)
+
+			d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+				MaximumTreeSizeBytes: 10000,
+				Caching: cd_vfs.RemoteOutputServiceCachingConfiguration{
+					FileDigestCacheSize: fileDigestCacheSize,
+				},
+			})
+
+			casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+			f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+			casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+			outputPath := mock.NewMockOutputPath(ctrl)
+			f.outputPathFactory.EXPECT().StartInitialBuild(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(outputPath)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
+
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(b, err)
+
+			// Let the output path contain a single file that
+			// was written locally. Computing its digest
+			// requires the file to be read in its entirety,
+			// which is simulated by hashing 1 MB of data.
+			file := mock.NewMockNativeLeaf(ctrl)
+			outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil).AnyTimes()
+			file.EXPECT().Readlink().Return("", syscall.EINVAL).AnyTimes()
+			file.EXPECT().GetContainingDigests().Return(digest.EmptySet).AnyTimes()
+			file.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskChangeID, gomock.Any()).
+				Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+					attributes.SetChangeID(1)
+				}).
+				AnyTimes()
+			contents := make([]byte, 1024*1024)
+			file.EXPECT().GetOutputServiceFileStatus(gomock.Any()).
+				DoAndReturn(func(digestFunction *digest.Function) (*remoteoutputservice.FileStatus, error) {
+					fileStatus := &remoteoutputservice.FileStatus_File{}
+					if digestFunction != nil {
+						generator := digestFunction.NewGenerator(int64(len(contents)))
+						generator.Write(contents)
+						fileStatus.Digest = generator.Sum().GetProto()
+					}
+					return &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: fileStatus,
 						},
-					},
-				},
-				// "nested/symlink_internal_absolute_alias".
+					}, nil
+				}).
+				AnyTimes()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+					BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+					IncludeFileDigest: true,
+					Paths:             []string{"file"},
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestRemoteOutputServiceDirectoryReadOnly(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, _ := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		ReadOnly:             true,
+	})
+
+	readOnlyErr := status.Error(codes.FailedPrecondition, "Remote Output Service is read-only")
+
+	t.Run("StartBuild", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, readOnlyErr, err)
+	})
+
+	t.Run("BatchCreate", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Symlinks: []*remoteexecution.OutputSymlink{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1003},
-							},
-						},
-					},
+					Path:   "foo",
+					Target: "target",
 				},
-				// "nested/symlink_external".
+			},
+		})
+		testutil.RequireEqualStatus(t, readOnlyErr, err)
+	})
+
+	t.Run("FinalizeBuild", func(t *testing.T) {
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		testutil.RequireEqualStatus(t, readOnlyErr, err)
+	})
+
+	t.Run("Clean", func(t *testing.T) {
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, readOnlyErr, err)
+	})
+
+	t.Run("BatchStat", func(t *testing.T) {
+		// BatchStat() should not be rejected. It fails, as no
+		// builds can be running.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"foo"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("VirtualLookup", func(t *testing.T) {
+		var out re_vfs.Attributes
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), 0, &out)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	})
+
+	t.Run("VirtualReadDir", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVirtualGetAttributes(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	newDirectory := func(rootDirectoryPermissions re_vfs.Permissions) (*cd_vfs.RemoteOutputServiceDirectory, *mock.MockStatefulDirectoryHandle) {
+		d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+			MaximumTreeSizeBytes:     10000,
+			RootDirectoryPermissions: rootDirectoryPermissions,
+		})
+		return d, f.dHandle
+	}
+	attributesMask := re_vfs.AttributesMaskFileType | re_vfs.AttributesMaskPermissions | re_vfs.AttributesMaskSizeBytes
+
+	t.Run("DefaultPermissions", func(t *testing.T) {
+		// If no permissions are configured, the root directory
+		// should be readable, but not writable.
+		d, dHandle := newDirectory(0)
+		dHandle.EXPECT().GetAttributes(attributesMask, gomock.Any())
+
+		var out re_vfs.Attributes
+		d.VirtualGetAttributes(ctx, attributesMask, &out)
+		require.Equal(
+			t,
+			*(&re_vfs.Attributes{}).
+				SetFileType(filesystem.FileTypeDirectory).
+				SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsExecute).
+				SetSizeBytes(0),
+			out)
+	})
+
+	t.Run("CustomPermissions", func(t *testing.T) {
+		d, dHandle := newDirectory(re_vfs.PermissionsRead | re_vfs.PermissionsWrite | re_vfs.PermissionsExecute)
+		dHandle.EXPECT().GetAttributes(attributesMask, gomock.Any())
+
+		var out re_vfs.Attributes
+		d.VirtualGetAttributes(ctx, attributesMask, &out)
+		require.Equal(
+			t,
+			*(&re_vfs.Attributes{}).
+				SetFileType(filesystem.FileTypeDirectory).
+				SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsWrite | re_vfs.PermissionsExecute).
+				SetSizeBytes(0),
+			out)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVirtualLookup(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	// No output paths exist, so VirtualLookup() should always fail.
+	var out1 re_vfs.Attributes
+	_, s := d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out1)
+	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+
+	// Create an output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
+		BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/eaf1d65b7ab802934e6b57d0e14b3f30/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "eaf1d65b7ab802934e6b57d0e14b3f30",
+	}, response)
+
+	outputPath.EXPECT().VirtualGetAttributes(gomock.Any(),
+		re_vfs.AttributesMaskInodeNumber,
+		gomock.Any(),
+	).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+		out.SetInodeNumber(101)
+	})
+
+	// Call VirtualLookup() again. It should now succeed.
+	var out2 re_vfs.Attributes
+	child, s := d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), re_vfs.AttributesMaskInodeNumber, &out2)
+	require.Equal(t, re_vfs.StatusOK, s)
+	remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath, child)
+	require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out2)
+
+	// Remove the output path.
+	outputPath.EXPECT().RemoveAllChildren(true)
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"))
+
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "eaf1d65b7ab802934e6b57d0e14b3f30",
+	})
+	require.NoError(t, err)
+
+	// VirtualLookup() should fail once again.
+	var out3 re_vfs.Attributes
+	_, s = d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out3)
+	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+}
+
+func TestRemoteOutputServiceDirectoryOutputPathChangeID(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
+		BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// The root directory of the output path only reports a change
+	// ID that accounts for changes to its own children. Let it
+	// report a constant value throughout this test.
+	outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskChangeID, gomock.Any()).
+		Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+			out.SetChangeID(5)
+		}).
+		AnyTimes()
+
+	var out1 re_vfs.Attributes
+	_, s := d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), re_vfs.AttributesMaskChangeID, &out1)
+	require.Equal(t, re_vfs.StatusOK, s)
+	changeIDBefore := out1.GetChangeID()
+
+	// Create a symbolic link in a subdirectory of the output path.
+	// This does not modify the root directory itself, but should
+	// still cause the change ID to increase.
+	subdirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+	outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).Return(subdirectory, nil)
+	symlink := mock.NewMockNativeLeaf(ctrl)
+	f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+	subdirectory.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+		path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+	}, true)
+
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId: "2840d789-16ff-4fe4-9639-3245f9bb9106",
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "a/foo",
+				Target: "target",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var out2 re_vfs.Attributes
+	_, s = d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), re_vfs.AttributesMaskChangeID, &out2)
+	require.Equal(t, re_vfs.StatusOK, s)
+	changeIDAfter := out2.GetChangeID()
+	require.Greater(t, changeIDAfter, changeIDBefore)
+
+	// VirtualReadDir() should report the same change ID.
+	reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+	var reportedChild re_vfs.DirectoryChild
+	reporter.EXPECT().ReportEntry(gomock.Any(), path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+			require.Equal(t, changeIDAfter, attributes.GetChangeID())
+			reportedChild = child
+			return true
+		})
+	require.Equal(
+		t,
+		re_vfs.StatusOK,
+		d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskChangeID, reporter))
+	remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath, reportedChild)
+}
+
+func TestRemoteOutputServiceDirectoryOutputPathLastAccessTime(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+	})
+
+	t.Run("NonexistentOutputPath", func(t *testing.T) {
+		_, err := d.GetOutputPathLastAccessTime("eaf1d65b7ab802934e6b57d0e14b3f30")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output path \"eaf1d65b7ab802934e6b57d0e14b3f30\" does not exist"), err)
+	})
+
+	// Starting a build should count as accessing the output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(2)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
+		BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	lastAccessTime, err := d.GetOutputPathLastAccessTime("eaf1d65b7ab802934e6b57d0e14b3f30")
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1000, 0), lastAccessTime)
+
+	var child re_vfs.DirectoryChild
+	t.Run("VirtualLookup", func(t *testing.T) {
+		// Looking up the output path through the virtual file
+		// system should count as activity, even if no calls
+		// are made through the Remote Output Service.
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMask(0), gomock.Any())
+
+		var out re_vfs.Attributes
+		var s re_vfs.Status
+		child, s = d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), 0, &out)
+		require.Equal(t, re_vfs.StatusOK, s)
+
+		lastAccessTime, err := d.GetOutputPathLastAccessTime("eaf1d65b7ab802934e6b57d0e14b3f30")
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(1010, 0), lastAccessTime)
+	})
+
+	t.Run("VirtualReadDir", func(t *testing.T) {
+		// The same holds for listing the output path.
+		clock.EXPECT().Now().Return(time.Unix(1020, 0))
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMask(0), gomock.Any())
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		reporter.EXPECT().ReportEntry(gomock.Any(), path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), child, gomock.Any()).Return(true)
+
+		require.Equal(t, re_vfs.StatusOK, d.VirtualReadDir(ctx, 0, 0, reporter))
+
+		lastAccessTime, err := d.GetOutputPathLastAccessTime("eaf1d65b7ab802934e6b57d0e14b3f30")
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(1020, 0), lastAccessTime)
+	})
+
+	t.Run("VirtualGetAttributes", func(t *testing.T) {
+		// Kernels tend to revalidate cached directory entries
+		// by requesting the attributes of the root directory of
+		// the output path directly, as opposed to performing
+		// another lookup. This should also count as activity.
+		clock.EXPECT().Now().Return(time.Unix(1030, 0))
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(101)
+			})
+
+		directory, _ := child.GetPair()
+		var out re_vfs.Attributes
+		directory.VirtualGetAttributes(ctx, re_vfs.AttributesMaskInodeNumber, &out)
+		require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out)
+
+		lastAccessTime, err := d.GetOutputPathLastAccessTime("eaf1d65b7ab802934e6b57d0e14b3f30")
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(1030, 0), lastAccessTime)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputBaseStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+		Clock:                clock,
+	})
+
+	t.Run("NonexistentOutputBase", func(t *testing.T) {
+		_, err := d.GetOutputBaseStatistics("9da951b8cb759233037166e28f7ea186")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output path \"9da951b8cb759233037166e28f7ea186\" does not exist"), err)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(2)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("EmptyOutputPath", func(t *testing.T) {
+		statistics, err := d.GetOutputBaseStatistics("9da951b8cb759233037166e28f7ea186")
+		require.NoError(t, err)
+		require.Equal(t, &cd_vfs.OutputBaseStatistics{
+			LastBuildStartTime: time.Unix(1000, 0),
+		}, statistics)
+	})
+
+	t.Run("BatchCreate", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+			casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+			fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(mock.NewMockNativeLeaf(ctrl))
+		}
+		f.symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(mock.NewMockNativeLeaf(ctrl))
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).Times(4)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_External_{
-							External: &remoteoutputservice.FileStatus_External{
-								NextPath: "/etc/passwd",
-							},
-						},
+					Path: "a.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8e1554fc1ad824a6e9180c7b145790d2",
+						SizeBytes: 100,
 					},
 				},
-				// "../foo".
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_External_{
-							External: &remoteoutputservice.FileStatus_External{
-								NextPath: "../foo",
-							},
-						},
+					Path: "b.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "0f8a5b1f3d6e5d4e5fba8bd3f8b8c8a1",
+						SizeBytes: 200,
 					},
 				},
-				// "/etc/passwd".
+			},
+			Directories: []*remoteexecution.OutputDirectory{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_External_{
-							External: &remoteoutputservice.FileStatus_External{
-								NextPath: "/etc/passwd",
-							},
-						},
+					Path: "objs",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "4df5f448a5e6b3c41e6aae7a8a9832aa",
+						SizeBytes: 123,
 					},
 				},
-				// "nonexistent".
-				{},
-				// ".".
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1004},
-							},
-						},
-					},
+					Path:   "link",
+					Target: "target",
 				},
 			},
-		}, response)
+		})
+		require.NoError(t, err)
+
+		statistics, err := d.GetOutputBaseStatistics("9da951b8cb759233037166e28f7ea186")
+		require.NoError(t, err)
+		require.Equal(t, &cd_vfs.OutputBaseStatistics{
+			FilesCount:         2,
+			DirectoriesCount:   1,
+			SymlinksCount:      1,
+			SizeBytes:          300,
+			LastBuildStartTime: time.Unix(1000, 0),
+		}, statistics)
+	})
+
+	t.Run("BatchRemove", func(t *testing.T) {
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		outputPath.EXPECT().Remove(path.MustNewComponent("a.o"))
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetContainingDigests().Return(digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "8e1554fc1ad824a6e9180c7b145790d2", 100).ToSingletonSet())
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("link")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlink), nil)
+		outputPath.EXPECT().Remove(path.MustNewComponent("link"))
+		symlink.EXPECT().Readlink().Return("target", nil)
+
+		errs, err := d.BatchRemove(ctx, "37f5dbef-b117-4fb6-bce8-5c147cb603b4", []string{"a.o", "link"}, false)
+		require.NoError(t, err)
+		require.Equal(t, []error{nil, nil}, errs)
+
+		statistics, err := d.GetOutputBaseStatistics("9da951b8cb759233037166e28f7ea186")
+		require.NoError(t, err)
+		require.Equal(t, &cd_vfs.OutputBaseStatistics{
+			FilesCount:         1,
+			DirectoriesCount:   1,
+			SymlinksCount:      0,
+			SizeBytes:          200,
+			LastBuildStartTime: time.Unix(1000, 0),
+		}, statistics)
 	})
 }
 
-func TestRemoteOutputServiceDirectoryVirtualLookup(t *testing.T) {
+func TestRemoteOutputServiceDirectoryCaseInsensitiveOutputBaseIDs(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
-	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
-	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
-	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
-	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(dHandleAllocation)
-	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
-	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
-		handleAllocator,
-		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
-		directoryFetcher,
-		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
-
-	// No output paths exist, so VirtualLookup() should always fail.
-	var out1 re_vfs.Attributes
-	_, s := d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out1)
-	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes:         10000,
+		CaseInsensitiveOutputBaseIDs: true,
+	})
 
-	// Create an output path.
+	// Create an output path whose output base ID contains
+	// uppercase characters.
 	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
-	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
-	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
 	outputPath := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
-		path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
+	f.outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("MyBase"),
 		gomock.Any(),
 		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
 		gomock.Any(),
@@ -1141,70 +10007,94 @@ func TestRemoteOutputServiceDirectoryVirtualLookup(t *testing.T) {
 	outputPath.EXPECT().FilterChildren(gomock.Any())
 
 	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-		OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
+		OutputBaseId:     "MyBase",
 		BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
 		DigestFunction:   remoteexecution.DigestFunction_MD5,
 		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
-		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/eaf1d65b7ab802934e6b57d0e14b3f30/execroot/myproject/bazel-out": ".",
-		},
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "eaf1d65b7ab802934e6b57d0e14b3f30",
+		OutputPathSuffix: "MyBase",
 	}, response)
 
-	outputPath.EXPECT().VirtualGetAttributes(
-		ctx,
-		re_vfs.AttributesMaskInodeNumber,
-		gomock.Any(),
-	).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
-		out.SetInodeNumber(101)
+	t.Run("VirtualLookup", func(t *testing.T) {
+		// Looking up the output path should succeed, regardless
+		// of the case used.
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(101)
+			})
+
+		var out re_vfs.Attributes
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("mybase"), re_vfs.AttributesMaskInodeNumber, &out)
+		require.Equal(t, re_vfs.StatusOK, s)
+		remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath, child)
+		require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out)
 	})
 
-	// Call VirtualLookup() again. It should now succeed.
-	var out2 re_vfs.Attributes
-	child, s := d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), re_vfs.AttributesMaskInodeNumber, &out2)
-	require.Equal(t, re_vfs.StatusOK, s)
-	require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
-	require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out2)
+	t.Run("VirtualReadDir", func(t *testing.T) {
+		// Directory listings should use the case with which the
+		// output path was created.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(101)
+			})
+		var reportedChild re_vfs.DirectoryChild
+		reporter.EXPECT().ReportEntry(
+			uint64(1),
+			path.MustNewComponent("MyBase"),
+			gomock.Any(),
+			(&re_vfs.Attributes{}).SetInodeNumber(101),
+		).DoAndReturn(func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+			reportedChild = child
+			return true
+		})
 
-	// Remove the output path.
-	outputPath.EXPECT().RemoveAllChildren(true)
-	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"))
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+		remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath, reportedChild)
+	})
 
-	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
-		OutputBaseId: "eaf1d65b7ab802934e6b57d0e14b3f30",
+	t.Run("Collision", func(t *testing.T) {
+		// Starting a build against an output base whose ID only
+		// differs in case should be rejected, as both would
+		// share the same output path.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "MYBASE",
+			BuildId:          "7f3bd1b9-4e64-4d3b-8b4e-0d1f3f0a2f5c",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.AlreadyExists, "Output base ID collides with that of existing output base \"MyBase\""), err)
 	})
-	require.NoError(t, err)
 
-	// VirtualLookup() should fail once again.
-	var out3 re_vfs.Attributes
-	_, s = d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out3)
-	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	t.Run("Clean", func(t *testing.T) {
+		// Cleaning should also be case insensitive. The removal
+		// notification should use the original case, as that's
+		// the name under which the output path was listed.
+		outputPath.EXPECT().RemoveAllChildren(true)
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("MyBase"))
+
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "mybase",
+		})
+		require.NoError(t, err)
+
+		var out re_vfs.Attributes
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("MyBase"), 0, &out)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	})
 }
 
 func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
-	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
-	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
-	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
-	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(dHandleAllocation)
-	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
-	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
-		handleAllocator,
-		outputPathFactory,
-		bareContentAddressableStorage,
-		retryingContentAddressableStorage,
-		directoryFetcher,
-		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
 
 	t.Run("InitialState", func(t *testing.T) {
 		// The directory should initially be empty.
@@ -1218,11 +10108,11 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 
 	// Create two output paths.
 	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
 	casFileHandleAllocator1 := mock.NewMockStatelessHandleAllocator(ctrl)
 	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator1)
 	outputPath1 := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
+	f.outputPathFactory.EXPECT().StartInitialBuild(
 		path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"),
 		gomock.Any(),
 		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
@@ -1245,11 +10135,11 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 	}, response)
 
 	casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
+	f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
 	casFileHandleAllocator2 := mock.NewMockStatelessHandleAllocator(ctrl)
 	casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator2)
 	outputPath2 := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
+	f.outputPathFactory.EXPECT().StartInitialBuild(
 		path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
 		gomock.Any(),
 		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
@@ -1274,21 +10164,24 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 	t.Run("FromStart", func(t *testing.T) {
 		// The directory listing should contain both output paths.
 		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-		outputPath1.EXPECT().VirtualGetAttributes(
-			ctx,
+		outputPath1.EXPECT().VirtualGetAttributes(gomock.Any(),
 			re_vfs.AttributesMaskInodeNumber,
 			gomock.Any(),
 		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
 			out.SetInodeNumber(101)
 		})
+		var reportedChildren []re_vfs.DirectoryChild
+		reportChild := func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+			reportedChildren = append(reportedChildren, child)
+			return true
+		}
 		reporter.EXPECT().ReportEntry(
 			uint64(1),
 			path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"),
-			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
+			gomock.Any(),
 			(&re_vfs.Attributes{}).SetInodeNumber(101),
-		).Return(true)
-		outputPath2.EXPECT().VirtualGetAttributes(
-			ctx,
+		).DoAndReturn(reportChild)
+		outputPath2.EXPECT().VirtualGetAttributes(gomock.Any(),
 			re_vfs.AttributesMaskInodeNumber,
 			gomock.Any(),
 		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
@@ -1297,36 +10190,43 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 		reporter.EXPECT().ReportEntry(
 			uint64(2),
 			path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
-			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
+			gomock.Any(),
 			(&re_vfs.Attributes{}).SetInodeNumber(102),
-		).Return(true)
+		).DoAndReturn(reportChild)
 
 		require.Equal(
 			t,
 			re_vfs.StatusOK,
 			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+		require.Len(t, reportedChildren, 2)
+		remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath1, reportedChildren[0])
+		remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath2, reportedChildren[1])
 	})
 
 	t.Run("Partial", func(t *testing.T) {
 		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-		outputPath2.EXPECT().VirtualGetAttributes(
-			ctx,
+		outputPath2.EXPECT().VirtualGetAttributes(gomock.Any(),
 			re_vfs.AttributesMaskInodeNumber,
 			gomock.Any(),
 		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
 			out.SetInodeNumber(102)
 		})
+		var reportedChild re_vfs.DirectoryChild
 		reporter.EXPECT().ReportEntry(
 			uint64(2),
 			path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
-			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
+			gomock.Any(),
 			(&re_vfs.Attributes{}).SetInodeNumber(102),
-		).Return(true)
+		).DoAndReturn(func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+			reportedChild = child
+			return true
+		})
 
 		require.Equal(
 			t,
 			re_vfs.StatusOK,
 			d.VirtualReadDir(ctx, 1, re_vfs.AttributesMaskInodeNumber, reporter))
+		remoteOutputServiceDirectoryRequireOutputPathRoot(ctx, t, outputPath2, reportedChild)
 	})
 
 	t.Run("AtEOF", func(t *testing.T) {
@@ -1347,14 +10247,14 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 
 	// Remove all output paths.
 	outputPath1.EXPECT().RemoveAllChildren(true)
-	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"))
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"))
 	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
 		OutputBaseId: "83f3e6ff93a5403cbfb14682d8165968",
 	})
 	require.NoError(t, err)
 
 	outputPath2.EXPECT().RemoveAllChildren(true)
-	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"))
+	f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"))
 	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
 		OutputBaseId: "d4b145a6191c6d8d037d13986274d08d",
 	})
@@ -1369,3 +10269,273 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
 	})
 }
+
+func TestRemoteOutputServiceDirectoryVirtualReadDirSorted(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes:   10000,
+		SortedDirectoryListing: true,
+	})
+
+	// Create three output paths, in an order that differs from
+	// their lexicographic order.
+	outputPaths := map[string]*mock.MockOutputPath{}
+	for i, outputBaseID := range []string{
+		"d4b145a6191c6d8d037d13986274d08d",
+		"83f3e6ff93a5403cbfb14682d8165968",
+		"a9c3f0e5d1b27e6a8f4c0d3b2e1f5a7c",
+	} {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).AnyTimes()
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          fmt.Sprintf("build-%d", i),
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		outputPaths[outputBaseID] = outputPath
+	}
+
+	expectReportEntries := func(reporter *mock.MockDirectoryEntryReporter, cookies []uint64, outputBaseIDs []string) {
+		var calls []*gomock.Call
+		for i, outputBaseID := range outputBaseIDs {
+			calls = append(calls, reporter.EXPECT().ReportEntry(
+				cookies[i],
+				path.MustNewComponent(outputBaseID),
+				gomock.Any(),
+				gomock.Any(),
+			).Return(true))
+		}
+		gomock.InOrder(calls...)
+	}
+
+	t.Run("FromStart", func(t *testing.T) {
+		// Output paths should be reported in lexicographic
+		// order. Cookies should remain tied to the output
+		// paths, as opposed to their position.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		expectReportEntries(
+			reporter,
+			[]uint64{2, 3, 1},
+			[]string{
+				"83f3e6ff93a5403cbfb14682d8165968",
+				"a9c3f0e5d1b27e6a8f4c0d3b2e1f5a7c",
+				"d4b145a6191c6d8d037d13986274d08d",
+			})
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		expectReportEntries(
+			reporter,
+			[]uint64{3, 1},
+			[]string{
+				"a9c3f0e5d1b27e6a8f4c0d3b2e1f5a7c",
+				"d4b145a6191c6d8d037d13986274d08d",
+			})
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 2, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("AtEOF", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 1, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("AfterRemoval", func(t *testing.T) {
+		// Partial reads should continue at the right position,
+		// even if the output path that was reported last has
+		// been removed in the meantime.
+		outputPaths["a9c3f0e5d1b27e6a8f4c0d3b2e1f5a7c"].EXPECT().RemoveAllChildren(true)
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("a9c3f0e5d1b27e6a8f4c0d3b2e1f5a7c"))
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "a9c3f0e5d1b27e6a8f4c0d3b2e1f5a7c",
+		})
+		require.NoError(t, err)
+
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		expectReportEntries(
+			reporter,
+			[]uint64{1},
+			[]string{"d4b145a6191c6d8d037d13986274d08d"})
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 3, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("RemovedNamesPruned", func(t *testing.T) {
+		// Only a bounded number of output base IDs of removed
+		// output paths is retained. Once many other output
+		// paths have been removed, resuming a partial read
+		// using the cookie of the output path removed above
+		// should terminate the listing.
+		for i := 0; i < 1000; i++ {
+			outputBaseID := fmt.Sprintf("%032x", i)
+			casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+			f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+			casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+			outputPath := mock.NewMockOutputPath(ctrl)
+			f.outputPathFactory.EXPECT().StartInitialBuild(
+				path.MustNewComponent(outputBaseID),
+				gomock.Any(),
+				digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+				gomock.Any(),
+			).Return(outputPath)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
+
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     outputBaseID,
+				BuildId:          fmt.Sprintf("pruned-build-%d", i),
+				DigestFunction:   remoteexecution.DigestFunction_MD5,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(t, err)
+
+			outputPath.EXPECT().RemoveAllChildren(true)
+			f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent(outputBaseID))
+			_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+				OutputBaseId: outputBaseID,
+			})
+			require.NoError(t, err)
+		}
+
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 3, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVirtualReadDirConcurrentClean(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d, f := newRemoteOutputServiceDirectoryFixture(ctrl, &cd_vfs.RemoteOutputServiceDirectoryConfiguration{
+		MaximumTreeSizeBytes: 10000,
+	})
+
+	// Helpers for creating and cleaning output paths.
+	nextOutputPathID := 0
+	outputPaths := map[string]*mock.MockOutputPath{}
+	createOutputPath := func() {
+		outputBaseID := fmt.Sprintf("%032x", nextOutputPathID)
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		f.handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(mock.NewMockStatelessHandleAllocator(ctrl))
+		outputPath := mock.NewMockOutputPath(ctrl)
+		f.outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(outputBaseID),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).AnyTimes()
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          fmt.Sprintf("build-%d", nextOutputPathID),
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		outputPaths[outputBaseID] = outputPath
+		nextOutputPathID++
+	}
+	cleanOutputPath := func(outputBaseID string) {
+		outputPaths[outputBaseID].EXPECT().RemoveAllChildren(true)
+		f.dHandle.EXPECT().NotifyRemoval(path.MustNewComponent(outputBaseID))
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: outputBaseID,
+		})
+		require.NoError(t, err)
+		delete(outputPaths, outputBaseID)
+	}
+
+	for i := 0; i < 100; i++ {
+		createOutputPath()
+	}
+
+	// Perform a number of paginated directory listings, while
+	// cleaning and creating output paths in between pages. Output
+	// paths that are present during the entire listing must be
+	// returned exactly once. No output path may be returned more
+	// than once.
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		presentThroughout := map[string]struct{}{}
+		for outputBaseID := range outputPaths {
+			presentThroughout[outputBaseID] = struct{}{}
+		}
+		reported := map[string]struct{}{}
+
+		cookie := uint64(0)
+		for {
+			pageSize := 1 + random.Intn(5)
+			reportedInPage := 0
+			reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+			reporter.EXPECT().ReportEntry(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+					if reportedInPage == pageSize {
+						return false
+					}
+					require.Greater(t, nextCookie, cookie)
+					require.NotContains(t, reported, name.String())
+					reported[name.String()] = struct{}{}
+					reportedInPage++
+					cookie = nextCookie
+					return true
+				}).AnyTimes()
+			require.Equal(t, re_vfs.StatusOK, d.VirtualReadDir(ctx, cookie, re_vfs.AttributesMaskInodeNumber, reporter))
+			if reportedInPage < pageSize {
+				break
+			}
+
+			// Clean a random output path, which may or may
+			// not have been reported already.
+			if len(outputPaths) > 0 && random.Intn(2) == 0 {
+				outputBaseIDs := make([]string, 0, len(outputPaths))
+				for outputBaseID := range outputPaths {
+					outputBaseIDs = append(outputBaseIDs, outputBaseID)
+				}
+				sort.Strings(outputBaseIDs)
+				outputBaseID := outputBaseIDs[random.Intn(len(outputBaseIDs))]
+				cleanOutputPath(outputBaseID)
+				delete(presentThroughout, outputBaseID)
+			}
+			if random.Intn(3) == 0 {
+				createOutputPath()
+			}
+		}
+
+		for outputBaseID := range presentThroughout {
+			require.Contains(t, reported, outputBaseID)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package virtual
+
+import (
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// maximumRemovedChildrenCount is the maximum number of removed
+// children that are tracked for a single build. Output paths may
+// contain millions of files, meaning that reporting all of them would
+// exceed the maximum size of responses.
+const maximumRemovedChildrenCount = 10000
+
+// RemovedChildren contains the digests of files and directories that
+// were removed from the output path at the start of a build, because
+// they were absent from the Content Addressable Storage, or because
+// they used a different instance name or digest function. As
+// FilterChildren() does not report the paths of children, children are
+// identified by the digest that caused them to be removed. Directories
+// whose contents could not be loaded are reported as digest.BadDigest.
+type RemovedChildren struct {
+	Digests []digest.Digest
+
+	// Set if more children were removed than can be reported.
+	Truncated bool
+}
+
+// removedChildList keeps track of the children that were removed from
+// the output path at the start of a build, so that they can be
+// returned by FinalizeBuildWithRemovedChildren().
+type removedChildList struct {
+	lock            sync.Mutex
+	removedChildren RemovedChildren
+}
+
+func (l *removedChildList) add(blobDigest digest.Digest) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if len(l.removedChildren.Digests) >= maximumRemovedChildrenCount {
+		l.removedChildren.Truncated = true
+		return
+	}
+	l.removedChildren.Digests = append(l.removedChildren.Digests, blobDigest)
+}
+
+// get the digests of the children that have been added to the list.
+func (l *removedChildList) get() RemovedChildren {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return RemovedChildren{
+		Digests:   append([]digest.Digest(nil), l.removedChildren.Digests...),
+		Truncated: l.removedChildren.Truncated,
+	}
+}
@@ -0,0 +1,43 @@
+package virtual
+
+import (
+	"context"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// Snapshot of the contents of an output path, created by
+// FinalizeBuildWithSnapshot() at the end of a build.
+type Snapshot struct {
+	// The output base and the build from which the snapshot was
+	// created.
+	OutputBaseID string
+	BuildID      string
+
+	// The digest of a REv2 Tree object stored in the Content
+	// Addressable Storage, containing the contents of the output
+	// path. Like any other object in the Content Addressable
+	// Storage, it may be evicted, in which case the snapshot can
+	// no longer be restored.
+	TreeDigest digest.Digest
+
+	// The time at which the snapshot was created, and whether the
+	// build from which it was created was successful.
+	CreationTime    time.Time
+	BuildSuccessful bool
+}
+
+// SnapshotStore is used by RemoteOutputServiceDirectory to persist
+// named snapshots of output paths, so that output bases can be
+// repopulated with the results of builds other than the latest one
+// through RestoreSnapshot().
+type SnapshotStore interface {
+	// Put stores a snapshot under a given name, replacing any
+	// snapshot that was previously stored under the same name.
+	Put(ctx context.Context, name string, snapshot Snapshot) error
+
+	// Get returns the snapshot stored under a given name. If no
+	// such snapshot exists, NOT_FOUND is returned.
+	Get(ctx context.Context, name string) (Snapshot, error)
+}
@@ -0,0 +1,119 @@
+package virtual
+
+import (
+	"sort"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// sortedOutputPathIndex keeps track of all output paths, sorted by
+// output base ID. If SortedDirectoryListing is set, it is used by
+// VirtualReadDir() to report output paths in lexicographic order, as
+// opposed to the order in which they were created.
+//
+// Cookies reported by VirtualReadDir() are derived from the cookies of
+// the output paths, which remain stable while output paths are added
+// and removed. A partial read is resumed by looking up the output path
+// corresponding to the cookie, and continuing with the output path
+// that follows it in sorted order. To be able to do this for output
+// paths that have been removed in the meantime, the output base IDs of
+// recently removed output paths are retained. Only a bounded number of
+// them is retained, as partial reads tend to be resumed shortly after
+// they are started. Resuming a partial read using the cookie of an
+// output path whose output base ID is no longer retained terminates
+// the listing.
+//
+// This type is not thread safe. All methods must be called while
+// holding the directory lock.
+type sortedOutputPathIndex struct {
+	outputPaths []*outputPathState
+
+	// Output base IDs of removed output paths, keyed by cookie.
+	// removedCookies contains the same cookies in the order in
+	// which the output paths were removed, so that the oldest
+	// entries can be pruned.
+	removedNames   map[uint64]path.Component
+	removedCookies []uint64
+}
+
+// maximumRemovedOutputPathNames is the maximum number of output base
+// IDs of removed output paths that sortedOutputPathIndex retains.
+const maximumRemovedOutputPathNames = 1000
+
+func newSortedOutputPathIndex() *sortedOutputPathIndex {
+	return &sortedOutputPathIndex{
+		removedNames: map[uint64]path.Component{},
+	}
+}
+
+// search returns the index of the first output path whose output base
+// ID is greater than or equal to the provided one.
+func (idx *sortedOutputPathIndex) search(outputBaseID path.Component) int {
+	name := outputBaseID.String()
+	return sort.Search(len(idx.outputPaths), func(i int) bool {
+		return idx.outputPaths[i].outputBaseID.String() >= name
+	})
+}
+
+// insert an output path into the index. It may be called against a nil
+// instance, in which case it is a no-op.
+func (idx *sortedOutputPathIndex) insert(state *outputPathState) {
+	if idx == nil {
+		return
+	}
+	i := idx.search(state.outputBaseID)
+	idx.outputPaths = append(idx.outputPaths, nil)
+	copy(idx.outputPaths[i+1:], idx.outputPaths[i:])
+	idx.outputPaths[i] = state
+}
+
+// remove an output path from the index. Its output base ID is retained,
+// so that partial reads that were positioned after it can be resumed.
+// If too many output base IDs are retained, the ones of the output
+// paths that were removed least recently are discarded. It may be
+// called against a nil instance, in which case it is a no-op.
+func (idx *sortedOutputPathIndex) remove(state *outputPathState) {
+	if idx == nil {
+		return
+	}
+	i := idx.search(state.outputBaseID)
+	if i < len(idx.outputPaths) && idx.outputPaths[i] == state {
+		idx.outputPaths = append(idx.outputPaths[:i], idx.outputPaths[i+1:]...)
+	}
+	idx.removedNames[state.cookie] = state.outputBaseID
+	idx.removedCookies = append(idx.removedCookies, state.cookie)
+	for len(idx.removedCookies) > maximumRemovedOutputPathNames {
+		delete(idx.removedNames, idx.removedCookies[0])
+		idx.removedCookies = idx.removedCookies[1:]
+	}
+}
+
+// getOutputPathsAfterCookie returns all output paths that should be
+// reported by a call to VirtualReadDir() that is provided the given
+// cookie.
+func (idx *sortedOutputPathIndex) getOutputPathsAfterCookie(firstCookie uint64) []*outputPathState {
+	if firstCookie == 0 {
+		return idx.outputPaths
+	}
+
+	// Cookies returned by VirtualReadDir() are one higher than the
+	// cookie of the output path that was reported last.
+	cookie := firstCookie - 1
+	for i, state := range idx.outputPaths {
+		if state.cookie == cookie {
+			return idx.outputPaths[i+1:]
+		}
+	}
+	if name, ok := idx.removedNames[cookie]; ok {
+		i := idx.search(name)
+		if i < len(idx.outputPaths) && idx.outputPaths[i].outputBaseID == name {
+			// An output path with the same output base ID
+			// has been created in the meantime.
+			i++
+		}
+		return idx.outputPaths[i:]
+	}
+
+	// Cookie was not handed out by this directory.
+	return nil
+}
@@ -0,0 +1,101 @@
+package virtual
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// trimmableDirectorySet keeps track of directories in an output path
+// that were created through BatchCreate(), and whose contents are
+// still identical to those of the Tree object from which they were
+// created. These directories may be trimmed by TrimBuild(), meaning
+// they are replaced by directories whose contents are loaded lazily.
+//
+// Directories are keyed by their path relative to the root of the
+// output path. Directories are removed from the set as soon as any
+// path inside of them is modified through RemoteOutputServiceDirectory,
+// meaning that no directory in the set is contained in another.
+type trimmableDirectorySet struct {
+	lock        sync.Mutex
+	directories map[string]digest.Digest
+}
+
+// joinTrimmablePath joins a path prefix and a path provided to
+// BatchCreate(). Paths that are not in normalized form (e.g., because
+// they contain "." or ".." components) are not tracked, as they can't
+// be compared reliably.
+func joinTrimmablePath(pathPrefix, p string) (string, bool) {
+	var components []string
+	for _, s := range []string{pathPrefix, p} {
+		if s == "" {
+			continue
+		}
+		for _, component := range strings.Split(s, "/") {
+			if component == "" || component == "." || component == ".." {
+				return "", false
+			}
+			components = append(components, component)
+		}
+	}
+	return strings.Join(components, "/"), true
+}
+
+// add a directory to the set, replacing any directories contained in
+// it.
+func (s *trimmableDirectorySet) add(pathPrefix, p string, treeDigest digest.Digest) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key, ok := joinTrimmablePath(pathPrefix, p)
+	if !ok {
+		s.directories = nil
+		return
+	}
+	s.invalidateLocked(key)
+	if s.directories == nil {
+		s.directories = map[string]digest.Digest{}
+	}
+	s.directories[key] = treeDigest
+}
+
+// invalidate all directories in the set that are affected by a
+// modification of a given path. These are the directories that are
+// contained in the path, and those that contain the path.
+func (s *trimmableDirectorySet) invalidate(pathPrefix, p string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key, ok := joinTrimmablePath(pathPrefix, p)
+	if !ok {
+		s.directories = nil
+		return
+	}
+	s.invalidateLocked(key)
+}
+
+func (s *trimmableDirectorySet) invalidateLocked(key string) {
+	for directory := range s.directories {
+		if key == "" || directory == key || strings.HasPrefix(directory, key+"/") || strings.HasPrefix(key, directory+"/") {
+			delete(s.directories, directory)
+		}
+	}
+}
+
+// clear the set. This needs to be called when the contents of the
+// output path are modified without knowing which paths are affected.
+func (s *trimmableDirectorySet) clear() {
+	s.lock.Lock()
+	s.directories = nil
+	s.lock.Unlock()
+}
+
+// getAll returns a copy of all directories contained in the set.
+func (s *trimmableDirectorySet) getAll() map[string]digest.Digest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	directories := make(map[string]digest.Digest, len(s.directories))
+	for directory, treeDigest := range s.directories {
+		directories[directory] = treeDigest
+	}
+	return directories
+}
@@ -0,0 +1,7980 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.19.4
+// source: pkg/proto/clientd/clientd.proto
+
+package clientd
+
+import (
+	context "context"
+	v2 "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	remoteoutputservice "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status1 "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OutputPathRemovalEvent_Reason int32
+
+const (
+	OutputPathRemovalEvent_CLEAN     OutputPathRemovalEvent_Reason = 0
+	OutputPathRemovalEvent_CORRUPTED OutputPathRemovalEvent_Reason = 1
+)
+
+// Enum value maps for OutputPathRemovalEvent_Reason.
+var (
+	OutputPathRemovalEvent_Reason_name = map[int32]string{
+		0: "CLEAN",
+		1: "CORRUPTED",
+	}
+	OutputPathRemovalEvent_Reason_value = map[string]int32{
+		"CLEAN":     0,
+		"CORRUPTED": 1,
+	}
+)
+
+func (x OutputPathRemovalEvent_Reason) Enum() *OutputPathRemovalEvent_Reason {
+	p := new(OutputPathRemovalEvent_Reason)
+	*p = x
+	return p
+}
+
+func (x OutputPathRemovalEvent_Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputPathRemovalEvent_Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_clientd_clientd_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputPathRemovalEvent_Reason) Type() protoreflect.EnumType {
+	return &file_pkg_proto_clientd_clientd_proto_enumTypes[0]
+}
+
+func (x OutputPathRemovalEvent_Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputPathRemovalEvent_Reason.Descriptor instead.
+func (OutputPathRemovalEvent_Reason) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{11, 0}
+}
+
+type DiagnoseSelfTestResponse_StageResult_Stage int32
+
+const (
+	DiagnoseSelfTestResponse_StageResult_UPLOAD       DiagnoseSelfTestResponse_StageResult_Stage = 0
+	DiagnoseSelfTestResponse_StageResult_START_BUILD  DiagnoseSelfTestResponse_StageResult_Stage = 1
+	DiagnoseSelfTestResponse_StageResult_BATCH_CREATE DiagnoseSelfTestResponse_StageResult_Stage = 2
+	DiagnoseSelfTestResponse_StageResult_BATCH_STAT   DiagnoseSelfTestResponse_StageResult_Stage = 3
+	DiagnoseSelfTestResponse_StageResult_READ         DiagnoseSelfTestResponse_StageResult_Stage = 4
+	DiagnoseSelfTestResponse_StageResult_CLEAN        DiagnoseSelfTestResponse_StageResult_Stage = 5
+)
+
+// Enum value maps for DiagnoseSelfTestResponse_StageResult_Stage.
+var (
+	DiagnoseSelfTestResponse_StageResult_Stage_name = map[int32]string{
+		0: "UPLOAD",
+		1: "START_BUILD",
+		2: "BATCH_CREATE",
+		3: "BATCH_STAT",
+		4: "READ",
+		5: "CLEAN",
+	}
+	DiagnoseSelfTestResponse_StageResult_Stage_value = map[string]int32{
+		"UPLOAD":       0,
+		"START_BUILD":  1,
+		"BATCH_CREATE": 2,
+		"BATCH_STAT":   3,
+		"READ":         4,
+		"CLEAN":        5,
+	}
+)
+
+func (x DiagnoseSelfTestResponse_StageResult_Stage) Enum() *DiagnoseSelfTestResponse_StageResult_Stage {
+	p := new(DiagnoseSelfTestResponse_StageResult_Stage)
+	*p = x
+	return p
+}
+
+func (x DiagnoseSelfTestResponse_StageResult_Stage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiagnoseSelfTestResponse_StageResult_Stage) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_clientd_clientd_proto_enumTypes[1].Descriptor()
+}
+
+func (DiagnoseSelfTestResponse_StageResult_Stage) Type() protoreflect.EnumType {
+	return &file_pkg_proto_clientd_clientd_proto_enumTypes[1]
+}
+
+func (x DiagnoseSelfTestResponse_StageResult_Stage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiagnoseSelfTestResponse_StageResult_Stage.Descriptor instead.
+func (DiagnoseSelfTestResponse_StageResult_Stage) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{16, 0, 0}
+}
+
+type GetBuildStatusResponse_Phase int32
+
+const (
+	GetBuildStatusResponse_FILTERING GetBuildStatusResponse_Phase = 0
+	GetBuildStatusResponse_READY     GetBuildStatusResponse_Phase = 1
+	GetBuildStatusResponse_FAILED    GetBuildStatusResponse_Phase = 2
+)
+
+// Enum value maps for GetBuildStatusResponse_Phase.
+var (
+	GetBuildStatusResponse_Phase_name = map[int32]string{
+		0: "FILTERING",
+		1: "READY",
+		2: "FAILED",
+	}
+	GetBuildStatusResponse_Phase_value = map[string]int32{
+		"FILTERING": 0,
+		"READY":     1,
+		"FAILED":    2,
+	}
+)
+
+func (x GetBuildStatusResponse_Phase) Enum() *GetBuildStatusResponse_Phase {
+	p := new(GetBuildStatusResponse_Phase)
+	*p = x
+	return p
+}
+
+func (x GetBuildStatusResponse_Phase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GetBuildStatusResponse_Phase) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_clientd_clientd_proto_enumTypes[2].Descriptor()
+}
+
+func (GetBuildStatusResponse_Phase) Type() protoreflect.EnumType {
+	return &file_pkg_proto_clientd_clientd_proto_enumTypes[2]
+}
+
+func (x GetBuildStatusResponse_Phase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GetBuildStatusResponse_Phase.Descriptor instead.
+func (GetBuildStatusResponse_Phase) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{24, 0}
+}
+
+type BuildEvent_Type int32
+
+const (
+	BuildEvent_CHILD_REMOVED     BuildEvent_Type = 0
+	BuildEvent_DIRECTORY_CHANGED BuildEvent_Type = 1
+	BuildEvent_BLOB_MISSING      BuildEvent_Type = 2
+)
+
+// Enum value maps for BuildEvent_Type.
+var (
+	BuildEvent_Type_name = map[int32]string{
+		0: "CHILD_REMOVED",
+		1: "DIRECTORY_CHANGED",
+		2: "BLOB_MISSING",
+	}
+	BuildEvent_Type_value = map[string]int32{
+		"CHILD_REMOVED":     0,
+		"DIRECTORY_CHANGED": 1,
+		"BLOB_MISSING":      2,
+	}
+)
+
+func (x BuildEvent_Type) Enum() *BuildEvent_Type {
+	p := new(BuildEvent_Type)
+	*p = x
+	return p
+}
+
+func (x BuildEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BuildEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_clientd_clientd_proto_enumTypes[3].Descriptor()
+}
+
+func (BuildEvent_Type) Type() protoreflect.EnumType {
+	return &file_pkg_proto_clientd_clientd_proto_enumTypes[3]
+}
+
+func (x BuildEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BuildEvent_Type.Descriptor instead.
+func (BuildEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{37, 0}
+}
+
+type BatchStatWithOptionsRequest_DigestInclusionMode int32
+
+const (
+	BatchStatWithOptionsRequest_NEVER           BatchStatWithOptionsRequest_DigestInclusionMode = 0
+	BatchStatWithOptionsRequest_ALWAYS          BatchStatWithOptionsRequest_DigestInclusionMode = 1
+	BatchStatWithOptionsRequest_IF_KNOWN        BatchStatWithOptionsRequest_DigestInclusionMode = 2
+	BatchStatWithOptionsRequest_ALWAYS_VERIFIED BatchStatWithOptionsRequest_DigestInclusionMode = 3
+)
+
+// Enum value maps for BatchStatWithOptionsRequest_DigestInclusionMode.
+var (
+	BatchStatWithOptionsRequest_DigestInclusionMode_name = map[int32]string{
+		0: "NEVER",
+		1: "ALWAYS",
+		2: "IF_KNOWN",
+		3: "ALWAYS_VERIFIED",
+	}
+	BatchStatWithOptionsRequest_DigestInclusionMode_value = map[string]int32{
+		"NEVER":           0,
+		"ALWAYS":          1,
+		"IF_KNOWN":        2,
+		"ALWAYS_VERIFIED": 3,
+	}
+)
+
+func (x BatchStatWithOptionsRequest_DigestInclusionMode) Enum() *BatchStatWithOptionsRequest_DigestInclusionMode {
+	p := new(BatchStatWithOptionsRequest_DigestInclusionMode)
+	*p = x
+	return p
+}
+
+func (x BatchStatWithOptionsRequest_DigestInclusionMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchStatWithOptionsRequest_DigestInclusionMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_clientd_clientd_proto_enumTypes[4].Descriptor()
+}
+
+func (BatchStatWithOptionsRequest_DigestInclusionMode) Type() protoreflect.EnumType {
+	return &file_pkg_proto_clientd_clientd_proto_enumTypes[4]
+}
+
+func (x BatchStatWithOptionsRequest_DigestInclusionMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchStatWithOptionsRequest_DigestInclusionMode.Descriptor instead.
+func (BatchStatWithOptionsRequest_DigestInclusionMode) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{47, 0}
+}
+
+type BatchStatExistenceResponse_PathExistence int32
+
+const (
+	BatchStatExistenceResponse_ABSENT  BatchStatExistenceResponse_PathExistence = 0
+	BatchStatExistenceResponse_PRESENT BatchStatExistenceResponse_PathExistence = 1
+	BatchStatExistenceResponse_UNKNOWN BatchStatExistenceResponse_PathExistence = 2
+)
+
+// Enum value maps for BatchStatExistenceResponse_PathExistence.
+var (
+	BatchStatExistenceResponse_PathExistence_name = map[int32]string{
+		0: "ABSENT",
+		1: "PRESENT",
+		2: "UNKNOWN",
+	}
+	BatchStatExistenceResponse_PathExistence_value = map[string]int32{
+		"ABSENT":  0,
+		"PRESENT": 1,
+		"UNKNOWN": 2,
+	}
+)
+
+func (x BatchStatExistenceResponse_PathExistence) Enum() *BatchStatExistenceResponse_PathExistence {
+	p := new(BatchStatExistenceResponse_PathExistence)
+	*p = x
+	return p
+}
+
+func (x BatchStatExistenceResponse_PathExistence) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchStatExistenceResponse_PathExistence) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_clientd_clientd_proto_enumTypes[5].Descriptor()
+}
+
+func (BatchStatExistenceResponse_PathExistence) Type() protoreflect.EnumType {
+	return &file_pkg_proto_clientd_clientd_proto_enumTypes[5]
+}
+
+func (x BatchStatExistenceResponse_PathExistence) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchStatExistenceResponse_PathExistence.Descriptor instead.
+func (BatchStatExistenceResponse_PathExistence) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{51, 0}
+}
+
+type CleanProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RemovedLeavesCount uint64 `protobuf:"varint,1,opt,name=removed_leaves_count,json=removedLeavesCount,proto3" json:"removed_leaves_count,omitempty"`
+}
+
+func (x *CleanProgress) Reset() {
+	*x = CleanProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanProgress) ProtoMessage() {}
+
+func (x *CleanProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanProgress.ProtoReflect.Descriptor instead.
+func (*CleanProgress) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CleanProgress) GetRemovedLeavesCount() uint64 {
+	if x != nil {
+		return x.RemovedLeavesCount
+	}
+	return 0
+}
+
+type CleanPathPrefixRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	PathPrefix   string `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+}
+
+func (x *CleanPathPrefixRequest) Reset() {
+	*x = CleanPathPrefixRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanPathPrefixRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanPathPrefixRequest) ProtoMessage() {}
+
+func (x *CleanPathPrefixRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanPathPrefixRequest.ProtoReflect.Descriptor instead.
+func (*CleanPathPrefixRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CleanPathPrefixRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *CleanPathPrefixRequest) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+type CleanDryRunRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId                string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	MaximumSampleLeafPathsCount uint32 `protobuf:"varint,2,opt,name=maximum_sample_leaf_paths_count,json=maximumSampleLeafPathsCount,proto3" json:"maximum_sample_leaf_paths_count,omitempty"`
+}
+
+func (x *CleanDryRunRequest) Reset() {
+	*x = CleanDryRunRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanDryRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanDryRunRequest) ProtoMessage() {}
+
+func (x *CleanDryRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanDryRunRequest.ProtoReflect.Descriptor instead.
+func (*CleanDryRunRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CleanDryRunRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *CleanDryRunRequest) GetMaximumSampleLeafPathsCount() uint32 {
+	if x != nil {
+		return x.MaximumSampleLeafPathsCount
+	}
+	return 0
+}
+
+type CleanDryRunResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DirectoriesCount uint64   `protobuf:"varint,1,opt,name=directories_count,json=directoriesCount,proto3" json:"directories_count,omitempty"`
+	LeavesCount      uint64   `protobuf:"varint,2,opt,name=leaves_count,json=leavesCount,proto3" json:"leaves_count,omitempty"`
+	SampleLeafPaths  []string `protobuf:"bytes,3,rep,name=sample_leaf_paths,json=sampleLeafPaths,proto3" json:"sample_leaf_paths,omitempty"`
+}
+
+func (x *CleanDryRunResponse) Reset() {
+	*x = CleanDryRunResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanDryRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanDryRunResponse) ProtoMessage() {}
+
+func (x *CleanDryRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanDryRunResponse.ProtoReflect.Descriptor instead.
+func (*CleanDryRunResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CleanDryRunResponse) GetDirectoriesCount() uint64 {
+	if x != nil {
+		return x.DirectoriesCount
+	}
+	return 0
+}
+
+func (x *CleanDryRunResponse) GetLeavesCount() uint64 {
+	if x != nil {
+		return x.LeavesCount
+	}
+	return 0
+}
+
+func (x *CleanDryRunResponse) GetSampleLeafPaths() []string {
+	if x != nil {
+		return x.SampleLeafPaths
+	}
+	return nil
+}
+
+type StartBuildFromSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartBuild     *remoteoutputservice.StartBuildRequest `protobuf:"bytes,1,opt,name=start_build,json=startBuild,proto3" json:"start_build,omitempty"`
+	SnapshotDigest *v2.Digest                             `protobuf:"bytes,2,opt,name=snapshot_digest,json=snapshotDigest,proto3" json:"snapshot_digest,omitempty"`
+}
+
+func (x *StartBuildFromSnapshotRequest) Reset() {
+	*x = StartBuildFromSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartBuildFromSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBuildFromSnapshotRequest) ProtoMessage() {}
+
+func (x *StartBuildFromSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBuildFromSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*StartBuildFromSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StartBuildFromSnapshotRequest) GetStartBuild() *remoteoutputservice.StartBuildRequest {
+	if x != nil {
+		return x.StartBuild
+	}
+	return nil
+}
+
+func (x *StartBuildFromSnapshotRequest) GetSnapshotDigest() *v2.Digest {
+	if x != nil {
+		return x.SnapshotDigest
+	}
+	return nil
+}
+
+type StartBuildWithDigestFunctionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartBuild      *remoteoutputservice.StartBuildRequest `protobuf:"bytes,1,opt,name=start_build,json=startBuild,proto3" json:"start_build,omitempty"`
+	DigestFunctions []v2.DigestFunction_Value              `protobuf:"varint,2,rep,packed,name=digest_functions,json=digestFunctions,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_functions,omitempty"`
+}
+
+func (x *StartBuildWithDigestFunctionsRequest) Reset() {
+	*x = StartBuildWithDigestFunctionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartBuildWithDigestFunctionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBuildWithDigestFunctionsRequest) ProtoMessage() {}
+
+func (x *StartBuildWithDigestFunctionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBuildWithDigestFunctionsRequest.ProtoReflect.Descriptor instead.
+func (*StartBuildWithDigestFunctionsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StartBuildWithDigestFunctionsRequest) GetStartBuild() *remoteoutputservice.StartBuildRequest {
+	if x != nil {
+		return x.StartBuild
+	}
+	return nil
+}
+
+func (x *StartBuildWithDigestFunctionsRequest) GetDigestFunctions() []v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunctions
+	}
+	return nil
+}
+
+type StartBuildWithDigestFunctionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartBuild     *remoteoutputservice.StartBuildResponse `protobuf:"bytes,1,opt,name=start_build,json=startBuild,proto3" json:"start_build,omitempty"`
+	DigestFunction v2.DigestFunction_Value                 `protobuf:"varint,2,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+}
+
+func (x *StartBuildWithDigestFunctionsResponse) Reset() {
+	*x = StartBuildWithDigestFunctionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartBuildWithDigestFunctionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBuildWithDigestFunctionsResponse) ProtoMessage() {}
+
+func (x *StartBuildWithDigestFunctionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBuildWithDigestFunctionsResponse.ProtoReflect.Descriptor instead.
+func (*StartBuildWithDigestFunctionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StartBuildWithDigestFunctionsResponse) GetStartBuild() *remoteoutputservice.StartBuildResponse {
+	if x != nil {
+		return x.StartBuild
+	}
+	return nil
+}
+
+func (x *StartBuildWithDigestFunctionsResponse) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+type RestoreSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartBuild   *remoteoutputservice.StartBuildRequest `protobuf:"bytes,1,opt,name=start_build,json=startBuild,proto3" json:"start_build,omitempty"`
+	SnapshotName string                                 `protobuf:"bytes,2,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+}
+
+func (x *RestoreSnapshotRequest) Reset() {
+	*x = RestoreSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSnapshotRequest) ProtoMessage() {}
+
+func (x *RestoreSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RestoreSnapshotRequest) GetStartBuild() *remoteoutputservice.StartBuildRequest {
+	if x != nil {
+		return x.StartBuild
+	}
+	return nil
+}
+
+func (x *RestoreSnapshotRequest) GetSnapshotName() string {
+	if x != nil {
+		return x.SnapshotName
+	}
+	return ""
+}
+
+type PrepareOutputBaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId   string                  `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	InstanceName   string                  `protobuf:"bytes,2,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction v2.DigestFunction_Value `protobuf:"varint,3,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+}
+
+func (x *PrepareOutputBaseRequest) Reset() {
+	*x = PrepareOutputBaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrepareOutputBaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareOutputBaseRequest) ProtoMessage() {}
+
+func (x *PrepareOutputBaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareOutputBaseRequest.ProtoReflect.Descriptor instead.
+func (*PrepareOutputBaseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PrepareOutputBaseRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *PrepareOutputBaseRequest) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *PrepareOutputBaseRequest) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+type GetOutputPathTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetOutputPathTreeRequest) Reset() {
+	*x = GetOutputPathTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathTreeRequest) ProtoMessage() {}
+
+func (x *GetOutputPathTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputPathTreeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOutputPathTreeRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetOutputPathTreeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeDigest *v2.Digest `protobuf:"bytes,1,opt,name=tree_digest,json=treeDigest,proto3" json:"tree_digest,omitempty"`
+}
+
+func (x *GetOutputPathTreeResponse) Reset() {
+	*x = GetOutputPathTreeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathTreeResponse) ProtoMessage() {}
+
+func (x *GetOutputPathTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputPathTreeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOutputPathTreeResponse) GetTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.TreeDigest
+	}
+	return nil
+}
+
+type OutputPathRemovalEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId   string                        `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	Reason         OutputPathRemovalEvent_Reason `protobuf:"varint,2,opt,name=reason,proto3,enum=buildbarn.clientd.OutputPathRemovalEvent_Reason" json:"reason,omitempty"`
+	LastAccessTime *timestamppb.Timestamp        `protobuf:"bytes,3,opt,name=last_access_time,json=lastAccessTime,proto3" json:"last_access_time,omitempty"`
+}
+
+func (x *OutputPathRemovalEvent) Reset() {
+	*x = OutputPathRemovalEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutputPathRemovalEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputPathRemovalEvent) ProtoMessage() {}
+
+func (x *OutputPathRemovalEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputPathRemovalEvent.ProtoReflect.Descriptor instead.
+func (*OutputPathRemovalEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OutputPathRemovalEvent) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *OutputPathRemovalEvent) GetReason() OutputPathRemovalEvent_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return OutputPathRemovalEvent_CLEAN
+}
+
+func (x *OutputPathRemovalEvent) GetLastAccessTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessTime
+	}
+	return nil
+}
+
+type CheckpointResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputPathsCount int64 `protobuf:"varint,1,opt,name=output_paths_count,json=outputPathsCount,proto3" json:"output_paths_count,omitempty"`
+}
+
+func (x *CheckpointResponse) Reset() {
+	*x = CheckpointResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckpointResponse) ProtoMessage() {}
+
+func (x *CheckpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckpointResponse.ProtoReflect.Descriptor instead.
+func (*CheckpointResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CheckpointResponse) GetOutputPathsCount() int64 {
+	if x != nil {
+		return x.OutputPathsCount
+	}
+	return 0
+}
+
+type CloneOutputPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceOutputBaseId      string `protobuf:"bytes,1,opt,name=source_output_base_id,json=sourceOutputBaseId,proto3" json:"source_output_base_id,omitempty"`
+	DestinationOutputBaseId string `protobuf:"bytes,2,opt,name=destination_output_base_id,json=destinationOutputBaseId,proto3" json:"destination_output_base_id,omitempty"`
+	Overwrite               bool   `protobuf:"varint,3,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+}
+
+func (x *CloneOutputPathRequest) Reset() {
+	*x = CloneOutputPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneOutputPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneOutputPathRequest) ProtoMessage() {}
+
+func (x *CloneOutputPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneOutputPathRequest.ProtoReflect.Descriptor instead.
+func (*CloneOutputPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CloneOutputPathRequest) GetSourceOutputBaseId() string {
+	if x != nil {
+		return x.SourceOutputBaseId
+	}
+	return ""
+}
+
+func (x *CloneOutputPathRequest) GetDestinationOutputBaseId() string {
+	if x != nil {
+		return x.DestinationOutputBaseId
+	}
+	return ""
+}
+
+func (x *CloneOutputPathRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type RenameOutputBaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceOutputBaseId      string `protobuf:"bytes,1,opt,name=source_output_base_id,json=sourceOutputBaseId,proto3" json:"source_output_base_id,omitempty"`
+	DestinationOutputBaseId string `protobuf:"bytes,2,opt,name=destination_output_base_id,json=destinationOutputBaseId,proto3" json:"destination_output_base_id,omitempty"`
+}
+
+func (x *RenameOutputBaseRequest) Reset() {
+	*x = RenameOutputBaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameOutputBaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameOutputBaseRequest) ProtoMessage() {}
+
+func (x *RenameOutputBaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameOutputBaseRequest.ProtoReflect.Descriptor instead.
+func (*RenameOutputBaseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RenameOutputBaseRequest) GetSourceOutputBaseId() string {
+	if x != nil {
+		return x.SourceOutputBaseId
+	}
+	return ""
+}
+
+func (x *RenameOutputBaseRequest) GetDestinationOutputBaseId() string {
+	if x != nil {
+		return x.DestinationOutputBaseId
+	}
+	return ""
+}
+
+type DiagnoseSelfTestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName   string                  `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction v2.DigestFunction_Value `protobuf:"varint,2,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+}
+
+func (x *DiagnoseSelfTestRequest) Reset() {
+	*x = DiagnoseSelfTestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiagnoseSelfTestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnoseSelfTestRequest) ProtoMessage() {}
+
+func (x *DiagnoseSelfTestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnoseSelfTestRequest.ProtoReflect.Descriptor instead.
+func (*DiagnoseSelfTestRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DiagnoseSelfTestRequest) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *DiagnoseSelfTestRequest) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+type DiagnoseSelfTestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StageResults []*DiagnoseSelfTestResponse_StageResult `protobuf:"bytes,1,rep,name=stage_results,json=stageResults,proto3" json:"stage_results,omitempty"`
+}
+
+func (x *DiagnoseSelfTestResponse) Reset() {
+	*x = DiagnoseSelfTestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiagnoseSelfTestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnoseSelfTestResponse) ProtoMessage() {}
+
+func (x *DiagnoseSelfTestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnoseSelfTestResponse.ProtoReflect.Descriptor instead.
+func (*DiagnoseSelfTestResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DiagnoseSelfTestResponse) GetStageResults() []*DiagnoseSelfTestResponse_StageResult {
+	if x != nil {
+		return x.StageResults
+	}
+	return nil
+}
+
+type CheckHealthResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Serving bool `protobuf:"varint,1,opt,name=serving,proto3" json:"serving,omitempty"`
+}
+
+func (x *CheckHealthResponse) Reset() {
+	*x = CheckHealthResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckHealthResponse) ProtoMessage() {}
+
+func (x *CheckHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckHealthResponse.ProtoReflect.Descriptor instead.
+func (*CheckHealthResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CheckHealthResponse) GetServing() bool {
+	if x != nil {
+		return x.Serving
+	}
+	return false
+}
+
+type GetRuntimeStatisticsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputPathsCount                         int64  `protobuf:"varint,1,opt,name=output_paths_count,json=outputPathsCount,proto3" json:"output_paths_count,omitempty"`
+	RunningBuildsCount                       int64  `protobuf:"varint,2,opt,name=running_builds_count,json=runningBuildsCount,proto3" json:"running_builds_count,omitempty"`
+	CorruptedOutputPathsCount                int64  `protobuf:"varint,3,opt,name=corrupted_output_paths_count,json=corruptedOutputPathsCount,proto3" json:"corrupted_output_paths_count,omitempty"`
+	QueuedStartBuildsCount                   int64  `protobuf:"varint,4,opt,name=queued_start_builds_count,json=queuedStartBuildsCount,proto3" json:"queued_start_builds_count,omitempty"`
+	FilteringOutputPathsCount                int64  `protobuf:"varint,5,opt,name=filtering_output_paths_count,json=filteringOutputPathsCount,proto3" json:"filtering_output_paths_count,omitempty"`
+	CasCallsInFlightCount                    int64  `protobuf:"varint,6,opt,name=cas_calls_in_flight_count,json=casCallsInFlightCount,proto3" json:"cas_calls_in_flight_count,omitempty"`
+	FileDigestCacheHits                      uint64 `protobuf:"varint,7,opt,name=file_digest_cache_hits,json=fileDigestCacheHits,proto3" json:"file_digest_cache_hits,omitempty"`
+	FileDigestCacheMisses                    uint64 `protobuf:"varint,8,opt,name=file_digest_cache_misses,json=fileDigestCacheMisses,proto3" json:"file_digest_cache_misses,omitempty"`
+	FileDigestCacheEntriesCount              int64  `protobuf:"varint,9,opt,name=file_digest_cache_entries_count,json=fileDigestCacheEntriesCount,proto3" json:"file_digest_cache_entries_count,omitempty"`
+	NameInternerHits                         uint64 `protobuf:"varint,10,opt,name=name_interner_hits,json=nameInternerHits,proto3" json:"name_interner_hits,omitempty"`
+	NameInternerMisses                       uint64 `protobuf:"varint,11,opt,name=name_interner_misses,json=nameInternerMisses,proto3" json:"name_interner_misses,omitempty"`
+	InternedNamesCount                       int64  `protobuf:"varint,12,opt,name=interned_names_count,json=internedNamesCount,proto3" json:"interned_names_count,omitempty"`
+	EstimatedOutputPathsMemoryUsageBytes     int64  `protobuf:"varint,13,opt,name=estimated_output_paths_memory_usage_bytes,json=estimatedOutputPathsMemoryUsageBytes,proto3" json:"estimated_output_paths_memory_usage_bytes,omitempty"`
+	EstimatedFileDigestCacheMemoryUsageBytes int64  `protobuf:"varint,14,opt,name=estimated_file_digest_cache_memory_usage_bytes,json=estimatedFileDigestCacheMemoryUsageBytes,proto3" json:"estimated_file_digest_cache_memory_usage_bytes,omitempty"`
+	EstimatedNameInternerMemoryUsageBytes    int64  `protobuf:"varint,15,opt,name=estimated_name_interner_memory_usage_bytes,json=estimatedNameInternerMemoryUsageBytes,proto3" json:"estimated_name_interner_memory_usage_bytes,omitempty"`
+}
+
+func (x *GetRuntimeStatisticsResponse) Reset() {
+	*x = GetRuntimeStatisticsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRuntimeStatisticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuntimeStatisticsResponse) ProtoMessage() {}
+
+func (x *GetRuntimeStatisticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuntimeStatisticsResponse.ProtoReflect.Descriptor instead.
+func (*GetRuntimeStatisticsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetRuntimeStatisticsResponse) GetOutputPathsCount() int64 {
+	if x != nil {
+		return x.OutputPathsCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetRunningBuildsCount() int64 {
+	if x != nil {
+		return x.RunningBuildsCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetCorruptedOutputPathsCount() int64 {
+	if x != nil {
+		return x.CorruptedOutputPathsCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetQueuedStartBuildsCount() int64 {
+	if x != nil {
+		return x.QueuedStartBuildsCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetFilteringOutputPathsCount() int64 {
+	if x != nil {
+		return x.FilteringOutputPathsCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetCasCallsInFlightCount() int64 {
+	if x != nil {
+		return x.CasCallsInFlightCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetFileDigestCacheHits() uint64 {
+	if x != nil {
+		return x.FileDigestCacheHits
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetFileDigestCacheMisses() uint64 {
+	if x != nil {
+		return x.FileDigestCacheMisses
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetFileDigestCacheEntriesCount() int64 {
+	if x != nil {
+		return x.FileDigestCacheEntriesCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetNameInternerHits() uint64 {
+	if x != nil {
+		return x.NameInternerHits
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetNameInternerMisses() uint64 {
+	if x != nil {
+		return x.NameInternerMisses
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetInternedNamesCount() int64 {
+	if x != nil {
+		return x.InternedNamesCount
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetEstimatedOutputPathsMemoryUsageBytes() int64 {
+	if x != nil {
+		return x.EstimatedOutputPathsMemoryUsageBytes
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetEstimatedFileDigestCacheMemoryUsageBytes() int64 {
+	if x != nil {
+		return x.EstimatedFileDigestCacheMemoryUsageBytes
+	}
+	return 0
+}
+
+func (x *GetRuntimeStatisticsResponse) GetEstimatedNameInternerMemoryUsageBytes() int64 {
+	if x != nil {
+		return x.EstimatedNameInternerMemoryUsageBytes
+	}
+	return 0
+}
+
+type GetOutputPathLastAccessTimeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetOutputPathLastAccessTimeRequest) Reset() {
+	*x = GetOutputPathLastAccessTimeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathLastAccessTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathLastAccessTimeRequest) ProtoMessage() {}
+
+func (x *GetOutputPathLastAccessTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathLastAccessTimeRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputPathLastAccessTimeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetOutputPathLastAccessTimeRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetOutputPathLastAccessTimeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LastAccessTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=last_access_time,json=lastAccessTime,proto3" json:"last_access_time,omitempty"`
+}
+
+func (x *GetOutputPathLastAccessTimeResponse) Reset() {
+	*x = GetOutputPathLastAccessTimeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathLastAccessTimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathLastAccessTimeResponse) ProtoMessage() {}
+
+func (x *GetOutputPathLastAccessTimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathLastAccessTimeResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputPathLastAccessTimeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetOutputPathLastAccessTimeResponse) GetLastAccessTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessTime
+	}
+	return nil
+}
+
+type GetOutputBaseStatisticsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetOutputBaseStatisticsRequest) Reset() {
+	*x = GetOutputBaseStatisticsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputBaseStatisticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputBaseStatisticsRequest) ProtoMessage() {}
+
+func (x *GetOutputBaseStatisticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputBaseStatisticsRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputBaseStatisticsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetOutputBaseStatisticsRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetOutputBaseStatisticsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FilesCount         int64                  `protobuf:"varint,1,opt,name=files_count,json=filesCount,proto3" json:"files_count,omitempty"`
+	DirectoriesCount   int64                  `protobuf:"varint,2,opt,name=directories_count,json=directoriesCount,proto3" json:"directories_count,omitempty"`
+	SymlinksCount      int64                  `protobuf:"varint,3,opt,name=symlinks_count,json=symlinksCount,proto3" json:"symlinks_count,omitempty"`
+	SizeBytes          int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	LastBuildStartTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_build_start_time,json=lastBuildStartTime,proto3" json:"last_build_start_time,omitempty"`
+}
+
+func (x *GetOutputBaseStatisticsResponse) Reset() {
+	*x = GetOutputBaseStatisticsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputBaseStatisticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputBaseStatisticsResponse) ProtoMessage() {}
+
+func (x *GetOutputBaseStatisticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputBaseStatisticsResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputBaseStatisticsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetOutputBaseStatisticsResponse) GetFilesCount() int64 {
+	if x != nil {
+		return x.FilesCount
+	}
+	return 0
+}
+
+func (x *GetOutputBaseStatisticsResponse) GetDirectoriesCount() int64 {
+	if x != nil {
+		return x.DirectoriesCount
+	}
+	return 0
+}
+
+func (x *GetOutputBaseStatisticsResponse) GetSymlinksCount() int64 {
+	if x != nil {
+		return x.SymlinksCount
+	}
+	return 0
+}
+
+func (x *GetOutputBaseStatisticsResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *GetOutputBaseStatisticsResponse) GetLastBuildStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastBuildStartTime
+	}
+	return nil
+}
+
+type GetBuildStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetBuildStatusRequest) Reset() {
+	*x = GetBuildStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBuildStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuildStatusRequest) ProtoMessage() {}
+
+func (x *GetBuildStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuildStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetBuildStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetBuildStatusRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetBuildStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Phase                GetBuildStatusResponse_Phase `protobuf:"varint,1,opt,name=phase,proto3,enum=buildbarn.clientd.GetBuildStatusResponse_Phase" json:"phase,omitempty"`
+	ChildrenScannedCount uint64                       `protobuf:"varint,2,opt,name=children_scanned_count,json=childrenScannedCount,proto3" json:"children_scanned_count,omitempty"`
+	ChildrenRemovedCount uint64                       `protobuf:"varint,3,opt,name=children_removed_count,json=childrenRemovedCount,proto3" json:"children_removed_count,omitempty"`
+	Error                *status.Status               `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *GetBuildStatusResponse) Reset() {
+	*x = GetBuildStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBuildStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuildStatusResponse) ProtoMessage() {}
+
+func (x *GetBuildStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuildStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetBuildStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetBuildStatusResponse) GetPhase() GetBuildStatusResponse_Phase {
+	if x != nil {
+		return x.Phase
+	}
+	return GetBuildStatusResponse_FILTERING
+}
+
+func (x *GetBuildStatusResponse) GetChildrenScannedCount() uint64 {
+	if x != nil {
+		return x.ChildrenScannedCount
+	}
+	return 0
+}
+
+func (x *GetBuildStatusResponse) GetChildrenRemovedCount() uint64 {
+	if x != nil {
+		return x.ChildrenRemovedCount
+	}
+	return 0
+}
+
+func (x *GetBuildStatusResponse) GetError() *status.Status {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type GetMissingBlobsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetMissingBlobsRequest) Reset() {
+	*x = GetMissingBlobsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMissingBlobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMissingBlobsRequest) ProtoMessage() {}
+
+func (x *GetMissingBlobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMissingBlobsRequest.ProtoReflect.Descriptor instead.
+func (*GetMissingBlobsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetMissingBlobsRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetMissingBlobsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MissingBlobDigests []*v2.Digest `protobuf:"bytes,1,rep,name=missing_blob_digests,json=missingBlobDigests,proto3" json:"missing_blob_digests,omitempty"`
+}
+
+func (x *GetMissingBlobsResponse) Reset() {
+	*x = GetMissingBlobsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMissingBlobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMissingBlobsResponse) ProtoMessage() {}
+
+func (x *GetMissingBlobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMissingBlobsResponse.ProtoReflect.Descriptor instead.
+func (*GetMissingBlobsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetMissingBlobsResponse) GetMissingBlobDigests() []*v2.Digest {
+	if x != nil {
+		return x.MissingBlobDigests
+	}
+	return nil
+}
+
+type GetExecutableBitChangesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetExecutableBitChangesRequest) Reset() {
+	*x = GetExecutableBitChangesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetExecutableBitChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutableBitChangesRequest) ProtoMessage() {}
+
+func (x *GetExecutableBitChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutableBitChangesRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutableBitChangesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetExecutableBitChangesRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetExecutableBitChangesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+}
+
+func (x *GetExecutableBitChangesResponse) Reset() {
+	*x = GetExecutableBitChangesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetExecutableBitChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutableBitChangesResponse) ProtoMessage() {}
+
+func (x *GetExecutableBitChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutableBitChangesResponse.ProtoReflect.Descriptor instead.
+func (*GetExecutableBitChangesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetExecutableBitChangesResponse) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+type GetInitialOutputPathContentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetInitialOutputPathContentsRequest) Reset() {
+	*x = GetInitialOutputPathContentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInitialOutputPathContentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInitialOutputPathContentsRequest) ProtoMessage() {}
+
+func (x *GetInitialOutputPathContentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInitialOutputPathContentsRequest.ProtoReflect.Descriptor instead.
+func (*GetInitialOutputPathContentsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetInitialOutputPathContentsRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetInitialOutputPathContentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeDigest *v2.Digest `protobuf:"bytes,1,opt,name=tree_digest,json=treeDigest,proto3" json:"tree_digest,omitempty"`
+}
+
+func (x *GetInitialOutputPathContentsResponse) Reset() {
+	*x = GetInitialOutputPathContentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInitialOutputPathContentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInitialOutputPathContentsResponse) ProtoMessage() {}
+
+func (x *GetInitialOutputPathContentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInitialOutputPathContentsResponse.ProtoReflect.Descriptor instead.
+func (*GetInitialOutputPathContentsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetInitialOutputPathContentsResponse) GetTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.TreeDigest
+	}
+	return nil
+}
+
+type GetBuildErrorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetBuildErrorsRequest) Reset() {
+	*x = GetBuildErrorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBuildErrorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuildErrorsRequest) ProtoMessage() {}
+
+func (x *GetBuildErrorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuildErrorsRequest.ProtoReflect.Descriptor instead.
+func (*GetBuildErrorsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetBuildErrorsRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetBuildErrorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Errors []*status.Status `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *GetBuildErrorsResponse) Reset() {
+	*x = GetBuildErrorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBuildErrorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuildErrorsResponse) ProtoMessage() {}
+
+func (x *GetBuildErrorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuildErrorsResponse.ProtoReflect.Descriptor instead.
+func (*GetBuildErrorsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetBuildErrorsResponse) GetErrors() []*status.Status {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type GetOutputBasePathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetOutputBasePathRequest) Reset() {
+	*x = GetOutputBasePathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputBasePathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputBasePathRequest) ProtoMessage() {}
+
+func (x *GetOutputBasePathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputBasePathRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputBasePathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetOutputBasePathRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetOutputBasePathResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBasePath string `protobuf:"bytes,1,opt,name=output_base_path,json=outputBasePath,proto3" json:"output_base_path,omitempty"`
+}
+
+func (x *GetOutputBasePathResponse) Reset() {
+	*x = GetOutputBasePathResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputBasePathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputBasePathResponse) ProtoMessage() {}
+
+func (x *GetOutputBasePathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputBasePathResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputBasePathResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetOutputBasePathResponse) GetOutputBasePath() string {
+	if x != nil {
+		return x.OutputBasePath
+	}
+	return ""
+}
+
+type PingBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *PingBuildRequest) Reset() {
+	*x = PingBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingBuildRequest) ProtoMessage() {}
+
+func (x *PingBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingBuildRequest.ProtoReflect.Descriptor instead.
+func (*PingBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PingBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type WatchBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId       string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	AfterChangeId uint64 `protobuf:"varint,2,opt,name=after_change_id,json=afterChangeId,proto3" json:"after_change_id,omitempty"`
+}
+
+func (x *WatchBuildRequest) Reset() {
+	*x = WatchBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBuildRequest) ProtoMessage() {}
+
+func (x *WatchBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBuildRequest.ProtoReflect.Descriptor instead.
+func (*WatchBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *WatchBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *WatchBuildRequest) GetAfterChangeId() uint64 {
+	if x != nil {
+		return x.AfterChangeId
+	}
+	return 0
+}
+
+type BuildEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChangeId uint64          `protobuf:"varint,1,opt,name=change_id,json=changeId,proto3" json:"change_id,omitempty"`
+	Type     BuildEvent_Type `protobuf:"varint,2,opt,name=type,proto3,enum=buildbarn.clientd.BuildEvent_Type" json:"type,omitempty"`
+	Digest   *v2.Digest      `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	Path     string          `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *BuildEvent) Reset() {
+	*x = BuildEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildEvent) ProtoMessage() {}
+
+func (x *BuildEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildEvent.ProtoReflect.Descriptor instead.
+func (*BuildEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *BuildEvent) GetChangeId() uint64 {
+	if x != nil {
+		return x.ChangeId
+	}
+	return 0
+}
+
+func (x *BuildEvent) GetType() BuildEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return BuildEvent_CHILD_REMOVED
+}
+
+func (x *BuildEvent) GetDigest() *v2.Digest {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type BatchCreateWithIdempotencyTokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchCreate      *remoteoutputservice.BatchCreateRequest `protobuf:"bytes,1,opt,name=batch_create,json=batchCreate,proto3" json:"batch_create,omitempty"`
+	IdempotencyToken string                                  `protobuf:"bytes,2,opt,name=idempotency_token,json=idempotencyToken,proto3" json:"idempotency_token,omitempty"`
+}
+
+func (x *BatchCreateWithIdempotencyTokenRequest) Reset() {
+	*x = BatchCreateWithIdempotencyTokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateWithIdempotencyTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateWithIdempotencyTokenRequest) ProtoMessage() {}
+
+func (x *BatchCreateWithIdempotencyTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateWithIdempotencyTokenRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateWithIdempotencyTokenRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *BatchCreateWithIdempotencyTokenRequest) GetBatchCreate() *remoteoutputservice.BatchCreateRequest {
+	if x != nil {
+		return x.BatchCreate
+	}
+	return nil
+}
+
+func (x *BatchCreateWithIdempotencyTokenRequest) GetIdempotencyToken() string {
+	if x != nil {
+		return x.IdempotencyToken
+	}
+	return ""
+}
+
+type BatchCreateWithInlineTreesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchCreate *remoteoutputservice.BatchCreateRequest `protobuf:"bytes,1,opt,name=batch_create,json=batchCreate,proto3" json:"batch_create,omitempty"`
+	InlineTrees [][]byte                                `protobuf:"bytes,2,rep,name=inline_trees,json=inlineTrees,proto3" json:"inline_trees,omitempty"`
+}
+
+func (x *BatchCreateWithInlineTreesRequest) Reset() {
+	*x = BatchCreateWithInlineTreesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateWithInlineTreesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateWithInlineTreesRequest) ProtoMessage() {}
+
+func (x *BatchCreateWithInlineTreesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateWithInlineTreesRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateWithInlineTreesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *BatchCreateWithInlineTreesRequest) GetBatchCreate() *remoteoutputservice.BatchCreateRequest {
+	if x != nil {
+		return x.BatchCreate
+	}
+	return nil
+}
+
+func (x *BatchCreateWithInlineTreesRequest) GetInlineTrees() [][]byte {
+	if x != nil {
+		return x.InlineTrees
+	}
+	return nil
+}
+
+type BatchCreateContinuingOnErrorResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileErrors      []*status.Status `protobuf:"bytes,1,rep,name=file_errors,json=fileErrors,proto3" json:"file_errors,omitempty"`
+	DirectoryErrors []*status.Status `protobuf:"bytes,2,rep,name=directory_errors,json=directoryErrors,proto3" json:"directory_errors,omitempty"`
+	SymlinkErrors   []*status.Status `protobuf:"bytes,3,rep,name=symlink_errors,json=symlinkErrors,proto3" json:"symlink_errors,omitempty"`
+}
+
+func (x *BatchCreateContinuingOnErrorResponse) Reset() {
+	*x = BatchCreateContinuingOnErrorResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateContinuingOnErrorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateContinuingOnErrorResponse) ProtoMessage() {}
+
+func (x *BatchCreateContinuingOnErrorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateContinuingOnErrorResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateContinuingOnErrorResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BatchCreateContinuingOnErrorResponse) GetFileErrors() []*status.Status {
+	if x != nil {
+		return x.FileErrors
+	}
+	return nil
+}
+
+func (x *BatchCreateContinuingOnErrorResponse) GetDirectoryErrors() []*status.Status {
+	if x != nil {
+		return x.DirectoryErrors
+	}
+	return nil
+}
+
+func (x *BatchCreateContinuingOnErrorResponse) GetSymlinkErrors() []*status.Status {
+	if x != nil {
+		return x.SymlinkErrors
+	}
+	return nil
+}
+
+type BatchCreateLinksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string                          `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Links   []*BatchCreateLinksRequest_Link `protobuf:"bytes,2,rep,name=links,proto3" json:"links,omitempty"`
+}
+
+func (x *BatchCreateLinksRequest) Reset() {
+	*x = BatchCreateLinksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateLinksRequest) ProtoMessage() {}
+
+func (x *BatchCreateLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateLinksRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateLinksRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *BatchCreateLinksRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *BatchCreateLinksRequest) GetLinks() []*BatchCreateLinksRequest_Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type BatchRemoveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId   string   `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Paths     []string `protobuf:"bytes,2,rep,name=paths,proto3" json:"paths,omitempty"`
+	Recursive bool     `protobuf:"varint,3,opt,name=recursive,proto3" json:"recursive,omitempty"`
+}
+
+func (x *BatchRemoveRequest) Reset() {
+	*x = BatchRemoveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchRemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRemoveRequest) ProtoMessage() {}
+
+func (x *BatchRemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRemoveRequest.ProtoReflect.Descriptor instead.
+func (*BatchRemoveRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *BatchRemoveRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *BatchRemoveRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *BatchRemoveRequest) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+type BatchRemoveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Errors []*status.Status `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *BatchRemoveResponse) Reset() {
+	*x = BatchRemoveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchRemoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRemoveResponse) ProtoMessage() {}
+
+func (x *BatchRemoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRemoveResponse.ProtoReflect.Descriptor instead.
+func (*BatchRemoveResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BatchRemoveResponse) GetErrors() []*status.Status {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type TrimBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *TrimBuildRequest) Reset() {
+	*x = TrimBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrimBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrimBuildRequest) ProtoMessage() {}
+
+func (x *TrimBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrimBuildRequest.ProtoReflect.Descriptor instead.
+func (*TrimBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *TrimBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type TrimBuildResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TrimmedDirectoriesCount int64 `protobuf:"varint,1,opt,name=trimmed_directories_count,json=trimmedDirectoriesCount,proto3" json:"trimmed_directories_count,omitempty"`
+}
+
+func (x *TrimBuildResponse) Reset() {
+	*x = TrimBuildResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrimBuildResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrimBuildResponse) ProtoMessage() {}
+
+func (x *TrimBuildResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrimBuildResponse.ProtoReflect.Descriptor instead.
+func (*TrimBuildResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *TrimBuildResponse) GetTrimmedDirectoriesCount() int64 {
+	if x != nil {
+		return x.TrimmedDirectoriesCount
+	}
+	return 0
+}
+
+type ExportPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId         string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	SourcePath      string `protobuf:"bytes,2,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	DestinationPath string `protobuf:"bytes,3,opt,name=destination_path,json=destinationPath,proto3" json:"destination_path,omitempty"`
+}
+
+func (x *ExportPathRequest) Reset() {
+	*x = ExportPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPathRequest) ProtoMessage() {}
+
+func (x *ExportPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPathRequest.ProtoReflect.Descriptor instead.
+func (*ExportPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ExportPathRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ExportPathRequest) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
+func (x *ExportPathRequest) GetDestinationPath() string {
+	if x != nil {
+		return x.DestinationPath
+	}
+	return ""
+}
+
+type BatchStatWithOptionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchStat           *remoteoutputservice.BatchStatRequest           `protobuf:"bytes,1,opt,name=batch_stat,json=batchStat,proto3" json:"batch_stat,omitempty"`
+	DigestInclusionMode BatchStatWithOptionsRequest_DigestInclusionMode `protobuf:"varint,2,opt,name=digest_inclusion_mode,json=digestInclusionMode,proto3,enum=buildbarn.clientd.BatchStatWithOptionsRequest_DigestInclusionMode" json:"digest_inclusion_mode,omitempty"`
+}
+
+func (x *BatchStatWithOptionsRequest) Reset() {
+	*x = BatchStatWithOptionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStatWithOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStatWithOptionsRequest) ProtoMessage() {}
+
+func (x *BatchStatWithOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStatWithOptionsRequest.ProtoReflect.Descriptor instead.
+func (*BatchStatWithOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *BatchStatWithOptionsRequest) GetBatchStat() *remoteoutputservice.BatchStatRequest {
+	if x != nil {
+		return x.BatchStat
+	}
+	return nil
+}
+
+func (x *BatchStatWithOptionsRequest) GetDigestInclusionMode() BatchStatWithOptionsRequest_DigestInclusionMode {
+	if x != nil {
+		return x.DigestInclusionMode
+	}
+	return BatchStatWithOptionsRequest_NEVER
+}
+
+type BatchStatWithExecutableBitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchStat      *remoteoutputservice.BatchStatResponse `protobuf:"bytes,1,opt,name=batch_stat,json=batchStat,proto3" json:"batch_stat,omitempty"`
+	ExecutableBits []bool                                 `protobuf:"varint,2,rep,packed,name=executable_bits,json=executableBits,proto3" json:"executable_bits,omitempty"`
+}
+
+func (x *BatchStatWithExecutableBitsResponse) Reset() {
+	*x = BatchStatWithExecutableBitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStatWithExecutableBitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStatWithExecutableBitsResponse) ProtoMessage() {}
+
+func (x *BatchStatWithExecutableBitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStatWithExecutableBitsResponse.ProtoReflect.Descriptor instead.
+func (*BatchStatWithExecutableBitsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *BatchStatWithExecutableBitsResponse) GetBatchStat() *remoteoutputservice.BatchStatResponse {
+	if x != nil {
+		return x.BatchStat
+	}
+	return nil
+}
+
+func (x *BatchStatWithExecutableBitsResponse) GetExecutableBits() []bool {
+	if x != nil {
+		return x.ExecutableBits
+	}
+	return nil
+}
+
+type BatchStatWithMissingPathDetailsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchStat         *remoteoutputservice.BatchStatResponse `protobuf:"bytes,1,opt,name=batch_stat,json=batchStat,proto3" json:"batch_stat,omitempty"`
+	MissingPathErrors []*status.Status                       `protobuf:"bytes,2,rep,name=missing_path_errors,json=missingPathErrors,proto3" json:"missing_path_errors,omitempty"`
+}
+
+func (x *BatchStatWithMissingPathDetailsResponse) Reset() {
+	*x = BatchStatWithMissingPathDetailsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStatWithMissingPathDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStatWithMissingPathDetailsResponse) ProtoMessage() {}
+
+func (x *BatchStatWithMissingPathDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStatWithMissingPathDetailsResponse.ProtoReflect.Descriptor instead.
+func (*BatchStatWithMissingPathDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *BatchStatWithMissingPathDetailsResponse) GetBatchStat() *remoteoutputservice.BatchStatResponse {
+	if x != nil {
+		return x.BatchStat
+	}
+	return nil
+}
+
+func (x *BatchStatWithMissingPathDetailsResponse) GetMissingPathErrors() []*status.Status {
+	if x != nil {
+		return x.MissingPathErrors
+	}
+	return nil
+}
+
+type BatchStatPartialResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchStat             *remoteoutputservice.BatchStatResponse `protobuf:"bytes,1,opt,name=batch_stat,json=batchStat,proto3" json:"batch_stat,omitempty"`
+	UnprocessedPathsCount int64                                  `protobuf:"varint,2,opt,name=unprocessed_paths_count,json=unprocessedPathsCount,proto3" json:"unprocessed_paths_count,omitempty"`
+}
+
+func (x *BatchStatPartialResponse) Reset() {
+	*x = BatchStatPartialResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStatPartialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStatPartialResponse) ProtoMessage() {}
+
+func (x *BatchStatPartialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStatPartialResponse.ProtoReflect.Descriptor instead.
+func (*BatchStatPartialResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *BatchStatPartialResponse) GetBatchStat() *remoteoutputservice.BatchStatResponse {
+	if x != nil {
+		return x.BatchStat
+	}
+	return nil
+}
+
+func (x *BatchStatPartialResponse) GetUnprocessedPathsCount() int64 {
+	if x != nil {
+		return x.UnprocessedPathsCount
+	}
+	return 0
+}
+
+type BatchStatExistenceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PathExistences []BatchStatExistenceResponse_PathExistence `protobuf:"varint,1,rep,packed,name=path_existences,json=pathExistences,proto3,enum=buildbarn.clientd.BatchStatExistenceResponse_PathExistence" json:"path_existences,omitempty"`
+}
+
+func (x *BatchStatExistenceResponse) Reset() {
+	*x = BatchStatExistenceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStatExistenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStatExistenceResponse) ProtoMessage() {}
+
+func (x *BatchStatExistenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStatExistenceResponse.ProtoReflect.Descriptor instead.
+func (*BatchStatExistenceResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *BatchStatExistenceResponse) GetPathExistences() []BatchStatExistenceResponse_PathExistence {
+	if x != nil {
+		return x.PathExistences
+	}
+	return nil
+}
+
+type ReadDirectoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId            string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	DirectoryPath      string `protobuf:"bytes,2,opt,name=directory_path,json=directoryPath,proto3" json:"directory_path,omitempty"`
+	IncludeFileDigests bool   `protobuf:"varint,3,opt,name=include_file_digests,json=includeFileDigests,proto3" json:"include_file_digests,omitempty"`
+}
+
+func (x *ReadDirectoryRequest) Reset() {
+	*x = ReadDirectoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirectoryRequest) ProtoMessage() {}
+
+func (x *ReadDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*ReadDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ReadDirectoryRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ReadDirectoryRequest) GetDirectoryPath() string {
+	if x != nil {
+		return x.DirectoryPath
+	}
+	return ""
+}
+
+func (x *ReadDirectoryRequest) GetIncludeFileDigests() bool {
+	if x != nil {
+		return x.IncludeFileDigests
+	}
+	return false
+}
+
+type ReadDirectoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*ReadDirectoryResponse_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ReadDirectoryResponse) Reset() {
+	*x = ReadDirectoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirectoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirectoryResponse) ProtoMessage() {}
+
+func (x *ReadDirectoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirectoryResponse.ProtoReflect.Descriptor instead.
+func (*ReadDirectoryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ReadDirectoryResponse) GetEntries() []*ReadDirectoryResponse_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetFileContentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId          string   `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Paths            []string `protobuf:"bytes,2,rep,name=paths,proto3" json:"paths,omitempty"`
+	MaximumSizeBytes uint64   `protobuf:"varint,3,opt,name=maximum_size_bytes,json=maximumSizeBytes,proto3" json:"maximum_size_bytes,omitempty"`
+}
+
+func (x *GetFileContentsRequest) Reset() {
+	*x = GetFileContentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileContentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileContentsRequest) ProtoMessage() {}
+
+func (x *GetFileContentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileContentsRequest.ProtoReflect.Descriptor instead.
+func (*GetFileContentsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetFileContentsRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *GetFileContentsRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *GetFileContentsRequest) GetMaximumSizeBytes() uint64 {
+	if x != nil {
+		return x.MaximumSizeBytes
+	}
+	return 0
+}
+
+type GetFileContentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileContents []*GetFileContentsResponse_FileContents `protobuf:"bytes,1,rep,name=file_contents,json=fileContents,proto3" json:"file_contents,omitempty"`
+}
+
+func (x *GetFileContentsResponse) Reset() {
+	*x = GetFileContentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileContentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileContentsResponse) ProtoMessage() {}
+
+func (x *GetFileContentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileContentsResponse.ProtoReflect.Descriptor instead.
+func (*GetFileContentsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetFileContentsResponse) GetFileContents() []*GetFileContentsResponse_FileContents {
+	if x != nil {
+		return x.FileContents
+	}
+	return nil
+}
+
+type ReadFileRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId   string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	FilePath  string `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Offset    uint64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	SizeBytes uint64 `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+}
+
+func (x *ReadFileRangeRequest) Reset() {
+	*x = ReadFileRangeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadFileRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileRangeRequest) ProtoMessage() {}
+
+func (x *ReadFileRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileRangeRequest.ProtoReflect.Descriptor instead.
+func (*ReadFileRangeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ReadFileRangeRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ReadFileRangeRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *ReadFileRangeRequest) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadFileRangeRequest) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+type ReadFileRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Contents []byte `protobuf:"bytes,1,opt,name=contents,proto3" json:"contents,omitempty"`
+}
+
+func (x *ReadFileRangeResponse) Reset() {
+	*x = ReadFileRangeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadFileRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileRangeResponse) ProtoMessage() {}
+
+func (x *ReadFileRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileRangeResponse.ProtoReflect.Descriptor instead.
+func (*ReadFileRangeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ReadFileRangeResponse) GetContents() []byte {
+	if x != nil {
+		return x.Contents
+	}
+	return nil
+}
+
+type ResolveSymlinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId      string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	AbsolutePath string `protobuf:"bytes,2,opt,name=absolute_path,json=absolutePath,proto3" json:"absolute_path,omitempty"`
+}
+
+func (x *ResolveSymlinkRequest) Reset() {
+	*x = ResolveSymlinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveSymlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveSymlinkRequest) ProtoMessage() {}
+
+func (x *ResolveSymlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveSymlinkRequest.ProtoReflect.Descriptor instead.
+func (*ResolveSymlinkRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ResolveSymlinkRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ResolveSymlinkRequest) GetAbsolutePath() string {
+	if x != nil {
+		return x.AbsolutePath
+	}
+	return ""
+}
+
+type FinalizeBuildWithStatisticsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CasFileBytesRead      uint64 `protobuf:"varint,1,opt,name=cas_file_bytes_read,json=casFileBytesRead,proto3" json:"cas_file_bytes_read,omitempty"`
+	DirectoryBytesFetched uint64 `protobuf:"varint,2,opt,name=directory_bytes_fetched,json=directoryBytesFetched,proto3" json:"directory_bytes_fetched,omitempty"`
+}
+
+func (x *FinalizeBuildWithStatisticsResponse) Reset() {
+	*x = FinalizeBuildWithStatisticsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeBuildWithStatisticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeBuildWithStatisticsResponse) ProtoMessage() {}
+
+func (x *FinalizeBuildWithStatisticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeBuildWithStatisticsResponse.ProtoReflect.Descriptor instead.
+func (*FinalizeBuildWithStatisticsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *FinalizeBuildWithStatisticsResponse) GetCasFileBytesRead() uint64 {
+	if x != nil {
+		return x.CasFileBytesRead
+	}
+	return 0
+}
+
+func (x *FinalizeBuildWithStatisticsResponse) GetDirectoryBytesFetched() uint64 {
+	if x != nil {
+		return x.DirectoryBytesFetched
+	}
+	return 0
+}
+
+type FinalizeBuildWithRemovedChildrenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RemovedChildDigests []*v2.Digest `protobuf:"bytes,1,rep,name=removed_child_digests,json=removedChildDigests,proto3" json:"removed_child_digests,omitempty"`
+	Truncated           bool         `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`
+}
+
+func (x *FinalizeBuildWithRemovedChildrenResponse) Reset() {
+	*x = FinalizeBuildWithRemovedChildrenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeBuildWithRemovedChildrenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeBuildWithRemovedChildrenResponse) ProtoMessage() {}
+
+func (x *FinalizeBuildWithRemovedChildrenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeBuildWithRemovedChildrenResponse.ProtoReflect.Descriptor instead.
+func (*FinalizeBuildWithRemovedChildrenResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *FinalizeBuildWithRemovedChildrenResponse) GetRemovedChildDigests() []*v2.Digest {
+	if x != nil {
+		return x.RemovedChildDigests
+	}
+	return nil
+}
+
+func (x *FinalizeBuildWithRemovedChildrenResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type FinalizeBuildWithSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FinalizeBuild *remoteoutputservice.FinalizeBuildRequest `protobuf:"bytes,1,opt,name=finalize_build,json=finalizeBuild,proto3" json:"finalize_build,omitempty"`
+	SnapshotName  string                                    `protobuf:"bytes,2,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+}
+
+func (x *FinalizeBuildWithSnapshotRequest) Reset() {
+	*x = FinalizeBuildWithSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeBuildWithSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeBuildWithSnapshotRequest) ProtoMessage() {}
+
+func (x *FinalizeBuildWithSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeBuildWithSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*FinalizeBuildWithSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *FinalizeBuildWithSnapshotRequest) GetFinalizeBuild() *remoteoutputservice.FinalizeBuildRequest {
+	if x != nil {
+		return x.FinalizeBuild
+	}
+	return nil
+}
+
+func (x *FinalizeBuildWithSnapshotRequest) GetSnapshotName() string {
+	if x != nil {
+		return x.SnapshotName
+	}
+	return ""
+}
+
+type CancelBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId          string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	RevertOutputPath bool   `protobuf:"varint,2,opt,name=revert_output_path,json=revertOutputPath,proto3" json:"revert_output_path,omitempty"`
+}
+
+func (x *CancelBuildRequest) Reset() {
+	*x = CancelBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBuildRequest) ProtoMessage() {}
+
+func (x *CancelBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBuildRequest.ProtoReflect.Descriptor instead.
+func (*CancelBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *CancelBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *CancelBuildRequest) GetRevertOutputPath() bool {
+	if x != nil {
+		return x.RevertOutputPath
+	}
+	return false
+}
+
+type DiagnoseSelfTestResponse_StageResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stage  DiagnoseSelfTestResponse_StageResult_Stage `protobuf:"varint,1,opt,name=stage,proto3,enum=buildbarn.clientd.DiagnoseSelfTestResponse_StageResult_Stage" json:"stage,omitempty"`
+	Status *status.Status                             `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *DiagnoseSelfTestResponse_StageResult) Reset() {
+	*x = DiagnoseSelfTestResponse_StageResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiagnoseSelfTestResponse_StageResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnoseSelfTestResponse_StageResult) ProtoMessage() {}
+
+func (x *DiagnoseSelfTestResponse_StageResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnoseSelfTestResponse_StageResult.ProtoReflect.Descriptor instead.
+func (*DiagnoseSelfTestResponse_StageResult) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{16, 0}
+}
+
+func (x *DiagnoseSelfTestResponse_StageResult) GetStage() DiagnoseSelfTestResponse_StageResult_Stage {
+	if x != nil {
+		return x.Stage
+	}
+	return DiagnoseSelfTestResponse_StageResult_UPLOAD
+}
+
+func (x *DiagnoseSelfTestResponse_StageResult) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type BatchCreateLinksRequest_Link struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourcePath      string `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	DestinationPath string `protobuf:"bytes,2,opt,name=destination_path,json=destinationPath,proto3" json:"destination_path,omitempty"`
+}
+
+func (x *BatchCreateLinksRequest_Link) Reset() {
+	*x = BatchCreateLinksRequest_Link{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateLinksRequest_Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateLinksRequest_Link) ProtoMessage() {}
+
+func (x *BatchCreateLinksRequest_Link) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateLinksRequest_Link.ProtoReflect.Descriptor instead.
+func (*BatchCreateLinksRequest_Link) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{41, 0}
+}
+
+func (x *BatchCreateLinksRequest_Link) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
+func (x *BatchCreateLinksRequest_Link) GetDestinationPath() string {
+	if x != nil {
+		return x.DestinationPath
+	}
+	return ""
+}
+
+type ReadDirectoryResponse_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string                          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FileStatus *remoteoutputservice.FileStatus `protobuf:"bytes,2,opt,name=file_status,json=fileStatus,proto3" json:"file_status,omitempty"`
+}
+
+func (x *ReadDirectoryResponse_Entry) Reset() {
+	*x = ReadDirectoryResponse_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirectoryResponse_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirectoryResponse_Entry) ProtoMessage() {}
+
+func (x *ReadDirectoryResponse_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirectoryResponse_Entry.ProtoReflect.Descriptor instead.
+func (*ReadDirectoryResponse_Entry) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{53, 0}
+}
+
+func (x *ReadDirectoryResponse_Entry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReadDirectoryResponse_Entry) GetFileStatus() *remoteoutputservice.FileStatus {
+	if x != nil {
+		return x.FileStatus
+	}
+	return nil
+}
+
+type GetFileContentsResponse_FileContents struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to ContentsOrDigest:
+	//	*GetFileContentsResponse_FileContents_Contents
+	//	*GetFileContentsResponse_FileContents_Digest
+	ContentsOrDigest isGetFileContentsResponse_FileContents_ContentsOrDigest `protobuf_oneof:"contents_or_digest"`
+}
+
+func (x *GetFileContentsResponse_FileContents) Reset() {
+	*x = GetFileContentsResponse_FileContents{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileContentsResponse_FileContents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileContentsResponse_FileContents) ProtoMessage() {}
+
+func (x *GetFileContentsResponse_FileContents) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_clientd_clientd_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileContentsResponse_FileContents.ProtoReflect.Descriptor instead.
+func (*GetFileContentsResponse_FileContents) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_clientd_clientd_proto_rawDescGZIP(), []int{55, 0}
+}
+
+func (m *GetFileContentsResponse_FileContents) GetContentsOrDigest() isGetFileContentsResponse_FileContents_ContentsOrDigest {
+	if m != nil {
+		return m.ContentsOrDigest
+	}
+	return nil
+}
+
+func (x *GetFileContentsResponse_FileContents) GetContents() []byte {
+	if x, ok := x.GetContentsOrDigest().(*GetFileContentsResponse_FileContents_Contents); ok {
+		return x.Contents
+	}
+	return nil
+}
+
+func (x *GetFileContentsResponse_FileContents) GetDigest() *v2.Digest {
+	if x, ok := x.GetContentsOrDigest().(*GetFileContentsResponse_FileContents_Digest); ok {
+		return x.Digest
+	}
+	return nil
+}
+
+type isGetFileContentsResponse_FileContents_ContentsOrDigest interface {
+	isGetFileContentsResponse_FileContents_ContentsOrDigest()
+}
+
+type GetFileContentsResponse_FileContents_Contents struct {
+	Contents []byte `protobuf:"bytes,1,opt,name=contents,proto3,oneof"`
+}
+
+type GetFileContentsResponse_FileContents_Digest struct {
+	Digest *v2.Digest `protobuf:"bytes,2,opt,name=digest,proto3,oneof"`
+}
+
+func (*GetFileContentsResponse_FileContents_Contents) isGetFileContentsResponse_FileContents_ContentsOrDigest() {
+}
+
+func (*GetFileContentsResponse_FileContents_Digest) isGetFileContentsResponse_FileContents_ContentsOrDigest() {
+}
+
+var File_pkg_proto_clientd_clientd_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_clientd_clientd_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x11, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x64, 0x1a, 0x36, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x61, 0x7a, 0x65,
+	0x6c, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x65, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d,
+	0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x39, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x41,
+	0x0a, 0x0d, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x30, 0x0a, 0x14, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x6c, 0x65, 0x61, 0x76, 0x65,
+	0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x5f, 0x0a, 0x16, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x22, 0x80, 0x01, 0x0a, 0x12, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12,
+	0x44, 0x0a, 0x1f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x5f, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1b, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75,
+	0x6d, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x4c, 0x65, 0x61, 0x66, 0x50, 0x61, 0x74, 0x68, 0x73,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x91, 0x01, 0x0a, 0x13, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44,
+	0x72, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a,
+	0x11, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x65,
+	0x61, 0x76, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0b, 0x6c, 0x65, 0x61, 0x76, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a,
+	0x11, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x4c, 0x65, 0x61, 0x66, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x1d, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x49, 0x0a, 0x0b, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x50, 0x0a, 0x0f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76,
+	0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xd3, 0x01, 0x0a, 0x24, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x49, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x60, 0x0a, 0x10,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62,
+	0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46,
+	0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xd3,
+	0x01, 0x0a, 0x25, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74,
+	0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x12, 0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66,
+	0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x88, 0x01, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x49, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0a,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22,
+	0xc5, 0x01, 0x0a, 0x18, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x42, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65,
+	0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46,
+	0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x40, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61,
+	0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x65, 0x0a, 0x19, 0x47, 0x65, 0x74,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x74, 0x72, 0x65, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x22, 0xf2, 0x01, 0x0a, 0x16, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49,
+	0x64, 0x12, 0x48, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x30, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x44, 0x0a, 0x10, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d,
+	0x65, 0x22, 0x22, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x09, 0x0a, 0x05, 0x43,
+	0x4c, 0x45, 0x41, 0x4e, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x52, 0x52, 0x55, 0x50,
+	0x54, 0x45, 0x44, 0x10, 0x01, 0x22, 0x42, 0x0a, 0x12, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xa6, 0x01, 0x0a, 0x16, 0x43, 0x6c,
+	0x6f, 0x6e, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x15, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x12, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x3b, 0x0a, 0x1a, 0x64, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61,
+	0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x17, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61,
+	0x73, 0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x22, 0x89, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31,
+	0x0a, 0x15, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49,
+	0x64, 0x12, 0x3b, 0x0a, 0x1a, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x17, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x9e,
+	0x01, 0x0a, 0x17, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x6c, 0x66, 0x54,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0xe6, 0x02, 0x0a, 0x18, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x6c, 0x66,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x0d,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65,
+	0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x0c, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x1a, 0xeb, 0x01, 0x0a, 0x0b, 0x53,
+	0x74, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x53, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x3d, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x44, 0x69,
+	0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x12,
+	0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x5b, 0x0a, 0x05, 0x53,
+	0x74, 0x61, 0x67, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x00,
+	0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x54, 0x41, 0x52, 0x54, 0x5f, 0x42, 0x55, 0x49, 0x4c, 0x44, 0x10,
+	0x01, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54,
+	0x45, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x52, 0x45, 0x41, 0x44, 0x10, 0x04, 0x12, 0x09, 0x0a,
+	0x05, 0x43, 0x4c, 0x45, 0x41, 0x4e, 0x10, 0x05, 0x22, 0x2f, 0x0a, 0x13, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x6e, 0x67, 0x22, 0xd1, 0x07, 0x0a, 0x1c, 0x47, 0x65,
+	0x74, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69,
+	0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x14, 0x72, 0x75, 0x6e, 0x6e,
+	0x69, 0x6e, 0x67, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x1c, 0x63, 0x6f,
+	0x72, 0x72, 0x75, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x19, 0x63, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x19, 0x71,
+	0x75, 0x65, 0x75, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16,
+	0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x1c, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x19, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x19, 0x63, 0x61, 0x73, 0x5f, 0x63,
+	0x61, 0x6c, 0x6c, 0x73, 0x5f, 0x69, 0x6e, 0x5f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x63, 0x61, 0x73, 0x43,
+	0x61, 0x6c, 0x6c, 0x73, 0x49, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x33, 0x0a, 0x16, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x68, 0x69, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x13, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x48, 0x69, 0x74, 0x73, 0x12, 0x37, 0x0a, 0x18, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x6d, 0x69, 0x73, 0x73,
+	0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x43, 0x61, 0x63, 0x68, 0x65, 0x4d, 0x69, 0x73, 0x73, 0x65, 0x73, 0x12,
+	0x44, 0x0a, 0x1f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1b, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x43, 0x61, 0x63, 0x68, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x65, 0x72, 0x5f, 0x68, 0x69, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x10, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x72, 0x48,
+	0x69, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x65, 0x72, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x12, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x72, 0x4d,
+	0x69, 0x73, 0x73, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65,
+	0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x12, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x64, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x57, 0x0a, 0x29, 0x65, 0x73, 0x74, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x24, 0x65, 0x73, 0x74, 0x69,
+	0x6d, 0x61, 0x74, 0x65, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73,
+	0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x60, 0x0a, 0x2e, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x28, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x59, 0x0a, 0x2a, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x72, 0x5f, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x25, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x72, 0x4d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x4a, 0x0a,
+	0x22, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x4c, 0x61,
+	0x73, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61,
+	0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x6b, 0x0a, 0x23, 0x47, 0x65, 0x74,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x4c, 0x61, 0x73, 0x74, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x44, 0x0a, 0x10, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x46, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x84,
+	0x02, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69,
+	0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x25, 0x0a, 0x0e, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e,
+	0x6b, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x7a,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x15, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x32, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0xa4, 0x02, 0x0a, 0x16, 0x47, 0x65,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50,
+	0x68, 0x61, 0x73, 0x65, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x63,
+	0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x14, 0x63, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x53, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x5f, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x14, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x2d, 0x0a, 0x05, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x49,
+	0x4c, 0x54, 0x45, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x45, 0x41,
+	0x44, 0x59, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x02,
+	0x22, 0x33, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x42, 0x6c,
+	0x6f, 0x62, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x74, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6e, 0x67, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x59, 0x0a, 0x14, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x6c, 0x6f, 0x62,
+	0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32,
+	0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x12, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x42, 0x6c, 0x6f, 0x62, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0x3b, 0x0a, 0x1e, 0x47,
+	0x65, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x37, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x22, 0x40, 0x0a, 0x23, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x64, 0x22, 0x70, 0x0a, 0x24, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x74,
+	0x72, 0x65, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x74, 0x72, 0x65, 0x65, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x44, 0x0a, 0x16, 0x47, 0x65, 0x74,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22,
+	0x35, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65,
+	0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x45, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61,
+	0x73, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22, 0x2d, 0x0a,
+	0x10, 0x50, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x56, 0x0a, 0x11,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0f,
+	0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x61, 0x66, 0x74, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x49, 0x64, 0x22, 0xfa, 0x01, 0x0a, 0x0a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x49, 0x64,
+	0x12, 0x36, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x64, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x42, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x48, 0x49, 0x4c, 0x44, 0x5f, 0x52,
+	0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x44, 0x49, 0x52, 0x45,
+	0x43, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x01, 0x12,
+	0x10, 0x0a, 0x0c, 0x42, 0x4c, 0x4f, 0x42, 0x5f, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x10,
+	0x02, 0x22, 0xa3, 0x01, 0x0a, 0x26, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x57, 0x69, 0x74, 0x68, 0x49, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4c, 0x0a, 0x0c,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x69, 0x64,
+	0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x94, 0x01, 0x0a, 0x21, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x69, 0x74, 0x68, 0x49, 0x6e, 0x6c, 0x69, 0x6e,
+	0x65, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4c, 0x0a,
+	0x0c, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69,
+	0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x54, 0x72, 0x65, 0x65, 0x73, 0x22, 0xd5,
+	0x01, 0x0a, 0x24, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x69, 0x6e, 0x75, 0x69, 0x6e, 0x67, 0x4f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x5f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x3d, 0x0a, 0x10,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0f, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x39, 0x0a, 0x0e, 0x73,
+	0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0d, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0xcf, 0x01, 0x0a, 0x17, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x45, 0x0a,
+	0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x6c,
+	0x69, 0x6e, 0x6b, 0x73, 0x1a, 0x52, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x1f, 0x0a, 0x0b,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x29, 0x0a,
+	0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x22, 0x63, 0x0a, 0x12, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x22, 0x41, 0x0a,
+	0x13, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x22, 0x2d, 0x0a, 0x10, 0x54, 0x72, 0x69, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22,
+	0x4f, 0x0a, 0x11, 0x54, 0x72, 0x69, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x19, 0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64, 0x5f,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x17, 0x74, 0x72, 0x69, 0x6d, 0x6d, 0x65, 0x64,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0x7a, 0x0a, 0x11, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x22, 0xae, 0x02, 0x0a,
+	0x1b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x46, 0x0a, 0x0a,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x27, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x12, 0x76, 0x0a, 0x15, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x42, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73,
+	0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x13, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x49,
+	0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x22, 0x4f, 0x0a, 0x13,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x4d,
+	0x6f, 0x64, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x45, 0x56, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0a,
+	0x0a, 0x06, 0x41, 0x4c, 0x57, 0x41, 0x59, 0x53, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x46,
+	0x5f, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x41, 0x4c, 0x57, 0x41,
+	0x59, 0x53, 0x5f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x03, 0x22, 0x97, 0x01,
+	0x0a, 0x23, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x12, 0x27,
+	0x0a, 0x0f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x69, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x08, 0x52, 0x0e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x27, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x50, 0x61, 0x74, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x12, 0x42, 0x0a, 0x13,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x11, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x22, 0x9b, 0x01, 0x0a, 0x18, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x50, 0x61,
+	0x72, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a,
+	0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x62, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x75, 0x6e, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x75, 0x6e, 0x70, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb9,
+	0x01, 0x0a, 0x1a, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x45, 0x78, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a,
+	0x0f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x65, 0x78, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x3b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x45, 0x78, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x63, 0x65, 0x52, 0x0e, 0x70, 0x61, 0x74, 0x68, 0x45, 0x78, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x63, 0x65, 0x73, 0x22, 0x35, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x68, 0x45, 0x78, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x63, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x42, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x0b, 0x0a,
+	0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x02, 0x22, 0x8a, 0x01, 0x0a, 0x14, 0x52,
+	0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x25,
+	0x0a, 0x0e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x79, 0x50, 0x61, 0x74, 0x68, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0xc2, 0x01, 0x0a, 0x15, 0x52, 0x65, 0x61, 0x64,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x48, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x5f, 0x0a, 0x05, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x77, 0x0a, 0x16,
+	0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x69, 0x7a, 0x65,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xff, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c,
+	0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x5c, 0x0a, 0x0d, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74,
+	0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x0c, 0x66, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x1a,
+	0x85, 0x01, 0x0a, 0x0c, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x1c, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x41,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32,
+	0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x42, 0x14, 0x0a, 0x12, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x6f, 0x72,
+	0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x85, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x61, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22,
+	0x33, 0x0a, 0x15, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0x57, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x53,
+	0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x62, 0x73, 0x6f,
+	0x6c, 0x75, 0x74, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x61, 0x62, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22, 0x8c, 0x01,
+	0x0a, 0x23, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57,
+	0x69, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x13, 0x63, 0x61, 0x73, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x10, 0x63, 0x61, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x52, 0x65, 0x61, 0x64, 0x12, 0x36, 0x0a, 0x17, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x79, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x22, 0xa5, 0x01, 0x0a,
+	0x28, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69,
+	0x74, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x15, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x5f, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x52, 0x13, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x72, 0x75, 0x6e, 0x63,
+	0x61, 0x74, 0x65, 0x64, 0x22, 0x9b, 0x01, 0x0a, 0x20, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x52, 0x0a, 0x0e, 0x66, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2b, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0d,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x23, 0x0a,
+	0x0d, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x22, 0x5d, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x10, 0x72, 0x65, 0x76, 0x65, 0x72, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x32, 0x91, 0x28, 0x0a, 0x0c, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x65, 0x6d,
+	0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x0e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x12, 0x23, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6c, 0x65,
+	0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x6c,
+	0x65, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x30, 0x01, 0x12, 0x54, 0x0a,
+	0x0f, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x12, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x12, 0x5c, 0x0a, 0x0b, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x12, 0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x44, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x6c,
+	0x65, 0x61, 0x6e, 0x44, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x6f, 0x0a, 0x18, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41,
+	0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x6f, 0x75, 0x73, 0x6c, 0x79, 0x12, 0x28, 0x2e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x75, 0x0a, 0x16, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x46, 0x72, 0x6f, 0x6d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x30, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
+	0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29,
+	0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6d, 0x0a, 0x16, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x46, 0x6f, 0x72, 0x63, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x73, 0x65, 0x74, 0x12, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x92, 0x01, 0x0a, 0x1d, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x37, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x38, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x67, 0x0a,
+	0x0f, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x12, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x11, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72,
+	0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2b, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x12, 0x6e, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x54, 0x72, 0x65, 0x65, 0x12, 0x2b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x50, 0x61, 0x74, 0x68, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x5e, 0x0a, 0x17, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01,
+	0x12, 0x4b, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a,
+	0x0f, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x12, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x12, 0x56, 0x0a, 0x10, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6b, 0x0a, 0x10, 0x44,
+	0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73, 0x74, 0x12,
+	0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x6c, 0x66,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0b, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x52, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x52,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8c, 0x01, 0x0a, 0x1b, 0x47, 0x65, 0x74,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x4c, 0x61, 0x73, 0x74, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x4c, 0x61, 0x73, 0x74, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x36, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x4c, 0x61, 0x73, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x80, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x12, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x42, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69,
+	0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
+	0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x68, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x42,
+	0x6c, 0x6f, 0x62, 0x73, 0x12, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6e, 0x67, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x42, 0x6c,
+	0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x80, 0x01, 0x0a, 0x17,
+	0x47, 0x65, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47,
+	0x65, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8f,
+	0x01, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x36, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x65, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
+	0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6e, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2b, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
+	0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65,
+	0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x09, 0x50, 0x69, 0x6e, 0x67, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x12, 0x23, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x12, 0x53, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12,
+	0x24, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x74, 0x0a, 0x1f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x69, 0x74, 0x68, 0x49, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x39, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x69, 0x74, 0x68, 0x49, 0x64, 0x65,
+	0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6a, 0x0a, 0x1a,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x69, 0x74, 0x68, 0x49,
+	0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x54, 0x72, 0x65, 0x65, 0x73, 0x12, 0x34, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x69, 0x74, 0x68, 0x49, 0x6e,
+	0x6c, 0x69, 0x6e, 0x65, 0x54, 0x72, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x82, 0x01, 0x0a, 0x1c, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x69,
+	0x6e, 0x67, 0x4f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x69, 0x6e, 0x67, 0x4f, 0x6e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a,
+	0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x28, 0x01, 0x12, 0x56, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x2a, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x5c, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x25,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a,
+	0x09, 0x54, 0x72, 0x69, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x23, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x54,
+	0x72, 0x69, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x24, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x54, 0x72, 0x69, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x12, 0x24, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x12, 0x7c, 0x0a, 0x20, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69,
+	0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f,
+	0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x85, 0x01, 0x0a, 0x1b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74,
+	0x68, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x73, 0x12,
+	0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74,
+	0x68, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x36, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74,
+	0x68, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8d, 0x01, 0x0a, 0x1f, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x50, 0x61, 0x74, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x2e, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3a, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x69, 0x73,
+	0x73, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x74, 0x68, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x2e, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6c, 0x0a, 0x12, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x74, 0x61, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x27,
+	0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x74, 0x61, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x0d, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x61, 0x64,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a, 0x0f, 0x47, 0x65,
+	0x74, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x29, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x64, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x47, 0x65, 0x74,
+	0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x0d, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x64, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x82, 0x01, 0x0a, 0x1b, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x2b, 0x2e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8c,
+	0x01, 0x0a, 0x20, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x57, 0x69, 0x74, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x68, 0x69, 0x6c, 0x64,
+	0x72, 0x65, 0x6e, 0x12, 0x2b, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x3b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x57, 0x69, 0x74, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a,
+	0x19, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69,
+	0x74, 0x68, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x33, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x46,
+	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x57, 0x69, 0x74, 0x68,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4c, 0x0a, 0x0b, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62,
+	0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_clientd_clientd_proto_rawDescOnce sync.Once
+	file_pkg_proto_clientd_clientd_proto_rawDescData = file_pkg_proto_clientd_clientd_proto_rawDesc
+)
+
+func file_pkg_proto_clientd_clientd_proto_rawDescGZIP() []byte {
+	file_pkg_proto_clientd_clientd_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_clientd_clientd_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_clientd_clientd_proto_rawDescData)
+	})
+	return file_pkg_proto_clientd_clientd_proto_rawDescData
+}
+
+var file_pkg_proto_clientd_clientd_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_pkg_proto_clientd_clientd_proto_msgTypes = make([]protoimpl.MessageInfo, 67)
+var file_pkg_proto_clientd_clientd_proto_goTypes = []interface{}{
+	(OutputPathRemovalEvent_Reason)(0),                   // 0: buildbarn.clientd.OutputPathRemovalEvent.Reason
+	(DiagnoseSelfTestResponse_StageResult_Stage)(0),      // 1: buildbarn.clientd.DiagnoseSelfTestResponse.StageResult.Stage
+	(GetBuildStatusResponse_Phase)(0),                    // 2: buildbarn.clientd.GetBuildStatusResponse.Phase
+	(BuildEvent_Type)(0),                                 // 3: buildbarn.clientd.BuildEvent.Type
+	(BatchStatWithOptionsRequest_DigestInclusionMode)(0), // 4: buildbarn.clientd.BatchStatWithOptionsRequest.DigestInclusionMode
+	(BatchStatExistenceResponse_PathExistence)(0),        // 5: buildbarn.clientd.BatchStatExistenceResponse.PathExistence
+	(*CleanProgress)(nil),                                // 6: buildbarn.clientd.CleanProgress
+	(*CleanPathPrefixRequest)(nil),                       // 7: buildbarn.clientd.CleanPathPrefixRequest
+	(*CleanDryRunRequest)(nil),                           // 8: buildbarn.clientd.CleanDryRunRequest
+	(*CleanDryRunResponse)(nil),                          // 9: buildbarn.clientd.CleanDryRunResponse
+	(*StartBuildFromSnapshotRequest)(nil),                // 10: buildbarn.clientd.StartBuildFromSnapshotRequest
+	(*StartBuildWithDigestFunctionsRequest)(nil),         // 11: buildbarn.clientd.StartBuildWithDigestFunctionsRequest
+	(*StartBuildWithDigestFunctionsResponse)(nil),        // 12: buildbarn.clientd.StartBuildWithDigestFunctionsResponse
+	(*RestoreSnapshotRequest)(nil),                       // 13: buildbarn.clientd.RestoreSnapshotRequest
+	(*PrepareOutputBaseRequest)(nil),                     // 14: buildbarn.clientd.PrepareOutputBaseRequest
+	(*GetOutputPathTreeRequest)(nil),                     // 15: buildbarn.clientd.GetOutputPathTreeRequest
+	(*GetOutputPathTreeResponse)(nil),                    // 16: buildbarn.clientd.GetOutputPathTreeResponse
+	(*OutputPathRemovalEvent)(nil),                       // 17: buildbarn.clientd.OutputPathRemovalEvent
+	(*CheckpointResponse)(nil),                           // 18: buildbarn.clientd.CheckpointResponse
+	(*CloneOutputPathRequest)(nil),                       // 19: buildbarn.clientd.CloneOutputPathRequest
+	(*RenameOutputBaseRequest)(nil),                      // 20: buildbarn.clientd.RenameOutputBaseRequest
+	(*DiagnoseSelfTestRequest)(nil),                      // 21: buildbarn.clientd.DiagnoseSelfTestRequest
+	(*DiagnoseSelfTestResponse)(nil),                     // 22: buildbarn.clientd.DiagnoseSelfTestResponse
+	(*CheckHealthResponse)(nil),                          // 23: buildbarn.clientd.CheckHealthResponse
+	(*GetRuntimeStatisticsResponse)(nil),                 // 24: buildbarn.clientd.GetRuntimeStatisticsResponse
+	(*GetOutputPathLastAccessTimeRequest)(nil),           // 25: buildbarn.clientd.GetOutputPathLastAccessTimeRequest
+	(*GetOutputPathLastAccessTimeResponse)(nil),          // 26: buildbarn.clientd.GetOutputPathLastAccessTimeResponse
+	(*GetOutputBaseStatisticsRequest)(nil),               // 27: buildbarn.clientd.GetOutputBaseStatisticsRequest
+	(*GetOutputBaseStatisticsResponse)(nil),              // 28: buildbarn.clientd.GetOutputBaseStatisticsResponse
+	(*GetBuildStatusRequest)(nil),                        // 29: buildbarn.clientd.GetBuildStatusRequest
+	(*GetBuildStatusResponse)(nil),                       // 30: buildbarn.clientd.GetBuildStatusResponse
+	(*GetMissingBlobsRequest)(nil),                       // 31: buildbarn.clientd.GetMissingBlobsRequest
+	(*GetMissingBlobsResponse)(nil),                      // 32: buildbarn.clientd.GetMissingBlobsResponse
+	(*GetExecutableBitChangesRequest)(nil),               // 33: buildbarn.clientd.GetExecutableBitChangesRequest
+	(*GetExecutableBitChangesResponse)(nil),              // 34: buildbarn.clientd.GetExecutableBitChangesResponse
+	(*GetInitialOutputPathContentsRequest)(nil),          // 35: buildbarn.clientd.GetInitialOutputPathContentsRequest
+	(*GetInitialOutputPathContentsResponse)(nil),         // 36: buildbarn.clientd.GetInitialOutputPathContentsResponse
+	(*GetBuildErrorsRequest)(nil),                        // 37: buildbarn.clientd.GetBuildErrorsRequest
+	(*GetBuildErrorsResponse)(nil),                       // 38: buildbarn.clientd.GetBuildErrorsResponse
+	(*GetOutputBasePathRequest)(nil),                     // 39: buildbarn.clientd.GetOutputBasePathRequest
+	(*GetOutputBasePathResponse)(nil),                    // 40: buildbarn.clientd.GetOutputBasePathResponse
+	(*PingBuildRequest)(nil),                             // 41: buildbarn.clientd.PingBuildRequest
+	(*WatchBuildRequest)(nil),                            // 42: buildbarn.clientd.WatchBuildRequest
+	(*BuildEvent)(nil),                                   // 43: buildbarn.clientd.BuildEvent
+	(*BatchCreateWithIdempotencyTokenRequest)(nil),       // 44: buildbarn.clientd.BatchCreateWithIdempotencyTokenRequest
+	(*BatchCreateWithInlineTreesRequest)(nil),            // 45: buildbarn.clientd.BatchCreateWithInlineTreesRequest
+	(*BatchCreateContinuingOnErrorResponse)(nil),         // 46: buildbarn.clientd.BatchCreateContinuingOnErrorResponse
+	(*BatchCreateLinksRequest)(nil),                      // 47: buildbarn.clientd.BatchCreateLinksRequest
+	(*BatchRemoveRequest)(nil),                           // 48: buildbarn.clientd.BatchRemoveRequest
+	(*BatchRemoveResponse)(nil),                          // 49: buildbarn.clientd.BatchRemoveResponse
+	(*TrimBuildRequest)(nil),                             // 50: buildbarn.clientd.TrimBuildRequest
+	(*TrimBuildResponse)(nil),                            // 51: buildbarn.clientd.TrimBuildResponse
+	(*ExportPathRequest)(nil),                            // 52: buildbarn.clientd.ExportPathRequest
+	(*BatchStatWithOptionsRequest)(nil),                  // 53: buildbarn.clientd.BatchStatWithOptionsRequest
+	(*BatchStatWithExecutableBitsResponse)(nil),          // 54: buildbarn.clientd.BatchStatWithExecutableBitsResponse
+	(*BatchStatWithMissingPathDetailsResponse)(nil),      // 55: buildbarn.clientd.BatchStatWithMissingPathDetailsResponse
+	(*BatchStatPartialResponse)(nil),                     // 56: buildbarn.clientd.BatchStatPartialResponse
+	(*BatchStatExistenceResponse)(nil),                   // 57: buildbarn.clientd.BatchStatExistenceResponse
+	(*ReadDirectoryRequest)(nil),                         // 58: buildbarn.clientd.ReadDirectoryRequest
+	(*ReadDirectoryResponse)(nil),                        // 59: buildbarn.clientd.ReadDirectoryResponse
+	(*GetFileContentsRequest)(nil),                       // 60: buildbarn.clientd.GetFileContentsRequest
+	(*GetFileContentsResponse)(nil),                      // 61: buildbarn.clientd.GetFileContentsResponse
+	(*ReadFileRangeRequest)(nil),                         // 62: buildbarn.clientd.ReadFileRangeRequest
+	(*ReadFileRangeResponse)(nil),                        // 63: buildbarn.clientd.ReadFileRangeResponse
+	(*ResolveSymlinkRequest)(nil),                        // 64: buildbarn.clientd.ResolveSymlinkRequest
+	(*FinalizeBuildWithStatisticsResponse)(nil),          // 65: buildbarn.clientd.FinalizeBuildWithStatisticsResponse
+	(*FinalizeBuildWithRemovedChildrenResponse)(nil),     // 66: buildbarn.clientd.FinalizeBuildWithRemovedChildrenResponse
+	(*FinalizeBuildWithSnapshotRequest)(nil),             // 67: buildbarn.clientd.FinalizeBuildWithSnapshotRequest
+	(*CancelBuildRequest)(nil),                           // 68: buildbarn.clientd.CancelBuildRequest
+	(*DiagnoseSelfTestResponse_StageResult)(nil),         // 69: buildbarn.clientd.DiagnoseSelfTestResponse.StageResult
+	(*BatchCreateLinksRequest_Link)(nil),                 // 70: buildbarn.clientd.BatchCreateLinksRequest.Link
+	(*ReadDirectoryResponse_Entry)(nil),                  // 71: buildbarn.clientd.ReadDirectoryResponse.Entry
+	(*GetFileContentsResponse_FileContents)(nil),         // 72: buildbarn.clientd.GetFileContentsResponse.FileContents
+	(*remoteoutputservice.StartBuildRequest)(nil),        // 73: remote_output_service.StartBuildRequest
+	(*v2.Digest)(nil),                                    // 74: build.bazel.remote.execution.v2.Digest
+	(v2.DigestFunction_Value)(0),                         // 75: build.bazel.remote.execution.v2.DigestFunction.Value
+	(*remoteoutputservice.StartBuildResponse)(nil),       // 76: remote_output_service.StartBuildResponse
+	(*timestamppb.Timestamp)(nil),                        // 77: google.protobuf.Timestamp
+	(*status.Status)(nil),                                // 78: google.rpc.Status
+	(*remoteoutputservice.BatchCreateRequest)(nil),       // 79: remote_output_service.BatchCreateRequest
+	(*remoteoutputservice.BatchStatRequest)(nil),         // 80: remote_output_service.BatchStatRequest
+	(*remoteoutputservice.BatchStatResponse)(nil),        // 81: remote_output_service.BatchStatResponse
+	(*remoteoutputservice.FinalizeBuildRequest)(nil),     // 82: remote_output_service.FinalizeBuildRequest
+	(*remoteoutputservice.FileStatus)(nil),               // 83: remote_output_service.FileStatus
+	(*remoteoutputservice.CleanRequest)(nil),             // 84: remote_output_service.CleanRequest
+	(*emptypb.Empty)(nil),                                // 85: google.protobuf.Empty
+	(*remoteoutputservice.StatResponse)(nil),             // 86: remote_output_service.StatResponse
+}
+var file_pkg_proto_clientd_clientd_proto_depIdxs = []int32{
+	73, // 0: buildbarn.clientd.StartBuildFromSnapshotRequest.start_build:type_name -> remote_output_service.StartBuildRequest
+	74, // 1: buildbarn.clientd.StartBuildFromSnapshotRequest.snapshot_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	73, // 2: buildbarn.clientd.StartBuildWithDigestFunctionsRequest.start_build:type_name -> remote_output_service.StartBuildRequest
+	75, // 3: buildbarn.clientd.StartBuildWithDigestFunctionsRequest.digest_functions:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	76, // 4: buildbarn.clientd.StartBuildWithDigestFunctionsResponse.start_build:type_name -> remote_output_service.StartBuildResponse
+	75, // 5: buildbarn.clientd.StartBuildWithDigestFunctionsResponse.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	73, // 6: buildbarn.clientd.RestoreSnapshotRequest.start_build:type_name -> remote_output_service.StartBuildRequest
+	75, // 7: buildbarn.clientd.PrepareOutputBaseRequest.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	74, // 8: buildbarn.clientd.GetOutputPathTreeResponse.tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	0,  // 9: buildbarn.clientd.OutputPathRemovalEvent.reason:type_name -> buildbarn.clientd.OutputPathRemovalEvent.Reason
+	77, // 10: buildbarn.clientd.OutputPathRemovalEvent.last_access_time:type_name -> google.protobuf.Timestamp
+	75, // 11: buildbarn.clientd.DiagnoseSelfTestRequest.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	69, // 12: buildbarn.clientd.DiagnoseSelfTestResponse.stage_results:type_name -> buildbarn.clientd.DiagnoseSelfTestResponse.StageResult
+	77, // 13: buildbarn.clientd.GetOutputPathLastAccessTimeResponse.last_access_time:type_name -> google.protobuf.Timestamp
+	77, // 14: buildbarn.clientd.GetOutputBaseStatisticsResponse.last_build_start_time:type_name -> google.protobuf.Timestamp
+	2,  // 15: buildbarn.clientd.GetBuildStatusResponse.phase:type_name -> buildbarn.clientd.GetBuildStatusResponse.Phase
+	78, // 16: buildbarn.clientd.GetBuildStatusResponse.error:type_name -> google.rpc.Status
+	74, // 17: buildbarn.clientd.GetMissingBlobsResponse.missing_blob_digests:type_name -> build.bazel.remote.execution.v2.Digest
+	74, // 18: buildbarn.clientd.GetInitialOutputPathContentsResponse.tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	78, // 19: buildbarn.clientd.GetBuildErrorsResponse.errors:type_name -> google.rpc.Status
+	3,  // 20: buildbarn.clientd.BuildEvent.type:type_name -> buildbarn.clientd.BuildEvent.Type
+	74, // 21: buildbarn.clientd.BuildEvent.digest:type_name -> build.bazel.remote.execution.v2.Digest
+	79, // 22: buildbarn.clientd.BatchCreateWithIdempotencyTokenRequest.batch_create:type_name -> remote_output_service.BatchCreateRequest
+	79, // 23: buildbarn.clientd.BatchCreateWithInlineTreesRequest.batch_create:type_name -> remote_output_service.BatchCreateRequest
+	78, // 24: buildbarn.clientd.BatchCreateContinuingOnErrorResponse.file_errors:type_name -> google.rpc.Status
+	78, // 25: buildbarn.clientd.BatchCreateContinuingOnErrorResponse.directory_errors:type_name -> google.rpc.Status
+	78, // 26: buildbarn.clientd.BatchCreateContinuingOnErrorResponse.symlink_errors:type_name -> google.rpc.Status
+	70, // 27: buildbarn.clientd.BatchCreateLinksRequest.links:type_name -> buildbarn.clientd.BatchCreateLinksRequest.Link
+	78, // 28: buildbarn.clientd.BatchRemoveResponse.errors:type_name -> google.rpc.Status
+	80, // 29: buildbarn.clientd.BatchStatWithOptionsRequest.batch_stat:type_name -> remote_output_service.BatchStatRequest
+	4,  // 30: buildbarn.clientd.BatchStatWithOptionsRequest.digest_inclusion_mode:type_name -> buildbarn.clientd.BatchStatWithOptionsRequest.DigestInclusionMode
+	81, // 31: buildbarn.clientd.BatchStatWithExecutableBitsResponse.batch_stat:type_name -> remote_output_service.BatchStatResponse
+	81, // 32: buildbarn.clientd.BatchStatWithMissingPathDetailsResponse.batch_stat:type_name -> remote_output_service.BatchStatResponse
+	78, // 33: buildbarn.clientd.BatchStatWithMissingPathDetailsResponse.missing_path_errors:type_name -> google.rpc.Status
+	81, // 34: buildbarn.clientd.BatchStatPartialResponse.batch_stat:type_name -> remote_output_service.BatchStatResponse
+	5,  // 35: buildbarn.clientd.BatchStatExistenceResponse.path_existences:type_name -> buildbarn.clientd.BatchStatExistenceResponse.PathExistence
+	71, // 36: buildbarn.clientd.ReadDirectoryResponse.entries:type_name -> buildbarn.clientd.ReadDirectoryResponse.Entry
+	72, // 37: buildbarn.clientd.GetFileContentsResponse.file_contents:type_name -> buildbarn.clientd.GetFileContentsResponse.FileContents
+	74, // 38: buildbarn.clientd.FinalizeBuildWithRemovedChildrenResponse.removed_child_digests:type_name -> build.bazel.remote.execution.v2.Digest
+	82, // 39: buildbarn.clientd.FinalizeBuildWithSnapshotRequest.finalize_build:type_name -> remote_output_service.FinalizeBuildRequest
+	1,  // 40: buildbarn.clientd.DiagnoseSelfTestResponse.StageResult.stage:type_name -> buildbarn.clientd.DiagnoseSelfTestResponse.StageResult.Stage
+	78, // 41: buildbarn.clientd.DiagnoseSelfTestResponse.StageResult.status:type_name -> google.rpc.Status
+	83, // 42: buildbarn.clientd.ReadDirectoryResponse.Entry.file_status:type_name -> remote_output_service.FileStatus
+	74, // 43: buildbarn.clientd.GetFileContentsResponse.FileContents.digest:type_name -> build.bazel.remote.execution.v2.Digest
+	84, // 44: buildbarn.clientd.ClientDaemon.CleanStreaming:input_type -> remote_output_service.CleanRequest
+	7,  // 45: buildbarn.clientd.ClientDaemon.CleanPathPrefix:input_type -> buildbarn.clientd.CleanPathPrefixRequest
+	8,  // 46: buildbarn.clientd.ClientDaemon.CleanDryRun:input_type -> buildbarn.clientd.CleanDryRunRequest
+	73, // 47: buildbarn.clientd.ClientDaemon.StartBuildAsynchronously:input_type -> remote_output_service.StartBuildRequest
+	10, // 48: buildbarn.clientd.ClientDaemon.StartBuildFromSnapshot:input_type -> buildbarn.clientd.StartBuildFromSnapshotRequest
+	73, // 49: buildbarn.clientd.ClientDaemon.StartBuildForcingReset:input_type -> remote_output_service.StartBuildRequest
+	11, // 50: buildbarn.clientd.ClientDaemon.StartBuildWithDigestFunctions:input_type -> buildbarn.clientd.StartBuildWithDigestFunctionsRequest
+	13, // 51: buildbarn.clientd.ClientDaemon.RestoreSnapshot:input_type -> buildbarn.clientd.RestoreSnapshotRequest
+	14, // 52: buildbarn.clientd.ClientDaemon.PrepareOutputBase:input_type -> buildbarn.clientd.PrepareOutputBaseRequest
+	15, // 53: buildbarn.clientd.ClientDaemon.GetOutputPathTree:input_type -> buildbarn.clientd.GetOutputPathTreeRequest
+	85, // 54: buildbarn.clientd.ClientDaemon.WatchOutputPathRemovals:input_type -> google.protobuf.Empty
+	85, // 55: buildbarn.clientd.ClientDaemon.Checkpoint:input_type -> google.protobuf.Empty
+	19, // 56: buildbarn.clientd.ClientDaemon.CloneOutputPath:input_type -> buildbarn.clientd.CloneOutputPathRequest
+	20, // 57: buildbarn.clientd.ClientDaemon.RenameOutputBase:input_type -> buildbarn.clientd.RenameOutputBaseRequest
+	21, // 58: buildbarn.clientd.ClientDaemon.DiagnoseSelfTest:input_type -> buildbarn.clientd.DiagnoseSelfTestRequest
+	85, // 59: buildbarn.clientd.ClientDaemon.CheckHealth:input_type -> google.protobuf.Empty
+	85, // 60: buildbarn.clientd.ClientDaemon.GetRuntimeStatistics:input_type -> google.protobuf.Empty
+	25, // 61: buildbarn.clientd.ClientDaemon.GetOutputPathLastAccessTime:input_type -> buildbarn.clientd.GetOutputPathLastAccessTimeRequest
+	27, // 62: buildbarn.clientd.ClientDaemon.GetOutputBaseStatistics:input_type -> buildbarn.clientd.GetOutputBaseStatisticsRequest
+	29, // 63: buildbarn.clientd.ClientDaemon.GetBuildStatus:input_type -> buildbarn.clientd.GetBuildStatusRequest
+	31, // 64: buildbarn.clientd.ClientDaemon.GetMissingBlobs:input_type -> buildbarn.clientd.GetMissingBlobsRequest
+	33, // 65: buildbarn.clientd.ClientDaemon.GetExecutableBitChanges:input_type -> buildbarn.clientd.GetExecutableBitChangesRequest
+	35, // 66: buildbarn.clientd.ClientDaemon.GetInitialOutputPathContents:input_type -> buildbarn.clientd.GetInitialOutputPathContentsRequest
+	37, // 67: buildbarn.clientd.ClientDaemon.GetBuildErrors:input_type -> buildbarn.clientd.GetBuildErrorsRequest
+	39, // 68: buildbarn.clientd.ClientDaemon.GetOutputBasePath:input_type -> buildbarn.clientd.GetOutputBasePathRequest
+	41, // 69: buildbarn.clientd.ClientDaemon.PingBuild:input_type -> buildbarn.clientd.PingBuildRequest
+	42, // 70: buildbarn.clientd.ClientDaemon.WatchBuild:input_type -> buildbarn.clientd.WatchBuildRequest
+	44, // 71: buildbarn.clientd.ClientDaemon.BatchCreateWithIdempotencyToken:input_type -> buildbarn.clientd.BatchCreateWithIdempotencyTokenRequest
+	45, // 72: buildbarn.clientd.ClientDaemon.BatchCreateWithInlineTrees:input_type -> buildbarn.clientd.BatchCreateWithInlineTreesRequest
+	79, // 73: buildbarn.clientd.ClientDaemon.BatchCreateContinuingOnError:input_type -> remote_output_service.BatchCreateRequest
+	79, // 74: buildbarn.clientd.ClientDaemon.BatchCreateStream:input_type -> remote_output_service.BatchCreateRequest
+	47, // 75: buildbarn.clientd.ClientDaemon.BatchCreateLinks:input_type -> buildbarn.clientd.BatchCreateLinksRequest
+	48, // 76: buildbarn.clientd.ClientDaemon.BatchRemove:input_type -> buildbarn.clientd.BatchRemoveRequest
+	50, // 77: buildbarn.clientd.ClientDaemon.TrimBuild:input_type -> buildbarn.clientd.TrimBuildRequest
+	52, // 78: buildbarn.clientd.ClientDaemon.ExportPath:input_type -> buildbarn.clientd.ExportPathRequest
+	53, // 79: buildbarn.clientd.ClientDaemon.BatchStatWithDigestInclusionMode:input_type -> buildbarn.clientd.BatchStatWithOptionsRequest
+	53, // 80: buildbarn.clientd.ClientDaemon.BatchStatWithExecutableBits:input_type -> buildbarn.clientd.BatchStatWithOptionsRequest
+	53, // 81: buildbarn.clientd.ClientDaemon.BatchStatWithMissingPathDetails:input_type -> buildbarn.clientd.BatchStatWithOptionsRequest
+	53, // 82: buildbarn.clientd.ClientDaemon.BatchStatPartial:input_type -> buildbarn.clientd.BatchStatWithOptionsRequest
+	80, // 83: buildbarn.clientd.ClientDaemon.BatchStatExistence:input_type -> remote_output_service.BatchStatRequest
+	58, // 84: buildbarn.clientd.ClientDaemon.ReadDirectory:input_type -> buildbarn.clientd.ReadDirectoryRequest
+	60, // 85: buildbarn.clientd.ClientDaemon.GetFileContents:input_type -> buildbarn.clientd.GetFileContentsRequest
+	62, // 86: buildbarn.clientd.ClientDaemon.ReadFileRange:input_type -> buildbarn.clientd.ReadFileRangeRequest
+	64, // 87: buildbarn.clientd.ClientDaemon.ResolveSymlink:input_type -> buildbarn.clientd.ResolveSymlinkRequest
+	82, // 88: buildbarn.clientd.ClientDaemon.FinalizeBuildWithStatistics:input_type -> remote_output_service.FinalizeBuildRequest
+	82, // 89: buildbarn.clientd.ClientDaemon.FinalizeBuildWithRemovedChildren:input_type -> remote_output_service.FinalizeBuildRequest
+	67, // 90: buildbarn.clientd.ClientDaemon.FinalizeBuildWithSnapshot:input_type -> buildbarn.clientd.FinalizeBuildWithSnapshotRequest
+	68, // 91: buildbarn.clientd.ClientDaemon.CancelBuild:input_type -> buildbarn.clientd.CancelBuildRequest
+	6,  // 92: buildbarn.clientd.ClientDaemon.CleanStreaming:output_type -> buildbarn.clientd.CleanProgress
+	85, // 93: buildbarn.clientd.ClientDaemon.CleanPathPrefix:output_type -> google.protobuf.Empty
+	9,  // 94: buildbarn.clientd.ClientDaemon.CleanDryRun:output_type -> buildbarn.clientd.CleanDryRunResponse
+	76, // 95: buildbarn.clientd.ClientDaemon.StartBuildAsynchronously:output_type -> remote_output_service.StartBuildResponse
+	76, // 96: buildbarn.clientd.ClientDaemon.StartBuildFromSnapshot:output_type -> remote_output_service.StartBuildResponse
+	76, // 97: buildbarn.clientd.ClientDaemon.StartBuildForcingReset:output_type -> remote_output_service.StartBuildResponse
+	12, // 98: buildbarn.clientd.ClientDaemon.StartBuildWithDigestFunctions:output_type -> buildbarn.clientd.StartBuildWithDigestFunctionsResponse
+	76, // 99: buildbarn.clientd.ClientDaemon.RestoreSnapshot:output_type -> remote_output_service.StartBuildResponse
+	85, // 100: buildbarn.clientd.ClientDaemon.PrepareOutputBase:output_type -> google.protobuf.Empty
+	16, // 101: buildbarn.clientd.ClientDaemon.GetOutputPathTree:output_type -> buildbarn.clientd.GetOutputPathTreeResponse
+	17, // 102: buildbarn.clientd.ClientDaemon.WatchOutputPathRemovals:output_type -> buildbarn.clientd.OutputPathRemovalEvent
+	18, // 103: buildbarn.clientd.ClientDaemon.Checkpoint:output_type -> buildbarn.clientd.CheckpointResponse
+	85, // 104: buildbarn.clientd.ClientDaemon.CloneOutputPath:output_type -> google.protobuf.Empty
+	85, // 105: buildbarn.clientd.ClientDaemon.RenameOutputBase:output_type -> google.protobuf.Empty
+	22, // 106: buildbarn.clientd.ClientDaemon.DiagnoseSelfTest:output_type -> buildbarn.clientd.DiagnoseSelfTestResponse
+	23, // 107: buildbarn.clientd.ClientDaemon.CheckHealth:output_type -> buildbarn.clientd.CheckHealthResponse
+	24, // 108: buildbarn.clientd.ClientDaemon.GetRuntimeStatistics:output_type -> buildbarn.clientd.GetRuntimeStatisticsResponse
+	26, // 109: buildbarn.clientd.ClientDaemon.GetOutputPathLastAccessTime:output_type -> buildbarn.clientd.GetOutputPathLastAccessTimeResponse
+	28, // 110: buildbarn.clientd.ClientDaemon.GetOutputBaseStatistics:output_type -> buildbarn.clientd.GetOutputBaseStatisticsResponse
+	30, // 111: buildbarn.clientd.ClientDaemon.GetBuildStatus:output_type -> buildbarn.clientd.GetBuildStatusResponse
+	32, // 112: buildbarn.clientd.ClientDaemon.GetMissingBlobs:output_type -> buildbarn.clientd.GetMissingBlobsResponse
+	34, // 113: buildbarn.clientd.ClientDaemon.GetExecutableBitChanges:output_type -> buildbarn.clientd.GetExecutableBitChangesResponse
+	36, // 114: buildbarn.clientd.ClientDaemon.GetInitialOutputPathContents:output_type -> buildbarn.clientd.GetInitialOutputPathContentsResponse
+	38, // 115: buildbarn.clientd.ClientDaemon.GetBuildErrors:output_type -> buildbarn.clientd.GetBuildErrorsResponse
+	40, // 116: buildbarn.clientd.ClientDaemon.GetOutputBasePath:output_type -> buildbarn.clientd.GetOutputBasePathResponse
+	85, // 117: buildbarn.clientd.ClientDaemon.PingBuild:output_type -> google.protobuf.Empty
+	43, // 118: buildbarn.clientd.ClientDaemon.WatchBuild:output_type -> buildbarn.clientd.BuildEvent
+	85, // 119: buildbarn.clientd.ClientDaemon.BatchCreateWithIdempotencyToken:output_type -> google.protobuf.Empty
+	85, // 120: buildbarn.clientd.ClientDaemon.BatchCreateWithInlineTrees:output_type -> google.protobuf.Empty
+	46, // 121: buildbarn.clientd.ClientDaemon.BatchCreateContinuingOnError:output_type -> buildbarn.clientd.BatchCreateContinuingOnErrorResponse
+	85, // 122: buildbarn.clientd.ClientDaemon.BatchCreateStream:output_type -> google.protobuf.Empty
+	85, // 123: buildbarn.clientd.ClientDaemon.BatchCreateLinks:output_type -> google.protobuf.Empty
+	49, // 124: buildbarn.clientd.ClientDaemon.BatchRemove:output_type -> buildbarn.clientd.BatchRemoveResponse
+	51, // 125: buildbarn.clientd.ClientDaemon.TrimBuild:output_type -> buildbarn.clientd.TrimBuildResponse
+	85, // 126: buildbarn.clientd.ClientDaemon.ExportPath:output_type -> google.protobuf.Empty
+	81, // 127: buildbarn.clientd.ClientDaemon.BatchStatWithDigestInclusionMode:output_type -> remote_output_service.BatchStatResponse
+	54, // 128: buildbarn.clientd.ClientDaemon.BatchStatWithExecutableBits:output_type -> buildbarn.clientd.BatchStatWithExecutableBitsResponse
+	55, // 129: buildbarn.clientd.ClientDaemon.BatchStatWithMissingPathDetails:output_type -> buildbarn.clientd.BatchStatWithMissingPathDetailsResponse
+	56, // 130: buildbarn.clientd.ClientDaemon.BatchStatPartial:output_type -> buildbarn.clientd.BatchStatPartialResponse
+	57, // 131: buildbarn.clientd.ClientDaemon.BatchStatExistence:output_type -> buildbarn.clientd.BatchStatExistenceResponse
+	59, // 132: buildbarn.clientd.ClientDaemon.ReadDirectory:output_type -> buildbarn.clientd.ReadDirectoryResponse
+	61, // 133: buildbarn.clientd.ClientDaemon.GetFileContents:output_type -> buildbarn.clientd.GetFileContentsResponse
+	63, // 134: buildbarn.clientd.ClientDaemon.ReadFileRange:output_type -> buildbarn.clientd.ReadFileRangeResponse
+	86, // 135: buildbarn.clientd.ClientDaemon.ResolveSymlink:output_type -> remote_output_service.StatResponse
+	65, // 136: buildbarn.clientd.ClientDaemon.FinalizeBuildWithStatistics:output_type -> buildbarn.clientd.FinalizeBuildWithStatisticsResponse
+	66, // 137: buildbarn.clientd.ClientDaemon.FinalizeBuildWithRemovedChildren:output_type -> buildbarn.clientd.FinalizeBuildWithRemovedChildrenResponse
+	85, // 138: buildbarn.clientd.ClientDaemon.FinalizeBuildWithSnapshot:output_type -> google.protobuf.Empty
+	85, // 139: buildbarn.clientd.ClientDaemon.CancelBuild:output_type -> google.protobuf.Empty
+	92, // [92:140] is the sub-list for method output_type
+	44, // [44:92] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_clientd_clientd_proto_init() }
+func file_pkg_proto_clientd_clientd_proto_init() {
+	if File_pkg_proto_clientd_clientd_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_clientd_clientd_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanPathPrefixRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanDryRunRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanDryRunResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartBuildFromSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartBuildWithDigestFunctionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartBuildWithDigestFunctionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareOutputBaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathTreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputPathRemovalEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckpointResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloneOutputPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameOutputBaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiagnoseSelfTestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiagnoseSelfTestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckHealthResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRuntimeStatisticsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathLastAccessTimeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathLastAccessTimeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputBaseStatisticsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputBaseStatisticsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBuildStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBuildStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMissingBlobsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMissingBlobsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetExecutableBitChangesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetExecutableBitChangesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInitialOutputPathContentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInitialOutputPathContentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBuildErrorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBuildErrorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputBasePathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputBasePathResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateWithIdempotencyTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateWithInlineTreesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateContinuingOnErrorResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateLinksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchRemoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchRemoveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrimBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrimBuildResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStatWithOptionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStatWithExecutableBitsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStatWithMissingPathDetailsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStatPartialResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStatExistenceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadDirectoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileContentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileContentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadFileRangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadFileRangeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveSymlinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeBuildWithStatisticsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeBuildWithRemovedChildrenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeBuildWithSnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiagnoseSelfTestResponse_StageResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateLinksRequest_Link); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadDirectoryResponse_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_clientd_clientd_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileContentsResponse_FileContents); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_proto_clientd_clientd_proto_msgTypes[66].OneofWrappers = []interface{}{
+		(*GetFileContentsResponse_FileContents_Contents)(nil),
+		(*GetFileContentsResponse_FileContents_Digest)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_clientd_clientd_proto_rawDesc,
+			NumEnums:      6,
+			NumMessages:   67,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_proto_clientd_clientd_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_clientd_clientd_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_clientd_clientd_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_clientd_clientd_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_clientd_clientd_proto = out.File
+	file_pkg_proto_clientd_clientd_proto_rawDesc = nil
+	file_pkg_proto_clientd_clientd_proto_goTypes = nil
+	file_pkg_proto_clientd_clientd_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ClientDaemonClient is the client API for ClientDaemon service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ClientDaemonClient interface {
+	CleanStreaming(ctx context.Context, in *remoteoutputservice.CleanRequest, opts ...grpc.CallOption) (ClientDaemon_CleanStreamingClient, error)
+	CleanPathPrefix(ctx context.Context, in *CleanPathPrefixRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CleanDryRun(ctx context.Context, in *CleanDryRunRequest, opts ...grpc.CallOption) (*CleanDryRunResponse, error)
+	StartBuildAsynchronously(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error)
+	StartBuildFromSnapshot(ctx context.Context, in *StartBuildFromSnapshotRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error)
+	StartBuildForcingReset(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error)
+	StartBuildWithDigestFunctions(ctx context.Context, in *StartBuildWithDigestFunctionsRequest, opts ...grpc.CallOption) (*StartBuildWithDigestFunctionsResponse, error)
+	RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error)
+	PrepareOutputBase(ctx context.Context, in *PrepareOutputBaseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOutputPathTree(ctx context.Context, in *GetOutputPathTreeRequest, opts ...grpc.CallOption) (*GetOutputPathTreeResponse, error)
+	WatchOutputPathRemovals(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ClientDaemon_WatchOutputPathRemovalsClient, error)
+	Checkpoint(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CheckpointResponse, error)
+	CloneOutputPath(ctx context.Context, in *CloneOutputPathRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RenameOutputBase(ctx context.Context, in *RenameOutputBaseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DiagnoseSelfTest(ctx context.Context, in *DiagnoseSelfTestRequest, opts ...grpc.CallOption) (*DiagnoseSelfTestResponse, error)
+	CheckHealth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CheckHealthResponse, error)
+	GetRuntimeStatistics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetRuntimeStatisticsResponse, error)
+	GetOutputPathLastAccessTime(ctx context.Context, in *GetOutputPathLastAccessTimeRequest, opts ...grpc.CallOption) (*GetOutputPathLastAccessTimeResponse, error)
+	GetOutputBaseStatistics(ctx context.Context, in *GetOutputBaseStatisticsRequest, opts ...grpc.CallOption) (*GetOutputBaseStatisticsResponse, error)
+	GetBuildStatus(ctx context.Context, in *GetBuildStatusRequest, opts ...grpc.CallOption) (*GetBuildStatusResponse, error)
+	GetMissingBlobs(ctx context.Context, in *GetMissingBlobsRequest, opts ...grpc.CallOption) (*GetMissingBlobsResponse, error)
+	GetExecutableBitChanges(ctx context.Context, in *GetExecutableBitChangesRequest, opts ...grpc.CallOption) (*GetExecutableBitChangesResponse, error)
+	GetInitialOutputPathContents(ctx context.Context, in *GetInitialOutputPathContentsRequest, opts ...grpc.CallOption) (*GetInitialOutputPathContentsResponse, error)
+	GetBuildErrors(ctx context.Context, in *GetBuildErrorsRequest, opts ...grpc.CallOption) (*GetBuildErrorsResponse, error)
+	GetOutputBasePath(ctx context.Context, in *GetOutputBasePathRequest, opts ...grpc.CallOption) (*GetOutputBasePathResponse, error)
+	PingBuild(ctx context.Context, in *PingBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	WatchBuild(ctx context.Context, in *WatchBuildRequest, opts ...grpc.CallOption) (ClientDaemon_WatchBuildClient, error)
+	BatchCreateWithIdempotencyToken(ctx context.Context, in *BatchCreateWithIdempotencyTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BatchCreateWithInlineTrees(ctx context.Context, in *BatchCreateWithInlineTreesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BatchCreateContinuingOnError(ctx context.Context, in *remoteoutputservice.BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateContinuingOnErrorResponse, error)
+	BatchCreateStream(ctx context.Context, opts ...grpc.CallOption) (ClientDaemon_BatchCreateStreamClient, error)
+	BatchCreateLinks(ctx context.Context, in *BatchCreateLinksRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BatchRemove(ctx context.Context, in *BatchRemoveRequest, opts ...grpc.CallOption) (*BatchRemoveResponse, error)
+	TrimBuild(ctx context.Context, in *TrimBuildRequest, opts ...grpc.CallOption) (*TrimBuildResponse, error)
+	ExportPath(ctx context.Context, in *ExportPathRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BatchStatWithDigestInclusionMode(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*remoteoutputservice.BatchStatResponse, error)
+	BatchStatWithExecutableBits(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*BatchStatWithExecutableBitsResponse, error)
+	BatchStatWithMissingPathDetails(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*BatchStatWithMissingPathDetailsResponse, error)
+	BatchStatPartial(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*BatchStatPartialResponse, error)
+	BatchStatExistence(ctx context.Context, in *remoteoutputservice.BatchStatRequest, opts ...grpc.CallOption) (*BatchStatExistenceResponse, error)
+	ReadDirectory(ctx context.Context, in *ReadDirectoryRequest, opts ...grpc.CallOption) (*ReadDirectoryResponse, error)
+	GetFileContents(ctx context.Context, in *GetFileContentsRequest, opts ...grpc.CallOption) (*GetFileContentsResponse, error)
+	ReadFileRange(ctx context.Context, in *ReadFileRangeRequest, opts ...grpc.CallOption) (*ReadFileRangeResponse, error)
+	ResolveSymlink(ctx context.Context, in *ResolveSymlinkRequest, opts ...grpc.CallOption) (*remoteoutputservice.StatResponse, error)
+	FinalizeBuildWithStatistics(ctx context.Context, in *remoteoutputservice.FinalizeBuildRequest, opts ...grpc.CallOption) (*FinalizeBuildWithStatisticsResponse, error)
+	FinalizeBuildWithRemovedChildren(ctx context.Context, in *remoteoutputservice.FinalizeBuildRequest, opts ...grpc.CallOption) (*FinalizeBuildWithRemovedChildrenResponse, error)
+	FinalizeBuildWithSnapshot(ctx context.Context, in *FinalizeBuildWithSnapshotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CancelBuild(ctx context.Context, in *CancelBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type clientDaemonClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClientDaemonClient(cc grpc.ClientConnInterface) ClientDaemonClient {
+	return &clientDaemonClient{cc}
+}
+
+func (c *clientDaemonClient) CleanStreaming(ctx context.Context, in *remoteoutputservice.CleanRequest, opts ...grpc.CallOption) (ClientDaemon_CleanStreamingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ClientDaemon_serviceDesc.Streams[0], "/buildbarn.clientd.ClientDaemon/CleanStreaming", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clientDaemonCleanStreamingClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClientDaemon_CleanStreamingClient interface {
+	Recv() (*CleanProgress, error)
+	grpc.ClientStream
+}
+
+type clientDaemonCleanStreamingClient struct {
+	grpc.ClientStream
+}
+
+func (x *clientDaemonCleanStreamingClient) Recv() (*CleanProgress, error) {
+	m := new(CleanProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *clientDaemonClient) CleanPathPrefix(ctx context.Context, in *CleanPathPrefixRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/CleanPathPrefix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) CleanDryRun(ctx context.Context, in *CleanDryRunRequest, opts ...grpc.CallOption) (*CleanDryRunResponse, error) {
+	out := new(CleanDryRunResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/CleanDryRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) StartBuildAsynchronously(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error) {
+	out := new(remoteoutputservice.StartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/StartBuildAsynchronously", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) StartBuildFromSnapshot(ctx context.Context, in *StartBuildFromSnapshotRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error) {
+	out := new(remoteoutputservice.StartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/StartBuildFromSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) StartBuildForcingReset(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error) {
+	out := new(remoteoutputservice.StartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/StartBuildForcingReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) StartBuildWithDigestFunctions(ctx context.Context, in *StartBuildWithDigestFunctionsRequest, opts ...grpc.CallOption) (*StartBuildWithDigestFunctionsResponse, error) {
+	out := new(StartBuildWithDigestFunctionsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/StartBuildWithDigestFunctions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error) {
+	out := new(remoteoutputservice.StartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/RestoreSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) PrepareOutputBase(ctx context.Context, in *PrepareOutputBaseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/PrepareOutputBase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetOutputPathTree(ctx context.Context, in *GetOutputPathTreeRequest, opts ...grpc.CallOption) (*GetOutputPathTreeResponse, error) {
+	out := new(GetOutputPathTreeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetOutputPathTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) WatchOutputPathRemovals(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ClientDaemon_WatchOutputPathRemovalsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ClientDaemon_serviceDesc.Streams[1], "/buildbarn.clientd.ClientDaemon/WatchOutputPathRemovals", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clientDaemonWatchOutputPathRemovalsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClientDaemon_WatchOutputPathRemovalsClient interface {
+	Recv() (*OutputPathRemovalEvent, error)
+	grpc.ClientStream
+}
+
+type clientDaemonWatchOutputPathRemovalsClient struct {
+	grpc.ClientStream
+}
+
+func (x *clientDaemonWatchOutputPathRemovalsClient) Recv() (*OutputPathRemovalEvent, error) {
+	m := new(OutputPathRemovalEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *clientDaemonClient) Checkpoint(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CheckpointResponse, error) {
+	out := new(CheckpointResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/Checkpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) CloneOutputPath(ctx context.Context, in *CloneOutputPathRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/CloneOutputPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) RenameOutputBase(ctx context.Context, in *RenameOutputBaseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/RenameOutputBase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) DiagnoseSelfTest(ctx context.Context, in *DiagnoseSelfTestRequest, opts ...grpc.CallOption) (*DiagnoseSelfTestResponse, error) {
+	out := new(DiagnoseSelfTestResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/DiagnoseSelfTest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) CheckHealth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CheckHealthResponse, error) {
+	out := new(CheckHealthResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/CheckHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetRuntimeStatistics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetRuntimeStatisticsResponse, error) {
+	out := new(GetRuntimeStatisticsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetRuntimeStatistics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetOutputPathLastAccessTime(ctx context.Context, in *GetOutputPathLastAccessTimeRequest, opts ...grpc.CallOption) (*GetOutputPathLastAccessTimeResponse, error) {
+	out := new(GetOutputPathLastAccessTimeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetOutputPathLastAccessTime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetOutputBaseStatistics(ctx context.Context, in *GetOutputBaseStatisticsRequest, opts ...grpc.CallOption) (*GetOutputBaseStatisticsResponse, error) {
+	out := new(GetOutputBaseStatisticsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetOutputBaseStatistics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetBuildStatus(ctx context.Context, in *GetBuildStatusRequest, opts ...grpc.CallOption) (*GetBuildStatusResponse, error) {
+	out := new(GetBuildStatusResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetBuildStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetMissingBlobs(ctx context.Context, in *GetMissingBlobsRequest, opts ...grpc.CallOption) (*GetMissingBlobsResponse, error) {
+	out := new(GetMissingBlobsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetMissingBlobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetExecutableBitChanges(ctx context.Context, in *GetExecutableBitChangesRequest, opts ...grpc.CallOption) (*GetExecutableBitChangesResponse, error) {
+	out := new(GetExecutableBitChangesResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetExecutableBitChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetInitialOutputPathContents(ctx context.Context, in *GetInitialOutputPathContentsRequest, opts ...grpc.CallOption) (*GetInitialOutputPathContentsResponse, error) {
+	out := new(GetInitialOutputPathContentsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetInitialOutputPathContents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetBuildErrors(ctx context.Context, in *GetBuildErrorsRequest, opts ...grpc.CallOption) (*GetBuildErrorsResponse, error) {
+	out := new(GetBuildErrorsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetBuildErrors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetOutputBasePath(ctx context.Context, in *GetOutputBasePathRequest, opts ...grpc.CallOption) (*GetOutputBasePathResponse, error) {
+	out := new(GetOutputBasePathResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetOutputBasePath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) PingBuild(ctx context.Context, in *PingBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/PingBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) WatchBuild(ctx context.Context, in *WatchBuildRequest, opts ...grpc.CallOption) (ClientDaemon_WatchBuildClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ClientDaemon_serviceDesc.Streams[2], "/buildbarn.clientd.ClientDaemon/WatchBuild", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clientDaemonWatchBuildClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClientDaemon_WatchBuildClient interface {
+	Recv() (*BuildEvent, error)
+	grpc.ClientStream
+}
+
+type clientDaemonWatchBuildClient struct {
+	grpc.ClientStream
+}
+
+func (x *clientDaemonWatchBuildClient) Recv() (*BuildEvent, error) {
+	m := new(BuildEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *clientDaemonClient) BatchCreateWithIdempotencyToken(ctx context.Context, in *BatchCreateWithIdempotencyTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchCreateWithIdempotencyToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchCreateWithInlineTrees(ctx context.Context, in *BatchCreateWithInlineTreesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchCreateWithInlineTrees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchCreateContinuingOnError(ctx context.Context, in *remoteoutputservice.BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateContinuingOnErrorResponse, error) {
+	out := new(BatchCreateContinuingOnErrorResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchCreateContinuingOnError", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchCreateStream(ctx context.Context, opts ...grpc.CallOption) (ClientDaemon_BatchCreateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ClientDaemon_serviceDesc.Streams[3], "/buildbarn.clientd.ClientDaemon/BatchCreateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clientDaemonBatchCreateStreamClient{stream}
+	return x, nil
+}
+
+type ClientDaemon_BatchCreateStreamClient interface {
+	Send(*remoteoutputservice.BatchCreateRequest) error
+	CloseAndRecv() (*emptypb.Empty, error)
+	grpc.ClientStream
+}
+
+type clientDaemonBatchCreateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *clientDaemonBatchCreateStreamClient) Send(m *remoteoutputservice.BatchCreateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *clientDaemonBatchCreateStreamClient) CloseAndRecv() (*emptypb.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(emptypb.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *clientDaemonClient) BatchCreateLinks(ctx context.Context, in *BatchCreateLinksRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchCreateLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchRemove(ctx context.Context, in *BatchRemoveRequest, opts ...grpc.CallOption) (*BatchRemoveResponse, error) {
+	out := new(BatchRemoveResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchRemove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) TrimBuild(ctx context.Context, in *TrimBuildRequest, opts ...grpc.CallOption) (*TrimBuildResponse, error) {
+	out := new(TrimBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/TrimBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) ExportPath(ctx context.Context, in *ExportPathRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/ExportPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchStatWithDigestInclusionMode(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*remoteoutputservice.BatchStatResponse, error) {
+	out := new(remoteoutputservice.BatchStatResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchStatWithDigestInclusionMode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchStatWithExecutableBits(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*BatchStatWithExecutableBitsResponse, error) {
+	out := new(BatchStatWithExecutableBitsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchStatWithExecutableBits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchStatWithMissingPathDetails(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*BatchStatWithMissingPathDetailsResponse, error) {
+	out := new(BatchStatWithMissingPathDetailsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchStatWithMissingPathDetails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchStatPartial(ctx context.Context, in *BatchStatWithOptionsRequest, opts ...grpc.CallOption) (*BatchStatPartialResponse, error) {
+	out := new(BatchStatPartialResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchStatPartial", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) BatchStatExistence(ctx context.Context, in *remoteoutputservice.BatchStatRequest, opts ...grpc.CallOption) (*BatchStatExistenceResponse, error) {
+	out := new(BatchStatExistenceResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/BatchStatExistence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) ReadDirectory(ctx context.Context, in *ReadDirectoryRequest, opts ...grpc.CallOption) (*ReadDirectoryResponse, error) {
+	out := new(ReadDirectoryResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/ReadDirectory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) GetFileContents(ctx context.Context, in *GetFileContentsRequest, opts ...grpc.CallOption) (*GetFileContentsResponse, error) {
+	out := new(GetFileContentsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/GetFileContents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) ReadFileRange(ctx context.Context, in *ReadFileRangeRequest, opts ...grpc.CallOption) (*ReadFileRangeResponse, error) {
+	out := new(ReadFileRangeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/ReadFileRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) ResolveSymlink(ctx context.Context, in *ResolveSymlinkRequest, opts ...grpc.CallOption) (*remoteoutputservice.StatResponse, error) {
+	out := new(remoteoutputservice.StatResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/ResolveSymlink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) FinalizeBuildWithStatistics(ctx context.Context, in *remoteoutputservice.FinalizeBuildRequest, opts ...grpc.CallOption) (*FinalizeBuildWithStatisticsResponse, error) {
+	out := new(FinalizeBuildWithStatisticsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/FinalizeBuildWithStatistics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) FinalizeBuildWithRemovedChildren(ctx context.Context, in *remoteoutputservice.FinalizeBuildRequest, opts ...grpc.CallOption) (*FinalizeBuildWithRemovedChildrenResponse, error) {
+	out := new(FinalizeBuildWithRemovedChildrenResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/FinalizeBuildWithRemovedChildren", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) FinalizeBuildWithSnapshot(ctx context.Context, in *FinalizeBuildWithSnapshotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/FinalizeBuildWithSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientDaemonClient) CancelBuild(ctx context.Context, in *CancelBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.clientd.ClientDaemon/CancelBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClientDaemonServer is the server API for ClientDaemon service.
+type ClientDaemonServer interface {
+	CleanStreaming(*remoteoutputservice.CleanRequest, ClientDaemon_CleanStreamingServer) error
+	CleanPathPrefix(context.Context, *CleanPathPrefixRequest) (*emptypb.Empty, error)
+	CleanDryRun(context.Context, *CleanDryRunRequest) (*CleanDryRunResponse, error)
+	StartBuildAsynchronously(context.Context, *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error)
+	StartBuildFromSnapshot(context.Context, *StartBuildFromSnapshotRequest) (*remoteoutputservice.StartBuildResponse, error)
+	StartBuildForcingReset(context.Context, *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error)
+	StartBuildWithDigestFunctions(context.Context, *StartBuildWithDigestFunctionsRequest) (*StartBuildWithDigestFunctionsResponse, error)
+	RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*remoteoutputservice.StartBuildResponse, error)
+	PrepareOutputBase(context.Context, *PrepareOutputBaseRequest) (*emptypb.Empty, error)
+	GetOutputPathTree(context.Context, *GetOutputPathTreeRequest) (*GetOutputPathTreeResponse, error)
+	WatchOutputPathRemovals(*emptypb.Empty, ClientDaemon_WatchOutputPathRemovalsServer) error
+	Checkpoint(context.Context, *emptypb.Empty) (*CheckpointResponse, error)
+	CloneOutputPath(context.Context, *CloneOutputPathRequest) (*emptypb.Empty, error)
+	RenameOutputBase(context.Context, *RenameOutputBaseRequest) (*emptypb.Empty, error)
+	DiagnoseSelfTest(context.Context, *DiagnoseSelfTestRequest) (*DiagnoseSelfTestResponse, error)
+	CheckHealth(context.Context, *emptypb.Empty) (*CheckHealthResponse, error)
+	GetRuntimeStatistics(context.Context, *emptypb.Empty) (*GetRuntimeStatisticsResponse, error)
+	GetOutputPathLastAccessTime(context.Context, *GetOutputPathLastAccessTimeRequest) (*GetOutputPathLastAccessTimeResponse, error)
+	GetOutputBaseStatistics(context.Context, *GetOutputBaseStatisticsRequest) (*GetOutputBaseStatisticsResponse, error)
+	GetBuildStatus(context.Context, *GetBuildStatusRequest) (*GetBuildStatusResponse, error)
+	GetMissingBlobs(context.Context, *GetMissingBlobsRequest) (*GetMissingBlobsResponse, error)
+	GetExecutableBitChanges(context.Context, *GetExecutableBitChangesRequest) (*GetExecutableBitChangesResponse, error)
+	GetInitialOutputPathContents(context.Context, *GetInitialOutputPathContentsRequest) (*GetInitialOutputPathContentsResponse, error)
+	GetBuildErrors(context.Context, *GetBuildErrorsRequest) (*GetBuildErrorsResponse, error)
+	GetOutputBasePath(context.Context, *GetOutputBasePathRequest) (*GetOutputBasePathResponse, error)
+	PingBuild(context.Context, *PingBuildRequest) (*emptypb.Empty, error)
+	WatchBuild(*WatchBuildRequest, ClientDaemon_WatchBuildServer) error
+	BatchCreateWithIdempotencyToken(context.Context, *BatchCreateWithIdempotencyTokenRequest) (*emptypb.Empty, error)
+	BatchCreateWithInlineTrees(context.Context, *BatchCreateWithInlineTreesRequest) (*emptypb.Empty, error)
+	BatchCreateContinuingOnError(context.Context, *remoteoutputservice.BatchCreateRequest) (*BatchCreateContinuingOnErrorResponse, error)
+	BatchCreateStream(ClientDaemon_BatchCreateStreamServer) error
+	BatchCreateLinks(context.Context, *BatchCreateLinksRequest) (*emptypb.Empty, error)
+	BatchRemove(context.Context, *BatchRemoveRequest) (*BatchRemoveResponse, error)
+	TrimBuild(context.Context, *TrimBuildRequest) (*TrimBuildResponse, error)
+	ExportPath(context.Context, *ExportPathRequest) (*emptypb.Empty, error)
+	BatchStatWithDigestInclusionMode(context.Context, *BatchStatWithOptionsRequest) (*remoteoutputservice.BatchStatResponse, error)
+	BatchStatWithExecutableBits(context.Context, *BatchStatWithOptionsRequest) (*BatchStatWithExecutableBitsResponse, error)
+	BatchStatWithMissingPathDetails(context.Context, *BatchStatWithOptionsRequest) (*BatchStatWithMissingPathDetailsResponse, error)
+	BatchStatPartial(context.Context, *BatchStatWithOptionsRequest) (*BatchStatPartialResponse, error)
+	BatchStatExistence(context.Context, *remoteoutputservice.BatchStatRequest) (*BatchStatExistenceResponse, error)
+	ReadDirectory(context.Context, *ReadDirectoryRequest) (*ReadDirectoryResponse, error)
+	GetFileContents(context.Context, *GetFileContentsRequest) (*GetFileContentsResponse, error)
+	ReadFileRange(context.Context, *ReadFileRangeRequest) (*ReadFileRangeResponse, error)
+	ResolveSymlink(context.Context, *ResolveSymlinkRequest) (*remoteoutputservice.StatResponse, error)
+	FinalizeBuildWithStatistics(context.Context, *remoteoutputservice.FinalizeBuildRequest) (*FinalizeBuildWithStatisticsResponse, error)
+	FinalizeBuildWithRemovedChildren(context.Context, *remoteoutputservice.FinalizeBuildRequest) (*FinalizeBuildWithRemovedChildrenResponse, error)
+	FinalizeBuildWithSnapshot(context.Context, *FinalizeBuildWithSnapshotRequest) (*emptypb.Empty, error)
+	CancelBuild(context.Context, *CancelBuildRequest) (*emptypb.Empty, error)
+}
+
+// UnimplementedClientDaemonServer can be embedded to have forward compatible implementations.
+type UnimplementedClientDaemonServer struct {
+}
+
+func (*UnimplementedClientDaemonServer) CleanStreaming(*remoteoutputservice.CleanRequest, ClientDaemon_CleanStreamingServer) error {
+	return status1.Errorf(codes.Unimplemented, "method CleanStreaming not implemented")
+}
+func (*UnimplementedClientDaemonServer) CleanPathPrefix(context.Context, *CleanPathPrefixRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CleanPathPrefix not implemented")
+}
+func (*UnimplementedClientDaemonServer) CleanDryRun(context.Context, *CleanDryRunRequest) (*CleanDryRunResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CleanDryRun not implemented")
+}
+func (*UnimplementedClientDaemonServer) StartBuildAsynchronously(context.Context, *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method StartBuildAsynchronously not implemented")
+}
+func (*UnimplementedClientDaemonServer) StartBuildFromSnapshot(context.Context, *StartBuildFromSnapshotRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method StartBuildFromSnapshot not implemented")
+}
+func (*UnimplementedClientDaemonServer) StartBuildForcingReset(context.Context, *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method StartBuildForcingReset not implemented")
+}
+func (*UnimplementedClientDaemonServer) StartBuildWithDigestFunctions(context.Context, *StartBuildWithDigestFunctionsRequest) (*StartBuildWithDigestFunctionsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method StartBuildWithDigestFunctions not implemented")
+}
+func (*UnimplementedClientDaemonServer) RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method RestoreSnapshot not implemented")
+}
+func (*UnimplementedClientDaemonServer) PrepareOutputBase(context.Context, *PrepareOutputBaseRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method PrepareOutputBase not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetOutputPathTree(context.Context, *GetOutputPathTreeRequest) (*GetOutputPathTreeResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputPathTree not implemented")
+}
+func (*UnimplementedClientDaemonServer) WatchOutputPathRemovals(*emptypb.Empty, ClientDaemon_WatchOutputPathRemovalsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method WatchOutputPathRemovals not implemented")
+}
+func (*UnimplementedClientDaemonServer) Checkpoint(context.Context, *emptypb.Empty) (*CheckpointResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method Checkpoint not implemented")
+}
+func (*UnimplementedClientDaemonServer) CloneOutputPath(context.Context, *CloneOutputPathRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CloneOutputPath not implemented")
+}
+func (*UnimplementedClientDaemonServer) RenameOutputBase(context.Context, *RenameOutputBaseRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method RenameOutputBase not implemented")
+}
+func (*UnimplementedClientDaemonServer) DiagnoseSelfTest(context.Context, *DiagnoseSelfTestRequest) (*DiagnoseSelfTestResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method DiagnoseSelfTest not implemented")
+}
+func (*UnimplementedClientDaemonServer) CheckHealth(context.Context, *emptypb.Empty) (*CheckHealthResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CheckHealth not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetRuntimeStatistics(context.Context, *emptypb.Empty) (*GetRuntimeStatisticsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetRuntimeStatistics not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetOutputPathLastAccessTime(context.Context, *GetOutputPathLastAccessTimeRequest) (*GetOutputPathLastAccessTimeResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputPathLastAccessTime not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetOutputBaseStatistics(context.Context, *GetOutputBaseStatisticsRequest) (*GetOutputBaseStatisticsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputBaseStatistics not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetBuildStatus(context.Context, *GetBuildStatusRequest) (*GetBuildStatusResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetBuildStatus not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetMissingBlobs(context.Context, *GetMissingBlobsRequest) (*GetMissingBlobsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetMissingBlobs not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetExecutableBitChanges(context.Context, *GetExecutableBitChangesRequest) (*GetExecutableBitChangesResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetExecutableBitChanges not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetInitialOutputPathContents(context.Context, *GetInitialOutputPathContentsRequest) (*GetInitialOutputPathContentsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetInitialOutputPathContents not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetBuildErrors(context.Context, *GetBuildErrorsRequest) (*GetBuildErrorsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetBuildErrors not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetOutputBasePath(context.Context, *GetOutputBasePathRequest) (*GetOutputBasePathResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputBasePath not implemented")
+}
+func (*UnimplementedClientDaemonServer) PingBuild(context.Context, *PingBuildRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method PingBuild not implemented")
+}
+func (*UnimplementedClientDaemonServer) WatchBuild(*WatchBuildRequest, ClientDaemon_WatchBuildServer) error {
+	return status1.Errorf(codes.Unimplemented, "method WatchBuild not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchCreateWithIdempotencyToken(context.Context, *BatchCreateWithIdempotencyTokenRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchCreateWithIdempotencyToken not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchCreateWithInlineTrees(context.Context, *BatchCreateWithInlineTreesRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchCreateWithInlineTrees not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchCreateContinuingOnError(context.Context, *remoteoutputservice.BatchCreateRequest) (*BatchCreateContinuingOnErrorResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchCreateContinuingOnError not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchCreateStream(ClientDaemon_BatchCreateStreamServer) error {
+	return status1.Errorf(codes.Unimplemented, "method BatchCreateStream not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchCreateLinks(context.Context, *BatchCreateLinksRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchCreateLinks not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchRemove(context.Context, *BatchRemoveRequest) (*BatchRemoveResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchRemove not implemented")
+}
+func (*UnimplementedClientDaemonServer) TrimBuild(context.Context, *TrimBuildRequest) (*TrimBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method TrimBuild not implemented")
+}
+func (*UnimplementedClientDaemonServer) ExportPath(context.Context, *ExportPathRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ExportPath not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchStatWithDigestInclusionMode(context.Context, *BatchStatWithOptionsRequest) (*remoteoutputservice.BatchStatResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchStatWithDigestInclusionMode not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchStatWithExecutableBits(context.Context, *BatchStatWithOptionsRequest) (*BatchStatWithExecutableBitsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchStatWithExecutableBits not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchStatWithMissingPathDetails(context.Context, *BatchStatWithOptionsRequest) (*BatchStatWithMissingPathDetailsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchStatWithMissingPathDetails not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchStatPartial(context.Context, *BatchStatWithOptionsRequest) (*BatchStatPartialResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchStatPartial not implemented")
+}
+func (*UnimplementedClientDaemonServer) BatchStatExistence(context.Context, *remoteoutputservice.BatchStatRequest) (*BatchStatExistenceResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchStatExistence not implemented")
+}
+func (*UnimplementedClientDaemonServer) ReadDirectory(context.Context, *ReadDirectoryRequest) (*ReadDirectoryResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ReadDirectory not implemented")
+}
+func (*UnimplementedClientDaemonServer) GetFileContents(context.Context, *GetFileContentsRequest) (*GetFileContentsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetFileContents not implemented")
+}
+func (*UnimplementedClientDaemonServer) ReadFileRange(context.Context, *ReadFileRangeRequest) (*ReadFileRangeResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ReadFileRange not implemented")
+}
+func (*UnimplementedClientDaemonServer) ResolveSymlink(context.Context, *ResolveSymlinkRequest) (*remoteoutputservice.StatResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ResolveSymlink not implemented")
+}
+func (*UnimplementedClientDaemonServer) FinalizeBuildWithStatistics(context.Context, *remoteoutputservice.FinalizeBuildRequest) (*FinalizeBuildWithStatisticsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method FinalizeBuildWithStatistics not implemented")
+}
+func (*UnimplementedClientDaemonServer) FinalizeBuildWithRemovedChildren(context.Context, *remoteoutputservice.FinalizeBuildRequest) (*FinalizeBuildWithRemovedChildrenResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method FinalizeBuildWithRemovedChildren not implemented")
+}
+func (*UnimplementedClientDaemonServer) FinalizeBuildWithSnapshot(context.Context, *FinalizeBuildWithSnapshotRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method FinalizeBuildWithSnapshot not implemented")
+}
+func (*UnimplementedClientDaemonServer) CancelBuild(context.Context, *CancelBuildRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CancelBuild not implemented")
+}
+
+func RegisterClientDaemonServer(s grpc.ServiceRegistrar, srv ClientDaemonServer) {
+	s.RegisterService(&_ClientDaemon_serviceDesc, srv)
+}
+
+func _ClientDaemon_CleanStreaming_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(remoteoutputservice.CleanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClientDaemonServer).CleanStreaming(m, &clientDaemonCleanStreamingServer{stream})
+}
+
+type ClientDaemon_CleanStreamingServer interface {
+	Send(*CleanProgress) error
+	grpc.ServerStream
+}
+
+type clientDaemonCleanStreamingServer struct {
+	grpc.ServerStream
+}
+
+func (x *clientDaemonCleanStreamingServer) Send(m *CleanProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ClientDaemon_CleanPathPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanPathPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).CleanPathPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/CleanPathPrefix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).CleanPathPrefix(ctx, req.(*CleanPathPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_CleanDryRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanDryRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).CleanDryRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/CleanDryRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).CleanDryRun(ctx, req.(*CleanDryRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_StartBuildAsynchronously_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.StartBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).StartBuildAsynchronously(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/StartBuildAsynchronously",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).StartBuildAsynchronously(ctx, req.(*remoteoutputservice.StartBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_StartBuildFromSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartBuildFromSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).StartBuildFromSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/StartBuildFromSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).StartBuildFromSnapshot(ctx, req.(*StartBuildFromSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_StartBuildForcingReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.StartBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).StartBuildForcingReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/StartBuildForcingReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).StartBuildForcingReset(ctx, req.(*remoteoutputservice.StartBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_StartBuildWithDigestFunctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartBuildWithDigestFunctionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).StartBuildWithDigestFunctions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/StartBuildWithDigestFunctions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).StartBuildWithDigestFunctions(ctx, req.(*StartBuildWithDigestFunctionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_RestoreSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).RestoreSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/RestoreSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).RestoreSnapshot(ctx, req.(*RestoreSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_PrepareOutputBase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareOutputBaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).PrepareOutputBase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/PrepareOutputBase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).PrepareOutputBase(ctx, req.(*PrepareOutputBaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetOutputPathTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputPathTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetOutputPathTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetOutputPathTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetOutputPathTree(ctx, req.(*GetOutputPathTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_WatchOutputPathRemovals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClientDaemonServer).WatchOutputPathRemovals(m, &clientDaemonWatchOutputPathRemovalsServer{stream})
+}
+
+type ClientDaemon_WatchOutputPathRemovalsServer interface {
+	Send(*OutputPathRemovalEvent) error
+	grpc.ServerStream
+}
+
+type clientDaemonWatchOutputPathRemovalsServer struct {
+	grpc.ServerStream
+}
+
+func (x *clientDaemonWatchOutputPathRemovalsServer) Send(m *OutputPathRemovalEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ClientDaemon_Checkpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).Checkpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/Checkpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).Checkpoint(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_CloneOutputPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneOutputPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).CloneOutputPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/CloneOutputPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).CloneOutputPath(ctx, req.(*CloneOutputPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_RenameOutputBase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameOutputBaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).RenameOutputBase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/RenameOutputBase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).RenameOutputBase(ctx, req.(*RenameOutputBaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_DiagnoseSelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiagnoseSelfTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).DiagnoseSelfTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/DiagnoseSelfTest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).DiagnoseSelfTest(ctx, req.(*DiagnoseSelfTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_CheckHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).CheckHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/CheckHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).CheckHealth(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetRuntimeStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetRuntimeStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetRuntimeStatistics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetRuntimeStatistics(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetOutputPathLastAccessTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputPathLastAccessTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetOutputPathLastAccessTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetOutputPathLastAccessTime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetOutputPathLastAccessTime(ctx, req.(*GetOutputPathLastAccessTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetOutputBaseStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputBaseStatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetOutputBaseStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetOutputBaseStatistics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetOutputBaseStatistics(ctx, req.(*GetOutputBaseStatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetBuildStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBuildStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetBuildStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetBuildStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetBuildStatus(ctx, req.(*GetBuildStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetMissingBlobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMissingBlobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetMissingBlobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetMissingBlobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetMissingBlobs(ctx, req.(*GetMissingBlobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetExecutableBitChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExecutableBitChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetExecutableBitChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetExecutableBitChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetExecutableBitChanges(ctx, req.(*GetExecutableBitChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetInitialOutputPathContents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInitialOutputPathContentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetInitialOutputPathContents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetInitialOutputPathContents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetInitialOutputPathContents(ctx, req.(*GetInitialOutputPathContentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetBuildErrors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBuildErrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetBuildErrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetBuildErrors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetBuildErrors(ctx, req.(*GetBuildErrorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetOutputBasePath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputBasePathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetOutputBasePath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetOutputBasePath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetOutputBasePath(ctx, req.(*GetOutputBasePathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_PingBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).PingBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/PingBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).PingBuild(ctx, req.(*PingBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_WatchBuild_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBuildRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClientDaemonServer).WatchBuild(m, &clientDaemonWatchBuildServer{stream})
+}
+
+type ClientDaemon_WatchBuildServer interface {
+	Send(*BuildEvent) error
+	grpc.ServerStream
+}
+
+type clientDaemonWatchBuildServer struct {
+	grpc.ServerStream
+}
+
+func (x *clientDaemonWatchBuildServer) Send(m *BuildEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ClientDaemon_BatchCreateWithIdempotencyToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateWithIdempotencyTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchCreateWithIdempotencyToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchCreateWithIdempotencyToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchCreateWithIdempotencyToken(ctx, req.(*BatchCreateWithIdempotencyTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchCreateWithInlineTrees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateWithInlineTreesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchCreateWithInlineTrees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchCreateWithInlineTrees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchCreateWithInlineTrees(ctx, req.(*BatchCreateWithInlineTreesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchCreateContinuingOnError_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchCreateContinuingOnError(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchCreateContinuingOnError",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchCreateContinuingOnError(ctx, req.(*remoteoutputservice.BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchCreateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ClientDaemonServer).BatchCreateStream(&clientDaemonBatchCreateStreamServer{stream})
+}
+
+type ClientDaemon_BatchCreateStreamServer interface {
+	SendAndClose(*emptypb.Empty) error
+	Recv() (*remoteoutputservice.BatchCreateRequest, error)
+	grpc.ServerStream
+}
+
+type clientDaemonBatchCreateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *clientDaemonBatchCreateStreamServer) SendAndClose(m *emptypb.Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *clientDaemonBatchCreateStreamServer) Recv() (*remoteoutputservice.BatchCreateRequest, error) {
+	m := new(remoteoutputservice.BatchCreateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ClientDaemon_BatchCreateLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchCreateLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchCreateLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchCreateLinks(ctx, req.(*BatchCreateLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchRemove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchRemove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchRemove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchRemove(ctx, req.(*BatchRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_TrimBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrimBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).TrimBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/TrimBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).TrimBuild(ctx, req.(*TrimBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_ExportPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).ExportPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/ExportPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).ExportPath(ctx, req.(*ExportPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchStatWithDigestInclusionMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchStatWithOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchStatWithDigestInclusionMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchStatWithDigestInclusionMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchStatWithDigestInclusionMode(ctx, req.(*BatchStatWithOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchStatWithExecutableBits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchStatWithOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchStatWithExecutableBits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchStatWithExecutableBits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchStatWithExecutableBits(ctx, req.(*BatchStatWithOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchStatWithMissingPathDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchStatWithOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchStatWithMissingPathDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchStatWithMissingPathDetails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchStatWithMissingPathDetails(ctx, req.(*BatchStatWithOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchStatPartial_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchStatWithOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchStatPartial(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchStatPartial",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchStatPartial(ctx, req.(*BatchStatWithOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_BatchStatExistence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.BatchStatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).BatchStatExistence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/BatchStatExistence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).BatchStatExistence(ctx, req.(*remoteoutputservice.BatchStatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_ReadDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).ReadDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/ReadDirectory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).ReadDirectory(ctx, req.(*ReadDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_GetFileContents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileContentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).GetFileContents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/GetFileContents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).GetFileContents(ctx, req.(*GetFileContentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_ReadFileRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadFileRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).ReadFileRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/ReadFileRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).ReadFileRange(ctx, req.(*ReadFileRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_ResolveSymlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveSymlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).ResolveSymlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/ResolveSymlink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).ResolveSymlink(ctx, req.(*ResolveSymlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_FinalizeBuildWithStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.FinalizeBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).FinalizeBuildWithStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/FinalizeBuildWithStatistics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).FinalizeBuildWithStatistics(ctx, req.(*remoteoutputservice.FinalizeBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_FinalizeBuildWithRemovedChildren_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.FinalizeBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).FinalizeBuildWithRemovedChildren(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/FinalizeBuildWithRemovedChildren",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).FinalizeBuildWithRemovedChildren(ctx, req.(*remoteoutputservice.FinalizeBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_FinalizeBuildWithSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizeBuildWithSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).FinalizeBuildWithSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/FinalizeBuildWithSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).FinalizeBuildWithSnapshot(ctx, req.(*FinalizeBuildWithSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientDaemon_CancelBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientDaemonServer).CancelBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.clientd.ClientDaemon/CancelBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientDaemonServer).CancelBuild(ctx, req.(*CancelBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ClientDaemon_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "buildbarn.clientd.ClientDaemon",
+	HandlerType: (*ClientDaemonServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CleanPathPrefix",
+			Handler:    _ClientDaemon_CleanPathPrefix_Handler,
+		},
+		{
+			MethodName: "CleanDryRun",
+			Handler:    _ClientDaemon_CleanDryRun_Handler,
+		},
+		{
+			MethodName: "StartBuildAsynchronously",
+			Handler:    _ClientDaemon_StartBuildAsynchronously_Handler,
+		},
+		{
+			MethodName: "StartBuildFromSnapshot",
+			Handler:    _ClientDaemon_StartBuildFromSnapshot_Handler,
+		},
+		{
+			MethodName: "StartBuildForcingReset",
+			Handler:    _ClientDaemon_StartBuildForcingReset_Handler,
+		},
+		{
+			MethodName: "StartBuildWithDigestFunctions",
+			Handler:    _ClientDaemon_StartBuildWithDigestFunctions_Handler,
+		},
+		{
+			MethodName: "RestoreSnapshot",
+			Handler:    _ClientDaemon_RestoreSnapshot_Handler,
+		},
+		{
+			MethodName: "PrepareOutputBase",
+			Handler:    _ClientDaemon_PrepareOutputBase_Handler,
+		},
+		{
+			MethodName: "GetOutputPathTree",
+			Handler:    _ClientDaemon_GetOutputPathTree_Handler,
+		},
+		{
+			MethodName: "Checkpoint",
+			Handler:    _ClientDaemon_Checkpoint_Handler,
+		},
+		{
+			MethodName: "CloneOutputPath",
+			Handler:    _ClientDaemon_CloneOutputPath_Handler,
+		},
+		{
+			MethodName: "RenameOutputBase",
+			Handler:    _ClientDaemon_RenameOutputBase_Handler,
+		},
+		{
+			MethodName: "DiagnoseSelfTest",
+			Handler:    _ClientDaemon_DiagnoseSelfTest_Handler,
+		},
+		{
+			MethodName: "CheckHealth",
+			Handler:    _ClientDaemon_CheckHealth_Handler,
+		},
+		{
+			MethodName: "GetRuntimeStatistics",
+			Handler:    _ClientDaemon_GetRuntimeStatistics_Handler,
+		},
+		{
+			MethodName: "GetOutputPathLastAccessTime",
+			Handler:    _ClientDaemon_GetOutputPathLastAccessTime_Handler,
+		},
+		{
+			MethodName: "GetOutputBaseStatistics",
+			Handler:    _ClientDaemon_GetOutputBaseStatistics_Handler,
+		},
+		{
+			MethodName: "GetBuildStatus",
+			Handler:    _ClientDaemon_GetBuildStatus_Handler,
+		},
+		{
+			MethodName: "GetMissingBlobs",
+			Handler:    _ClientDaemon_GetMissingBlobs_Handler,
+		},
+		{
+			MethodName: "GetExecutableBitChanges",
+			Handler:    _ClientDaemon_GetExecutableBitChanges_Handler,
+		},
+		{
+			MethodName: "GetInitialOutputPathContents",
+			Handler:    _ClientDaemon_GetInitialOutputPathContents_Handler,
+		},
+		{
+			MethodName: "GetBuildErrors",
+			Handler:    _ClientDaemon_GetBuildErrors_Handler,
+		},
+		{
+			MethodName: "GetOutputBasePath",
+			Handler:    _ClientDaemon_GetOutputBasePath_Handler,
+		},
+		{
+			MethodName: "PingBuild",
+			Handler:    _ClientDaemon_PingBuild_Handler,
+		},
+		{
+			MethodName: "BatchCreateWithIdempotencyToken",
+			Handler:    _ClientDaemon_BatchCreateWithIdempotencyToken_Handler,
+		},
+		{
+			MethodName: "BatchCreateWithInlineTrees",
+			Handler:    _ClientDaemon_BatchCreateWithInlineTrees_Handler,
+		},
+		{
+			MethodName: "BatchCreateContinuingOnError",
+			Handler:    _ClientDaemon_BatchCreateContinuingOnError_Handler,
+		},
+		{
+			MethodName: "BatchCreateLinks",
+			Handler:    _ClientDaemon_BatchCreateLinks_Handler,
+		},
+		{
+			MethodName: "BatchRemove",
+			Handler:    _ClientDaemon_BatchRemove_Handler,
+		},
+		{
+			MethodName: "TrimBuild",
+			Handler:    _ClientDaemon_TrimBuild_Handler,
+		},
+		{
+			MethodName: "ExportPath",
+			Handler:    _ClientDaemon_ExportPath_Handler,
+		},
+		{
+			MethodName: "BatchStatWithDigestInclusionMode",
+			Handler:    _ClientDaemon_BatchStatWithDigestInclusionMode_Handler,
+		},
+		{
+			MethodName: "BatchStatWithExecutableBits",
+			Handler:    _ClientDaemon_BatchStatWithExecutableBits_Handler,
+		},
+		{
+			MethodName: "BatchStatWithMissingPathDetails",
+			Handler:    _ClientDaemon_BatchStatWithMissingPathDetails_Handler,
+		},
+		{
+			MethodName: "BatchStatPartial",
+			Handler:    _ClientDaemon_BatchStatPartial_Handler,
+		},
+		{
+			MethodName: "BatchStatExistence",
+			Handler:    _ClientDaemon_BatchStatExistence_Handler,
+		},
+		{
+			MethodName: "ReadDirectory",
+			Handler:    _ClientDaemon_ReadDirectory_Handler,
+		},
+		{
+			MethodName: "GetFileContents",
+			Handler:    _ClientDaemon_GetFileContents_Handler,
+		},
+		{
+			MethodName: "ReadFileRange",
+			Handler:    _ClientDaemon_ReadFileRange_Handler,
+		},
+		{
+			MethodName: "ResolveSymlink",
+			Handler:    _ClientDaemon_ResolveSymlink_Handler,
+		},
+		{
+			MethodName: "FinalizeBuildWithStatistics",
+			Handler:    _ClientDaemon_FinalizeBuildWithStatistics_Handler,
+		},
+		{
+			MethodName: "FinalizeBuildWithRemovedChildren",
+			Handler:    _ClientDaemon_FinalizeBuildWithRemovedChildren_Handler,
+		},
+		{
+			MethodName: "FinalizeBuildWithSnapshot",
+			Handler:    _ClientDaemon_FinalizeBuildWithSnapshot_Handler,
+		},
+		{
+			MethodName: "CancelBuild",
+			Handler:    _ClientDaemon_CancelBuild_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CleanStreaming",
+			Handler:       _ClientDaemon_CleanStreaming_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchOutputPathRemovals",
+			Handler:       _ClientDaemon_WatchOutputPathRemovals_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBuild",
+			Handler:       _ClientDaemon_WatchBuild_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BatchCreateStream",
+			Handler:       _ClientDaemon_BatchCreateStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/proto/clientd/clientd.proto",
+}
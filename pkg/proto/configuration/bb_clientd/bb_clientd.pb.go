@@ -33,17 +33,19 @@ type ApplicationConfiguration struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Blobstore                   *blobstore.BlobstoreConfiguration          `protobuf:"bytes,1,opt,name=blobstore,proto3" json:"blobstore,omitempty"`
-	MaximumMessageSizeBytes     int64                                      `protobuf:"varint,2,opt,name=maximum_message_size_bytes,json=maximumMessageSizeBytes,proto3" json:"maximum_message_size_bytes,omitempty"`
-	MaximumTreeSizeBytes        int64                                      `protobuf:"varint,11,opt,name=maximum_tree_size_bytes,json=maximumTreeSizeBytes,proto3" json:"maximum_tree_size_bytes,omitempty"`
-	Global                      *global.Configuration                      `protobuf:"bytes,3,opt,name=global,proto3" json:"global,omitempty"`
-	Mount                       *virtual.MountConfiguration                `protobuf:"bytes,4,opt,name=mount,proto3" json:"mount,omitempty"`
-	GrpcServers                 []*grpc.ServerConfiguration                `protobuf:"bytes,5,rep,name=grpc_servers,json=grpcServers,proto3" json:"grpc_servers,omitempty"`
-	Schedulers                  map[string]*builder.SchedulerConfiguration `protobuf:"bytes,6,rep,name=schedulers,proto3" json:"schedulers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	FilePool                    *filesystem.FilePoolConfiguration          `protobuf:"bytes,7,opt,name=file_pool,json=filePool,proto3" json:"file_pool,omitempty"`
-	OutputPathPersistency       *OutputPathPersistencyConfiguration        `protobuf:"bytes,8,opt,name=output_path_persistency,json=outputPathPersistency,proto3" json:"output_path_persistency,omitempty"`
-	MaximumFileSystemRetryDelay *durationpb.Duration                       `protobuf:"bytes,9,opt,name=maximum_file_system_retry_delay,json=maximumFileSystemRetryDelay,proto3" json:"maximum_file_system_retry_delay,omitempty"`
-	DirectoryCache              *cas.CachingDirectoryFetcherConfiguration  `protobuf:"bytes,10,opt,name=directory_cache,json=directoryCache,proto3" json:"directory_cache,omitempty"`
+	Blobstore                            *blobstore.BlobstoreConfiguration                  `protobuf:"bytes,1,opt,name=blobstore,proto3" json:"blobstore,omitempty"`
+	MaximumMessageSizeBytes              int64                                              `protobuf:"varint,2,opt,name=maximum_message_size_bytes,json=maximumMessageSizeBytes,proto3" json:"maximum_message_size_bytes,omitempty"`
+	MaximumTreeSizeBytes                 int64                                              `protobuf:"varint,11,opt,name=maximum_tree_size_bytes,json=maximumTreeSizeBytes,proto3" json:"maximum_tree_size_bytes,omitempty"`
+	Global                               *global.Configuration                              `protobuf:"bytes,3,opt,name=global,proto3" json:"global,omitempty"`
+	Mount                                *virtual.MountConfiguration                        `protobuf:"bytes,4,opt,name=mount,proto3" json:"mount,omitempty"`
+	GrpcServers                          []*grpc.ServerConfiguration                        `protobuf:"bytes,5,rep,name=grpc_servers,json=grpcServers,proto3" json:"grpc_servers,omitempty"`
+	Schedulers                           map[string]*builder.SchedulerConfiguration         `protobuf:"bytes,6,rep,name=schedulers,proto3" json:"schedulers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	FilePool                             *filesystem.FilePoolConfiguration                  `protobuf:"bytes,7,opt,name=file_pool,json=filePool,proto3" json:"file_pool,omitempty"`
+	OutputPathPersistency                *OutputPathPersistencyConfiguration                `protobuf:"bytes,8,opt,name=output_path_persistency,json=outputPathPersistency,proto3" json:"output_path_persistency,omitempty"`
+	MaximumFileSystemRetryDelay          *durationpb.Duration                               `protobuf:"bytes,9,opt,name=maximum_file_system_retry_delay,json=maximumFileSystemRetryDelay,proto3" json:"maximum_file_system_retry_delay,omitempty"`
+	DirectoryCache                       *cas.CachingDirectoryFetcherConfiguration          `protobuf:"bytes,10,opt,name=directory_cache,json=directoryCache,proto3" json:"directory_cache,omitempty"`
+	RemoteOutputService                  *RemoteOutputServiceConfiguration                  `protobuf:"bytes,12,opt,name=remote_output_service,json=remoteOutputService,proto3" json:"remote_output_service,omitempty"`
+	ContentAddressableStorageHealthCheck *ContentAddressableStorageHealthCheckConfiguration `protobuf:"bytes,13,opt,name=content_addressable_storage_health_check,json=contentAddressableStorageHealthCheck,proto3" json:"content_addressable_storage_health_check,omitempty"`
 }
 
 func (x *ApplicationConfiguration) Reset() {
@@ -155,6 +157,20 @@ func (x *ApplicationConfiguration) GetDirectoryCache() *cas.CachingDirectoryFetc
 	return nil
 }
 
+func (x *ApplicationConfiguration) GetRemoteOutputService() *RemoteOutputServiceConfiguration {
+	if x != nil {
+		return x.RemoteOutputService
+	}
+	return nil
+}
+
+func (x *ApplicationConfiguration) GetContentAddressableStorageHealthCheck() *ContentAddressableStorageHealthCheckConfiguration {
+	if x != nil {
+		return x.ContentAddressableStorageHealthCheck
+	}
+	return nil
+}
+
 type OutputPathPersistencyConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -226,6 +242,578 @@ func (x *OutputPathPersistencyConfiguration) GetLocalFileUploadConcurrency() int
 	return 0
 }
 
+type RemoteOutputServiceConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CleanCorruptedOutputPaths            bool                               `protobuf:"varint,1,opt,name=clean_corrupted_output_paths,json=cleanCorruptedOutputPaths,proto3" json:"clean_corrupted_output_paths,omitempty"`
+	SnapshotUploadConcurrency            int64                              `protobuf:"varint,2,opt,name=snapshot_upload_concurrency,json=snapshotUploadConcurrency,proto3" json:"snapshot_upload_concurrency,omitempty"`
+	FindMissingConcurrency               int64                              `protobuf:"varint,3,opt,name=find_missing_concurrency,json=findMissingConcurrency,proto3" json:"find_missing_concurrency,omitempty"`
+	FreezeOutputPathsBetweenBuilds       bool                               `protobuf:"varint,4,opt,name=freeze_output_paths_between_builds,json=freezeOutputPathsBetweenBuilds,proto3" json:"freeze_output_paths_between_builds,omitempty"`
+	MaximumFilesCountPerOutputPath       int64                              `protobuf:"varint,5,opt,name=maximum_files_count_per_output_path,json=maximumFilesCountPerOutputPath,proto3" json:"maximum_files_count_per_output_path,omitempty"`
+	MaximumSizeBytesPerOutputPath        int64                              `protobuf:"varint,6,opt,name=maximum_size_bytes_per_output_path,json=maximumSizeBytesPerOutputPath,proto3" json:"maximum_size_bytes_per_output_path,omitempty"`
+	FindMissingRetry                     *FindMissingRetryConfiguration     `protobuf:"bytes,7,opt,name=find_missing_retry,json=findMissingRetry,proto3" json:"find_missing_retry,omitempty"`
+	CaseInsensitiveOutputBaseIds         bool                               `protobuf:"varint,8,opt,name=case_insensitive_output_base_ids,json=caseInsensitiveOutputBaseIds,proto3" json:"case_insensitive_output_base_ids,omitempty"`
+	MaximumSymlinkFollowsPerPath         int64                              `protobuf:"varint,9,opt,name=maximum_symlink_follows_per_path,json=maximumSymlinkFollowsPerPath,proto3" json:"maximum_symlink_follows_per_path,omitempty"`
+	EagerlyFetchDirectories              bool                               `protobuf:"varint,10,opt,name=eagerly_fetch_directories,json=eagerlyFetchDirectories,proto3" json:"eagerly_fetch_directories,omitempty"`
+	AccessLog                            *AccessLogConfiguration            `protobuf:"bytes,11,opt,name=access_log,json=accessLog,proto3" json:"access_log,omitempty"`
+	CacheDirectoryAttributes             bool                               `protobuf:"varint,12,opt,name=cache_directory_attributes,json=cacheDirectoryAttributes,proto3" json:"cache_directory_attributes,omitempty"`
+	RejectConcurrentBuilds               bool                               `protobuf:"varint,13,opt,name=reject_concurrent_builds,json=rejectConcurrentBuilds,proto3" json:"reject_concurrent_builds,omitempty"`
+	ValidatePaths                        bool                               `protobuf:"varint,14,opt,name=validate_paths,json=validatePaths,proto3" json:"validate_paths,omitempty"`
+	PinDigestFunctionPerOutputBase       bool                               `protobuf:"varint,15,opt,name=pin_digest_function_per_output_base,json=pinDigestFunctionPerOutputBase,proto3" json:"pin_digest_function_per_output_base,omitempty"`
+	DirectoryFetchConcurrency            int64                              `protobuf:"varint,16,opt,name=directory_fetch_concurrency,json=directoryFetchConcurrency,proto3" json:"directory_fetch_concurrency,omitempty"`
+	PreserveUnchangedFiles               bool                               `protobuf:"varint,17,opt,name=preserve_unchanged_files,json=preserveUnchangedFiles,proto3" json:"preserve_unchanged_files,omitempty"`
+	MaximumEstimatedMemoryUsageBytes     int64                              `protobuf:"varint,18,opt,name=maximum_estimated_memory_usage_bytes,json=maximumEstimatedMemoryUsageBytes,proto3" json:"maximum_estimated_memory_usage_bytes,omitempty"`
+	ReadOnly                             bool                               `protobuf:"varint,19,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	FindMissingBatchSize                 int64                              `protobuf:"varint,20,opt,name=find_missing_batch_size,json=findMissingBatchSize,proto3" json:"find_missing_batch_size,omitempty"`
+	PrefetchRootDirectories              bool                               `protobuf:"varint,21,opt,name=prefetch_root_directories,json=prefetchRootDirectories,proto3" json:"prefetch_root_directories,omitempty"`
+	ConcurrentBuildGracePeriod           *durationpb.Duration               `protobuf:"bytes,22,opt,name=concurrent_build_grace_period,json=concurrentBuildGracePeriod,proto3" json:"concurrent_build_grace_period,omitempty"`
+	PreparedOutputBaseTimeout            *durationpb.Duration               `protobuf:"bytes,23,opt,name=prepared_output_base_timeout,json=preparedOutputBaseTimeout,proto3" json:"prepared_output_base_timeout,omitempty"`
+	FileDigestCacheSize                  int64                              `protobuf:"varint,24,opt,name=file_digest_cache_size,json=fileDigestCacheSize,proto3" json:"file_digest_cache_size,omitempty"`
+	ShutdownTimeout                      *durationpb.Duration               `protobuf:"bytes,25,opt,name=shutdown_timeout,json=shutdownTimeout,proto3" json:"shutdown_timeout,omitempty"`
+	MaximumInternedNamesCount            int64                              `protobuf:"varint,26,opt,name=maximum_interned_names_count,json=maximumInternedNamesCount,proto3" json:"maximum_interned_names_count,omitempty"`
+	FindMissingContentAddressableStorage *blobstore.BlobAccessConfiguration `protobuf:"bytes,27,opt,name=find_missing_content_addressable_storage,json=findMissingContentAddressableStorage,proto3" json:"find_missing_content_addressable_storage,omitempty"`
+	MaximumBatchCreateEntriesCount       int64                              `protobuf:"varint,28,opt,name=maximum_batch_create_entries_count,json=maximumBatchCreateEntriesCount,proto3" json:"maximum_batch_create_entries_count,omitempty"`
+	MaximumBatchStatPathsCount           int64                              `protobuf:"varint,29,opt,name=maximum_batch_stat_paths_count,json=maximumBatchStatPathsCount,proto3" json:"maximum_batch_stat_paths_count,omitempty"`
+	MaximumBatchRemovePathsCount         int64                              `protobuf:"varint,30,opt,name=maximum_batch_remove_paths_count,json=maximumBatchRemovePathsCount,proto3" json:"maximum_batch_remove_paths_count,omitempty"`
+	MaximumSymlinkTargetLengthBytes      int64                              `protobuf:"varint,31,opt,name=maximum_symlink_target_length_bytes,json=maximumSymlinkTargetLengthBytes,proto3" json:"maximum_symlink_target_length_bytes,omitempty"`
+	MaximumReadFileRangeSizeBytes        uint64                             `protobuf:"varint,32,opt,name=maximum_read_file_range_size_bytes,json=maximumReadFileRangeSizeBytes,proto3" json:"maximum_read_file_range_size_bytes,omitempty"`
+	DirectoryFetchTimeout                *durationpb.Duration               `protobuf:"bytes,33,opt,name=directory_fetch_timeout,json=directoryFetchTimeout,proto3" json:"directory_fetch_timeout,omitempty"`
+	InstanceNameAliases                  map[string]string                  `protobuf:"bytes,34,rep,name=instance_name_aliases,json=instanceNameAliases,proto3" json:"instance_name_aliases,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	TrackLazyDirectories                 bool                               `protobuf:"varint,35,opt,name=track_lazy_directories,json=trackLazyDirectories,proto3" json:"track_lazy_directories,omitempty"`
+	FilterMissingConcurrency             int64                              `protobuf:"varint,36,opt,name=filter_missing_concurrency,json=filterMissingConcurrency,proto3" json:"filter_missing_concurrency,omitempty"`
+	SortedDirectoryListing               bool                               `protobuf:"varint,37,opt,name=sorted_directory_listing,json=sortedDirectoryListing,proto3" json:"sorted_directory_listing,omitempty"`
+	FindMissingRevalidationInterval      *durationpb.Duration               `protobuf:"bytes,38,opt,name=find_missing_revalidation_interval,json=findMissingRevalidationInterval,proto3" json:"find_missing_revalidation_interval,omitempty"`
+	RemoveChildrenMissingDuringBuild     bool                               `protobuf:"varint,39,opt,name=remove_children_missing_during_build,json=removeChildrenMissingDuringBuild,proto3" json:"remove_children_missing_during_build,omitempty"`
+	MaximumPathDepth                     int64                              `protobuf:"varint,40,opt,name=maximum_path_depth,json=maximumPathDepth,proto3" json:"maximum_path_depth,omitempty"`
+	MaximumSnapshotsCount                int64                              `protobuf:"varint,41,opt,name=maximum_snapshots_count,json=maximumSnapshotsCount,proto3" json:"maximum_snapshots_count,omitempty"`
+	IdempotencyTokenExpiration           *durationpb.Duration               `protobuf:"bytes,42,opt,name=idempotency_token_expiration,json=idempotencyTokenExpiration,proto3" json:"idempotency_token_expiration,omitempty"`
+	MaximumIdempotencyTokensPerBuild     int64                              `protobuf:"varint,43,opt,name=maximum_idempotency_tokens_per_build,json=maximumIdempotencyTokensPerBuild,proto3" json:"maximum_idempotency_tokens_per_build,omitempty"`
+	TrackExecutableBitChanges            bool                               `protobuf:"varint,44,opt,name=track_executable_bit_changes,json=trackExecutableBitChanges,proto3" json:"track_executable_bit_changes,omitempty"`
+	CasFileReadConcurrency               int64                              `protobuf:"varint,46,opt,name=cas_file_read_concurrency,json=casFileReadConcurrency,proto3" json:"cas_file_read_concurrency,omitempty"`
+}
+
+func (x *RemoteOutputServiceConfiguration) Reset() {
+	*x = RemoteOutputServiceConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteOutputServiceConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteOutputServiceConfiguration) ProtoMessage() {}
+
+func (x *RemoteOutputServiceConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteOutputServiceConfiguration.ProtoReflect.Descriptor instead.
+func (*RemoteOutputServiceConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemoteOutputServiceConfiguration) GetCleanCorruptedOutputPaths() bool {
+	if x != nil {
+		return x.CleanCorruptedOutputPaths
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetSnapshotUploadConcurrency() int64 {
+	if x != nil {
+		return x.SnapshotUploadConcurrency
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFindMissingConcurrency() int64 {
+	if x != nil {
+		return x.FindMissingConcurrency
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFreezeOutputPathsBetweenBuilds() bool {
+	if x != nil {
+		return x.FreezeOutputPathsBetweenBuilds
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumFilesCountPerOutputPath() int64 {
+	if x != nil {
+		return x.MaximumFilesCountPerOutputPath
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumSizeBytesPerOutputPath() int64 {
+	if x != nil {
+		return x.MaximumSizeBytesPerOutputPath
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFindMissingRetry() *FindMissingRetryConfiguration {
+	if x != nil {
+		return x.FindMissingRetry
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetCaseInsensitiveOutputBaseIds() bool {
+	if x != nil {
+		return x.CaseInsensitiveOutputBaseIds
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumSymlinkFollowsPerPath() int64 {
+	if x != nil {
+		return x.MaximumSymlinkFollowsPerPath
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetEagerlyFetchDirectories() bool {
+	if x != nil {
+		return x.EagerlyFetchDirectories
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetAccessLog() *AccessLogConfiguration {
+	if x != nil {
+		return x.AccessLog
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetCacheDirectoryAttributes() bool {
+	if x != nil {
+		return x.CacheDirectoryAttributes
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetRejectConcurrentBuilds() bool {
+	if x != nil {
+		return x.RejectConcurrentBuilds
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetValidatePaths() bool {
+	if x != nil {
+		return x.ValidatePaths
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetPinDigestFunctionPerOutputBase() bool {
+	if x != nil {
+		return x.PinDigestFunctionPerOutputBase
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetDirectoryFetchConcurrency() int64 {
+	if x != nil {
+		return x.DirectoryFetchConcurrency
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetPreserveUnchangedFiles() bool {
+	if x != nil {
+		return x.PreserveUnchangedFiles
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumEstimatedMemoryUsageBytes() int64 {
+	if x != nil {
+		return x.MaximumEstimatedMemoryUsageBytes
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFindMissingBatchSize() int64 {
+	if x != nil {
+		return x.FindMissingBatchSize
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetPrefetchRootDirectories() bool {
+	if x != nil {
+		return x.PrefetchRootDirectories
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetConcurrentBuildGracePeriod() *durationpb.Duration {
+	if x != nil {
+		return x.ConcurrentBuildGracePeriod
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetPreparedOutputBaseTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.PreparedOutputBaseTimeout
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFileDigestCacheSize() int64 {
+	if x != nil {
+		return x.FileDigestCacheSize
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetShutdownTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ShutdownTimeout
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumInternedNamesCount() int64 {
+	if x != nil {
+		return x.MaximumInternedNamesCount
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFindMissingContentAddressableStorage() *blobstore.BlobAccessConfiguration {
+	if x != nil {
+		return x.FindMissingContentAddressableStorage
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumBatchCreateEntriesCount() int64 {
+	if x != nil {
+		return x.MaximumBatchCreateEntriesCount
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumBatchStatPathsCount() int64 {
+	if x != nil {
+		return x.MaximumBatchStatPathsCount
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumBatchRemovePathsCount() int64 {
+	if x != nil {
+		return x.MaximumBatchRemovePathsCount
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumSymlinkTargetLengthBytes() int64 {
+	if x != nil {
+		return x.MaximumSymlinkTargetLengthBytes
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumReadFileRangeSizeBytes() uint64 {
+	if x != nil {
+		return x.MaximumReadFileRangeSizeBytes
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetDirectoryFetchTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.DirectoryFetchTimeout
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetInstanceNameAliases() map[string]string {
+	if x != nil {
+		return x.InstanceNameAliases
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetTrackLazyDirectories() bool {
+	if x != nil {
+		return x.TrackLazyDirectories
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFilterMissingConcurrency() int64 {
+	if x != nil {
+		return x.FilterMissingConcurrency
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetSortedDirectoryListing() bool {
+	if x != nil {
+		return x.SortedDirectoryListing
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetFindMissingRevalidationInterval() *durationpb.Duration {
+	if x != nil {
+		return x.FindMissingRevalidationInterval
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetRemoveChildrenMissingDuringBuild() bool {
+	if x != nil {
+		return x.RemoveChildrenMissingDuringBuild
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumPathDepth() int64 {
+	if x != nil {
+		return x.MaximumPathDepth
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumSnapshotsCount() int64 {
+	if x != nil {
+		return x.MaximumSnapshotsCount
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetIdempotencyTokenExpiration() *durationpb.Duration {
+	if x != nil {
+		return x.IdempotencyTokenExpiration
+	}
+	return nil
+}
+
+func (x *RemoteOutputServiceConfiguration) GetMaximumIdempotencyTokensPerBuild() int64 {
+	if x != nil {
+		return x.MaximumIdempotencyTokensPerBuild
+	}
+	return 0
+}
+
+func (x *RemoteOutputServiceConfiguration) GetTrackExecutableBitChanges() bool {
+	if x != nil {
+		return x.TrackExecutableBitChanges
+	}
+	return false
+}
+
+func (x *RemoteOutputServiceConfiguration) GetCasFileReadConcurrency() int64 {
+	if x != nil {
+		return x.CasFileReadConcurrency
+	}
+	return 0
+}
+
+type AccessLogConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MaximumPathsCount int64 `protobuf:"varint,1,opt,name=maximum_paths_count,json=maximumPathsCount,proto3" json:"maximum_paths_count,omitempty"`
+}
+
+func (x *AccessLogConfiguration) Reset() {
+	*x = AccessLogConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AccessLogConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessLogConfiguration) ProtoMessage() {}
+
+func (x *AccessLogConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessLogConfiguration.ProtoReflect.Descriptor instead.
+func (*AccessLogConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AccessLogConfiguration) GetMaximumPathsCount() int64 {
+	if x != nil {
+		return x.MaximumPathsCount
+	}
+	return 0
+}
+
+type FindMissingRetryConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MaximumAttempts int64                `protobuf:"varint,1,opt,name=maximum_attempts,json=maximumAttempts,proto3" json:"maximum_attempts,omitempty"`
+	InitialBackoff  *durationpb.Duration `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	MaximumBackoff  *durationpb.Duration `protobuf:"bytes,3,opt,name=maximum_backoff,json=maximumBackoff,proto3" json:"maximum_backoff,omitempty"`
+}
+
+func (x *FindMissingRetryConfiguration) Reset() {
+	*x = FindMissingRetryConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindMissingRetryConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMissingRetryConfiguration) ProtoMessage() {}
+
+func (x *FindMissingRetryConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMissingRetryConfiguration.ProtoReflect.Descriptor instead.
+func (*FindMissingRetryConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FindMissingRetryConfiguration) GetMaximumAttempts() int64 {
+	if x != nil {
+		return x.MaximumAttempts
+	}
+	return 0
+}
+
+func (x *FindMissingRetryConfiguration) GetInitialBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return nil
+}
+
+func (x *FindMissingRetryConfiguration) GetMaximumBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.MaximumBackoff
+	}
+	return nil
+}
+
+type ContentAddressableStorageHealthCheckConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName  string               `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	CacheDuration *durationpb.Duration `protobuf:"bytes,2,opt,name=cache_duration,json=cacheDuration,proto3" json:"cache_duration,omitempty"`
+	Timeout       *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (x *ContentAddressableStorageHealthCheckConfiguration) Reset() {
+	*x = ContentAddressableStorageHealthCheckConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContentAddressableStorageHealthCheckConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentAddressableStorageHealthCheckConfiguration) ProtoMessage() {}
+
+func (x *ContentAddressableStorageHealthCheckConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentAddressableStorageHealthCheckConfiguration.ProtoReflect.Descriptor instead.
+func (*ContentAddressableStorageHealthCheckConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ContentAddressableStorageHealthCheckConfiguration) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *ContentAddressableStorageHealthCheckConfiguration) GetCacheDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CacheDuration
+	}
+	return nil
+}
+
+func (x *ContentAddressableStorageHealthCheckConfiguration) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
 var File_pkg_proto_configuration_bb_clientd_bb_clientd_proto protoreflect.FileDescriptor
 
 var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDesc = []byte{
@@ -257,7 +845,7 @@ var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDesc = []byte{
 	0x6c, 0x2f, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x27,
 	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
 	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe5, 0x08, 0x0a, 0x18, 0x41, 0x70, 0x70, 0x6c,
+	0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8f, 0x0b, 0x0a, 0x18, 0x41, 0x70, 0x70, 0x6c,
 	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
 	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x57, 0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
@@ -320,38 +908,300 @@ var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDesc = []byte{
 	0x61, 0x73, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
 	0x6f, 0x72, 0x79, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
 	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x1a, 0x76, 0x0a, 0x0f, 0x53, 0x63, 0x68, 0x65, 0x64,
-	0x75, 0x6c, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x4d, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x62, 0x75,
-	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x63,
-	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0xab, 0x02, 0x0a, 0x22, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x50, 0x65,
-	0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
-	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x73, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63,
-	0x74, 0x6f, 0x72, 0x79, 0x50, 0x61, 0x74, 0x68, 0x12, 0x40, 0x0a, 0x1d, 0x6d, 0x61, 0x78, 0x69,
-	0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73,
-	0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x19, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c,
-	0x65, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x4e, 0x0a, 0x16, 0x6d, 0x61,
-	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65,
-	0x5f, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x41, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x1d, 0x6c, 0x6f,
-	0x63, 0x61, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f,
-	0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x1a, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x42, 0x44, 0x5a,
-	0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c,
-	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
-	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x62, 0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x12, 0x78, 0x0a, 0x15, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x44, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x62, 0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x13, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0xad, 0x01, 0x0a, 0x28, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x5f, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x55, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62,
+	0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x24, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x1a, 0x76, 0x0a, 0x0f, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x4d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xab, 0x02, 0x0a, 0x22, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x50, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x30, 0x0a, 0x14, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x40, 0x0a, 0x1d, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x19, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x4e, 0x0a, 0x16, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x13, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c,
+	0x65, 0x41, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x1d, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1a, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0xbf, 0x1a, 0x0a, 0x20, 0x52, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x1c,
+	0x63, 0x6c, 0x65, 0x61, 0x6e, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x65, 0x64, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x19, 0x63, 0x6c, 0x65, 0x61, 0x6e, 0x43, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74,
+	0x65, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x3e, 0x0a,
+	0x1b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x19, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x38, 0x0a,
+	0x18, 0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f,
+	0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x16, 0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x4a, 0x0a, 0x22, 0x66, 0x72, 0x65, 0x65, 0x7a,
+	0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x62,
+	0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x1e, 0x66, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x42, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x73, 0x12, 0x4b, 0x0a, 0x23, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x1e, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x50, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x12, 0x49, 0x0a, 0x22, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1d, 0x6d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65,
+	0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x6f, 0x0a, 0x12, 0x66,
+	0x69, 0x6e, 0x64, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x46, 0x69, 0x6e,
+	0x64, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x64,
+	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x74, 0x72, 0x79, 0x12, 0x46, 0x0a, 0x20,
+	0x63, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x73,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1c, 0x63, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x73, 0x65,
+	0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73,
+	0x65, 0x49, 0x64, 0x73, 0x12, 0x46, 0x0a, 0x20, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x73, 0x5f,
+	0x70, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1c,
+	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x46, 0x6f,
+	0x6c, 0x6c, 0x6f, 0x77, 0x73, 0x50, 0x65, 0x72, 0x50, 0x61, 0x74, 0x68, 0x12, 0x3a, 0x0a, 0x19,
+	0x65, 0x61, 0x67, 0x65, 0x72, 0x6c, 0x79, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x17, 0x65, 0x61, 0x67, 0x65, 0x72, 0x6c, 0x79, 0x46, 0x65, 0x74, 0x63, 0x68, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x5f, 0x6c, 0x6f, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x64, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4c, 0x6f, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x4c, 0x6f, 0x67, 0x12, 0x3c, 0x0a, 0x1a, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
+	0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x18, 0x63, 0x61, 0x63, 0x68, 0x65, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
+	0x73, 0x12, 0x38, 0x0a, 0x18, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x16, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x12, 0x4b, 0x0a, 0x23, 0x70, 0x69, 0x6e, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x1e, 0x70, 0x69, 0x6e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x50, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x12,
+	0x3e, 0x0a, 0x1b, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x66, 0x65, 0x74,
+	0x63, 0x68, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x19, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x46,
+	0x65, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x38, 0x0a, 0x18, 0x70, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x75, 0x6e, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x16, 0x70, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x55, 0x6e, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x4e, 0x0a, 0x24, 0x6d, 0x61, 0x78,
+	0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x6d,
+	0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x20, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61,
+	0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x35, 0x0a, 0x17, 0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73,
+	0x73, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x3a, 0x0a,
+	0x19, 0x70, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x17, 0x70, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x74, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x5c, 0x0a, 0x1d, 0x63, 0x6f, 0x6e,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x67, 0x72,
+	0x61, 0x63, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x1a, 0x63, 0x6f, 0x6e,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x47, 0x72, 0x61, 0x63,
+	0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x5a, 0x0a, 0x1c, 0x70, 0x72, 0x65, 0x70, 0x61,
+	0x72, 0x65, 0x64, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x19, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72,
+	0x65, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x33, 0x0a, 0x16, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x18, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x13, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x43,
+	0x61, 0x63, 0x68, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x73, 0x68, 0x75, 0x74,
+	0x64, 0x6f, 0x77, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x19, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x73,
+	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x3f,
+	0x0a, 0x1c, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x1a,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x19, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x6e, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x92, 0x01, 0x0a, 0x28, 0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x6c, 0x6f,
+	0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x41, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x24,
+	0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x12, 0x4a, 0x0a, 0x22, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x1e, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x42, 0x0a, 0x1e, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x62, 0x61, 0x74, 0x63,
+	0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1a, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75,
+	0x6d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x46, 0x0a, 0x20, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1c,
+	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x4c, 0x0a, 0x23,
+	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1f, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x49, 0x0a, 0x22, 0x6d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x20, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1d, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52,
+	0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x51, 0x0a, 0x17, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x21, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x15, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x46, 0x65, 0x74, 0x63,
+	0x68, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x91, 0x01, 0x0a, 0x15, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73,
+	0x65, 0x73, 0x18, 0x22, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x5d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2e, 0x52, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x16,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x5f, 0x6c, 0x61, 0x7a, 0x79, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x23, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x4c, 0x61, 0x7a, 0x79, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69,
+	0x65, 0x73, 0x12, 0x3c, 0x0a, 0x1a, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x24, 0x20, 0x01, 0x28, 0x03, 0x52, 0x18, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x4d, 0x69,
+	0x73, 0x73, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x38, 0x0a, 0x18, 0x73, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x25, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x16, 0x73, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x66, 0x0a, 0x22, 0x66, 0x69,
+	0x6e, 0x64, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x18, 0x26, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x1f, 0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x12, 0x4e, 0x0a, 0x24, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x63, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x75,
+	0x72, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x27, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x20, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e,
+	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x44, 0x75, 0x72, 0x69, 0x6e, 0x67, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x28, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x44, 0x65, 0x70, 0x74, 0x68,
+	0x12, 0x36, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x29, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x15, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x5b, 0x0a, 0x1c, 0x69, 0x64, 0x65, 0x6d,
+	0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x1a, 0x69, 0x64, 0x65, 0x6d, 0x70,
+	0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4e, 0x0a, 0x24, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x5f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x2b, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x20, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x49, 0x64, 0x65, 0x6d,
+	0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x50, 0x65, 0x72,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x3f, 0x0a, 0x1c, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x5f, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x69, 0x74, 0x5f, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x19, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x69, 0x74, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x19, 0x63, 0x61, 0x73, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x2e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x63, 0x61, 0x73, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x1a, 0x46, 0x0a, 0x18, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x48, 0x0a, 0x16, 0x41, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x4c, 0x6f, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x11, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0xd2, 0x01, 0x0a, 0x1d, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73,
+	0x12, 0x42, 0x0a, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x63, 0x6b,
+	0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x42, 0x61, 0x63,
+	0x6b, 0x6f, 0x66, 0x66, 0x12, 0x42, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75,
+	0x6d, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x22, 0xcf, 0x01, 0x0a, 0x31, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23,
+	0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x40, 0x0a, 0x0e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x62, 0x62, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -366,37 +1216,59 @@ func file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDescGZIP() []by
 	return file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDescData
 }
 
-var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_goTypes = []interface{}{
-	(*ApplicationConfiguration)(nil),           // 0: buildbarn.configuration.bb_clientd.ApplicationConfiguration
-	(*OutputPathPersistencyConfiguration)(nil), // 1: buildbarn.configuration.bb_clientd.OutputPathPersistencyConfiguration
-	nil,                                              // 2: buildbarn.configuration.bb_clientd.ApplicationConfiguration.SchedulersEntry
-	(*blobstore.BlobstoreConfiguration)(nil),         // 3: buildbarn.configuration.blobstore.BlobstoreConfiguration
-	(*global.Configuration)(nil),                     // 4: buildbarn.configuration.global.Configuration
-	(*virtual.MountConfiguration)(nil),               // 5: buildbarn.configuration.filesystem.virtual.MountConfiguration
-	(*grpc.ServerConfiguration)(nil),                 // 6: buildbarn.configuration.grpc.ServerConfiguration
-	(*filesystem.FilePoolConfiguration)(nil),         // 7: buildbarn.configuration.filesystem.FilePoolConfiguration
-	(*durationpb.Duration)(nil),                      // 8: google.protobuf.Duration
-	(*cas.CachingDirectoryFetcherConfiguration)(nil), // 9: buildbarn.configuration.cas.CachingDirectoryFetcherConfiguration
-	(*builder.SchedulerConfiguration)(nil),           // 10: buildbarn.configuration.builder.SchedulerConfiguration
+	(*ApplicationConfiguration)(nil),                          // 0: buildbarn.configuration.bb_clientd.ApplicationConfiguration
+	(*OutputPathPersistencyConfiguration)(nil),                // 1: buildbarn.configuration.bb_clientd.OutputPathPersistencyConfiguration
+	(*RemoteOutputServiceConfiguration)(nil),                  // 2: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration
+	(*AccessLogConfiguration)(nil),                            // 3: buildbarn.configuration.bb_clientd.AccessLogConfiguration
+	(*FindMissingRetryConfiguration)(nil),                     // 4: buildbarn.configuration.bb_clientd.FindMissingRetryConfiguration
+	(*ContentAddressableStorageHealthCheckConfiguration)(nil), // 5: buildbarn.configuration.bb_clientd.ContentAddressableStorageHealthCheckConfiguration
+	nil,                                      // 6: buildbarn.configuration.bb_clientd.ApplicationConfiguration.SchedulersEntry
+	nil,                                      // 7: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.InstanceNameAliasesEntry
+	(*blobstore.BlobstoreConfiguration)(nil), // 8: buildbarn.configuration.blobstore.BlobstoreConfiguration
+	(*global.Configuration)(nil),             // 9: buildbarn.configuration.global.Configuration
+	(*virtual.MountConfiguration)(nil),       // 10: buildbarn.configuration.filesystem.virtual.MountConfiguration
+	(*grpc.ServerConfiguration)(nil),         // 11: buildbarn.configuration.grpc.ServerConfiguration
+	(*filesystem.FilePoolConfiguration)(nil), // 12: buildbarn.configuration.filesystem.FilePoolConfiguration
+	(*durationpb.Duration)(nil),              // 13: google.protobuf.Duration
+	(*cas.CachingDirectoryFetcherConfiguration)(nil), // 14: buildbarn.configuration.cas.CachingDirectoryFetcherConfiguration
+	(*blobstore.BlobAccessConfiguration)(nil),        // 15: buildbarn.configuration.blobstore.BlobAccessConfiguration
+	(*builder.SchedulerConfiguration)(nil),           // 16: buildbarn.configuration.builder.SchedulerConfiguration
 }
 var file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_depIdxs = []int32{
-	3,  // 0: buildbarn.configuration.bb_clientd.ApplicationConfiguration.blobstore:type_name -> buildbarn.configuration.blobstore.BlobstoreConfiguration
-	4,  // 1: buildbarn.configuration.bb_clientd.ApplicationConfiguration.global:type_name -> buildbarn.configuration.global.Configuration
-	5,  // 2: buildbarn.configuration.bb_clientd.ApplicationConfiguration.mount:type_name -> buildbarn.configuration.filesystem.virtual.MountConfiguration
-	6,  // 3: buildbarn.configuration.bb_clientd.ApplicationConfiguration.grpc_servers:type_name -> buildbarn.configuration.grpc.ServerConfiguration
-	2,  // 4: buildbarn.configuration.bb_clientd.ApplicationConfiguration.schedulers:type_name -> buildbarn.configuration.bb_clientd.ApplicationConfiguration.SchedulersEntry
-	7,  // 5: buildbarn.configuration.bb_clientd.ApplicationConfiguration.file_pool:type_name -> buildbarn.configuration.filesystem.FilePoolConfiguration
+	8,  // 0: buildbarn.configuration.bb_clientd.ApplicationConfiguration.blobstore:type_name -> buildbarn.configuration.blobstore.BlobstoreConfiguration
+	9,  // 1: buildbarn.configuration.bb_clientd.ApplicationConfiguration.global:type_name -> buildbarn.configuration.global.Configuration
+	10, // 2: buildbarn.configuration.bb_clientd.ApplicationConfiguration.mount:type_name -> buildbarn.configuration.filesystem.virtual.MountConfiguration
+	11, // 3: buildbarn.configuration.bb_clientd.ApplicationConfiguration.grpc_servers:type_name -> buildbarn.configuration.grpc.ServerConfiguration
+	6,  // 4: buildbarn.configuration.bb_clientd.ApplicationConfiguration.schedulers:type_name -> buildbarn.configuration.bb_clientd.ApplicationConfiguration.SchedulersEntry
+	12, // 5: buildbarn.configuration.bb_clientd.ApplicationConfiguration.file_pool:type_name -> buildbarn.configuration.filesystem.FilePoolConfiguration
 	1,  // 6: buildbarn.configuration.bb_clientd.ApplicationConfiguration.output_path_persistency:type_name -> buildbarn.configuration.bb_clientd.OutputPathPersistencyConfiguration
-	8,  // 7: buildbarn.configuration.bb_clientd.ApplicationConfiguration.maximum_file_system_retry_delay:type_name -> google.protobuf.Duration
-	9,  // 8: buildbarn.configuration.bb_clientd.ApplicationConfiguration.directory_cache:type_name -> buildbarn.configuration.cas.CachingDirectoryFetcherConfiguration
-	8,  // 9: buildbarn.configuration.bb_clientd.OutputPathPersistencyConfiguration.maximum_state_file_age:type_name -> google.protobuf.Duration
-	10, // 10: buildbarn.configuration.bb_clientd.ApplicationConfiguration.SchedulersEntry.value:type_name -> buildbarn.configuration.builder.SchedulerConfiguration
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	13, // 7: buildbarn.configuration.bb_clientd.ApplicationConfiguration.maximum_file_system_retry_delay:type_name -> google.protobuf.Duration
+	14, // 8: buildbarn.configuration.bb_clientd.ApplicationConfiguration.directory_cache:type_name -> buildbarn.configuration.cas.CachingDirectoryFetcherConfiguration
+	2,  // 9: buildbarn.configuration.bb_clientd.ApplicationConfiguration.remote_output_service:type_name -> buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration
+	5,  // 10: buildbarn.configuration.bb_clientd.ApplicationConfiguration.content_addressable_storage_health_check:type_name -> buildbarn.configuration.bb_clientd.ContentAddressableStorageHealthCheckConfiguration
+	13, // 11: buildbarn.configuration.bb_clientd.OutputPathPersistencyConfiguration.maximum_state_file_age:type_name -> google.protobuf.Duration
+	4,  // 12: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.find_missing_retry:type_name -> buildbarn.configuration.bb_clientd.FindMissingRetryConfiguration
+	3,  // 13: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.access_log:type_name -> buildbarn.configuration.bb_clientd.AccessLogConfiguration
+	13, // 14: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.concurrent_build_grace_period:type_name -> google.protobuf.Duration
+	13, // 15: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.prepared_output_base_timeout:type_name -> google.protobuf.Duration
+	13, // 16: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.shutdown_timeout:type_name -> google.protobuf.Duration
+	15, // 17: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.find_missing_content_addressable_storage:type_name -> buildbarn.configuration.blobstore.BlobAccessConfiguration
+	13, // 18: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.directory_fetch_timeout:type_name -> google.protobuf.Duration
+	7,  // 19: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.instance_name_aliases:type_name -> buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.InstanceNameAliasesEntry
+	13, // 20: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.find_missing_revalidation_interval:type_name -> google.protobuf.Duration
+	13, // 21: buildbarn.configuration.bb_clientd.RemoteOutputServiceConfiguration.idempotency_token_expiration:type_name -> google.protobuf.Duration
+	13, // 22: buildbarn.configuration.bb_clientd.FindMissingRetryConfiguration.initial_backoff:type_name -> google.protobuf.Duration
+	13, // 23: buildbarn.configuration.bb_clientd.FindMissingRetryConfiguration.maximum_backoff:type_name -> google.protobuf.Duration
+	13, // 24: buildbarn.configuration.bb_clientd.ContentAddressableStorageHealthCheckConfiguration.cache_duration:type_name -> google.protobuf.Duration
+	13, // 25: buildbarn.configuration.bb_clientd.ContentAddressableStorageHealthCheckConfiguration.timeout:type_name -> google.protobuf.Duration
+	16, // 26: buildbarn.configuration.bb_clientd.ApplicationConfiguration.SchedulersEntry.value:type_name -> buildbarn.configuration.builder.SchedulerConfiguration
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_init() }
@@ -429,6 +1301,54 @@ func file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoteOutputServiceConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AccessLogConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindMissingRetryConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentAddressableStorageHealthCheckConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -436,7 +1356,7 @@ func file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_configuration_bb_clientd_bb_clientd_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},